@@ -41,9 +41,14 @@ type API interface {
 	GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []flow.Identifier) ([]flow.BlockEvents, error)
 
 	GetLatestProtocolStateSnapshot(ctx context.Context) ([]byte, error)
+	GetProtocolStateSnapshotByHeight(ctx context.Context, height uint64) ([]byte, error)
 
 	GetExecutionResultForBlockID(ctx context.Context, blockID flow.Identifier) (*flow.ExecutionResult, error)
 	GetExecutionResultByID(ctx context.Context, id flow.Identifier) (*flow.ExecutionResult, error)
+
+	GetSlashingEvidence(ctx context.Context) ([]*flow.SlashingEvidence, error)
+
+	GetEpochServiceEventProofs(ctx context.Context, counter uint64) ([]*flow.ServiceEventProof, error)
 }
 
 // TODO: Combine this with flow.TransactionResult?
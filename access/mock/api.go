@@ -270,6 +270,29 @@ func (_m *API) GetCollectionByID(ctx context.Context, id flow.Identifier) (*flow
 	return r0, r1
 }
 
+// GetEpochServiceEventProofs provides a mock function with given fields: ctx, counter
+func (_m *API) GetEpochServiceEventProofs(ctx context.Context, counter uint64) ([]*flow.ServiceEventProof, error) {
+	ret := _m.Called(ctx, counter)
+
+	var r0 []*flow.ServiceEventProof
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []*flow.ServiceEventProof); ok {
+		r0 = rf(ctx, counter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*flow.ServiceEventProof)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, counter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEventsForBlockIDs provides a mock function with given fields: ctx, eventType, blockIDs
 func (_m *API) GetEventsForBlockIDs(ctx context.Context, eventType string, blockIDs []flow.Identifier) ([]flow.BlockEvents, error) {
 	ret := _m.Called(ctx, eventType, blockIDs)
@@ -445,6 +468,52 @@ func (_m *API) GetNetworkParameters(ctx context.Context) access.NetworkParameter
 	return r0
 }
 
+// GetProtocolStateSnapshotByHeight provides a mock function with given fields: ctx, height
+func (_m *API) GetProtocolStateSnapshotByHeight(ctx context.Context, height uint64) ([]byte, error) {
+	ret := _m.Called(ctx, height)
+
+	var r0 []byte
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []byte); ok {
+		r0 = rf(ctx, height)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, height)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSlashingEvidence provides a mock function with given fields: ctx
+func (_m *API) GetSlashingEvidence(ctx context.Context) ([]*flow.SlashingEvidence, error) {
+	ret := _m.Called(ctx)
+
+	var r0 []*flow.SlashingEvidence
+	if rf, ok := ret.Get(0).(func(context.Context) []*flow.SlashingEvidence); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*flow.SlashingEvidence)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransaction provides a mock function with given fields: ctx, id
 func (_m *API) GetTransaction(ctx context.Context, id flow.Identifier) (*flow.TransactionBody, error) {
 	ret := _m.Called(ctx, id)
@@ -0,0 +1,92 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/consensus/approvals"
+	"github.com/onflow/flow-go/engine/consensus/sealing"
+)
+
+var _ commands.AdminCommand = (*ReadSealingStateCommand)(nil)
+
+type readSealingStateRequest struct {
+	heights  uint64 // number of lowest heights to return, 0 for all heights
+	orphaned bool   // whether to include orphaned results
+}
+
+// ReadSealingStateCommand returns the state of the sealing pipeline: for each unsealed execution result, the
+// blocks incorporating it, the approvals collected per chunk versus the required approvals, the assigned and
+// missing verifiers, the approval requests made, and whether the result qualifies for emergency sealing.
+// Optional fields:
+//   - "heights": the number of lowest unsealed heights to return, which are the ones blocking sealing
+//   - "orphaned": whether to include results for orphaned forks (default false)
+type ReadSealingStateCommand struct {
+	inspect func() *sealing.Inspection
+}
+
+func (r *ReadSealingStateCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*readSealingStateRequest)
+
+	inspection := r.inspect()
+	if inspection == nil {
+		return nil, errors.New("sealing engine is not initialized yet")
+	}
+
+	collectors := make([]*approvals.CollectorInspection, 0, len(inspection.Collectors))
+	for _, collector := range inspection.Collectors {
+		if data.heights > 0 && collector.Height >= inspection.LowestHeight+data.heights {
+			break
+		}
+		if !data.orphaned && collector.Status == approvals.Orphaned.String() {
+			continue
+		}
+		collectors = append(collectors, collector)
+	}
+	inspection.Collectors = collectors
+
+	return commands.ConvertToMap(inspection)
+}
+
+func (r *ReadSealingStateCommand) Validator(req *admin.CommandRequest) error {
+	data := &readSealingStateRequest{}
+
+	if req.Data != nil {
+		input, ok := req.Data.(map[string]interface{})
+		if !ok {
+			return errors.New("wrong input format: expected JSON")
+		}
+
+		if heights, ok := input["heights"]; ok {
+			n, ok := heights.(float64)
+			if !ok || n <= 0 || math.Trunc(n) != n {
+				return fmt.Errorf("invalid value for \"heights\": expected a positive integer, but got: %v", heights)
+			}
+			data.heights = uint64(n)
+		}
+
+		if orphaned, ok := input["orphaned"]; ok {
+			b, ok := orphaned.(bool)
+			if !ok {
+				return fmt.Errorf("invalid value for \"orphaned\": expected a boolean, but got: %v", orphaned)
+			}
+			data.orphaned = b
+		}
+	}
+
+	req.ValidatorData = data
+	return nil
+}
+
+// NewReadSealingStateCommand creates a command returning the state of the sealing pipeline. Since the sealing
+// engine is created after the admin commands are registered, its inspection is passed as a function, which
+// returns nil until the engine is created.
+func NewReadSealingStateCommand(inspect func() *sealing.Inspection) commands.AdminCommand {
+	return &ReadSealingStateCommand{
+		inspect: inspect,
+	}
+}
@@ -0,0 +1,78 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/engine/consensus/approvals"
+	"github.com/onflow/flow-go/engine/consensus/sealing"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestReadSealingState(t *testing.T) {
+	inspection := func() *sealing.Inspection {
+		collector := func(height uint64, status approvals.ProcessingStatus) *approvals.CollectorInspection {
+			return &approvals.CollectorInspection{
+				ResultID: unittest.IdentifierFixture(),
+				BlockID:  unittest.IdentifierFixture(),
+				Height:   height,
+				Status:   status.String(),
+			}
+		}
+		return &sealing.Inspection{
+			TreeInspection: &approvals.TreeInspection{
+				LowestHeight: 10,
+				Collectors: []*approvals.CollectorInspection{
+					collector(10, approvals.VerifyingApprovals),
+					collector(11, approvals.VerifyingApprovals),
+					collector(11, approvals.Orphaned),
+					collector(12, approvals.CachingApprovals),
+				},
+			},
+		}
+	}
+
+	newRequest := func(data string) *admin.CommandRequest {
+		req := &admin.CommandRequest{}
+		require.NoError(t, json.Unmarshal([]byte(data), &req.Data))
+		return req
+	}
+	read := func(command *ReadSealingStateCommand, data string) []interface{} {
+		req := newRequest(data)
+		require.NoError(t, command.Validator(req))
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		return result.(map[string]interface{})["collectors"].([]interface{})
+	}
+
+	command := NewReadSealingStateCommand(inspection).(*ReadSealingStateCommand)
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, data := range []string{`"all"`, `{"heights": 0}`, `{"heights": 1.5}`, `{"orphaned": "yes"}`} {
+			require.Error(t, command.Validator(newRequest(data)), data)
+		}
+	})
+
+	t.Run("all heights", func(t *testing.T) {
+		require.Len(t, read(command, `{}`), 3)
+		require.Len(t, read(command, `{"orphaned": true}`), 4)
+	})
+
+	t.Run("lowest heights", func(t *testing.T) {
+		collectors := read(command, `{"heights": 2}`)
+		require.Len(t, collectors, 2)
+		require.Equal(t, float64(11), collectors[1].(map[string]interface{})["height"])
+	})
+
+	t.Run("engine not initialized", func(t *testing.T) {
+		command := NewReadSealingStateCommand(func() *sealing.Inspection { return nil })
+		req := newRequest(`{}`)
+		require.NoError(t, command.Validator(req))
+		_, err := command.Handler(context.Background(), req)
+		require.Error(t, err)
+	})
+}
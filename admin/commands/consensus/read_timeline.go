@@ -0,0 +1,86 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+)
+
+var _ commands.AdminCommand = (*ReadTimelineCommand)(nil)
+
+const (
+	timelineFormatJSON        = "json"
+	timelineFormatChromeTrace = "chrome-trace"
+)
+
+type readTimelineRequest struct {
+	format string
+	views  uint64 // number of most recent views to return, 0 for all recorded views
+}
+
+// ReadTimelineCommand returns the per-view timelines recorded by the HotStuff timeline recorder.
+// Optional fields:
+//   - "format": "json" (default) returns the timelines, "chrome-trace" returns them in the Chrome
+//     trace event format, which can be loaded into chrome://tracing or Perfetto
+//   - "views": the number of most recent views to return
+type ReadTimelineCommand struct {
+	recorder *notifications.TimelineRecorder
+}
+
+func (r *ReadTimelineCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	data := req.ValidatorData.(*readTimelineRequest)
+
+	timelines := r.recorder.Timelines()
+	if data.views > 0 && uint64(len(timelines)) > data.views {
+		timelines = timelines[uint64(len(timelines))-data.views:]
+	}
+
+	if data.format == timelineFormatChromeTrace {
+		return commands.ConvertToMap(notifications.NewChromeTrace(timelines))
+	}
+	return commands.ConvertToInterfaceList(timelines)
+}
+
+func (r *ReadTimelineCommand) Validator(req *admin.CommandRequest) error {
+	data := &readTimelineRequest{
+		format: timelineFormatJSON,
+	}
+
+	if req.Data != nil {
+		input, ok := req.Data.(map[string]interface{})
+		if !ok {
+			return errors.New("wrong input format: expected JSON")
+		}
+
+		if format, ok := input["format"]; ok {
+			str, ok := format.(string)
+			if !ok || (str != timelineFormatJSON && str != timelineFormatChromeTrace) {
+				return fmt.Errorf("invalid value for \"format\": expected %q or %q, but got: %v",
+					timelineFormatJSON, timelineFormatChromeTrace, format)
+			}
+			data.format = str
+		}
+
+		if views, ok := input["views"]; ok {
+			n, ok := views.(float64)
+			if !ok || n <= 0 || math.Trunc(n) != n {
+				return fmt.Errorf("invalid value for \"views\": expected a positive integer, but got: %v", views)
+			}
+			data.views = uint64(n)
+		}
+	}
+
+	req.ValidatorData = data
+	return nil
+}
+
+func NewReadTimelineCommand(recorder *notifications.TimelineRecorder) commands.AdminCommand {
+	return &ReadTimelineCommand{
+		recorder: recorder,
+	}
+}
@@ -0,0 +1,57 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestReadTimeline(t *testing.T) {
+	recorder := notifications.NewTimelineRecorder(10)
+	for view := uint64(1); view <= 5; view++ {
+		recorder.OnEnteringView(view, unittest.IdentifierFixture())
+	}
+	command := NewReadTimelineCommand(recorder)
+
+	newRequest := func(data string) *admin.CommandRequest {
+		req := &admin.CommandRequest{}
+		require.NoError(t, json.Unmarshal([]byte(data), &req.Data))
+		return req
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, data := range []string{`"json"`, `{"format": "xml"}`, `{"views": 0}`, `{"views": 1.5}`} {
+			require.Error(t, command.Validator(newRequest(data)), data)
+		}
+	})
+
+	t.Run("most recent views", func(t *testing.T) {
+		req := newRequest(`{"views": 2}`)
+		require.NoError(t, command.Validator(req))
+
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+
+		timelines := result.([]interface{})
+		require.Len(t, timelines, 2)
+		require.Equal(t, float64(4), timelines[0].(map[string]interface{})["view"])
+	})
+
+	t.Run("chrome trace", func(t *testing.T) {
+		req := newRequest(`{"format": "chrome-trace"}`)
+		require.NoError(t, command.Validator(req))
+
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+
+		trace := result.(map[string]interface{})
+		// a view span and an instant event for each of the views
+		require.Len(t, trace["traceEvents"], 10)
+	})
+}
@@ -0,0 +1,178 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
+	"github.com/onflow/flow-go/consensus/hotstuff/persister"
+	"github.com/onflow/flow-go/module"
+)
+
+var _ commands.AdminCommand = (*GetTimeoutConfigCommand)(nil)
+var _ commands.AdminCommand = (*SetTimeoutConfigCommand)(nil)
+
+// GetTimeoutConfigCommand returns the current configuration of the HotStuff timeout controller.
+type GetTimeoutConfigCommand struct {
+	controller func() *timeout.Controller
+}
+
+func (g *GetTimeoutConfigCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	controller := g.controller()
+	if controller == nil {
+		return nil, errors.New("hotstuff timeout controller is not initialized yet")
+	}
+	return configToMap(controller.Config()), nil
+}
+
+func (g *GetTimeoutConfigCommand) Validator(req *admin.CommandRequest) error {
+	return nil
+}
+
+// NewGetTimeoutConfigCommand creates a command returning the timeout configuration. Since the
+// controller is created after the admin commands are registered, it is passed as a getter.
+func NewGetTimeoutConfigCommand(controller func() *timeout.Controller) commands.AdminCommand {
+	return &GetTimeoutConfigCommand{
+		controller: controller,
+	}
+}
+
+// SetTimeoutConfigCommand changes parameters of the HotStuff timeout controller at runtime.
+// All fields are optional, but at least one has to be provided:
+//   - "min_replica_timeout", "max_replica_timeout", "block_rate_delay": durations, e.g. "1.5s"
+//   - "vote_aggregation_timeout_fraction", "timeout_increase", "timeout_decrease": numbers
+//
+// Accepted changes are persisted and re-applied when the node restarts, taking precedence over the
+// hotstuff timeout flags the node is started with. A warning is logged on startup when they do.
+type SetTimeoutConfigCommand struct {
+	controller func() *timeout.Controller
+	persist    *persister.Persister
+	metrics    module.HotstuffMetrics
+}
+
+func (s *SetTimeoutConfigCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	update := req.ValidatorData.(timeout.ConfigUpdate)
+
+	controller := s.controller()
+	if controller == nil {
+		return nil, errors.New("hotstuff timeout controller is not initialized yet")
+	}
+
+	persisted, err := s.persist.GetTimeoutConfigUpdate()
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve persisted timeout config: %w", err)
+	}
+
+	cfg, err := controller.Update(update)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout config: %w", err)
+	}
+
+	err = s.persist.PutTimeoutConfigUpdate(persisted.Merge(update))
+	if err != nil {
+		return nil, fmt.Errorf("timeout config updated, but could not be persisted: %w", err)
+	}
+
+	s.metrics.SetTimeoutConfig(
+		msToDuration(cfg.MinReplicaTimeout),
+		msToDuration(cfg.MaxReplicaTimeout),
+		msToDuration(cfg.BlockRateDelayMS),
+		cfg.VoteAggregationTimeoutFraction,
+		cfg.TimeoutIncrease,
+		cfg.TimeoutDecrease,
+	)
+
+	return configToMap(cfg), nil
+}
+
+func (s *SetTimeoutConfigCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return errors.New("wrong input format: expected JSON")
+	}
+
+	var update timeout.ConfigUpdate
+	for field, value := range input {
+		var err error
+		switch field {
+		case "min_replica_timeout":
+			update.MinReplicaTimeout, err = parseDuration(field, value)
+		case "max_replica_timeout":
+			update.MaxReplicaTimeout, err = parseDuration(field, value)
+		case "block_rate_delay":
+			update.BlockRateDelay, err = parseDuration(field, value)
+		case "vote_aggregation_timeout_fraction":
+			update.VoteAggregationTimeoutFraction, err = parseFloat(field, value)
+		case "timeout_increase":
+			update.TimeoutIncrease, err = parseFloat(field, value)
+		case "timeout_decrease":
+			update.TimeoutDecrease, err = parseFloat(field, value)
+		default:
+			err = fmt.Errorf("unknown field %q", field)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if update.IsEmpty() {
+		return errors.New("at least one timeout parameter has to be provided")
+	}
+
+	req.ValidatorData = update
+	return nil
+}
+
+// NewSetTimeoutConfigCommand creates a command updating the timeout configuration. Since the
+// controller is created after the admin commands are registered, it is passed as a getter.
+func NewSetTimeoutConfigCommand(
+	controller func() *timeout.Controller,
+	persist *persister.Persister,
+	metrics module.HotstuffMetrics,
+) commands.AdminCommand {
+	return &SetTimeoutConfigCommand{
+		controller: controller,
+		persist:    persist,
+		metrics:    metrics,
+	}
+}
+
+func parseDuration(field string, value interface{}) (*time.Duration, error) {
+	str, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid value for %q: expected a duration string, but got: %v", field, value)
+	}
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value for %q: %w", field, err)
+	}
+	return &duration, nil
+}
+
+func parseFloat(field string, value interface{}) (*float64, error) {
+	number, ok := value.(float64)
+	if !ok {
+		return nil, fmt.Errorf("invalid value for %q: expected a number, but got: %v", field, value)
+	}
+	return &number, nil
+}
+
+func msToDuration(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+func configToMap(cfg timeout.Config) map[string]interface{} {
+	return map[string]interface{}{
+		"replica_timeout":                   msToDuration(cfg.ReplicaTimeout).String(),
+		"min_replica_timeout":               msToDuration(cfg.MinReplicaTimeout).String(),
+		"max_replica_timeout":               msToDuration(cfg.MaxReplicaTimeout).String(),
+		"block_rate_delay":                  msToDuration(cfg.BlockRateDelayMS).String(),
+		"vote_aggregation_timeout_fraction": cfg.VoteAggregationTimeoutFraction,
+		"timeout_increase":                  cfg.TimeoutIncrease,
+		"timeout_decrease":                  cfg.TimeoutDecrease,
+	}
+}
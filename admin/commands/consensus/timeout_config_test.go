@@ -0,0 +1,84 @@
+package consensus
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
+	"github.com/onflow/flow-go/consensus/hotstuff/persister"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestSetTimeoutConfig(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		controller := timeout.NewController(timeout.DefaultConfig)
+		persist := persister.New(db, flow.Localnet)
+		command := NewSetTimeoutConfigCommand(func() *timeout.Controller { return controller }, persist, metrics.NewNoopCollector())
+
+		newRequest := func(data string) *admin.CommandRequest {
+			req := &admin.CommandRequest{}
+			require.NoError(t, json.Unmarshal([]byte(data), &req.Data))
+			return req
+		}
+
+		t.Run("invalid input", func(t *testing.T) {
+			for _, data := range []string{
+				`"1s"`,
+				`{}`,
+				`{"min_replica_timeout": 1000}`,
+				`{"min_replica_timeout": "soon"}`,
+				`{"timeout_increase": "2"}`,
+				`{"unknown": 1}`,
+			} {
+				require.Error(t, command.Validator(newRequest(data)), data)
+			}
+		})
+
+		t.Run("invalid config is rejected", func(t *testing.T) {
+			before := controller.Config()
+			req := newRequest(`{"min_replica_timeout": "10s", "max_replica_timeout": "5s"}`)
+			require.NoError(t, command.Validator(req))
+
+			_, err := command.Handler(context.Background(), req)
+			require.Error(t, err)
+			require.Equal(t, before, controller.Config())
+
+			persisted, err := persist.GetTimeoutConfigUpdate()
+			require.NoError(t, err)
+			require.True(t, persisted.IsEmpty())
+		})
+
+		t.Run("updates are applied and persisted", func(t *testing.T) {
+			req := newRequest(`{"min_replica_timeout": "3s", "block_rate_delay": "500ms"}`)
+			require.NoError(t, command.Validator(req))
+			_, err := command.Handler(context.Background(), req)
+			require.NoError(t, err)
+
+			req = newRequest(`{"timeout_increase": 1.5}`)
+			require.NoError(t, command.Validator(req))
+			_, err = command.Handler(context.Background(), req)
+			require.NoError(t, err)
+
+			require.Equal(t, 3*time.Second, time.Duration(controller.Config().MinReplicaTimeout)*time.Millisecond)
+			require.Equal(t, 500*time.Millisecond, controller.BlockRateDelay())
+			require.Equal(t, 1.5, controller.Config().TimeoutIncrease)
+
+			// both updates are persisted and re-applicable after a restart
+			persisted, err := persist.GetTimeoutConfigUpdate()
+			require.NoError(t, err)
+			restored, err := persisted.Apply(timeout.DefaultConfig)
+			require.NoError(t, err)
+			require.Equal(t, controller.Config().MinReplicaTimeout, restored.MinReplicaTimeout)
+			require.Equal(t, controller.Config().BlockRateDelayMS, restored.BlockRateDelayMS)
+			require.Equal(t, controller.Config().TimeoutIncrease, restored.TimeoutIncrease)
+		})
+	})
+}
@@ -0,0 +1,45 @@
+package execution
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
+)
+
+var _ commands.AdminCommand = (*ExecutionStatusCommand)(nil)
+
+// ExecutionStatusCommand returns the highest executed block, the stop height and whether execution is paused.
+type ExecutionStatusCommand struct {
+	stopControl *stop.StopControl
+	db          *badger.DB
+}
+
+func (e *ExecutionStatusCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	executedHeight, executedBlockID, err := highestExecutedBlock(e.db)
+	if err != nil {
+		return nil, fmt.Errorf("could not get highest executed block: %w", err)
+	}
+
+	return map[string]interface{}{
+		"executed_height":   executedHeight,
+		"executed_block_id": executedBlockID.String(),
+		"stop_height":       e.stopControl.StopHeight(),
+		"paused":            e.stopControl.IsPaused(),
+	}, nil
+}
+
+func (e *ExecutionStatusCommand) Validator(req *admin.CommandRequest) error {
+	return nil
+}
+
+func NewExecutionStatusCommand(stopControl *stop.StopControl, db *badger.DB) commands.AdminCommand {
+	return &ExecutionStatusCommand{
+		stopControl: stopControl,
+		db:          db,
+	}
+}
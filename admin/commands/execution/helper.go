@@ -0,0 +1,31 @@
+package execution
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// highestExecutedBlock returns the height and ID of the highest executed block
+func highestExecutedBlock(db *badger.DB) (uint64, flow.Identifier, error) {
+	var blockID flow.Identifier
+	var header flow.Header
+	err := db.View(func(tx *badger.Txn) error {
+		err := operation.RetrieveExecutedBlock(&blockID)(tx)
+		if err != nil {
+			return fmt.Errorf("could not lookup executed block: %w", err)
+		}
+		err = operation.RetrieveHeader(blockID, &header)(tx)
+		if err != nil {
+			return fmt.Errorf("could not retrieve executed header: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, flow.ZeroID, err
+	}
+	return header.Height, blockID, nil
+}
@@ -0,0 +1,59 @@
+package execution
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
+)
+
+var _ commands.AdminCommand = (*PauseExecutionCommand)(nil)
+var _ commands.AdminCommand = (*ResumeExecutionCommand)(nil)
+
+// PauseExecutionCommand pauses the execution of blocks. Blocks keep being received and
+// their collections fetched, so execution can continue right away once resumed.
+type PauseExecutionCommand struct {
+	stopControl *stop.StopControl
+}
+
+func (p *PauseExecutionCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	err := p.stopControl.Pause()
+	if err != nil {
+		return nil, err
+	}
+	return "ok", nil
+}
+
+func (p *PauseExecutionCommand) Validator(req *admin.CommandRequest) error {
+	return nil
+}
+
+func NewPauseExecutionCommand(stopControl *stop.StopControl) commands.AdminCommand {
+	return &PauseExecutionCommand{
+		stopControl: stopControl,
+	}
+}
+
+// ResumeExecutionCommand resumes the execution of blocks after it was paused.
+type ResumeExecutionCommand struct {
+	stopControl *stop.StopControl
+}
+
+func (r *ResumeExecutionCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	err := r.stopControl.Resume()
+	if err != nil {
+		return nil, err
+	}
+	return "ok", nil
+}
+
+func (r *ResumeExecutionCommand) Validator(req *admin.CommandRequest) error {
+	return nil
+}
+
+func NewResumeExecutionCommand(stopControl *stop.StopControl) commands.AdminCommand {
+	return &ResumeExecutionCommand{
+		stopControl: stopControl,
+	}
+}
@@ -0,0 +1,34 @@
+package execution
+
+import (
+	"context"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/pruner"
+)
+
+var _ commands.AdminCommand = (*PruneChunkDataPacksCommand)(nil)
+
+// PruneChunkDataPacksCommand runs the chunk data pack pruner immediately, using the configured retention policy.
+type PruneChunkDataPacksCommand struct {
+	pruner *pruner.Pruner
+}
+
+func (p *PruneChunkDataPacksCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	result, err := p.pruner.Prune()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (p *PruneChunkDataPacksCommand) Validator(req *admin.CommandRequest) error {
+	return nil
+}
+
+func NewPruneChunkDataPacksCommand(pruner *pruner.Pruner) commands.AdminCommand {
+	return &PruneChunkDataPacksCommand{
+		pruner: pruner,
+	}
+}
@@ -0,0 +1,68 @@
+package execution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
+)
+
+var _ commands.AdminCommand = (*SetStopHeightCommand)(nil)
+
+// SetStopHeightCommand sets the height after which the execution node stops executing blocks
+// and shuts down. A height of 0 removes the stop height.
+type SetStopHeightCommand struct {
+	stopControl *stop.StopControl
+	db          *badger.DB
+}
+
+func (s *SetStopHeightCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	height := req.ValidatorData.(uint64)
+
+	executedHeight, _, err := highestExecutedBlock(s.db)
+	if err != nil {
+		return nil, fmt.Errorf("could not get highest executed block: %w", err)
+	}
+
+	err = s.stopControl.SetStopHeight(height, executedHeight)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"stop_height":     height,
+		"executed_height": executedHeight,
+	}, nil
+}
+
+func (s *SetStopHeightCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return errors.New("wrong input format: expected JSON")
+	}
+
+	value, ok := input["height"]
+	if !ok {
+		return errors.New("the \"height\" field is required")
+	}
+	height, ok := value.(float64)
+	if !ok || height < 0 || math.Trunc(height) != height {
+		return fmt.Errorf("invalid value for \"height\": expected a non-negative integer, but got: %v", value)
+	}
+
+	req.ValidatorData = uint64(height)
+	return nil
+}
+
+func NewSetStopHeightCommand(stopControl *stop.StopControl, db *badger.DB) commands.AdminCommand {
+	return &SetStopHeightCommand{
+		stopControl: stopControl,
+		db:          db,
+	}
+}
@@ -0,0 +1,60 @@
+package execution
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestSetStopHeight(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		executed := unittest.BlockHeaderFixture()
+		executed.Height = 100
+		require.NoError(t, db.Update(operation.InsertHeader(executed.ID(), &executed)))
+		require.NoError(t, db.Update(operation.InsertExecutedBlock(executed.ID())))
+
+		stopControl, err := stop.NewStopControl(unittest.Logger(), db, nil)
+		require.NoError(t, err)
+
+		command := NewSetStopHeightCommand(stopControl, db)
+
+		newRequest := func(data string) *admin.CommandRequest {
+			req := &admin.CommandRequest{}
+			require.NoError(t, json.Unmarshal([]byte(data), &req.Data))
+			return req
+		}
+
+		t.Run("invalid input", func(t *testing.T) {
+			for _, data := range []string{`"120"`, `{}`, `{"height": -1}`, `{"height": 1.5}`, `{"height": "120"}`} {
+				require.Error(t, command.Validator(newRequest(data)), data)
+			}
+		})
+
+		t.Run("stop height below executed height", func(t *testing.T) {
+			req := newRequest(`{"height": 90}`)
+			require.NoError(t, command.Validator(req))
+
+			_, err := command.Handler(context.Background(), req)
+			require.Error(t, err)
+			require.Equal(t, uint64(0), stopControl.StopHeight())
+		})
+
+		t.Run("valid stop height", func(t *testing.T) {
+			req := newRequest(`{"height": 120}`)
+			require.NoError(t, command.Validator(req))
+
+			_, err := command.Handler(context.Background(), req)
+			require.NoError(t, err)
+			require.Equal(t, uint64(120), stopControl.StopHeight())
+			require.False(t, stopControl.ShouldExecute(121))
+		})
+	})
+}
@@ -0,0 +1,159 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/scoring"
+)
+
+var _ commands.AdminCommand = (*ReadPeerScoresCommand)(nil)
+var _ commands.AdminCommand = (*ResetPeerScoreCommand)(nil)
+
+var errScoringDisabled = errors.New("peer scoring is not enabled")
+
+type peerScore struct {
+	PeerID       string            `json:"peer_id"`
+	NodeID       string            `json:"node_id,omitempty"` // empty if the peer is not a known node
+	Score        float64           `json:"score"`
+	Blocked      bool              `json:"blocked"`
+	BlockedUntil *time.Time        `json:"blocked_until,omitempty"`
+	Misbehaviors map[string]uint64 `json:"misbehaviors"`
+}
+
+type peerScores struct {
+	BlockThreshold float64     `json:"block_threshold"`
+	Peers          []peerScore `json:"peers"`
+}
+
+// ReadPeerScoresCommand returns the scores of all peers with recently reported misbehaviors, ordered
+// from lowest to highest score, together with the score at which peers are blocked.
+type ReadPeerScoresCommand struct {
+	registry     *scoring.Registry
+	idTranslator p2p.IDTranslator
+}
+
+func (r *ReadPeerScoresCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if r.registry == nil {
+		return nil, errScoringDisabled
+	}
+
+	now := time.Now()
+	result := peerScores{
+		BlockThreshold: r.registry.Config().BlockThreshold,
+		Peers:          []peerScore{},
+	}
+	for _, score := range r.registry.Peers() {
+		entry := peerScore{
+			PeerID:       score.PeerID.String(),
+			Score:        score.Score,
+			Blocked:      score.Blocked(now),
+			Misbehaviors: make(map[string]uint64, len(score.Misbehaviors)),
+		}
+		if !score.BlockedUntil.IsZero() {
+			blockedUntil := score.BlockedUntil
+			entry.BlockedUntil = &blockedUntil
+		}
+		if nodeID, err := r.idTranslator.GetFlowID(score.PeerID); err == nil {
+			entry.NodeID = nodeID.String()
+		}
+		for misbehavior, count := range score.Misbehaviors {
+			entry.Misbehaviors[misbehavior.String()] = count
+		}
+		result.Peers = append(result.Peers, entry)
+	}
+
+	return commands.ConvertToMap(result)
+}
+
+func (r *ReadPeerScoresCommand) Validator(req *admin.CommandRequest) error {
+	return nil
+}
+
+// NewReadPeerScoresCommand creates a command returning the peer scores of the given registry, which is
+// nil if peer scoring is not enabled on the node.
+func NewReadPeerScoresCommand(registry *scoring.Registry, idTranslator p2p.IDTranslator) commands.AdminCommand {
+	return &ReadPeerScoresCommand{
+		registry:     registry,
+		idTranslator: idTranslator,
+	}
+}
+
+// ResetPeerScoreCommand forgets all misbehaviors of a peer and unblocks it. The peer is given by exactly
+// one of the fields:
+//   - "peer_id": the libp2p peer ID
+//   - "node_id": the Flow node ID (hex-encoded)
+type ResetPeerScoreCommand struct {
+	registry     *scoring.Registry
+	idTranslator p2p.IDTranslator
+}
+
+func (r *ResetPeerScoreCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if r.registry == nil {
+		return nil, errScoringDisabled
+	}
+
+	pid := req.ValidatorData.(peer.ID)
+	if !r.registry.Reset(pid) {
+		return nil, fmt.Errorf("no misbehaviors recorded for peer %s", pid)
+	}
+	return "ok", nil
+}
+
+func (r *ResetPeerScoreCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return errors.New("wrong input format: expected JSON")
+	}
+
+	peerIDValue, hasPeerID := input["peer_id"]
+	nodeIDValue, hasNodeID := input["node_id"]
+	if hasPeerID == hasNodeID {
+		return errors.New("exactly one of \"peer_id\" and \"node_id\" must be provided")
+	}
+
+	if hasPeerID {
+		s, ok := peerIDValue.(string)
+		if !ok {
+			return fmt.Errorf("invalid value for \"peer_id\": expected a string, but got: %v", peerIDValue)
+		}
+		pid, err := peer.Decode(s)
+		if err != nil {
+			return fmt.Errorf("invalid value for \"peer_id\": %w", err)
+		}
+		req.ValidatorData = pid
+		return nil
+	}
+
+	s, ok := nodeIDValue.(string)
+	if !ok {
+		return fmt.Errorf("invalid value for \"node_id\": expected a string, but got: %v", nodeIDValue)
+	}
+	nodeID, err := flow.HexStringToIdentifier(s)
+	if err != nil {
+		return fmt.Errorf("invalid value for \"node_id\": %w", err)
+	}
+	pid, err := r.idTranslator.GetPeerID(nodeID)
+	if err != nil {
+		return fmt.Errorf("could not find peer id for node %v: %w", nodeID, err)
+	}
+	req.ValidatorData = pid
+	return nil
+}
+
+// NewResetPeerScoreCommand creates a command resetting the score of a peer in the given registry, which is
+// nil if peer scoring is not enabled on the node.
+func NewResetPeerScoreCommand(registry *scoring.Registry, idTranslator p2p.IDTranslator) commands.AdminCommand {
+	return &ResetPeerScoreCommand{
+		registry:     registry,
+		idTranslator: idTranslator,
+	}
+}
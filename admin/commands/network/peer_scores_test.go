@@ -0,0 +1,130 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/test"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/model/flow"
+	flownet "github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/p2p/scoring"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// mapTranslator translates between the node and peer IDs of a fixed set of nodes.
+type mapTranslator map[flow.Identifier]peer.ID
+
+func (m mapTranslator) GetPeerID(nodeID flow.Identifier) (peer.ID, error) {
+	pid, ok := m[nodeID]
+	if !ok {
+		return "", fmt.Errorf("unknown node %v", nodeID)
+	}
+	return pid, nil
+}
+
+func (m mapTranslator) GetFlowID(pid peer.ID) (flow.Identifier, error) {
+	for nodeID, p := range m {
+		if p == pid {
+			return nodeID, nil
+		}
+	}
+	return flow.ZeroID, fmt.Errorf("unknown peer %v", pid)
+}
+
+func TestPeerScores(t *testing.T) {
+	config := scoring.DefaultConfig()
+	config.Enforce = true
+	registry, err := scoring.NewRegistry(zerolog.Nop(), config)
+	require.NoError(t, err)
+
+	nodeID := unittest.IdentifierFixture()
+	known, err := test.RandPeerID()
+	require.NoError(t, err)
+	unknown, err := test.RandPeerID()
+	require.NoError(t, err)
+	translator := mapTranslator{nodeID: known}
+
+	for i := 0; i < 5; i++ {
+		registry.Report(known, flownet.OversizedMessage)
+	}
+	registry.Report(unknown, flownet.InvalidMessage)
+
+	newRequest := func(data string) *admin.CommandRequest {
+		req := &admin.CommandRequest{}
+		require.NoError(t, json.Unmarshal([]byte(data), &req.Data))
+		return req
+	}
+	read := func() []interface{} {
+		command := NewReadPeerScoresCommand(registry, translator)
+		req := newRequest(`{}`)
+		require.NoError(t, command.Validator(req))
+		result, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		return result.(map[string]interface{})["peers"].([]interface{})
+	}
+
+	t.Run("read", func(t *testing.T) {
+		peers := read()
+		require.Len(t, peers, 2)
+
+		blocked := peers[0].(map[string]interface{})
+		assert.Equal(t, known.String(), blocked["peer_id"])
+		assert.Equal(t, nodeID.String(), blocked["node_id"])
+		assert.Equal(t, true, blocked["blocked"])
+		assert.Contains(t, blocked, "blocked_until")
+		assert.Equal(t, map[string]interface{}{"oversized_message": float64(5)}, blocked["misbehaviors"])
+
+		other := peers[1].(map[string]interface{})
+		assert.Equal(t, unknown.String(), other["peer_id"])
+		assert.NotContains(t, other, "node_id")
+		assert.Equal(t, false, other["blocked"])
+		assert.NotContains(t, other, "blocked_until")
+	})
+
+	t.Run("invalid reset input", func(t *testing.T) {
+		command := NewResetPeerScoreCommand(registry, translator)
+		for _, data := range []string{
+			`"peer"`,
+			`{}`,
+			fmt.Sprintf(`{"peer_id": %q, "node_id": %q}`, known, nodeID.String()),
+			`{"peer_id": "invalid"}`,
+			`{"node_id": 1}`,
+			fmt.Sprintf(`{"node_id": %q}`, unittest.IdentifierFixture().String()),
+		} {
+			require.Error(t, command.Validator(newRequest(data)), data)
+		}
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		command := NewResetPeerScoreCommand(registry, translator)
+
+		req := newRequest(fmt.Sprintf(`{"node_id": %q}`, nodeID.String()))
+		require.NoError(t, command.Validator(req))
+		_, err := command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		assert.False(t, registry.IsBlocked(known))
+
+		req = newRequest(fmt.Sprintf(`{"peer_id": %q}`, unknown))
+		require.NoError(t, command.Validator(req))
+		_, err = command.Handler(context.Background(), req)
+		require.NoError(t, err)
+		assert.Empty(t, read())
+
+		// resetting a peer without misbehaviors fails
+		_, err = command.Handler(context.Background(), req)
+		require.Error(t, err)
+	})
+
+	t.Run("scoring disabled", func(t *testing.T) {
+		_, err := NewReadPeerScoresCommand(nil, translator).Handler(context.Background(), newRequest(`{}`))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	flownet "github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+)
+
+var _ commands.AdminCommand = (*ReplayNetworkCaptureCommand)(nil)
+
+type replayRequest struct {
+	dir  string
+	opts []capture.ReplayerOption
+}
+
+// ReplayNetworkCaptureCommand feeds the inbound messages of a network capture into the engines of the node, through
+// its network. It is meant for reproducing the behavior of engines on a node started from the same state as the
+// node the capture was recorded on. The input fields are:
+//   - "dir": the directory of the capture files (required)
+//   - "channels": the list of channels to replay (optional, all channels if omitted)
+//   - "timing": whether to preserve the time between messages (optional, false if omitted)
+type ReplayNetworkCaptureCommand struct {
+	log     zerolog.Logger
+	overlay flownet.Overlay
+}
+
+func (r *ReplayNetworkCaptureCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if r.overlay == nil {
+		return nil, errors.New("network of the node does not support replaying messages")
+	}
+
+	data := req.ValidatorData.(*replayRequest)
+	replayed, err := capture.NewReplayer(r.log, data.opts...).ReplayToOverlay(ctx, data.dir, r.overlay)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{"replayed": replayed}, nil
+}
+
+func (r *ReplayNetworkCaptureCommand) Validator(req *admin.CommandRequest) error {
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return errors.New("wrong input format: expected JSON")
+	}
+
+	dir, ok := input["dir"].(string)
+	if !ok || dir == "" {
+		return errors.New("\"dir\" must be provided as a non-empty string")
+	}
+	data := &replayRequest{dir: dir}
+
+	if value, ok := input["channels"]; ok {
+		list, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("invalid value for \"channels\": expected a list of strings, but got: %v", value)
+		}
+		channels := make([]flownet.Channel, 0, len(list))
+		for _, item := range list {
+			channel, ok := item.(string)
+			if !ok {
+				return fmt.Errorf("invalid channel: expected a string, but got: %v", item)
+			}
+			channels = append(channels, flownet.Channel(channel))
+		}
+		data.opts = append(data.opts, capture.WithReplayedChannels(channels...))
+	}
+
+	if value, ok := input["timing"]; ok {
+		timing, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("invalid value for \"timing\": expected a bool, but got: %v", value)
+		}
+		if timing {
+			data.opts = append(data.opts, capture.WithTiming())
+		}
+	}
+
+	req.ValidatorData = data
+	return nil
+}
+
+// NewReplayNetworkCaptureCommand creates a command replaying network captures into the given overlay, which is nil
+// if the network of the node does not support it.
+func NewReplayNetworkCaptureCommand(log zerolog.Logger, overlay flownet.Overlay) commands.AdminCommand {
+	return &ReplayNetworkCaptureCommand{
+		log:     log,
+		overlay: overlay,
+	}
+}
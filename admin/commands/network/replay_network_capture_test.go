@@ -0,0 +1,75 @@
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestReplayNetworkCapture(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		recorder, err := capture.NewRecorder(zerolog.Nop(), capture.Config{Dir: dir})
+		require.NoError(t, err)
+		originID := unittest.IdentifierFixture()
+		for _, channel := range []string{engine.SyncCommittee.String(), engine.PushBlocks.String()} {
+			recorder.Capture(capture.Inbound, &message.Message{ChannelID: channel, OriginID: originID[:], Payload: []byte{1}})
+		}
+		require.NoError(t, recorder.Close())
+
+		newRequest := func(data string) *admin.CommandRequest {
+			req := &admin.CommandRequest{}
+			require.NoError(t, json.Unmarshal([]byte(data), &req.Data))
+			return req
+		}
+
+		t.Run("invalid input", func(t *testing.T) {
+			command := NewReplayNetworkCaptureCommand(zerolog.Nop(), &mocknetwork.Overlay{})
+			for _, data := range []string{
+				`"dir"`,
+				`{}`,
+				`{"dir": ""}`,
+				fmt.Sprintf(`{"dir": %q, "channels": "sync-committee"}`, dir),
+				fmt.Sprintf(`{"dir": %q, "channels": [1]}`, dir),
+				fmt.Sprintf(`{"dir": %q, "timing": "yes"}`, dir),
+			} {
+				require.Error(t, command.Validator(newRequest(data)), data)
+			}
+		})
+
+		t.Run("replay", func(t *testing.T) {
+			overlay := &mocknetwork.Overlay{}
+			overlay.On("Receive", originID, mock.MatchedBy(func(msg *message.Message) bool {
+				return msg.ChannelID == engine.SyncCommittee.String()
+			})).Return(nil).Once()
+			command := NewReplayNetworkCaptureCommand(zerolog.Nop(), overlay)
+
+			req := newRequest(fmt.Sprintf(`{"dir": %q, "channels": ["sync-committee"]}`, dir))
+			require.NoError(t, command.Validator(req))
+			result, err := command.Handler(context.Background(), req)
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"replayed": 1}, result)
+			overlay.AssertExpectations(t)
+		})
+
+		t.Run("replay unsupported", func(t *testing.T) {
+			req := newRequest(fmt.Sprintf(`{"dir": %q}`, dir))
+			command := NewReplayNetworkCaptureCommand(zerolog.Nop(), nil)
+			require.NoError(t, command.Validator(req))
+			_, err := command.Handler(context.Background(), req)
+			require.Error(t, err)
+		})
+	})
+}
@@ -0,0 +1,148 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/flow/order"
+	flownet "github.com/onflow/flow-go/network"
+)
+
+var _ commands.AdminCommand = (*ReadTopologyCommand)(nil)
+
+// TopologyProvider provides the topology of a node. It is implemented by the network.
+type TopologyProvider interface {
+	// Topology returns the fanout of the node, i.e. the nodes it should be directly connected to.
+	Topology() (flow.IdentityList, error)
+
+	// SubscribedChannels returns the channels the node is subscribed to.
+	SubscribedChannels() flownet.ChannelList
+
+	// Identity returns the identity of the node with the given peer ID, if it is known.
+	Identity(peer.ID) (*flow.Identity, bool)
+}
+
+// ConnectionProvider provides the peers a node is connected to. It is implemented by the middleware.
+type ConnectionProvider interface {
+	ConnectedPeers() peer.IDSlice
+}
+
+type topologyNode struct {
+	NodeID    string `json:"node_id"`
+	Role      string `json:"role"`
+	Address   string `json:"address"`
+	Connected bool   `json:"connected"`
+}
+
+type channelTopology struct {
+	Channel   string   `json:"channel"`
+	Fanout    []string `json:"fanout"`    // IDs of the nodes in the fanout which subscribe to the channel
+	Connected int      `json:"connected"` // number of nodes in the channel fanout this node is connected to
+}
+
+type topologyConnection struct {
+	PeerID   string `json:"peer_id"`
+	NodeID   string `json:"node_id,omitempty"` // empty if the peer is not a known node
+	Role     string `json:"role,omitempty"`
+	InFanout bool   `json:"in_fanout"`
+}
+
+type topology struct {
+	Fanout      []topologyNode       `json:"fanout"`
+	Channels    []channelTopology    `json:"channels"`
+	Connections []topologyConnection `json:"connections"`
+}
+
+// ReadTopologyCommand returns the topology of the node: its fanout, the part of the fanout subscribing to each of
+// the channels of the node, and the peers the node is actually connected to.
+type ReadTopologyCommand struct {
+	topology    TopologyProvider
+	connections ConnectionProvider
+}
+
+func (r *ReadTopologyCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if r.topology == nil || r.connections == nil {
+		return nil, errors.New("network of the node does not provide its topology")
+	}
+
+	fanout, err := r.topology.Topology()
+	if err != nil {
+		return nil, err
+	}
+	fanout = fanout.Sort(order.Canonical)
+
+	connected := make(map[flow.Identifier]bool)
+	result := topology{
+		Fanout:      make([]topologyNode, 0, len(fanout)),
+		Channels:    []channelTopology{},
+		Connections: []topologyConnection{},
+	}
+	for _, pid := range r.connections.ConnectedPeers() {
+		connection := topologyConnection{PeerID: pid.String()}
+		if identity, ok := r.topology.Identity(pid); ok {
+			connected[identity.NodeID] = true
+			connection.NodeID = identity.NodeID.String()
+			connection.Role = identity.Role.String()
+			_, connection.InFanout = fanout.ByNodeID(identity.NodeID)
+		}
+		result.Connections = append(result.Connections, connection)
+	}
+	sort.Slice(result.Connections, func(i, j int) bool {
+		return result.Connections[i].PeerID < result.Connections[j].PeerID
+	})
+
+	for _, identity := range fanout {
+		result.Fanout = append(result.Fanout, topologyNode{
+			NodeID:    identity.NodeID.String(),
+			Role:      identity.Role.String(),
+			Address:   identity.Address,
+			Connected: connected[identity.NodeID],
+		})
+	}
+
+	channels := r.topology.SubscribedChannels()
+	sort.Sort(channels)
+	for _, channel := range channels {
+		roles, ok := engine.RolesByChannel(channel)
+		if !ok {
+			continue
+		}
+		// for cluster channels, this includes the collection nodes of other clusters in the fanout, as the
+		// cluster assignment is not known to the network
+		channelFanout := fanout.Filter(filter.HasRole(roles...))
+		entry := channelTopology{
+			Channel: channel.String(),
+			Fanout:  make([]string, 0, len(channelFanout)),
+		}
+		for _, identity := range channelFanout {
+			entry.Fanout = append(entry.Fanout, identity.NodeID.String())
+			if connected[identity.NodeID] {
+				entry.Connected++
+			}
+		}
+		result.Channels = append(result.Channels, entry)
+	}
+
+	return commands.ConvertToMap(result)
+}
+
+func (r *ReadTopologyCommand) Validator(req *admin.CommandRequest) error {
+	return nil
+}
+
+// NewReadTopologyCommand creates a command returning the topology of the given network and the connections of the
+// given middleware, which are nil if the network of the node does not provide them.
+func NewReadTopologyCommand(topology TopologyProvider, connections ConnectionProvider) commands.AdminCommand {
+	return &ReadTopologyCommand{
+		topology:    topology,
+		connections: connections,
+	}
+}
@@ -0,0 +1,117 @@
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	flownet "github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// fixedTopology is a topology provider and connection provider with a fixed fanout and connections.
+type fixedTopology struct {
+	fanout     flow.IdentityList
+	channels   flownet.ChannelList
+	identities map[peer.ID]*flow.Identity
+	connected  peer.IDSlice
+}
+
+func (f *fixedTopology) Topology() (flow.IdentityList, error) {
+	return f.fanout, nil
+}
+
+func (f *fixedTopology) SubscribedChannels() flownet.ChannelList {
+	return f.channels
+}
+
+func (f *fixedTopology) Identity(pid peer.ID) (*flow.Identity, bool) {
+	identity, ok := f.identities[pid]
+	return identity, ok
+}
+
+func (f *fixedTopology) ConnectedPeers() peer.IDSlice {
+	return f.connected
+}
+
+func TestReadTopology(t *testing.T) {
+	consensus := unittest.IdentityFixture(unittest.WithRole(flow.RoleConsensus))
+	execution := unittest.IdentityFixture(unittest.WithRole(flow.RoleExecution))
+	verification := unittest.IdentityFixture(unittest.WithRole(flow.RoleVerification))
+
+	peers := make([]peer.ID, 4)
+	for i := range peers {
+		pid, err := test.RandPeerID()
+		require.NoError(t, err)
+		peers[i] = pid
+	}
+	unknown := peers[3]
+
+	top := &fixedTopology{
+		fanout:   flow.IdentityList{consensus, execution, verification},
+		channels: flownet.ChannelList{engine.ReceiveReceipts, engine.ConsensusCommittee},
+		identities: map[peer.ID]*flow.Identity{
+			peers[0]: consensus,
+			peers[1]: execution,
+			peers[2]: verification,
+		},
+		// connected to the consensus and execution node of the fanout, and an unknown peer
+		connected: peer.IDSlice{peers[0], peers[1], unknown},
+	}
+
+	command := NewReadTopologyCommand(top, top)
+	req := &admin.CommandRequest{}
+	require.NoError(t, command.Validator(req))
+	result, err := command.Handler(context.Background(), req)
+	require.NoError(t, err)
+	topology := result.(map[string]interface{})
+
+	connected := make(map[string]bool)
+	fanout := topology["fanout"].([]interface{})
+	require.Len(t, fanout, 3)
+	for _, node := range fanout {
+		node := node.(map[string]interface{})
+		connected[node["node_id"].(string)] = node["connected"].(bool)
+	}
+	assert.Equal(t, map[string]bool{
+		consensus.NodeID.String():    true,
+		execution.NodeID.String():    true,
+		verification.NodeID.String(): false,
+	}, connected)
+
+	channels := make(map[string]map[string]interface{})
+	for _, channel := range topology["channels"].([]interface{}) {
+		channel := channel.(map[string]interface{})
+		channels[channel["channel"].(string)] = channel
+	}
+	require.Len(t, channels, 2)
+	// receipts are exchanged among consensus, execution and verification nodes
+	assert.Len(t, channels[engine.ReceiveReceipts.String()]["fanout"], 3)
+	assert.Equal(t, float64(2), channels[engine.ReceiveReceipts.String()]["connected"])
+	assert.Equal(t, []interface{}{consensus.NodeID.String()}, channels[engine.ConsensusCommittee.String()]["fanout"])
+	assert.Equal(t, float64(1), channels[engine.ConsensusCommittee.String()]["connected"])
+
+	connections := topology["connections"].([]interface{})
+	require.Len(t, connections, 3)
+	for _, connection := range connections {
+		connection := connection.(map[string]interface{})
+		if connection["peer_id"] == unknown.String() {
+			assert.NotContains(t, connection, "node_id")
+			assert.Equal(t, false, connection["in_fanout"])
+			continue
+		}
+		assert.Equal(t, true, connection["in_fanout"])
+	}
+
+	t.Run("topology not available", func(t *testing.T) {
+		_, err := NewReadTopologyCommand(nil, nil).Handler(context.Background(), req)
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+var _ commands.AdminCommand = (*ReadSlashingEvidenceCommand)(nil)
+
+// ReadSlashingEvidenceCommand returns the slashing evidence stored by the node.
+// If an "id" is given, only the evidence with this ID is returned, otherwise all evidence is returned.
+type ReadSlashingEvidenceCommand struct {
+	evidence storage.SlashingEvidence
+}
+
+func (r *ReadSlashingEvidenceCommand) Handler(ctx context.Context, req *admin.CommandRequest) (interface{}, error) {
+	if evidenceID, ok := req.ValidatorData.(flow.Identifier); ok {
+		evidence, err := r.evidence.ByID(evidenceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get slashing evidence by ID: %w", err)
+		}
+		return commands.ConvertToInterfaceList([]*flow.SlashingEvidence{evidence})
+	}
+
+	evidence, err := r.evidence.All()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get slashing evidence: %w", err)
+	}
+	return commands.ConvertToInterfaceList(evidence)
+}
+
+func (r *ReadSlashingEvidenceCommand) Validator(req *admin.CommandRequest) error {
+	if req.Data == nil {
+		return nil
+	}
+
+	input, ok := req.Data.(map[string]interface{})
+	if !ok {
+		return ErrValidatorReqDataFormat
+	}
+
+	id, ok := input["id"]
+	if !ok {
+		return nil
+	}
+
+	errInvalidIDValue := fmt.Errorf("invalid value for \"id\": expected an evidence ID represented as a 64 character long hex string, but got: %v", id)
+	idStr, ok := id.(string)
+	if !ok {
+		return errInvalidIDValue
+	}
+	evidenceID, err := flow.HexStringToIdentifier(idStr)
+	if err != nil {
+		return errInvalidIDValue
+	}
+	req.ValidatorData = evidenceID
+
+	return nil
+}
+
+func NewReadSlashingEvidenceCommand(evidence storage.SlashingEvidence) commands.AdminCommand {
+	return &ReadSlashingEvidenceCommand{
+		evidence,
+	}
+}
@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/admin"
+	"github.com/onflow/flow-go/admin/commands"
+	"github.com/onflow/flow-go/model/flow"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestReadSlashingEvidence(t *testing.T) {
+	t.Parallel()
+
+	evidence1 := unittest.SlashingEvidenceFixture()
+	evidence2 := unittest.SlashingEvidenceFixture()
+
+	store := new(storagemock.SlashingEvidence)
+	store.On("All").Return([]*flow.SlashingEvidence{evidence1, evidence2}, nil)
+	store.On("ByID", evidence2.ID()).Return(evidence2, nil)
+
+	command := NewReadSlashingEvidenceCommand(store)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("all evidence", func(t *testing.T) {
+		req := &admin.CommandRequest{}
+		require.NoError(t, command.Validator(req))
+		result, err := command.Handler(ctx, req)
+		require.NoError(t, err)
+
+		expected, err := commands.ConvertToInterfaceList([]*flow.SlashingEvidence{evidence1, evidence2})
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("evidence by ID", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"id": evidence2.ID().String(),
+			},
+		}
+		require.NoError(t, command.Validator(req))
+		result, err := command.Handler(ctx, req)
+		require.NoError(t, err)
+
+		expected, err := commands.ConvertToInterfaceList([]*flow.SlashingEvidence{evidence2})
+		require.NoError(t, err)
+		require.Equal(t, expected, result)
+	})
+
+	t.Run("invalid ID", func(t *testing.T) {
+		req := &admin.CommandRequest{
+			Data: map[string]interface{}{
+				"id": "not an ID",
+			},
+		}
+		require.Error(t, command.Validator(req))
+	})
+}
@@ -41,6 +41,7 @@ import (
 	"github.com/onflow/flow-go/network"
 	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
 	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/peerexchange"
 	"github.com/onflow/flow-go/network/validator"
 	"github.com/onflow/flow-go/state/protocol"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
@@ -80,6 +81,8 @@ type AccessNodeConfig struct {
 	bootstrapNodePublicKeys      []string
 	observerNetworkingKeyPath    string
 	bootstrapIdentities          flow.IdentityList // the identity list of bootstrap peers the node uses to discover other nodes
+	peerExchangeEnabled          bool              // True if an unstaked access node discovers other peers by peer exchange
+	peerExchange                 peerexchange.Config
 	NetworkKey                   crypto.PrivateKey // the networking key passed in by the caller when being used as a library
 	supportsUnstakedFollower     bool              // True if this is a staked Access node which also supports unstaked access nodes/unstaked consensus follower engines
 	collectionGRPCPort           uint
@@ -140,6 +143,8 @@ func DefaultAccessNodeConfig() *AccessNodeConfig {
 		bootstrapNodeAddresses:       []string{},
 		bootstrapNodePublicKeys:      []string{},
 		supportsUnstakedFollower:     false,
+		peerExchangeEnabled:          true,
+		peerExchange:                 peerexchange.DefaultConfig(),
 		PublicNetworkConfig: PublicNetworkConfig{
 			BindAddress: cmd.NotSet,
 			Metrics:     metrics.NewNoopCollector(),
@@ -454,12 +459,22 @@ func (builder *FlowAccessNodeBuilder) extraFlags() {
 		flags.StringVar(&builder.observerNetworkingKeyPath, "observer-networking-key-path", defaultConfig.observerNetworkingKeyPath, "path to the networking key for observer")
 		flags.StringSliceVar(&builder.bootstrapNodeAddresses, "bootstrap-node-addresses", defaultConfig.bootstrapNodeAddresses, "the network addresses of the bootstrap access node if this is an unstaked access node e.g. access-001.mainnet.flow.org:9653,access-002.mainnet.flow.org:9653")
 		flags.StringSliceVar(&builder.bootstrapNodePublicKeys, "bootstrap-node-public-keys", defaultConfig.bootstrapNodePublicKeys, "the networking public key of the bootstrap access node if this is an unstaked access node (in the same order as the bootstrap node addresses) e.g. \"d57a5e9c5.....\",\"44ded42d....\"")
+		flags.BoolVar(&builder.peerExchangeEnabled, "peer-exchange-enabled", defaultConfig.peerExchangeEnabled, "whether an unstaked access node discovers other peers by exchanging the peers it knows with them, in addition to the bootstrap nodes")
+		flags.DurationVar(&builder.peerExchange.Interval, "peer-exchange-interval", defaultConfig.peerExchange.Interval, "time between two rounds of peer exchange")
+		flags.IntVar(&builder.peerExchange.Fanout, "peer-exchange-fanout", defaultConfig.peerExchange.Fanout, "number of connected peers queried for their peers in each round of peer exchange")
+		flags.IntVar(&builder.peerExchange.MinPeers, "peer-exchange-min-peers", defaultConfig.peerExchange.MinPeers, "number of connected peers below which an unstaked access node connects to the known peers of highest quality")
+		flags.IntVar(&builder.peerExchange.MaxPeers, "peer-exchange-max-peers", defaultConfig.peerExchange.MaxPeers, "maximum number of peers kept in the persisted peer store")
 		flags.BoolVar(&builder.supportsUnstakedFollower, "supports-unstaked-node", defaultConfig.supportsUnstakedFollower, "true if this staked access node supports unstaked node")
 		flags.StringVar(&builder.PublicNetworkConfig.BindAddress, "public-network-address", defaultConfig.PublicNetworkConfig.BindAddress, "staked access node's public network bind address")
 	}).ValidateFlags(func() error {
 		if builder.supportsUnstakedFollower && (builder.PublicNetworkConfig.BindAddress == cmd.NotSet || builder.PublicNetworkConfig.BindAddress == "") {
 			return errors.New("public-network-address must be set if supports-unstaked-node is true")
 		}
+		if !builder.staked && builder.peerExchangeEnabled {
+			if err := builder.peerExchange.Validate(); err != nil {
+				return fmt.Errorf("invalid peer exchange flags: %w", err)
+			}
+		}
 
 		return nil
 	})
@@ -14,6 +14,7 @@ import (
 	"github.com/onflow/flow/protobuf/go/flow/access"
 
 	"github.com/onflow/flow-go/cmd"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
 	"github.com/onflow/flow-go/crypto"
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/access/ingestion"
@@ -213,6 +214,7 @@ func (builder *StakedAccessNodeBuilder) Build() (cmd.Node, error) {
 				node.Storage.Transactions,
 				node.Storage.Receipts,
 				node.Storage.Results,
+				node.Storage.SlashingEvidence,
 				node.RootChainID,
 				builder.TransactionMetrics,
 				builder.collectionGRPCPort,
@@ -248,6 +250,7 @@ func (builder *StakedAccessNodeBuilder) Build() (cmd.Node, error) {
 			}
 			builder.RequestEng.WithHandle(builder.IngestEng.OnCollection)
 			builder.FinalizationDistributor.AddConsumer(builder.IngestEng)
+			builder.FinalizationDistributor.AddConsumer(notifications.NewSlashingEvidenceConsumer(node.Logger, node.Storage.SlashingEvidence, node.Storage.Headers))
 
 			return builder.IngestEng, nil
 		}).
@@ -24,14 +24,17 @@ import (
 	"github.com/onflow/flow-go/network/converter"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/keyutils"
+	"github.com/onflow/flow-go/network/p2p/peerexchange"
 	"github.com/onflow/flow-go/network/p2p/unicast"
 	"github.com/onflow/flow-go/state/protocol/events/gadgets"
+	storage "github.com/onflow/flow-go/storage/badger"
 	"github.com/onflow/flow-go/utils/io"
 )
 
 type UnstakedAccessNodeBuilder struct {
 	*FlowAccessNodeBuilder
-	peerID peer.ID
+	peerID    peer.ID
+	peerStore *peerexchange.PeerStore // the peers discovered by peer exchange, nil if peer exchange is disabled
 }
 
 func NewUnstakedAccessNodeBuilder(builder *FlowAccessNodeBuilder) *UnstakedAccessNodeBuilder {
@@ -129,12 +132,20 @@ func (builder *UnstakedAccessNodeBuilder) Initialize() error {
 
 	builder.InitIDProviders()
 
+	if builder.peerExchangeEnabled {
+		builder.enqueuePeerStore()
+	}
+
 	builder.enqueueMiddleware()
 
 	builder.enqueueUnstakedNetworkInit()
 
 	builder.enqueueConnectWithStakedAN()
 
+	if builder.peerExchangeEnabled {
+		builder.enqueuePeerExchange()
+	}
+
 	if builder.BaseConfig.MetricsEnabled {
 		builder.EnqueueMetricsServerInit()
 		if err := builder.RegisterBadgerMetrics(); err != nil {
@@ -169,7 +180,7 @@ func (builder *UnstakedAccessNodeBuilder) validateParams() error {
 // initLibP2PFactory creates the LibP2P factory function for the given node ID and network key for the unstaked node.
 // The factory function is later passed into the initMiddleware function to eventually instantiate the p2p.LibP2PNode instance
 // The LibP2P host is created with the following options:
-// 		DHT as client and seeded with the given bootstrap peers
+// 		DHT as client and seeded with the given bootstrap peers, and the healthy peers known from peer exchange
 // 		The specified bind address as the listen address
 // 		The passed in private key as the libp2p key
 //		No connection gater
@@ -189,6 +200,11 @@ func (builder *UnstakedAccessNodeBuilder) initLibP2PFactory(networkKey crypto.Pr
 			pis = append(pis, pi)
 		}
 
+		// the healthy peers discovered before a restart complement the bootstrap access nodes
+		if builder.peerStore != nil {
+			pis = append(pis, builder.peerStore.Healthy(builder.peerExchange.MaxResponsePeers)...)
+		}
+
 		node, err := p2p.NewNodeBuilder(builder.Logger, builder.BaseConfig.BindAddr, networkKey, builder.SporkID).
 			SetSubscriptionFilter(
 				p2p.NewRoleBasedFilter(
@@ -299,6 +315,34 @@ func (builder *UnstakedAccessNodeBuilder) enqueueConnectWithStakedAN() {
 	})
 }
 
+// enqueuePeerStore enqueues the creation of the peer store, which holds the peers discovered by peer exchange before
+// the node was restarted. This needs to be done before the middleware, as the healthy known peers are used as
+// bootstrap peers of the DHT in addition to the bootstrap access nodes.
+func (builder *UnstakedAccessNodeBuilder) enqueuePeerStore() {
+	builder.Module("peer store", func(node *cmd.NodeConfig) error {
+		store, err := peerexchange.NewPeerStore(node.Logger, storage.NewPeerRecords(node.DB), builder.IDTranslator, builder.peerExchange.MaxPeers)
+		if err != nil {
+			return fmt.Errorf("could not create peer store: %w", err)
+		}
+		builder.peerStore = store
+		return nil
+	})
+}
+
+// enqueuePeerExchange enqueues the peer exchange component, which discovers the peers of the unstaked network by
+// exchanging known peers with the connected peers. The discovered peers are persisted with their quality score in the
+// peer store, so that the node connects to them in addition to the bootstrap access nodes after a restart.
+func (builder *UnstakedAccessNodeBuilder) enqueuePeerExchange() {
+	builder.Component("peer exchange", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+		exchange, err := peerexchange.NewPeerExchange(node.Logger, builder.LibP2PNode, builder.SporkID, builder.peerStore, builder.peerExchange)
+		if err != nil {
+			return nil, fmt.Errorf("could not create peer exchange: %w", err)
+		}
+
+		return exchange, nil
+	})
+}
+
 // initMiddleware creates the network.Middleware implementation with the libp2p factory function, metrics, peer update
 // interval, and validators. The network.Middleware is then passed into the initNetwork function.
 func (builder *UnstakedAccessNodeBuilder) initMiddleware(nodeID flow.Identifier,
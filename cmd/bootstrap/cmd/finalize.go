@@ -40,6 +40,9 @@ var (
 	flagRootBlockVotesDir           string
 	flagRootCommit                  string
 	flagProtocolVersion             uint
+	flagLivenessWeighting           bool
+	flagLivenessMinWeight           uint64
+	flagLivenessToleratedMiss       uint64
 	flagServiceAccountPublicKeyJSON string
 	flagGenesisTokenSupply          string
 	flagEpochCounter                uint64
@@ -100,6 +103,12 @@ func addFinalizeCmdFlags() {
 	finalizeCmd.Flags().Uint64Var(&flagNumViewsInDKGPhase, "epoch-dkg-phase-length", 1000, "length of each DKG phase measured in views")
 	finalizeCmd.Flags().BytesHexVar(&flagBootstrapRandomSeed, "random-seed", GenerateRandomSeed(flow.EpochSetupRandomSourceLength), "The seed used to for DKG, Clustering and Cluster QC generation")
 	finalizeCmd.Flags().UintVar(&flagProtocolVersion, "protocol-version", flow.DefaultProtocolVersion, "major software version used for the duration of this spork")
+	finalizeCmd.Flags().BoolVar(&flagLivenessWeighting, "liveness-weighted-leader-selection", false,
+		"reduce the chance of consensus nodes to be selected as leader if they missed QCs during the previous epoch's setup phase, for the duration of this spork")
+	finalizeCmd.Flags().Uint64Var(&flagLivenessMinWeight, "liveness-min-weight-permille", flow.DefaultLivenessWeighting.MinWeightPermille,
+		"lower bound of the reduced leader selection weight of consensus nodes, in permille of their weight")
+	finalizeCmd.Flags().Uint64Var(&flagLivenessToleratedMiss, "liveness-tolerated-miss-permille", flow.DefaultLivenessWeighting.ToleratedMissPermille,
+		"fraction of missed QCs which does not reduce the leader selection weight of consensus nodes, in permille")
 
 	cmd.MarkFlagRequired(finalizeCmd, "root-block")
 	cmd.MarkFlagRequired(finalizeCmd, "root-block-votes-dir")
@@ -213,7 +222,18 @@ func finalize(cmd *cobra.Command, args []string) {
 
 	// construct serializable root protocol snapshot
 	log.Info().Msg("constructing root protocol snapshot")
-	snapshot, err := inmem.SnapshotFromBootstrapStateWithProtocolVersion(block, result, seal, rootQC, flagProtocolVersion)
+	var livenessWeighting *flow.LivenessWeighting
+	if flagLivenessWeighting {
+		livenessWeighting = &flow.LivenessWeighting{
+			MinWeightPermille:     flagLivenessMinWeight,
+			ToleratedMissPermille: flagLivenessToleratedMiss,
+		}
+		err := livenessWeighting.Validate()
+		if err != nil {
+			log.Fatal().Err(err).Msg("invalid liveness weighting")
+		}
+	}
+	snapshot, err := inmem.SnapshotFromBootstrapStateWithParams(block, result, seal, rootQC, flagProtocolVersion, livenessWeighting)
 	if err != nil {
 		log.Fatal().Err(err).Msg("unable to generate root protocol snapshot")
 	}
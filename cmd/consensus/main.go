@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -15,12 +16,15 @@ import (
 	"github.com/onflow/flow-go-sdk/client"
 	"github.com/onflow/flow-go-sdk/crypto"
 
+	"github.com/onflow/flow-go/admin/commands"
+	consensusCommands "github.com/onflow/flow-go/admin/commands/consensus"
 	"github.com/onflow/flow-go/cmd"
 	"github.com/onflow/flow-go/cmd/util/cmd/common"
 	"github.com/onflow/flow-go/consensus"
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/blockproducer"
 	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
 	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
 	"github.com/onflow/flow-go/consensus/hotstuff/persister"
@@ -90,6 +94,7 @@ func main() {
 		dkgControllerConfig                    dkgmodule.ControllerConfig
 		startupTimeString                      string
 		startupTime                            time.Time
+		timelineViews                          uint
 
 		// DKG contract client
 		machineAccountInfo *bootstrap.NodeMachineAccountInfo
@@ -119,6 +124,9 @@ func main() {
 		hotstuffModules         *consensus.HotstuffModules
 		dkgState                *bstorage.DKGState
 		safeBeaconKeys          *bstorage.SafeBeaconPrivateKeys
+		timeoutController       atomic.Value // *timeout.Controller, read by the admin server once the controller is created
+		timelineRecorder        *notifications.TimelineRecorder
+		sealingEngine           atomic.Value // *sealing.Engine, read by the admin server once the engine is created
 	)
 
 	nodeBuilder := cmd.FlowNode(flow.RoleConsensus.String())
@@ -149,6 +157,7 @@ func main() {
 		flags.DurationVar(&dkgControllerConfig.BaseStartDelay, "dkg-controller-base-start-delay", dkgmodule.DefaultBaseStartDelay, "used to define the range for jitter prior to DKG start (eg. 500µs) - the base value is scaled quadratically with the # of DKG participants")
 		flags.DurationVar(&dkgControllerConfig.BaseHandleFirstBroadcastDelay, "dkg-controller-base-handle-first-broadcast-delay", dkgmodule.DefaultBaseHandleFirstBroadcastDelay, "used to define the range for jitter prior to DKG handling the first broadcast messages (eg. 50ms) - the base value is scaled quadratically with the # of DKG participants")
 		flags.DurationVar(&dkgControllerConfig.HandleSubsequentBroadcastDelay, "dkg-controller-handle-subsequent-broadcast-delay", dkgmodule.DefaultHandleSubsequentBroadcastDelay, "used to define the constant delay introduced prior to DKG handling subsequent broadcast messages (eg. 2s)")
+		flags.UintVar(&timelineViews, "hotstuff-timeline-views", 1000, "number of most recent views for which a timeline of hotstuff events is kept for inspection through the admin API")
 		flags.StringVar(&startupTimeString, "hotstuff-startup-time", cmd.NotSet, "specifies date and time (in ISO 8601 format) after which the consensus participant may enter the first view (e.g 1996-04-24T15:04:05-07:00)")
 	}).ValidateFlags(func() error {
 		nodeBuilder.Logger.Info().Str("startup_time_str", startupTimeString).Msg("got startup_time_str")
@@ -167,12 +176,43 @@ func main() {
 		nodeBuilder.Logger.Fatal().Err(err).Send()
 	}
 
+	loadTimeoutController := func() *timeout.Controller {
+		controller, _ := timeoutController.Load().(*timeout.Controller)
+		return controller
+	}
+
 	nodeBuilder.
+		AdminCommand("get-hotstuff-timeout-config", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return consensusCommands.NewGetTimeoutConfigCommand(loadTimeoutController)
+		}).
+		AdminCommand("set-hotstuff-timeout-config", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return consensusCommands.NewSetTimeoutConfigCommand(
+				loadTimeoutController,
+				persister.New(config.DB, config.RootChainID),
+				mainMetrics,
+			)
+		}).
+		AdminCommand("read-hotstuff-timeline", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return consensusCommands.NewReadTimelineCommand(timelineRecorder)
+		}).
+		AdminCommand("read-sealing-state", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return consensusCommands.NewReadSealingStateCommand(func() *sealing.Inspection {
+				e, ok := sealingEngine.Load().(*sealing.Engine)
+				if !ok {
+					return nil
+				}
+				return e.Inspect()
+			})
+		}).
 		PreInit(cmd.DynamicStartPreInit).
 		Module("consensus node metrics", func(node *cmd.NodeConfig) error {
 			conMetrics = metrics.NewConsensusCollector(node.Tracer, node.MetricsRegisterer)
 			return nil
 		}).
+		Module("hotstuff timeline recorder", func(node *cmd.NodeConfig) error {
+			timelineRecorder = notifications.NewTimelineRecorder(timelineViews)
+			return nil
+		}).
 		Module("dkg state", func(node *cmd.NodeConfig) error {
 			dkgState, err = bstorage.NewDKGState(node.Metrics.Cache, node.SecretsDB)
 			return err
@@ -410,6 +450,10 @@ func main() {
 				seals,
 				config,
 			)
+			if err != nil {
+				return nil, err
+			}
+			sealingEngine.Store(e)
 
 			// subscribe for finalization events from hotstuff
 			finalizationDistributor.AddOnBlockFinalizedConsumer(e.OnFinalizedBlock)
@@ -547,6 +591,8 @@ func main() {
 			)
 
 			notifier.AddConsumer(finalizationDistributor)
+			notifier.AddConsumer(notifications.NewSlashingEvidenceConsumer(node.Logger, node.Storage.SlashingEvidence, node.Storage.Headers))
+			notifier.AddConsumer(timelineRecorder)
 
 			// initialize the persister
 			persist := persister.New(node.DB, node.RootChainID)
@@ -630,6 +676,24 @@ func main() {
 				opts = append(opts, consensus.WithStartupTime(startupTime))
 			}
 
+			// re-apply timeout changes made at runtime through the admin commands, they take precedence
+			// over the hotstuff timeout flags
+			timeoutConfigUpdate, err := persister.New(node.DB, node.RootChainID).GetTimeoutConfigUpdate()
+			if err != nil {
+				return nil, fmt.Errorf("could not retrieve persisted timeout config: %w", err)
+			}
+			if !timeoutConfigUpdate.IsEmpty() {
+				node.Logger.Warn().
+					Interface("timeout_config_update", timeoutConfigUpdate).
+					Msg("overriding hotstuff timeout flags with the timeout config set at runtime through the admin command")
+			}
+			opts = append(opts,
+				consensus.WithTimeoutConfigUpdate(timeoutConfigUpdate),
+				consensus.WithTimeoutControllerConsumer(func(controller *timeout.Controller) {
+					timeoutController.Store(controller)
+				}),
+			)
+
 			finalizedBlock, pending, err := recovery.FindLatest(node.State, node.Storage.Headers)
 			if err != nil {
 				return nil, err
@@ -5,6 +5,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -51,6 +53,90 @@ func GetSnapshot(ctx context.Context, client *client.Client) (*inmem.Snapshot, e
 	return snapshot, nil
 }
 
+// GetSnapshotAtHeight downloads the protocol snapshot at the given sealed height from the REST API of an access
+// node, which is only requested over https, so the epoch and identity data is authenticated by the access node's
+// TLS certificate. The snapshot is additionally verified against the block header at the same height retrieved
+// through the secure client: its sealing segment must be a chain ending in that block, the QC must certify it
+// and the current epoch must contain its view.
+func GetSnapshotAtHeight(ctx context.Context, client *client.Client, restAddress string, height uint64) (*inmem.Snapshot, error) {
+	ctx, cancel := context.WithTimeout(ctx, getSnapshotTimeout)
+	defer cancel()
+
+	if strings.Contains(restAddress, "://") {
+		return nil, fmt.Errorf("access node REST address must be given as host:port, as it is always accessed over https (got %s)", restAddress)
+	}
+	url := fmt.Sprintf("https://%s/v1/protocol_state_snapshots/%d", restAddress, height)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not create snapshot request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get protocol state snapshot at height %d: %w", height, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read protocol state snapshot response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get protocol state snapshot at height %d (status %d): %s", height, resp.StatusCode, b)
+	}
+
+	var snapshotEnc inmem.EncodableSnapshot
+	err = json.Unmarshal(b, &snapshotEnc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal protocol state snapshot: %w", err)
+	}
+	snapshot := inmem.SnapshotFromEncodable(snapshotEnc)
+
+	// verify the snapshot against the block header from the trusted access node
+	trusted, err := client.GetBlockHeaderByHeight(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header at height %d: %w", height, err)
+	}
+	head, err := snapshot.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get snapshot head: %w", err)
+	}
+	headID := head.ID()
+	if headID != flow.Identifier(trusted.ID) {
+		return nil, fmt.Errorf("snapshot head %x does not match trusted block %x at height %d", headID, trusted.ID, height)
+	}
+	segment, err := snapshot.SealingSegment()
+	if err != nil {
+		return nil, fmt.Errorf("could not get snapshot sealing segment: %w", err)
+	}
+	if segment.Highest().ID() != headID {
+		return nil, fmt.Errorf("sealing segment of snapshot does not end at the snapshot head %x", headID)
+	}
+	for i := 1; i < len(segment.Blocks); i++ {
+		if segment.Blocks[i].Header.ParentID != segment.Blocks[i-1].ID() {
+			return nil, fmt.Errorf("sealing segment of snapshot is not a chain at height %d", segment.Blocks[i].Header.Height)
+		}
+	}
+	qc, err := snapshot.QuorumCertificate()
+	if err != nil {
+		return nil, fmt.Errorf("could not get snapshot quorum certificate: %w", err)
+	}
+	if qc.BlockID != headID {
+		return nil, fmt.Errorf("quorum certificate of snapshot does not certify the snapshot head %x", headID)
+	}
+	firstView, err := snapshot.Epochs().Current().FirstView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get first view of snapshot epoch: %w", err)
+	}
+	finalView, err := snapshot.Epochs().Current().FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get final view of snapshot epoch: %w", err)
+	}
+	if head.View < firstView || head.View > finalView {
+		return nil, fmt.Errorf("current epoch of snapshot [%d, %d] does not contain the snapshot head view %d", firstView, finalView, head.View)
+	}
+
+	return snapshot, nil
+}
+
 // GetSnapshotAtEpochAndPhase will get the latest finalized protocol snapshot and check the current epoch and epoch phase.
 // If we are past the target epoch and epoch phase we exit the retry mechanism immediately.
 // If not check the snapshot at the specified interval until we reach the target epoch and phase.
@@ -184,6 +270,20 @@ func DynamicStartPreInit(nodeConfig *NodeConfig) error {
 		return fmt.Errorf("failed to create flow client for node dynamic startup pre-init: %w", err)
 	}
 
+	// bootstrap from the chosen height, rather than waiting for the target epoch and phase
+	if nodeConfig.DynamicStartupHeight > 0 {
+		if nodeConfig.DynamicStartupANRestAddress == "" {
+			return fmt.Errorf("--dynamic-startup-access-rest-address is required when using --dynamic-startup-height")
+		}
+		snapshot, err := GetSnapshotAtHeight(ctx, flowClient, nodeConfig.DynamicStartupANRestAddress, nodeConfig.DynamicStartupHeight)
+		if err != nil {
+			return fmt.Errorf("failed to get snapshot at start up height (%d): %w", nodeConfig.DynamicStartupHeight, err)
+		}
+		log.Info().Uint64("height", nodeConfig.DynamicStartupHeight).Msg("bootstrapping from snapshot at configured height")
+		nodeConfig.RootSnapshot = snapshot
+		return nil
+	}
+
 	getSnapshotFunc := func(ctx context.Context) (protocol.Snapshot, error) {
 		return GetSnapshot(ctx, flowClient)
 	}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
@@ -22,6 +23,7 @@ import (
 	"github.com/onflow/flow-core-contracts/lib/go/templates"
 
 	"github.com/onflow/flow-go/admin/commands"
+	executionCommands "github.com/onflow/flow-go/admin/commands/execution"
 	stateSyncCommands "github.com/onflow/flow-go/admin/commands/state_synchronization"
 	uploaderCommands "github.com/onflow/flow-go/admin/commands/uploader"
 	"github.com/onflow/flow-go/cmd"
@@ -41,7 +43,9 @@ import (
 	"github.com/onflow/flow-go/engine/execution/computation/committer"
 	"github.com/onflow/flow-go/engine/execution/computation/computer/uploader"
 	"github.com/onflow/flow-go/engine/execution/ingestion"
+	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
 	exeprovider "github.com/onflow/flow-go/engine/execution/provider"
+	"github.com/onflow/flow-go/engine/execution/pruner"
 	"github.com/onflow/flow-go/engine/execution/rpc"
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/bootstrap"
@@ -129,6 +133,10 @@ func main() {
 		executionDataCIDCache         state_synchronization.ExecutionDataCIDCache
 		executionDataCIDCacheSize     uint = 100
 		edsDatastoreTTL               time.Duration
+		stopControl                   *stop.StopControl
+		chunkDataPacks                *storage.ChunkDataPacks
+		chdpPrunerConfig              = pruner.DefaultConfig()
+		chdpPruner                    *pruner.Pruner
 	)
 
 	nodeBuilder := cmd.FlowNode(flow.RoleExecution.String())
@@ -147,6 +155,10 @@ func main() {
 			flags.UintVar(&stateDeltasLimit, "state-deltas-limit", 100, "maximum number of state deltas in the memory pool")
 			flags.UintVar(&cadenceExecutionCache, "cadence-execution-cache", computation.DefaultProgramsCacheSize, "cache size for Cadence execution")
 			flags.UintVar(&chdpCacheSize, "chdp-cache", storage.DefaultCacheSize, "cache size for Chunk Data Packs")
+			flags.DurationVar(&chdpPrunerConfig.Interval, "chdp-pruning-interval", chdpPrunerConfig.Interval, "interval between runs of the Chunk Data Pack pruner, 0 disables background pruning")
+			flags.Uint64Var(&chdpPrunerConfig.RetainBlocks, "chdp-retain-blocks", chdpPrunerConfig.RetainBlocks, "number of blocks below the latest sealed block for which Chunk Data Packs are retained")
+			flags.DurationVar(&chdpPrunerConfig.RetainDuration, "chdp-retain-duration", chdpPrunerConfig.RetainDuration, "minimum age of blocks whose Chunk Data Packs are pruned, 0 disables age based retention")
+			flags.Uint64Var(&chdpPrunerConfig.MaxBlocksPerRun, "chdp-pruning-max-blocks", chdpPrunerConfig.MaxBlocksPerRun, "maximum number of blocks whose Chunk Data Packs are pruned in a single run, 0 means no limit")
 			flags.DurationVar(&requestInterval, "request-interval", 60*time.Second, "the interval between requests for the requester engine")
 			flags.DurationVar(&scriptLogThreshold, "script-log-threshold", computation.DefaultScriptLogThreshold, "threshold for logging script execution")
 			flags.StringVar(&preferredExeNodeIDStr, "preferred-exe-node-id", "", "node ID for preferred execution node used for state sync")
@@ -183,6 +195,31 @@ func main() {
 		AdminCommand("set-uploader-enabled", func(config *cmd.NodeConfig) commands.AdminCommand {
 			return uploaderCommands.NewToggleUploaderCommand()
 		}).
+		AdminCommand("set-stop-height", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return executionCommands.NewSetStopHeightCommand(stopControl, config.DB)
+		}).
+		AdminCommand("pause-execution", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return executionCommands.NewPauseExecutionCommand(stopControl)
+		}).
+		AdminCommand("resume-execution", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return executionCommands.NewResumeExecutionCommand(stopControl)
+		}).
+		AdminCommand("get-execution-status", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return executionCommands.NewExecutionStatusCommand(stopControl, config.DB)
+		}).
+		AdminCommand("prune-chunk-data-packs", func(config *cmd.NodeConfig) commands.AdminCommand {
+			return executionCommands.NewPruneChunkDataPacksCommand(chdpPruner)
+		}).
+		Module("stop control", func(node *cmd.NodeConfig) error {
+			// once the stop height is executed, the node shuts down gracefully the same way as on SIGTERM
+			stopControl, err = stop.NewStopControl(node.Logger, node.DB, func() {
+				err := syscall.Kill(os.Getpid(), syscall.SIGTERM)
+				if err != nil {
+					node.Logger.Fatal().Err(err).Msg("could not shut down node after reaching stop height")
+				}
+			})
+			return err
+		}).
 		Module("mutable follower state", func(node *cmd.NodeConfig) error {
 			// For now, we only support state implementations from package badger.
 			// If we ever support different implementations, the following can be replaced by a type-aware factory
@@ -460,7 +497,7 @@ func main() {
 			}
 			computationManager = manager
 
-			chunkDataPacks := storage.NewChunkDataPacks(node.Metrics.Cache, node.DB, node.Storage.Collections, chdpCacheSize)
+			chunkDataPacks = storage.NewChunkDataPacks(node.Metrics.Cache, node.DB, node.Storage.Collections, chdpCacheSize)
 			stateCommitments := storage.NewCommits(node.Metrics.Cache, node.DB)
 
 			// Needed for gRPC server, make sure to assign to main scoped vars
@@ -559,6 +596,26 @@ func main() {
 			)
 			return checkerEng, nil
 		}).
+		Component("chunk data pack pruner", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
+			// the chunk block index is read by the execution state, and pruned by the chunk data pack pruner,
+			// so both must use the headers storage of the node and its cache
+			headers, ok := node.Storage.Headers.(pruner.Headers)
+			if !ok {
+				return nil, fmt.Errorf("headers storage %T does not support pruning the chunk block index", node.Storage.Headers)
+			}
+			chdpPruner, err = pruner.New(
+				node.Logger,
+				chdpPrunerConfig,
+				collector,
+				node.DB,
+				node.State,
+				executionState,
+				headers,
+				results,
+				chunkDataPacks,
+			)
+			return chdpPruner, err
+		}).
 		Component("ingestion engine", func(node *cmd.NodeConfig) (module.ReadyDoneAware, error) {
 			collectionRequester, err = requester.New(node.Logger, node.Metrics.Engine, node.Network, node.Me, node.State,
 				engine.RequestCollections,
@@ -603,6 +660,7 @@ func main() {
 				syncFast,
 				checkAuthorizedAtBlock,
 				pauseExecution,
+				stopControl,
 			)
 
 			// TODO: we should solve these mutual dependencies better
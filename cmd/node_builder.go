@@ -20,7 +20,10 @@ import (
 	"github.com/onflow/flow-go/module"
 	"github.com/onflow/flow-go/module/id"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
 	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/scoring"
+	"github.com/onflow/flow-go/network/queue"
 	"github.com/onflow/flow-go/network/topology"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/state/protocol/events"
@@ -122,6 +125,8 @@ type BaseConfig struct {
 	DynamicStartupEpochPhase        string
 	DynamicStartupEpoch             string
 	DynamicStartupSleepInterval     time.Duration
+	DynamicStartupHeight            uint64
+	DynamicStartupANRestAddress     string
 	datadir                         string
 	secretsdir                      string
 	secretsDBEnabled                bool
@@ -147,6 +152,21 @@ type BaseConfig struct {
 	NetworkReceivedMessageCacheSize int
 	topologyProtocolName            string
 	topologyEdgeProbability         float64
+	// EgressRateLimit is the default egress rate limit of channels in bytes per second, zero means unlimited.
+	EgressRateLimit int
+	// EgressChannelRateLimits are the egress rate limits of specific channels in bytes per second, overriding the default.
+	EgressChannelRateLimits map[string]int
+	// OutboundQueue configures the outbound queues of the middleware, which are disabled if their capacity is zero.
+	OutboundQueue queue.OutboundQueueConfig
+	// NetworkCapture configures the capture of network messages, which is disabled if its directory is empty.
+	NetworkCapture              capture.Config
+	networkCaptureChannels      []string
+	networkCaptureReplayEnabled bool
+	// PeerScoring configures the scoring of remote peers, which only blocks misbehaving peers if enforced.
+	PeerScoring scoring.Config
+	// EncryptChannels enables the end-to-end encryption of the messages on encrypted channels, which are exchanged in
+	// plaintext otherwise. It must be enabled on all the nodes at the same spork.
+	EncryptChannels bool
 }
 
 // NodeConfig contains all the derived parameters such the NodeID, private keys etc. and initialized instances of
@@ -171,6 +191,7 @@ type NodeConfig struct {
 	Network           network.Network
 	PingService       network.PingService
 	MsgValidators     []network.MessageValidator
+	PeerScores        *scoring.Registry
 	FvmOptions        []fvm.Option
 	StakingKey        crypto.PrivateKey
 	NetworkKey        crypto.PrivateKey
@@ -226,5 +247,6 @@ func DefaultBaseConfig() *BaseConfig {
 		NetworkReceivedMessageCacheSize: p2p.DefaultCacheSize,
 		topologyProtocolName:            string(topology.TopicBased),
 		topologyEdgeProbability:         topology.MaximumEdgeProbability,
+		PeerScoring:                     scoring.DefaultConfig(),
 	}
 }
@@ -23,6 +23,7 @@ import (
 	"github.com/onflow/flow-go/admin"
 	"github.com/onflow/flow-go/admin/commands"
 	"github.com/onflow/flow-go/admin/commands/common"
+	networkCommands "github.com/onflow/flow-go/admin/commands/network"
 	storageCommands "github.com/onflow/flow-go/admin/commands/storage"
 	"github.com/onflow/flow-go/cmd/build"
 	"github.com/onflow/flow-go/consensus/hotstuff/persister"
@@ -39,10 +40,15 @@ import (
 	"github.com/onflow/flow-go/module/trace"
 	"github.com/onflow/flow-go/module/util"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
 	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/encryption"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/dns"
+	"github.com/onflow/flow-go/network/p2p/ratelimit"
+	"github.com/onflow/flow-go/network/p2p/scoring"
 	"github.com/onflow/flow-go/network/p2p/unicast"
+	"github.com/onflow/flow-go/network/queue"
 	"github.com/onflow/flow-go/network/topology"
 	"github.com/onflow/flow-go/state/protocol"
 	badgerState "github.com/onflow/flow-go/state/protocol/badger"
@@ -134,9 +140,39 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.StringVar(&fnb.BaseConfig.AdminClientCAs, "admin-client-certs", defaultConfig.AdminClientCAs, "admin client certs (for mutual TLS)")
 
 	fnb.flags.DurationVar(&fnb.BaseConfig.DNSCacheTTL, "dns-cache-ttl", defaultConfig.DNSCacheTTL, "time-to-live for dns cache")
-	fnb.flags.StringSliceVar(&fnb.BaseConfig.PreferredUnicastProtocols, "preferred-unicast-protocols", nil, "preferred unicast protocols in ascending order of preference")
+	fnb.flags.StringSliceVar(&fnb.BaseConfig.PreferredUnicastProtocols, "preferred-unicast-protocols", nil, "preferred unicast protocols in ascending order of preference, one of: gzip-compression, zstd-compression, snappy-compression")
 	fnb.flags.IntVar(&fnb.BaseConfig.NetworkReceivedMessageCacheSize, "networking-receive-cache-size", p2p.DefaultCacheSize,
 		"incoming message cache size at networking layer")
+	fnb.flags.IntVar(&fnb.BaseConfig.EgressRateLimit, "egress-rate-limit", 0,
+		"default egress rate limit of channels in bytes per second, 0 means unlimited (consensus channels are never limited)")
+	fnb.flags.StringToIntVar(&fnb.BaseConfig.EgressChannelRateLimits, "egress-channel-rate-limits", nil,
+		"egress rate limits of specific channels in bytes per second, overriding the default, e.g. request-chunks=52428800,request-collections=10485760")
+	fnb.flags.IntVar(&fnb.BaseConfig.OutboundQueue.Capacity, "outbound-queue-capacity", 0,
+		"maximum number of outbound messages queued per peer, and for publishing, 0 disables the outbound queues and sends messages on the goroutine of the engine")
+	fnb.flags.IntVar(&fnb.BaseConfig.OutboundQueue.LowPriorityCapacity, "outbound-queue-low-priority-capacity", queue.DefaultOutboundQueueLowPriorityCapacity,
+		"number of queued outbound messages from which messages of low priority are dropped, at most the outbound queue capacity")
+	fnb.flags.IntVar(&fnb.BaseConfig.OutboundQueue.Workers, "outbound-queue-workers", queue.DefaultOutboundQueueWorkers,
+		"maximum number of messages sent concurrently from each outbound queue")
+	fnb.flags.StringVar(&fnb.BaseConfig.NetworkCapture.Dir, "network-capture-dir", "",
+		"directory to capture inbound and outbound network messages to, for debugging (disabled if empty)")
+	fnb.flags.Int64Var(&fnb.BaseConfig.NetworkCapture.MaxFileSize, "network-capture-max-file-size", capture.DefaultMaxFileSize,
+		"size in bytes at which network capture files are rotated")
+	fnb.flags.IntVar(&fnb.BaseConfig.NetworkCapture.MaxFiles, "network-capture-max-files", capture.DefaultMaxFiles,
+		"number of network capture files kept")
+	fnb.flags.StringSliceVar(&fnb.networkCaptureChannels, "network-capture-channels", nil,
+		"channels whose messages are captured (all channels if empty)")
+	fnb.flags.BoolVar(&fnb.networkCaptureReplayEnabled, "network-capture-replay-enabled", false,
+		"enable the replay-network-capture admin command, which injects captured messages into the engines of the node (for debugging only)")
+	fnb.flags.BoolVar(&fnb.BaseConfig.PeerScoring.Enforce, "peer-scoring-enforce", defaultConfig.PeerScoring.Enforce,
+		"disconnect and block peers whose score drops to the block threshold, peers are only logged otherwise")
+	fnb.flags.Float64Var(&fnb.BaseConfig.PeerScoring.BlockThreshold, "peer-scoring-block-threshold", defaultConfig.PeerScoring.BlockThreshold,
+		"(negative) peer score at or below which a peer is blocked")
+	fnb.flags.DurationVar(&fnb.BaseConfig.PeerScoring.BlockDuration, "peer-scoring-block-duration", defaultConfig.PeerScoring.BlockDuration,
+		"time for which a peer is blocked once its score dropped to the block threshold")
+	fnb.flags.DurationVar(&fnb.BaseConfig.PeerScoring.DecayHalfLife, "peer-scoring-decay-half-life", defaultConfig.PeerScoring.DecayHalfLife,
+		"time after which the penalties of a peer have decayed to half of their value")
+	fnb.flags.BoolVar(&fnb.BaseConfig.EncryptChannels, "encrypt-channels", false,
+		"encrypt the messages on encrypted channels (e.g. the DKG channel) end-to-end and drop the messages which are not encrypted, must be enabled on all the nodes at the same spork")
 	fnb.flags.UintVar(&fnb.BaseConfig.guaranteesCacheSize, "guarantees-cache-size", bstorage.DefaultCacheSize, "collection guarantees cache size")
 	fnb.flags.UintVar(&fnb.BaseConfig.receiptsCacheSize, "receipts-cache-size", bstorage.DefaultCacheSize, "receipts cache size")
 	fnb.flags.StringVar(&fnb.BaseConfig.topologyProtocolName, "topology", defaultConfig.topologyProtocolName, "networking overlay topology")
@@ -149,6 +185,8 @@ func (fnb *FlowNodeBuilder) BaseFlags() {
 	fnb.flags.StringVar(&fnb.BaseConfig.DynamicStartupEpochPhase, "dynamic-startup-epoch-phase", "EpochPhaseSetup", "the target epoch phase for dynamic startup <EpochPhaseStaking|EpochPhaseSetup|EpochPhaseCommitted")
 	fnb.flags.StringVar(&fnb.BaseConfig.DynamicStartupEpoch, "dynamic-startup-epoch", "current", "the target epoch for dynamic-startup, use \"current\" to start node in the current epoch")
 	fnb.flags.DurationVar(&fnb.BaseConfig.DynamicStartupSleepInterval, "dynamic-startup-sleep-interval", time.Minute, "the interval in which the node will check if it can start")
+	fnb.flags.Uint64Var(&fnb.BaseConfig.DynamicStartupHeight, "dynamic-startup-height", 0, "the sealed block height to bootstrap from using dynamic-startup, overrides the target epoch and phase if set")
+	fnb.flags.StringVar(&fnb.BaseConfig.DynamicStartupANRestAddress, "dynamic-startup-access-rest-address", "", "the REST API address (host:port) of the trusted access node, accessed over https, required when using --dynamic-startup-height")
 
 	fnb.flags.BoolVar(&fnb.BaseConfig.InsecureSecretsDB, "insecure-secrets-db", false, "allow the node to start up without an secrets DB encryption key")
 }
@@ -214,6 +252,12 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit() {
 			myAddr = fnb.BaseConfig.BindAddr
 		}
 
+		peerScores, err := scoring.NewRegistry(fnb.Logger, fnb.BaseConfig.PeerScoring)
+		if err != nil {
+			return nil, fmt.Errorf("could not create peer score registry: %w", err)
+		}
+		fnb.PeerScores = peerScores
+
 		libP2PNodeFactory := p2p.DefaultLibP2PNodeFactory(
 			fnb.Logger,
 			myAddr,
@@ -223,8 +267,21 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit() {
 			fnb.Metrics.Network,
 			fnb.Resolver,
 			fnb.BaseConfig.NodeRole,
+			fnb.PeerScores,
 		)
 
+		egressLimits := ratelimit.Config{
+			Default:  ratelimit.Limit{BytesPerSecond: fnb.EgressRateLimit},
+			Channels: make(map[network.Channel]ratelimit.Limit, len(fnb.EgressChannelRateLimits)),
+		}
+		for channel, bytesPerSecond := range fnb.EgressChannelRateLimits {
+			egressLimits.Channels[network.Channel(channel)] = ratelimit.Limit{BytesPerSecond: bytesPerSecond}
+		}
+		egressLimiter, err := ratelimit.NewEgressLimiter(egressLimits)
+		if err != nil {
+			return nil, fmt.Errorf("could not create egress limiter: %w", err)
+		}
+
 		var mwOpts []p2p.MiddlewareOption
 		if len(fnb.MsgValidators) > 0 {
 			mwOpts = append(mwOpts, p2p.WithMessageValidators(fnb.MsgValidators...))
@@ -235,8 +292,28 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit() {
 		mwOpts = append(mwOpts,
 			p2p.WithPeerManager(peerManagerFactory),
 			p2p.WithPreferredUnicastProtocols(unicast.ToProtocolNames(fnb.PreferredUnicastProtocols)),
+			p2p.WithPeerScores(fnb.PeerScores),
+			p2p.WithEgressLimiter(egressLimiter),
 		)
 
+		if fnb.OutboundQueue.Capacity > 0 {
+			mwOpts = append(mwOpts, p2p.WithOutboundQueues(fnb.OutboundQueue))
+		}
+
+		if fnb.NetworkCapture.Dir != "" {
+			for _, channel := range fnb.networkCaptureChannels {
+				fnb.NetworkCapture.Channels = append(fnb.NetworkCapture.Channels, network.Channel(channel))
+			}
+			recorder, err := capture.NewRecorder(fnb.Logger, fnb.NetworkCapture)
+			if err != nil {
+				return nil, fmt.Errorf("could not create network capture recorder: %w", err)
+			}
+			fnb.ShutdownFunc(recorder.Close)
+
+			mwOpts = append(mwOpts, p2p.WithMessageCapture(recorder))
+			fnb.Logger.Warn().Str("dir", fnb.NetworkCapture.Dir).Msg("network message capture enabled")
+		}
+
 		fnb.Middleware = p2p.NewMiddleware(
 			fnb.Logger,
 			libP2PNodeFactory,
@@ -260,6 +337,16 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit() {
 		}
 		topologyCache := topology.NewCache(fnb.Logger, top)
 
+		var netOpts []p2p.NetworkOptFunction
+		if fnb.BaseConfig.EncryptChannels {
+			// encrypts the messages on encrypted channels with keys derived from the networking keys of the nodes
+			encryptor, err := encryption.NewEncryptor(fnb.NodeID, fnb.NetworkKey, fnb.IdentityProvider)
+			if err != nil {
+				return nil, fmt.Errorf("could not create channel encryptor: %w", err)
+			}
+			netOpts = append(netOpts, p2p.WithEncryptor(encryptor))
+		}
+
 		// creates network instance
 		net, err := p2p.NewNetwork(fnb.Logger,
 			codec,
@@ -270,6 +357,7 @@ func (fnb *FlowNodeBuilder) EnqueueNetworkInit() {
 			subscriptionManager,
 			fnb.Metrics.Network,
 			fnb.IdentityProvider,
+			netOpts...,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("could not initialize network: %w", err)
@@ -596,21 +684,23 @@ func (fnb *FlowNodeBuilder) initStorage() {
 	setups := bstorage.NewEpochSetups(fnb.Metrics.Cache, fnb.DB)
 	commits := bstorage.NewEpochCommits(fnb.Metrics.Cache, fnb.DB)
 	statuses := bstorage.NewEpochStatuses(fnb.Metrics.Cache, fnb.DB)
+	slashingEvidence := bstorage.NewSlashingEvidence(fnb.DB)
 
 	fnb.Storage = Storage{
-		Headers:      headers,
-		Guarantees:   guarantees,
-		Receipts:     receipts,
-		Results:      results,
-		Seals:        seals,
-		Index:        index,
-		Payloads:     payloads,
-		Blocks:       blocks,
-		Transactions: transactions,
-		Collections:  collections,
-		Setups:       setups,
-		EpochCommits: commits,
-		Statuses:     statuses,
+		Headers:          headers,
+		Guarantees:       guarantees,
+		Receipts:         receipts,
+		Results:          results,
+		Seals:            seals,
+		Index:            index,
+		Payloads:         payloads,
+		Blocks:           blocks,
+		Transactions:     transactions,
+		Collections:      collections,
+		Setups:           setups,
+		EpochCommits:     commits,
+		Statuses:         statuses,
+		SlashingEvidence: slashingEvidence,
 	}
 }
 
@@ -783,23 +873,7 @@ func (fnb *FlowNodeBuilder) initLocal() {
 }
 
 func (fnb *FlowNodeBuilder) initFvmOptions() {
-	blockFinder := fvm.NewBlockFinder(fnb.Storage.Headers)
-	vmOpts := []fvm.Option{
-		fvm.WithChain(fnb.RootChainID.Chain()),
-		fvm.WithBlocks(blockFinder),
-		fvm.WithAccountStorageLimit(true),
-	}
-	if fnb.RootChainID == flow.Testnet || fnb.RootChainID == flow.Canary || fnb.RootChainID == flow.Mainnet {
-		vmOpts = append(vmOpts,
-			fvm.WithTransactionFeesEnabled(true),
-		)
-	}
-	if fnb.RootChainID == flow.Testnet || fnb.RootChainID == flow.Canary || fnb.RootChainID == flow.Localnet || fnb.RootChainID == flow.Benchnet {
-		vmOpts = append(vmOpts,
-			fvm.WithRestrictedDeployment(false),
-		)
-	}
-	fnb.FvmOptions = vmOpts
+	fnb.FvmOptions = fvm.NodeOptions(fnb.RootChainID, fnb.Storage.Headers)
 }
 
 func (fnb *FlowNodeBuilder) handleModule(v namedModuleFunc) error {
@@ -1077,7 +1151,27 @@ func (fnb *FlowNodeBuilder) RegisterDefaultAdminCommands() {
 		return storageCommands.NewReadResultsCommand(config.State, config.Storage.Results)
 	}).AdminCommand("read-seals", func(config *NodeConfig) commands.AdminCommand {
 		return storageCommands.NewReadSealsCommand(config.State, config.Storage.Seals, config.Storage.Index)
+	}).AdminCommand("read-slashing-evidence", func(config *NodeConfig) commands.AdminCommand {
+		return storageCommands.NewReadSlashingEvidenceCommand(config.Storage.SlashingEvidence)
+	}).AdminCommand("read-peer-scores", func(config *NodeConfig) commands.AdminCommand {
+		return networkCommands.NewReadPeerScoresCommand(config.PeerScores, config.IDTranslator)
+	}).AdminCommand("reset-peer-score", func(config *NodeConfig) commands.AdminCommand {
+		return networkCommands.NewResetPeerScoreCommand(config.PeerScores, config.IDTranslator)
+	}).AdminCommand("read-topology", func(config *NodeConfig) commands.AdminCommand {
+		top, _ := config.Network.(networkCommands.TopologyProvider)
+		connections, _ := config.Middleware.(networkCommands.ConnectionProvider)
+		return networkCommands.NewReadTopologyCommand(top, connections)
 	})
+
+	// replaying captured messages injects them into the engines as if they were received from their origin,
+	// hence the command is only available on nodes explicitly started for debugging
+	if fnb.networkCaptureReplayEnabled {
+		fnb.Logger.Warn().Msg("replay-network-capture admin command enabled")
+		fnb.AdminCommand("replay-network-capture", func(config *NodeConfig) commands.AdminCommand {
+			overlay, _ := config.Network.(network.Overlay)
+			return networkCommands.NewReplayNetworkCaptureCommand(config.Logger, overlay)
+		})
+	}
 }
 
 func (fnb *FlowNodeBuilder) Build() (Node, error) {
@@ -0,0 +1,252 @@
+package audit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	chunkmodels "github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ChunkAudit is the outcome of auditing the verifier assignment of a single chunk of a sealed result.
+type ChunkAudit struct {
+	BlockID             flow.Identifier     // ID of the executed block
+	ResultID            flow.Identifier     // ID of the sealed execution result
+	IncorporatedBlockID flow.Identifier     // ID of the block the result was first incorporated in
+	ChunkIndex          uint64              // index of the chunk within the result
+	Assigned            flow.IdentifierList // verifiers assigned to the chunk
+	Approved            flow.IdentifierList // assigned verifiers, whose approval is known
+	Missing             flow.IdentifierList // assigned verifiers, whose approval is known to be absent
+	Unconfirmed         flow.IdentifierList // assigned verifiers not in the seal, whose approval may or may not exist
+	Unassigned          flow.IdentifierList // approvers, which were not assigned to the chunk
+}
+
+// VerifierSummary summarizes the audited chunks assigned to a single verifier.
+type VerifierSummary struct {
+	VerifierID  flow.Identifier
+	Assigned    int           // number of chunks assigned to the verifier
+	Approved    int           // number of assigned chunks approved by the verifier
+	Unconfirmed int           // number of assigned chunks, for which the verifier is not in the seal
+	Missed      []*ChunkAudit // assigned chunks, which the verifier is known to not have approved
+}
+
+// Report is the outcome of auditing the verifier assignments for a range of finalized blocks.
+type Report struct {
+	Chunks    []*ChunkAudit
+	Verifiers []*VerifierSummary // sorted by number of missed chunks, descending
+	Unsealed  []flow.Identifier  // blocks, for which no seal is known up to the latest finalized block
+}
+
+// Auditor recomputes the chunk assignments of sealed execution results and cross-checks them against
+// the approvals of the verifiers. An approval is known if the verifier is a signer of the chunk's
+// aggregated approval signature in the seal, or if the approval is stored in the given ResultApprovals,
+// which is only populated on verification nodes, for the node's own approvals.
+//
+// A seal does not prove that an assigned verifier did not approve a chunk: consensus nodes construct
+// a seal from the approvals they collected, which are not necessarily all approvals of the chunk.
+// Assigned verifiers which are not in the seal are therefore reported as unconfirmed. The absence of
+// an approval is only known for the local verifier, i.e. the verification node owning the approvals
+// storage, which stores all of its own approvals.
+type Auditor struct {
+	blocks    storage.Blocks
+	approvals storage.ResultApprovals
+	assigner  module.ChunkAssigner
+	localID   flow.Identifier
+}
+
+// NewAuditor creates an Auditor reading blocks and approvals from the given storage and recomputing
+// assignments with the given chunk assigner. localID is the ID of the verification node owning the
+// approvals storage, or ZeroID if the storage is not the one of a verification node.
+func NewAuditor(blocks storage.Blocks, approvals storage.ResultApprovals, assigner module.ChunkAssigner, localID flow.Identifier) *Auditor {
+	return &Auditor{
+		blocks:    blocks,
+		approvals: approvals,
+		assigner:  assigner,
+		localID:   localID,
+	}
+}
+
+// Audit audits the verifier assignments of the finalized blocks with heights from `from` to `to` (inclusive).
+// The seals and the blocks incorporating the sealed results are searched in the finalized blocks above `from`,
+// up to and including finalizedHeight.
+func (a *Auditor) Audit(from, to, finalizedHeight uint64) (*Report, error) {
+	if from > to {
+		return nil, fmt.Errorf("invalid height range: %d > %d", from, to)
+	}
+	if to > finalizedHeight {
+		return nil, fmt.Errorf("height %d is above the latest finalized height %d", to, finalizedHeight)
+	}
+
+	blockIDs := make([]flow.Identifier, 0, to-from+1)
+	audited := make(map[flow.Identifier]struct{})
+	for height := from; height <= to; height++ {
+		block, err := a.blocks.ByHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+		}
+		blockID := block.ID()
+		blockIDs = append(blockIDs, blockID)
+		audited[blockID] = struct{}{}
+	}
+
+	// results for a block can only be incorporated in and sealed by its descendants, hence a single pass
+	// over the finalized blocks above `from` finds the first incorporation of every result and the seals
+	incorporated := make(map[flow.Identifier]flow.Identifier) // result ID -> incorporating block ID
+	results := make(map[flow.Identifier]*flow.ExecutionResult)
+	seals := make(map[flow.Identifier]*flow.Seal) // executed block ID -> seal
+	for height := from + 1; height <= finalizedHeight && len(seals) < len(blockIDs); height++ {
+		block, err := a.blocks.ByHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("could not get finalized block at height %d: %w", height, err)
+		}
+		blockID := block.ID()
+		for _, result := range block.Payload.Results {
+			if _, ok := audited[result.BlockID]; !ok {
+				continue
+			}
+			resultID := result.ID()
+			if _, ok := incorporated[resultID]; !ok {
+				incorporated[resultID] = blockID
+				results[resultID] = result
+			}
+		}
+		for _, seal := range block.Payload.Seals {
+			if _, ok := audited[seal.BlockID]; ok {
+				seals[seal.BlockID] = seal
+			}
+		}
+	}
+
+	report := &Report{}
+	for _, blockID := range blockIDs {
+		seal, ok := seals[blockID]
+		if !ok {
+			report.Unsealed = append(report.Unsealed, blockID)
+			continue
+		}
+		result, ok := results[seal.ResultID]
+		if !ok {
+			return nil, fmt.Errorf("sealed result %x for block %x is not incorporated in a finalized block", seal.ResultID, blockID)
+		}
+		incorporatedBlockID := incorporated[seal.ResultID]
+
+		assignment, err := a.assigner.Assign(result, incorporatedBlockID)
+		if err != nil {
+			return nil, fmt.Errorf("could not compute chunk assignment for result %x incorporated in block %x: %w",
+				seal.ResultID, incorporatedBlockID, err)
+		}
+
+		chunks, err := a.auditResult(result, incorporatedBlockID, assignment, seal)
+		if err != nil {
+			return nil, fmt.Errorf("could not audit result %x: %w", seal.ResultID, err)
+		}
+		report.Chunks = append(report.Chunks, chunks...)
+	}
+
+	report.Verifiers = summarize(report.Chunks)
+	return report, nil
+}
+
+// auditResult cross-checks the given assignment for the chunks of a result against the approvals known
+// from the seal and the approvals storage.
+func (a *Auditor) auditResult(
+	result *flow.ExecutionResult,
+	incorporatedBlockID flow.Identifier,
+	assignment *chunkmodels.Assignment,
+	seal *flow.Seal,
+) ([]*ChunkAudit, error) {
+
+	resultID := result.ID()
+	audits := make([]*ChunkAudit, 0, len(result.Chunks))
+	for _, chunk := range result.Chunks {
+		var signerIDs flow.IdentifierList
+		if chunk.Index < uint64(len(seal.AggregatedApprovalSigs)) {
+			signerIDs = seal.AggregatedApprovalSigs[chunk.Index].SignerIDs
+		}
+		approvers := make(map[flow.Identifier]struct{})
+		for _, signerID := range signerIDs {
+			approvers[signerID] = struct{}{}
+		}
+		approval, err := a.approvals.ByChunk(resultID, chunk.Index)
+		if err == nil {
+			approvers[approval.Body.ApproverID] = struct{}{}
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return nil, fmt.Errorf("could not get stored approval for chunk %d: %w", chunk.Index, err)
+		}
+
+		audit := &ChunkAudit{
+			BlockID:             result.BlockID,
+			ResultID:            resultID,
+			IncorporatedBlockID: incorporatedBlockID,
+			ChunkIndex:          chunk.Index,
+			Assigned:            assignment.Verifiers(chunk),
+		}
+		sort.Sort(audit.Assigned)
+		for _, verifierID := range audit.Assigned {
+			if _, ok := approvers[verifierID]; ok {
+				audit.Approved = append(audit.Approved, verifierID)
+				delete(approvers, verifierID)
+			} else if verifierID == a.localID {
+				// the local verifier stores all of its approvals, so its approval is known to be absent
+				audit.Missing = append(audit.Missing, verifierID)
+			} else {
+				audit.Unconfirmed = append(audit.Unconfirmed, verifierID)
+			}
+		}
+		for approverID := range approvers {
+			audit.Unassigned = append(audit.Unassigned, approverID)
+		}
+		sort.Sort(audit.Unassigned)
+
+		audits = append(audits, audit)
+	}
+	return audits, nil
+}
+
+// summarize aggregates the chunk audits by verifier. The summaries are ordered by the number of missed
+// chunks (descending), then by verifier ID.
+func summarize(chunks []*ChunkAudit) []*VerifierSummary {
+	byVerifier := make(map[flow.Identifier]*VerifierSummary)
+	summary := func(verifierID flow.Identifier) *VerifierSummary {
+		s, ok := byVerifier[verifierID]
+		if !ok {
+			s = &VerifierSummary{VerifierID: verifierID}
+			byVerifier[verifierID] = s
+		}
+		return s
+	}
+
+	for _, chunk := range chunks {
+		for _, verifierID := range chunk.Approved {
+			s := summary(verifierID)
+			s.Assigned++
+			s.Approved++
+		}
+		for _, verifierID := range chunk.Missing {
+			s := summary(verifierID)
+			s.Assigned++
+			s.Missed = append(s.Missed, chunk)
+		}
+		for _, verifierID := range chunk.Unconfirmed {
+			s := summary(verifierID)
+			s.Assigned++
+			s.Unconfirmed++
+		}
+	}
+
+	summaries := make([]*VerifierSummary, 0, len(byVerifier))
+	for _, s := range byVerifier {
+		summaries = append(summaries, s)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		if len(summaries[i].Missed) != len(summaries[j].Missed) {
+			return len(summaries[i].Missed) > len(summaries[j].Missed)
+		}
+		return bytes.Compare(summaries[i].VerifierID[:], summaries[j].VerifierID[:]) < 0
+	})
+	return summaries
+}
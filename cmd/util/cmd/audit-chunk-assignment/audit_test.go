@@ -0,0 +1,173 @@
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	chunkmodels "github.com/onflow/flow-go/model/chunks"
+	"github.com/onflow/flow-go/model/flow"
+	modulemock "github.com/onflow/flow-go/module/mock"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// blockAtHeight returns a block at the given height with the given payload.
+func blockAtHeight(height uint64, payload *flow.Payload) *flow.Block {
+	block := unittest.BlockFixture()
+	block.Header.Height = height
+	block.Header.PayloadHash = payload.Hash()
+	block.Payload = payload
+	return &block
+}
+
+// TestAudit checks that the assignment of a sealed result is cross-checked against the signers
+// of the seal and the stored approvals, and that missing approvals are only reported as missed
+// for the local verifier, which stores all of its approvals.
+func TestAudit(t *testing.T) {
+	verifiers := unittest.IdentifierListFixture(5)
+
+	executed := blockAtHeight(10, &flow.Payload{})
+	result := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(executed.ID()))
+	incorporating := blockAtHeight(11, &flow.Payload{Results: flow.ExecutionResultList{result}})
+	seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+	seal.AggregatedApprovalSigs[0].SignerIDs = flow.IdentifierList{verifiers[0], verifiers[1], verifiers[4]}
+	seal.AggregatedApprovalSigs[1].SignerIDs = flow.IdentifierList{verifiers[1]}
+	sealing := blockAtHeight(12, &flow.Payload{Seals: []*flow.Seal{seal}})
+
+	assignment := chunkmodels.NewAssignment()
+	assignment.Add(result.Chunks[0], flow.IdentifierList{verifiers[0], verifiers[1], verifiers[2]})
+	assignment.Add(result.Chunks[1], flow.IdentifierList{verifiers[1], verifiers[2], verifiers[3]})
+
+	blocks := &storagemock.Blocks{}
+	// the search must stop once all audited blocks are sealed, so height 13 is never read
+	for _, block := range []*flow.Block{executed, incorporating, sealing} {
+		blocks.On("ByHeight", block.Header.Height).Return(block, nil)
+	}
+	// the approvals of the local verifier are stored
+	local := verifiers[2]
+	approvals := &storagemock.ResultApprovals{}
+	approvals.On("ByChunk", result.ID(), uint64(0)).Return(nil, storage.ErrNotFound)
+	approvals.On("ByChunk", result.ID(), uint64(1)).Return(
+		unittest.ResultApprovalFixture(unittest.WithApproverID(local)), nil)
+	assigner := &modulemock.ChunkAssigner{}
+	assigner.On("Assign", result, incorporating.ID()).Return(assignment, nil).Once()
+
+	report, err := NewAuditor(blocks, approvals, assigner, local).Audit(10, 10, 20)
+	require.NoError(t, err)
+	assert.Empty(t, report.Unsealed)
+
+	require.Len(t, report.Chunks, 2)
+	chunk0, chunk1 := report.Chunks[0], report.Chunks[1]
+	assert.Equal(t, executed.ID(), chunk0.BlockID)
+	assert.Equal(t, result.ID(), chunk0.ResultID)
+	assert.Equal(t, incorporating.ID(), chunk0.IncorporatedBlockID)
+	// the local verifier did not store an approval for chunk 0, so it is known to have missed it
+	assert.ElementsMatch(t, flow.IdentifierList{verifiers[0], verifiers[1]}, chunk0.Approved)
+	assert.Equal(t, flow.IdentifierList{verifiers[2]}, chunk0.Missing)
+	assert.Empty(t, chunk0.Unconfirmed)
+	assert.Equal(t, flow.IdentifierList{verifiers[4]}, chunk0.Unassigned)
+	// verifier 3 is not in the seal, but may have approved the chunk
+	assert.ElementsMatch(t, flow.IdentifierList{verifiers[1], verifiers[2]}, chunk1.Approved)
+	assert.Empty(t, chunk1.Missing)
+	assert.Equal(t, flow.IdentifierList{verifiers[3]}, chunk1.Unconfirmed)
+	assert.Empty(t, chunk1.Unassigned)
+
+	// verifiers with missed chunks are listed first
+	require.Len(t, report.Verifiers, 4)
+	summaries := make(map[flow.Identifier]*VerifierSummary)
+	for i, summary := range report.Verifiers {
+		if i < 1 {
+			require.Len(t, summary.Missed, 1)
+		} else {
+			require.Empty(t, summary.Missed)
+		}
+		summaries[summary.VerifierID] = summary
+	}
+	assert.Equal(t, chunk0, summaries[verifiers[2]].Missed[0])
+	assert.Equal(t, 2, summaries[verifiers[2]].Assigned)
+	assert.Equal(t, 1, summaries[verifiers[2]].Approved)
+	assert.Equal(t, 1, summaries[verifiers[3]].Assigned)
+	assert.Equal(t, 1, summaries[verifiers[3]].Unconfirmed)
+	assert.Equal(t, 2, summaries[verifiers[1]].Approved)
+
+	blocks.AssertExpectations(t)
+	approvals.AssertExpectations(t)
+	assigner.AssertExpectations(t)
+}
+
+// TestAudit_NotInSeal checks that assigned verifiers which are not in the seal are reported as
+// unconfirmed rather than missed, regardless of the number of signers of the chunk.
+func TestAudit_NotInSeal(t *testing.T) {
+	verifiers := unittest.IdentifierListFixture(4)
+
+	executed := blockAtHeight(10, &flow.Payload{})
+	result := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(executed.ID()))
+	incorporating := blockAtHeight(11, &flow.Payload{Results: flow.ExecutionResultList{result}})
+	seal := unittest.Seal.Fixture(unittest.Seal.WithResult(result))
+	seal.AggregatedApprovalSigs[0].SignerIDs = flow.IdentifierList{verifiers[0], verifiers[1], verifiers[2]}
+	seal.AggregatedApprovalSigs[1].SignerIDs = nil
+	sealing := blockAtHeight(12, &flow.Payload{Seals: []*flow.Seal{seal}})
+
+	assignment := chunkmodels.NewAssignment()
+	assignment.Add(result.Chunks[0], verifiers)
+	assignment.Add(result.Chunks[1], verifiers)
+
+	blocks := &storagemock.Blocks{}
+	for _, block := range []*flow.Block{executed, incorporating, sealing} {
+		blocks.On("ByHeight", block.Header.Height).Return(block, nil)
+	}
+	approvals := &storagemock.ResultApprovals{}
+	approvals.On("ByChunk", result.ID(), mock.Anything).Return(nil, storage.ErrNotFound)
+	assigner := &modulemock.ChunkAssigner{}
+	assigner.On("Assign", result, incorporating.ID()).Return(assignment, nil).Once()
+
+	// the storage is not the one of a verification node
+	report, err := NewAuditor(blocks, approvals, assigner, flow.ZeroID).Audit(10, 10, 20)
+	require.NoError(t, err)
+
+	require.Len(t, report.Chunks, 2)
+	for _, chunk := range report.Chunks {
+		assert.Empty(t, chunk.Missing)
+	}
+	assert.Equal(t, flow.IdentifierList{verifiers[3]}, report.Chunks[0].Unconfirmed)
+	assert.ElementsMatch(t, verifiers, report.Chunks[1].Unconfirmed)
+	for _, summary := range report.Verifiers {
+		assert.Empty(t, summary.Missed)
+	}
+}
+
+// TestAudit_Unsealed checks that blocks without a finalized seal are reported, but not audited.
+func TestAudit_Unsealed(t *testing.T) {
+	executed := blockAtHeight(10, &flow.Payload{})
+	result := unittest.ExecutionResultFixture(unittest.WithExecutionResultBlockID(executed.ID()))
+	incorporating := blockAtHeight(11, &flow.Payload{Results: flow.ExecutionResultList{result}})
+
+	blocks := &storagemock.Blocks{}
+	blocks.On("ByHeight", uint64(10)).Return(executed, nil)
+	blocks.On("ByHeight", uint64(11)).Return(incorporating, nil)
+	approvals := &storagemock.ResultApprovals{}
+	assigner := &modulemock.ChunkAssigner{}
+
+	report, err := NewAuditor(blocks, approvals, assigner, flow.ZeroID).Audit(10, 10, 11)
+	require.NoError(t, err)
+	assert.Equal(t, []flow.Identifier{executed.ID()}, report.Unsealed)
+	assert.Empty(t, report.Chunks)
+	assert.Empty(t, report.Verifiers)
+
+	assigner.AssertNotCalled(t, "Assign", mock.Anything, mock.Anything)
+}
+
+// TestAudit_InvalidRange checks that heights above the finalized height are rejected.
+func TestAudit_InvalidRange(t *testing.T) {
+	auditor := NewAuditor(&storagemock.Blocks{}, &storagemock.ResultApprovals{}, &modulemock.ChunkAssigner{}, flow.ZeroID)
+
+	_, err := auditor.Audit(11, 10, 20)
+	require.Error(t, err)
+
+	_, err = auditor.Audit(10, 21, 20)
+	require.Error(t, err)
+}
@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/chunks"
+	"github.com/onflow/flow-go/module/metrics"
+	storagebadger "github.com/onflow/flow-go/storage/badger"
+)
+
+var (
+	flagDatadir    string
+	flagBlockID    string
+	flagFromHeight uint64
+	flagToHeight   uint64
+	flagAlpha      uint
+	flagNodeID     string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "audit-chunk-assignment",
+	Short: "Recomputes the chunk assignments of sealed blocks and reports verifiers that did not approve their chunks",
+	Long: `Recomputes the chunk to verifier assignment of the sealed execution result of every finalized block
+in the given range, using the source of randomness of the block incorporating the result, and cross-checks
+it against the approvals known for each chunk.
+
+An approval is known if the verifier signed the chunk's aggregated approval signature in the seal, or if it
+is stored in the result approvals of the database, which only contains the node's own approvals on
+verification nodes. Blocks which are not sealed yet are listed, but not audited.
+
+As seals are constructed from the approvals collected by the consensus nodes, a seal does not prove that
+an assigned verifier which is not a signer did not approve the chunk. Such verifiers are reported as
+unconfirmed. Only the verification node given by --node-id, which owns the database and stores all of its
+own approvals, is reported to have missed the assigned chunks it did not approve.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagBlockID, "block-id", "",
+		"ID of a single finalized block to audit (hex-encoded, 64 characters)")
+
+	Cmd.Flags().Uint64Var(&flagFromHeight, "from-height", 0,
+		"lowest height of the finalized blocks to audit, used if no block ID is given")
+
+	Cmd.Flags().Uint64Var(&flagToHeight, "to-height", 0,
+		"highest height of the finalized blocks to audit, defaults to from-height")
+
+	Cmd.Flags().UintVar(&flagAlpha, "chunk-alpha", chunks.DefaultChunkAssignmentAlpha,
+		"number of verifiers assigned to each chunk, must match the value used by the network")
+
+	Cmd.Flags().StringVar(&flagNodeID, "node-id", "",
+		"ID of the verification node owning the database (hex-encoded, 64 characters), whose missed chunks are reported")
+}
+
+func run(*cobra.Command, []string) {
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+	state, err := common.InitProtocolState(db, storages)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init protocol state")
+	}
+
+	final, err := state.Final().Head()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get finalized header")
+	}
+
+	from, to := flagFromHeight, flagToHeight
+	if len(flagBlockID) > 0 {
+		blockID, err := flow.HexStringToIdentifier(flagBlockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("malformed block id")
+		}
+		header, err := storages.Headers.ByBlockID(blockID)
+		if err != nil {
+			log.Fatal().Err(err).Hex("block_id", blockID[:]).Msg("could not find block")
+		}
+		finalized, err := storages.Headers.ByHeight(header.Height)
+		if err != nil || finalized.ID() != blockID {
+			log.Fatal().Hex("block_id", blockID[:]).Msg("block is not finalized")
+		}
+		from, to = header.Height, header.Height
+	} else if to == 0 {
+		to = from
+	}
+
+	assigner, err := chunks.NewChunkAssigner(flagAlpha, state)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not create chunk assigner")
+	}
+	approvals := storagebadger.NewResultApprovals(&metrics.NoopCollector{}, db)
+
+	localID := flow.ZeroID
+	if len(flagNodeID) > 0 {
+		localID, err = flow.HexStringToIdentifier(flagNodeID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("malformed node id")
+		}
+	}
+
+	report, err := NewAuditor(storages.Blocks, approvals, assigner, localID).Audit(from, to, final.Height)
+	if err != nil {
+		log.Fatal().Err(err).Uint64("from", from).Uint64("to", to).Msg("could not audit chunk assignments")
+	}
+
+	for _, blockID := range report.Unsealed {
+		log.Warn().Hex("block_id", blockID[:]).Msg("block is not sealed, skipping")
+	}
+
+	for _, chunk := range report.Chunks {
+		if len(chunk.Unassigned) > 0 {
+			log.Warn().
+				Hex("block_id", chunk.BlockID[:]).
+				Hex("result_id", chunk.ResultID[:]).
+				Uint64("chunk_index", chunk.ChunkIndex).
+				Strs("unassigned", chunk.Unassigned.Strings()).
+				Msg("chunk was approved by verifiers not assigned to it")
+		}
+	}
+
+	missed, unconfirmed := 0, 0
+	for _, verifier := range report.Verifiers {
+		for _, chunk := range verifier.Missed {
+			log.Warn().
+				Hex("verifier_id", verifier.VerifierID[:]).
+				Hex("block_id", chunk.BlockID[:]).
+				Hex("result_id", chunk.ResultID[:]).
+				Hex("incorporated_block_id", chunk.IncorporatedBlockID[:]).
+				Uint64("chunk_index", chunk.ChunkIndex).
+				Msg("verifier did not approve assigned chunk")
+		}
+		log.Info().
+			Hex("verifier_id", verifier.VerifierID[:]).
+			Int("assigned", verifier.Assigned).
+			Int("approved", verifier.Approved).
+			Int("unconfirmed", verifier.Unconfirmed).
+			Int("missed", len(verifier.Missed)).
+			Msg("verifier summary")
+		missed += len(verifier.Missed)
+		unconfirmed += verifier.Unconfirmed
+	}
+
+	log.Info().
+		Uint64("from", from).
+		Uint64("to", to).
+		Int("chunks", len(report.Chunks)).
+		Int("unsealed_blocks", len(report.Unsealed)).
+		Int("missed_approvals", missed).
+		Int("unconfirmed_approvals", unconfirmed).
+		Msg("chunk assignment audit finished")
+}
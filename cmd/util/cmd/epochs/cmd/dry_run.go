@@ -0,0 +1,333 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees/leader"
+	"github.com/onflow/flow-go/model/bootstrap"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/cluster"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/io"
+)
+
+// dryRunSetupCmd represents a command to validate a candidate EpochSetup service event
+// before it is emitted by the FlowEpoch smart contract.
+//
+// An invalid EpochSetup event is rejected by every node when the block sealing it is
+// incorporated, which triggers epoch emergency fallback. Valid events may still result
+// in committees which are unable to make progress, for example empty or unbalanced
+// collection clusters. The command applies the protocol state validation to the event,
+// builds the resulting committees and leader selections, and reports such problems.
+var dryRunSetupCmd = &cobra.Command{
+	Use:   "dry-run-setup",
+	Short: "Validates a candidate EpochSetup event and previews the resulting committees",
+	Long: "Validates a JSON-encoded candidate EpochSetup event against a protocol state snapshot, using the same " +
+		"validation as the protocol state, and writes a report of the resulting consensus committee, collection " +
+		"clusters and leader selections to STDOUT. Exits with an error if the event is invalid or problems were found.",
+	Run: dryRunSetupRun,
+}
+
+var (
+	flagSetupPath           string
+	flagSnapshotPath        string
+	flagMaxClusterImbalance float64
+)
+
+func init() {
+	rootCmd.AddCommand(dryRunSetupCmd)
+	addDryRunSetupCmdFlags()
+}
+
+func addDryRunSetupCmdFlags() {
+	dryRunSetupCmd.Flags().StringVar(&flagSetupPath, "setup", "", "path to the JSON-encoded candidate EpochSetup event")
+	_ = dryRunSetupCmd.MarkFlagRequired("setup")
+	dryRunSetupCmd.Flags().StringVar(&flagSnapshotPath, "snapshot", "", "path to a protocol state snapshot of the current epoch, defaults to the root snapshot in the boot dir")
+	dryRunSetupCmd.Flags().Float64Var(&flagMaxClusterImbalance, "max-cluster-imbalance", 0.2, "maximum relative deviation of a cluster's weight from the mean cluster weight")
+}
+
+// committeePreview describes a committee resulting from a candidate EpochSetup event.
+type committeePreview struct {
+	Members     flow.IdentifierList        `json:"members"`
+	TotalWeight uint64                     `json:"total_weight"`
+	LeaderViews map[flow.Identifier]uint64 `json:"leader_views"` // number of previewed views led by each member
+}
+
+// setupDryRunReport is the outcome of validating a candidate EpochSetup event.
+type setupDryRunReport struct {
+	Counter   uint64              `json:"counter"`
+	Invalid   string              `json:"invalid,omitempty"` // reason the protocol state would reject the event
+	Problems  []string            `json:"problems"`          // problems with the resulting committees
+	Joining   flow.IdentifierList `json:"joining"`           // participants which are not part of the current epoch
+	Leaving   flow.IdentifierList `json:"leaving"`           // current participants which are not part of the next epoch
+	Consensus *committeePreview   `json:"consensus,omitempty"`
+	Clusters  []*committeePreview `json:"clusters,omitempty"`
+}
+
+// Failed returns true if the event is invalid or any problems were found.
+func (r *setupDryRunReport) Failed() bool {
+	return r.Invalid != "" || len(r.Problems) > 0
+}
+
+func (r *setupDryRunReport) problemf(format string, args ...interface{}) {
+	r.Problems = append(r.Problems, fmt.Sprintf(format, args...))
+}
+
+// dryRunSetupRun validates the candidate EpochSetup event and writes the report to stdout
+func dryRunSetupRun(cmd *cobra.Command, args []string) {
+
+	stdout := cmd.OutOrStdout()
+
+	path := flagSnapshotPath
+	if path == "" {
+		if flagBootDir == "" {
+			log.Fatal().Msg("must provide a snapshot (specify either --snapshot or --boot-dir)")
+		}
+		path = filepath.Join(flagBootDir, bootstrap.PathRootProtocolStateSnapshot)
+	}
+	snapshot, err := getSnapshotFromLocalBootstrapDir(path)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("failed to retrieve protocol state snapshot")
+	}
+
+	bz, err := io.ReadFile(flagSetupPath)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", flagSetupPath).Msg("could not read candidate epoch setup")
+	}
+	var setup flow.EpochSetup
+	err = json.Unmarshal(bz, &setup)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", flagSetupPath).Msg("could not decode candidate epoch setup")
+	}
+
+	report, err := dryRunEpochSetup(snapshot, &setup, flagMaxClusterImbalance)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not dry-run epoch setup")
+	}
+
+	encoded, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not encode dry-run report")
+	}
+	_, err = stdout.Write(encoded)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not write dry-run report")
+	}
+
+	if report.Failed() {
+		log.Fatal().Str("invalid", report.Invalid).Int("problems", len(report.Problems)).Msg("candidate epoch setup failed dry-run")
+	}
+	log.Info().Uint64("counter", setup.Counter).Msg("candidate epoch setup passed dry-run")
+}
+
+// dryRunEpochSetup validates the candidate EpochSetup event against the current epoch of the given
+// snapshot and previews the committees resulting from it. Only unexpected errors are returned,
+// problems with the event are reported.
+func dryRunEpochSetup(snapshot protocol.Snapshot, setup *flow.EpochSetup, maxClusterImbalance float64) (*setupDryRunReport, error) {
+	report := &setupDryRunReport{Counter: setup.Counter}
+
+	activeSetup, err := protocol.ToEpochSetup(snapshot.Epochs().Current())
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch setup: %w", err)
+	}
+	status, err := epochStatusFromSnapshot(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	err = protocol.IsValidExtendingEpochSetup(setup, activeSetup, status)
+	if protocol.IsInvalidServiceEventError(err) {
+		report.Invalid = err.Error()
+	} else if err != nil {
+		return nil, fmt.Errorf("could not validate epoch setup: %w", err)
+	}
+
+	// the DKG phases are not validated by the protocol state, but the DKG fails if they are inconsistent
+	if !(setup.FirstView < setup.DKGPhase1FinalView && setup.DKGPhase1FinalView < setup.DKGPhase2FinalView &&
+		setup.DKGPhase2FinalView < setup.DKGPhase3FinalView && setup.DKGPhase3FinalView < setup.FinalView) {
+		report.problemf("dkg phase views (%d, %d, %d) are not strictly increasing within the epoch views (%d-%d)",
+			setup.DKGPhase1FinalView, setup.DKGPhase2FinalView, setup.DKGPhase3FinalView, setup.FirstView, setup.FinalView)
+	}
+
+	current := activeSetup.Participants.Lookup()
+	next := setup.Participants.Lookup()
+	for _, participant := range setup.Participants {
+		if _, ok := current[participant.NodeID]; !ok {
+			report.Joining = append(report.Joining, participant.NodeID)
+		}
+	}
+	for _, participant := range activeSetup.Participants {
+		if _, ok := next[participant.NodeID]; !ok {
+			report.Leaving = append(report.Leaving, participant.NodeID)
+		}
+	}
+
+	// the committees can only be built if the views and the clustering are consistent
+	if setup.FirstView > setup.FinalView {
+		return report, nil
+	}
+	participation, err := participationFromSnapshot(snapshot, setup.Counter)
+	if err != nil {
+		return nil, err
+	}
+	epoch, err := inmem.NewSetupEpoch(setup, participation)
+	if err != nil {
+		return nil, fmt.Errorf("could not create epoch from setup: %w", err)
+	}
+	views := setup.FinalView - setup.FirstView + 1
+
+	consensus := setup.Participants.Filter(filter.IsVotingConsensusCommitteeMember)
+	report.Consensus = previewCommittee(report, "consensus committee", consensus)
+	// select leaders like the consensus committee, which weights them by liveness if enabled for the spork
+	weighting, err := snapshot.Params().LivenessWeighting()
+	if err != nil {
+		return nil, fmt.Errorf("could not get liveness weighting: %w", err)
+	}
+	selection, err := leader.SelectionForConsensusWithWeighting(epoch, weighting)
+	if err != nil {
+		report.problemf("could not compute consensus leader selection: %s", err)
+	} else {
+		report.Consensus.LeaderViews, err = countLeaderViews(selection, setup.FirstView, views)
+		if err != nil {
+			return nil, fmt.Errorf("could not count consensus leader views: %w", err)
+		}
+	}
+
+	clustering, err := epoch.Clustering()
+	if err != nil {
+		report.problemf("invalid cluster assignments: %s", err)
+		return report, nil
+	}
+	for index, members := range clustering {
+		name := fmt.Sprintf("cluster %d", index)
+		preview := previewCommittee(report, name, members)
+		report.Clusters = append(report.Clusters, preview)
+		if len(members) == 0 {
+			report.problemf("%s is empty", name)
+			continue
+		}
+
+		// cluster views start at the view of the cluster root block, we preview as many views as the epoch has
+		rootBlock := cluster.CanonicalRootBlock(setup.Counter, members)
+		clusterCommittee, err := inmem.ClusterFromEncodable(inmem.EncodableCluster{
+			Index:     uint(index),
+			Counter:   setup.Counter,
+			Members:   members,
+			RootBlock: rootBlock,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not create %s: %w", name, err)
+		}
+		selection, err := leader.SelectionForCluster(clusterCommittee, epoch)
+		if err != nil {
+			report.problemf("could not compute leader selection for %s: %s", name, err)
+			continue
+		}
+		preview.LeaderViews, err = countLeaderViews(selection, rootBlock.Header.View, views)
+		if err != nil {
+			return nil, fmt.Errorf("could not count leader views for %s: %w", name, err)
+		}
+	}
+
+	// all clusters process a similar share of the transactions, so their weights should be similar
+	if len(report.Clusters) > 1 {
+		var total uint64
+		for _, preview := range report.Clusters {
+			total += preview.TotalWeight
+		}
+		mean := float64(total) / float64(len(report.Clusters))
+		for index, preview := range report.Clusters {
+			deviation := math.Abs(float64(preview.TotalWeight)-mean) / mean
+			if deviation > maxClusterImbalance {
+				report.problemf("cluster %d has weight %d, which deviates from the mean cluster weight %.1f by %.0f%%",
+					index, preview.TotalWeight, mean, deviation*100)
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// epochStatusFromSnapshot returns the epoch status of the snapshot, as far as relevant for validating
+// a candidate EpochSetup event extending the snapshot's current epoch.
+func epochStatusFromSnapshot(snapshot protocol.Snapshot) (*flow.EpochStatus, error) {
+	phase, err := snapshot.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch phase: %w", err)
+	}
+	status := &flow.EpochStatus{}
+	if phase == flow.EpochPhaseStaking {
+		return status, nil
+	}
+	nextSetup, err := protocol.ToEpochSetup(snapshot.Epochs().Next())
+	if err != nil {
+		return nil, fmt.Errorf("could not get next epoch setup: %w", err)
+	}
+	status.NextEpoch.SetupID = nextSetup.ID()
+	return status, nil
+}
+
+// participationFromSnapshot returns the QC participation recorded for the epoch with the given counter,
+// if the snapshot is in the setup phase of that epoch. Otherwise, the participation of the setup phase is
+// not known yet and nil is returned, so the previewed leader selection assumes full participation.
+func participationFromSnapshot(snapshot protocol.Snapshot, counter uint64) (*flow.QCParticipation, error) {
+	phase, err := snapshot.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch phase: %w", err)
+	}
+	if phase != flow.EpochPhaseSetup {
+		return nil, nil
+	}
+	next := snapshot.Epochs().Next()
+	nextCounter, err := next.Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get next epoch counter: %w", err)
+	}
+	if nextCounter != counter {
+		return nil, nil
+	}
+	participation, err := next.QCParticipation()
+	if err != nil {
+		return nil, fmt.Errorf("could not get next epoch qc participation: %w", err)
+	}
+	return participation, nil
+}
+
+// previewCommittee returns the preview of a committee with the given members, and reports
+// members which can prevent the committee from building QCs on their own.
+func previewCommittee(report *setupDryRunReport, name string, members flow.IdentityList) *committeePreview {
+	preview := &committeePreview{
+		Members:     members.NodeIDs(),
+		TotalWeight: members.TotalWeight(),
+	}
+	threshold := hotstuff.ComputeWeightThresholdForBuildingQC(preview.TotalWeight)
+	for _, member := range members {
+		if len(members) > 1 && preview.TotalWeight-member.Weight < threshold {
+			report.problemf("%s member %x has weight %d of %d, which prevents building QCs without it",
+				name, member.NodeID, member.Weight, preview.TotalWeight)
+		}
+	}
+	return preview
+}
+
+// countLeaderViews returns the number of views led by each member of a leader selection, for the
+// given number of views starting at firstView.
+func countLeaderViews(selection *leader.LeaderSelection, firstView uint64, views uint64) (map[flow.Identifier]uint64, error) {
+	counts := make(map[flow.Identifier]uint64)
+	for view := firstView; view < firstView+views; view++ {
+		leaderID, err := selection.LeaderForView(view)
+		if err != nil {
+			return nil, fmt.Errorf("could not get leader for view %d: %w", view, err)
+		}
+		counts[leaderID]++
+	}
+	return counts, nil
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/committees/leader"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// dryRunIdentities returns identities with the given roles, all with the default weight.
+func dryRunIdentities(roles ...flow.Role) flow.IdentityList {
+	identities := make(flow.IdentityList, 0, len(roles))
+	for _, role := range roles {
+		nodeID := unittest.IdentifierFixture()
+		identities = append(identities, &flow.Identity{
+			NodeID:  nodeID,
+			Address: fmt.Sprintf("%x.flow.test:3569", nodeID[:4]),
+			Role:    role,
+			Weight:  flow.DefaultInitialWeight,
+		})
+	}
+	return identities
+}
+
+// dryRunFixture returns a snapshot of epoch 1 (views 0 to 1000) and a valid setup for epoch 2
+// (views 1001 to 2000) with the same participants, and two clusters of six collectors each.
+// The committees are large enough to tolerate a single unavailable node.
+func dryRunFixture() (*inmem.Snapshot, *flow.EpochSetup) {
+	roles := []flow.Role{flow.RoleExecution, flow.RoleVerification}
+	for i := 0; i < 12; i++ {
+		roles = append(roles, flow.RoleCollection)
+	}
+	for i := 0; i < 4; i++ {
+		roles = append(roles, flow.RoleConsensus)
+	}
+	participants := dryRunIdentities(roles...).Sort(order.Canonical)
+	collectors := participants.Filter(filter.HasRole(flow.RoleCollection)).NodeIDs()
+	assignments := flow.AssignmentList{collectors[:6], collectors[6:]}
+	clustering, err := flow.NewClusterList(assignments, participants.Filter(filter.HasRole(flow.RoleCollection)))
+	if err != nil {
+		panic(err)
+	}
+
+	snapshot := inmem.SnapshotFromEncodable(inmem.EncodableSnapshot{
+		Phase: flow.EpochPhaseStaking,
+		Epochs: inmem.EncodableEpochs{
+			Current: inmem.EncodableEpoch{
+				Counter:            1,
+				FirstView:          0,
+				DKGPhase1FinalView: 100,
+				DKGPhase2FinalView: 200,
+				DKGPhase3FinalView: 300,
+				FinalView:          1000,
+				RandomSource:       unittest.SeedFixture(flow.EpochSetupRandomSourceLength),
+				InitialIdentities:  participants,
+				Clustering:         clustering,
+			},
+		},
+	})
+
+	setup := &flow.EpochSetup{
+		Counter:            2,
+		FirstView:          1001,
+		DKGPhase1FinalView: 1100,
+		DKGPhase2FinalView: 1200,
+		DKGPhase3FinalView: 1300,
+		FinalView:          2000,
+		Participants:       participants,
+		Assignments:        assignments,
+		RandomSource:       unittest.SeedFixture(flow.EpochSetupRandomSourceLength),
+	}
+	return snapshot, setup
+}
+
+// TestDryRunEpochSetup_Valid tests that a valid setup passes and the committees are previewed.
+func TestDryRunEpochSetup_Valid(t *testing.T) {
+	snapshot, setup := dryRunFixture()
+
+	report, err := dryRunEpochSetup(snapshot, setup, 0.2)
+	require.NoError(t, err)
+	assert.False(t, report.Failed(), "unexpected problems: %s %v", report.Invalid, report.Problems)
+	assert.Empty(t, report.Joining)
+	assert.Empty(t, report.Leaving)
+
+	consensus := setup.Participants.Filter(filter.HasRole(flow.RoleConsensus))
+	require.NotNil(t, report.Consensus)
+	assert.ElementsMatch(t, consensus.NodeIDs(), report.Consensus.Members)
+	assert.Equal(t, consensus.TotalWeight(), report.Consensus.TotalWeight)
+	var views uint64
+	for leaderID, count := range report.Consensus.LeaderViews {
+		assert.Contains(t, report.Consensus.Members, leaderID)
+		views += count
+	}
+	assert.Equal(t, uint64(1000), views)
+
+	require.Len(t, report.Clusters, 2)
+	for i, cluster := range report.Clusters {
+		assert.Equal(t, flow.IdentifierList(setup.Assignments[i]), cluster.Members)
+		assert.NotEmpty(t, cluster.LeaderViews)
+	}
+}
+
+// TestDryRunEpochSetup_LivenessWeighting tests that the leaders are selected like in the consensus
+// committee, weighted by the recorded QC participation if liveness weighting is enabled for the spork.
+func TestDryRunEpochSetup_LivenessWeighting(t *testing.T) {
+	snapshot, setup := dryRunFixture()
+	consensus := setup.Participants.Filter(filter.HasRole(flow.RoleConsensus))
+	offline := consensus[0].NodeID
+
+	// the setup was already emitted, and the first consensus node did not sign any QC of the setup phase
+	participation := flow.NewQCParticipation()
+	for i := 0; i < 100; i++ {
+		participation.Add(consensus[1:].NodeIDs())
+	}
+	weighting := flow.DefaultLivenessWeighting
+	enc := snapshot.Encodable()
+	enc.Params.LivenessWeighting = &weighting
+	enc.Phase = flow.EpochPhaseSetup
+	next := enc.Epochs.Current
+	next.Counter = setup.Counter
+	next.FirstView, next.FinalView = setup.FirstView, setup.FinalView
+	next.QCParticipation = participation
+	enc.Epochs.Next = &next
+	snapshot = inmem.SnapshotFromEncodable(enc)
+
+	report, err := dryRunEpochSetup(snapshot, setup, 0.2)
+	require.NoError(t, err)
+	require.NotNil(t, report.Consensus)
+
+	epoch, err := inmem.NewSetupEpoch(setup, participation)
+	require.NoError(t, err)
+	selection, err := leader.LivenessWeightedSelectionForConsensus(epoch, weighting)
+	require.NoError(t, err)
+	expected, err := countLeaderViews(selection, setup.FirstView, setup.FinalView-setup.FirstView+1)
+	require.NoError(t, err)
+	assert.Equal(t, expected, report.Consensus.LeaderViews)
+
+	for _, identity := range consensus[1:] {
+		assert.Less(t, report.Consensus.LeaderViews[offline], report.Consensus.LeaderViews[identity.NodeID])
+	}
+}
+
+// TestDryRunEpochSetup_Invalid tests that setups rejected by the protocol state are reported.
+func TestDryRunEpochSetup_Invalid(t *testing.T) {
+
+	t.Run("invalid counter", func(t *testing.T) {
+		snapshot, setup := dryRunFixture()
+		setup.Counter = 3
+
+		report, err := dryRunEpochSetup(snapshot, setup, 0.2)
+		require.NoError(t, err)
+		assert.True(t, report.Failed())
+		assert.Contains(t, report.Invalid, "invalid counter")
+	})
+
+	t.Run("next epoch already set up", func(t *testing.T) {
+		snapshot, setup := dryRunFixture()
+		enc := snapshot.Encodable()
+		enc.Phase = flow.EpochPhaseSetup
+		next := enc.Epochs.Current
+		next.Counter = 2
+		next.FirstView, next.FinalView = 1001, 2000
+		enc.Epochs.Next = &next
+		snapshot = inmem.SnapshotFromEncodable(enc)
+
+		report, err := dryRunEpochSetup(snapshot, setup, 0.2)
+		require.NoError(t, err)
+		assert.Contains(t, report.Invalid, "duplicate epoch setup")
+	})
+}
+
+// TestDryRunEpochSetup_Problems tests that valid setups resulting in problematic committees are reported.
+func TestDryRunEpochSetup_Problems(t *testing.T) {
+
+	t.Run("inconsistent dkg phases", func(t *testing.T) {
+		snapshot, setup := dryRunFixture()
+		setup.DKGPhase2FinalView = setup.DKGPhase3FinalView + 1
+
+		report, err := dryRunEpochSetup(snapshot, setup, 0.2)
+		require.NoError(t, err)
+		assert.Empty(t, report.Invalid)
+		require.Len(t, report.Problems, 1)
+		assert.Contains(t, report.Problems[0], "dkg phase views")
+	})
+
+	t.Run("empty cluster", func(t *testing.T) {
+		snapshot, setup := dryRunFixture()
+		collectors := setup.Participants.Filter(filter.HasRole(flow.RoleCollection)).NodeIDs()
+		setup.Assignments = flow.AssignmentList{collectors, {}}
+
+		report, err := dryRunEpochSetup(snapshot, setup, 0.2)
+		require.NoError(t, err)
+		assert.Empty(t, report.Invalid)
+		assert.Contains(t, report.Problems, "cluster 1 is empty")
+		require.Len(t, report.Clusters, 2)
+		assert.Empty(t, report.Clusters[1].Members)
+	})
+
+	t.Run("imbalanced clusters", func(t *testing.T) {
+		snapshot, setup := dryRunFixture()
+		collectors := setup.Participants.Filter(filter.HasRole(flow.RoleCollection)).NodeIDs()
+		setup.Assignments = flow.AssignmentList{collectors[:8], collectors[8:]}
+
+		report, err := dryRunEpochSetup(snapshot, setup, 0.2)
+		require.NoError(t, err)
+		assert.Empty(t, report.Invalid)
+		// both clusters deviate from the mean weight by 33%
+		assert.Len(t, report.Problems, 2)
+
+		report, err = dryRunEpochSetup(snapshot, setup, 0.5)
+		require.NoError(t, err)
+		assert.False(t, report.Failed())
+	})
+
+	t.Run("dominating consensus node", func(t *testing.T) {
+		snapshot, setup := dryRunFixture()
+		participants := setup.Participants.Copy()
+		dominating := participants.Filter(filter.HasRole(flow.RoleConsensus))[0]
+		dominating.Weight = 3 * flow.DefaultInitialWeight
+		setup.Participants = participants
+
+		report, err := dryRunEpochSetup(snapshot, setup, 0.2)
+		require.NoError(t, err)
+		assert.Empty(t, report.Invalid)
+		require.Len(t, report.Problems, 1)
+		assert.Contains(t, report.Problems[0], fmt.Sprintf("consensus committee member %x", dominating.NodeID))
+	})
+}
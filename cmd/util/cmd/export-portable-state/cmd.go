@@ -0,0 +1,104 @@
+package export_portable_state
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+var (
+	flagExecutionStateDir string
+	flagOutputDir         string
+	flagStateCommitment   string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "export-portable-state",
+	Short: "Exports the execution state at a state commitment into the portable, versioned export format",
+	Long: `Exports all registers of the execution state at a state commitment into the output directory.
+The export consists of a manifest.json file, which includes the format version and the root hash,
+and a registers.ndjson file with one register (path, owner, controller, key, value) per line.
+The export can be turned back into a checkpoint with the import-portable-state command.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where WAL logs are written)")
+	_ = Cmd.MarkFlagRequired("execution-state-dir")
+
+	Cmd.Flags().StringVar(&flagOutputDir, "output-dir", "",
+		"Directory to write the export to")
+	_ = Cmd.MarkFlagRequired("output-dir")
+
+	Cmd.Flags().StringVar(&flagStateCommitment, "state-commitment", "",
+		"State commitment (hex-encoded, 64 characters), the most recently touched state is exported if not set")
+}
+
+func run(*cobra.Command, []string) {
+	log.Info().Msg("start exporting ledger")
+
+	diskWal, err := wal.NewDiskWAL(
+		log.Logger,
+		nil,
+		metrics.NewNoopCollector(),
+		flagExecutionStateDir,
+		complete.DefaultCacheSize,
+		pathfinder.PathByteSize,
+		wal.SegmentSize,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create disk WAL")
+	}
+	defer func() {
+		<-diskWal.Done()
+	}()
+
+	led, err := complete.NewLedger(diskWal, complete.DefaultCacheSize, &metrics.NoopCollector{}, log.Logger, complete.DefaultPathFinderVersion)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create ledger from write-a-head logs and checkpoints")
+	}
+
+	state, err := targetState(led, flagStateCommitment)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid state commitment")
+	}
+
+	manifest, err := led.ExportPortableAt(state, flagOutputDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot export ledger")
+	}
+
+	log.Info().
+		Str("root_hash", manifest.RootHash).
+		Uint64("register_count", manifest.RegisterCount).
+		Msg("ledger exported")
+}
+
+func targetState(led *complete.Ledger, stateCommitment string) (ledger.State, error) {
+	if len(stateCommitment) == 0 {
+		state, err := led.MostRecentTouchedState()
+		if err != nil {
+			return ledger.DummyState, fmt.Errorf("failed to load most recently used state: %w", err)
+		}
+		return state, nil
+	}
+
+	st, err := hex.DecodeString(stateCommitment)
+	if err != nil {
+		return ledger.DummyState, fmt.Errorf("failed to decode hex code of state: %w", err)
+	}
+	state, err := ledger.ToState(st)
+	if err != nil {
+		return ledger.DummyState, fmt.Errorf("failed to convert bytes to state: %w", err)
+	}
+	return state, nil
+}
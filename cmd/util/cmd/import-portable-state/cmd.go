@@ -0,0 +1,69 @@
+package import_portable_state
+
+import (
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/ledger/complete/export"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/bootstrap"
+)
+
+var (
+	flagInputDir       string
+	flagOutputDir      string
+	flagCheckpointFile string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "import-portable-state",
+	Short: "Rebuilds the execution state from a portable export and writes it as a checkpoint",
+	Long: `Reads an export written by the export-portable-state command, rebuilds the trie from its registers
+and verifies that the root hash of the rebuilt trie matches the root hash in the manifest.
+The trie is then stored as a checkpoint, which can be used to bootstrap an execution node.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagInputDir, "input-dir", "",
+		"Directory containing the export")
+	_ = Cmd.MarkFlagRequired("input-dir")
+
+	Cmd.Flags().StringVar(&flagOutputDir, "output-dir", "",
+		"Directory to write the checkpoint to")
+	_ = Cmd.MarkFlagRequired("output-dir")
+
+	Cmd.Flags().StringVar(&flagCheckpointFile, "checkpoint-file", bootstrap.FilenameWALRootCheckpoint,
+		"name of the checkpoint file")
+}
+
+func run(*cobra.Command, []string) {
+	log.Info().Str("input_dir", flagInputDir).Msg("start importing ledger")
+
+	t, manifest, err := export.ImportTrie(flagInputDir)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot import ledger")
+	}
+
+	log.Info().
+		Str("root_hash", manifest.RootHash).
+		Uint64("register_count", manifest.RegisterCount).
+		Msg("ledger imported and root hash verified, storing checkpoint")
+
+	writer, err := wal.CreateCheckpointWriterForFile(flagOutputDir, flagCheckpointFile)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to create a checkpoint writer")
+	}
+
+	err = wal.StoreCheckpoint(writer, t)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to store the checkpoint")
+	}
+
+	err = writer.Close()
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to close the checkpoint")
+	}
+
+	log.Info().Msgf("checkpoint file successfully stored at: %v %v", flagOutputDir, flagCheckpointFile)
+}
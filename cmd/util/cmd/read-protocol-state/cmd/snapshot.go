@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/utils/logging"
+)
+
+var (
+	flagSnapshotHeight uint64
+	flagSnapshotSealed bool
+	flagSnapshotOutput string
+)
+
+var SnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Export the protocol state snapshot at a sealed height",
+	Long: `Export the protocol state snapshot at a sealed height, including the sealing segment and epoch data.
+The exported file can be used as root-protocol-state-snapshot.json to bootstrap a node from the chosen height.`,
+	Run: runSnapshot,
+}
+
+func init() {
+	rootCmd.AddCommand(SnapshotCmd)
+
+	SnapshotCmd.Flags().Uint64Var(&flagSnapshotHeight, "height", 0,
+		"Sealed block height to export the snapshot at")
+
+	SnapshotCmd.Flags().BoolVar(&flagSnapshotSealed, "sealed", false,
+		"export the snapshot at the latest sealed block")
+
+	SnapshotCmd.Flags().StringVar(&flagSnapshotOutput, "output", "",
+		"file to write the snapshot to, prints to stdout if not set")
+}
+
+func runSnapshot(*cobra.Command, []string) {
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+	state, err := common.InitProtocolState(db, storages)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not init protocol state")
+	}
+
+	height := flagSnapshotHeight
+	if flagSnapshotSealed {
+		sealed, err := state.Sealed().Head()
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not get sealed block")
+		}
+		height = sealed.Height
+	} else if height == 0 {
+		log.Fatal().Msg("missing flag, try --height or --sealed")
+	}
+
+	snapshot, err := protocol.SnapshotAtSealedHeight(state, height)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("could not get snapshot at height %d", height)
+	}
+
+	head, err := snapshot.Head()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get snapshot head")
+	}
+	counter, err := snapshot.Epochs().Current().Counter()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get current epoch counter")
+	}
+	phase, err := snapshot.Phase()
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get epoch phase")
+	}
+
+	bytes, err := convert.SnapshotToBytes(snapshot)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not encode snapshot")
+	}
+
+	log.Info().
+		Uint64("height", head.Height).
+		Hex("block_id", logging.ID(head.ID())).
+		Uint64("epoch_counter", counter).
+		Str("epoch_phase", phase.String()).
+		Msg("exporting protocol state snapshot")
+
+	if flagSnapshotOutput == "" {
+		fmt.Println(string(bytes))
+		return
+	}
+	err = os.WriteFile(flagSnapshotOutput, bytes, 0644)
+	if err != nil {
+		log.Fatal().Err(err).Msgf("could not write snapshot to %s", flagSnapshotOutput)
+	}
+}
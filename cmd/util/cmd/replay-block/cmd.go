@@ -0,0 +1,156 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/cmd/util/cmd/common"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/wal"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+)
+
+var (
+	flagExecutionStateDir string
+	flagDatadir           string
+	flagChain             string
+	flagBlockID           string
+	flagHeight            uint64
+	flagTrieCacheSize     int
+)
+
+var Cmd = &cobra.Command{
+	Use:   "replay-block",
+	Short: "Re-executes a sealed block and compares the outcome against the stored execution result",
+	Long: `Re-executes a sealed block on top of the execution state restored from checkpoint and WAL,
+and compares the end state, event hash and transaction results of every chunk against the stored
+execution result, reporting the first divergent transaction.
+
+The WAL is opened with recording paused, so the execution state dir is not modified. It is still
+recommended to run this command against a copy of the execution node's data.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagExecutionStateDir, "execution-state-dir", "",
+		"Execution Node state dir (where WAL logs are written)")
+	_ = Cmd.MarkFlagRequired("execution-state-dir")
+
+	Cmd.Flags().StringVar(&flagDatadir, "datadir", "",
+		"directory that stores the protocol state")
+	_ = Cmd.MarkFlagRequired("datadir")
+
+	Cmd.Flags().StringVar(&flagChain, "chain", "", "Chain name")
+	_ = Cmd.MarkFlagRequired("chain")
+
+	Cmd.Flags().StringVar(&flagBlockID, "block-id", "",
+		"ID of the block to re-execute (hex-encoded, 64 characters)")
+
+	Cmd.Flags().Uint64Var(&flagHeight, "height", 0,
+		"height of the block to re-execute")
+
+	Cmd.Flags().IntVar(&flagTrieCacheSize, "trie-cache-size", complete.DefaultCacheSize,
+		"number of tries restored from the WAL, must be large enough to include the parent state of the block")
+}
+
+func getChain(chainName string) (chain flow.Chain, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("invalid chain: %s", r)
+		}
+	}()
+	chain = flow.ChainID(chainName).Chain()
+	return
+}
+
+func run(cmd *cobra.Command, _ []string) {
+	chain, err := getChain(flagChain)
+	if err != nil {
+		log.Fatal().Err(err).Msg("invalid chain name")
+	}
+
+	db := common.InitStorage(flagDatadir)
+	defer db.Close()
+
+	storages := common.InitStorages(db)
+
+	heightSet := cmd.Flags().Changed("height")
+	if heightSet == (len(flagBlockID) > 0) {
+		log.Fatal().Msg("exactly one of --block-id and --height must be given")
+	}
+
+	var blockID flow.Identifier
+	if len(flagBlockID) > 0 {
+		blockID, err = flow.HexStringToIdentifier(flagBlockID)
+		if err != nil {
+			log.Fatal().Err(err).Msg("malformed block id")
+		}
+	} else {
+		header, err := storages.Headers.ByHeight(flagHeight)
+		if err != nil {
+			log.Fatal().Err(err).Uint64("height", flagHeight).Msg("could not find finalized block at height")
+		}
+		blockID = header.ID()
+	}
+
+	diskWal, err := wal.NewDiskWAL(
+		log.Logger,
+		nil,
+		metrics.NewNoopCollector(),
+		flagExecutionStateDir,
+		flagTrieCacheSize,
+		pathfinder.PathByteSize,
+		wal.SegmentSize,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create disk WAL")
+	}
+	defer func() {
+		<-diskWal.Done()
+	}()
+
+	led, err := complete.NewLedger(
+		diskWal,
+		flagTrieCacheSize,
+		&metrics.NoopCollector{},
+		log.Logger,
+		complete.DefaultPathFinderVersion,
+	)
+	if err != nil {
+		log.Fatal().Err(err).Msg("cannot create ledger from write-a-head logs and checkpoints")
+	}
+
+	// the re-executed updates and any evicted tries must not be recorded in the node's WAL
+	diskWal.PauseRecord()
+
+	replayer := &blockReplayer{
+		log:      log.Logger,
+		db:       db,
+		storages: storages,
+		ledger:   led,
+		vmCtx:    fvm.NewContext(log.Logger, fvm.NodeOptions(chain.ChainID(), storages.Headers)...),
+	}
+
+	divergence, err := replayer.replay(blockID)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not replay block")
+	}
+
+	if divergence != nil {
+		log.Error().
+			Hex("block_id", blockID[:]).
+			Int("chunk_index", divergence.ChunkIndex).
+			Int("tx_index", divergence.TransactionIndex).
+			Hex("tx_id", divergence.TransactionID[:]).
+			Str("reason", divergence.Reason).
+			Msg("re-executed block diverges from stored execution result")
+		return
+	}
+
+	log.Info().Hex("block_id", blockID[:]).Msg("re-executed block matches stored execution result")
+}
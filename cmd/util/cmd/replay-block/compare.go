@@ -0,0 +1,148 @@
+package replay
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// Divergence describes the first difference found between a re-executed block and
+// the execution result stored for it.
+type Divergence struct {
+	ChunkIndex       int
+	TransactionIndex int // -1 if the divergence cannot be attributed to a single transaction
+	TransactionID    flow.Identifier
+	Reason           string
+}
+
+func (d *Divergence) String() string {
+	if d.TransactionIndex < 0 {
+		return fmt.Sprintf("chunk %d: %s", d.ChunkIndex, d.Reason)
+	}
+	return fmt.Sprintf("chunk %d, transaction %d (%v): %s", d.ChunkIndex, d.TransactionIndex, d.TransactionID, d.Reason)
+}
+
+// compareResults compares the result of re-executing a block against the stored execution result,
+// the stored transaction results (in execution order) and the stored events (in execution order).
+// It walks the chunks in order and, inside every chunk, the transactions in order, so the returned
+// divergence is the first one that occurred during execution. It returns nil if the results match.
+func compareResults(
+	expected *flow.ExecutionResult,
+	expectedTxResults []flow.TransactionResult,
+	expectedEvents flow.EventsList,
+	computed *execution.ComputationResult,
+) *Divergence {
+
+	if len(expected.Chunks) != len(computed.StateCommitments) {
+		return &Divergence{
+			ChunkIndex:       0,
+			TransactionIndex: -1,
+			Reason: fmt.Sprintf("number of chunks differs (expected: %d, computed: %d)",
+				len(expected.Chunks), len(computed.StateCommitments)),
+		}
+	}
+
+	if len(expectedTxResults) != len(computed.TransactionResults) {
+		return &Divergence{
+			ChunkIndex:       0,
+			TransactionIndex: -1,
+			Reason: fmt.Sprintf("number of transactions differs (expected: %d, computed: %d)",
+				len(expectedTxResults), len(computed.TransactionResults)),
+		}
+	}
+
+	expectedEventsByTx := eventsByTransactionIndex(expectedEvents)
+
+	txIndex := 0
+	for chunkIndex, chunk := range expected.Chunks {
+		computedEventsByTx := eventsByTransactionIndex(computed.Events[chunkIndex])
+
+		for i := uint64(0); i < chunk.NumberOfTransactions; i++ {
+			if txIndex >= len(computed.TransactionResults) {
+				return &Divergence{
+					ChunkIndex:       chunkIndex,
+					TransactionIndex: -1,
+					Reason:           "chunk contains more transactions than were executed",
+				}
+			}
+
+			reason := compareTransaction(
+				expectedTxResults[txIndex],
+				computed.TransactionResults[txIndex],
+				expectedEventsByTx[uint32(txIndex)],
+				computedEventsByTx[uint32(txIndex)],
+			)
+			if reason != "" {
+				return &Divergence{
+					ChunkIndex:       chunkIndex,
+					TransactionIndex: txIndex,
+					TransactionID:    computed.TransactionResults[txIndex].TransactionID,
+					Reason:           reason,
+				}
+			}
+			txIndex++
+		}
+
+		if chunk.EventCollection != computed.EventsHashes[chunkIndex] {
+			return &Divergence{
+				ChunkIndex:       chunkIndex,
+				TransactionIndex: -1,
+				Reason: fmt.Sprintf("event collection hash differs (expected: %v, computed: %v)",
+					chunk.EventCollection, computed.EventsHashes[chunkIndex]),
+			}
+		}
+
+		if chunk.EndState != computed.StateCommitments[chunkIndex] {
+			return &Divergence{
+				ChunkIndex:       chunkIndex,
+				TransactionIndex: -1,
+				Reason: fmt.Sprintf("end state differs (expected: %x, computed: %x)",
+					chunk.EndState, computed.StateCommitments[chunkIndex]),
+			}
+		}
+	}
+
+	return nil
+}
+
+// compareTransaction returns a description of the difference between the expected and
+// computed outcome of a single transaction, or an empty string if they match.
+func compareTransaction(
+	expected flow.TransactionResult,
+	computed flow.TransactionResult,
+	expectedEvents flow.EventsList,
+	computedEvents flow.EventsList,
+) string {
+	if expected.TransactionID != computed.TransactionID {
+		return fmt.Sprintf("transaction ID differs (expected: %v, computed: %v)",
+			expected.TransactionID, computed.TransactionID)
+	}
+	if expected.ErrorMessage != computed.ErrorMessage {
+		return fmt.Sprintf("error message differs (expected: %q, computed: %q)",
+			expected.ErrorMessage, computed.ErrorMessage)
+	}
+	if expected.ComputationUsed != computed.ComputationUsed {
+		return fmt.Sprintf("computation used differs (expected: %d, computed: %d)",
+			expected.ComputationUsed, computed.ComputationUsed)
+	}
+	if len(expectedEvents) != len(computedEvents) {
+		return fmt.Sprintf("number of events differs (expected: %d, computed: %d)",
+			len(expectedEvents), len(computedEvents))
+	}
+	for i := range expectedEvents {
+		if expectedEvents[i].Checksum() != computedEvents[i].Checksum() {
+			return fmt.Sprintf("event %d differs (expected: %s, computed: %s)",
+				i, expectedEvents[i].String(), computedEvents[i].String())
+		}
+	}
+	return ""
+}
+
+func eventsByTransactionIndex(events flow.EventsList) map[uint32]flow.EventsList {
+	byTx := make(map[uint32]flow.EventsList)
+	for _, event := range events {
+		byTx[event.TransactionIndex] = append(byTx[event.TransactionIndex], event)
+	}
+	return byTx
+}
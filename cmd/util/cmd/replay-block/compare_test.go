@@ -0,0 +1,97 @@
+package replay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// matchingResults returns an execution result with two chunks of two transactions each,
+// the transaction results and events stored for it, and a computation result matching them.
+func matchingResults() (*flow.ExecutionResult, []flow.TransactionResult, flow.EventsList, *execution.ComputationResult) {
+	blockID := unittest.IdentifierFixture()
+	expected := unittest.ExecutionResultFixture()
+	expected.Chunks = unittest.ChunkListFixture(2, blockID)
+
+	computed := &execution.ComputationResult{
+		Events: make([]flow.EventsList, 2),
+	}
+	var txResults []flow.TransactionResult
+	var events flow.EventsList
+
+	txIndex := uint32(0)
+	for i, chunk := range expected.Chunks {
+		chunk.NumberOfTransactions = 2
+		for j := 0; j < 2; j++ {
+			txResult := flow.TransactionResult{
+				TransactionID:   unittest.IdentifierFixture(),
+				ComputationUsed: 10,
+			}
+			event := unittest.EventFixture(flow.EventAccountCreated, txIndex, 0, txResult.TransactionID, 0)
+
+			txResults = append(txResults, txResult)
+			events = append(events, event)
+			computed.TransactionResults = append(computed.TransactionResults, txResult)
+			computed.Events[i] = append(computed.Events[i], event)
+			txIndex++
+		}
+		computed.EventsHashes = append(computed.EventsHashes, chunk.EventCollection)
+		computed.StateCommitments = append(computed.StateCommitments, chunk.EndState)
+	}
+
+	return expected, txResults, events, computed
+}
+
+func TestCompareResults(t *testing.T) {
+
+	t.Run("matching results", func(t *testing.T) {
+		expected, txResults, events, computed := matchingResults()
+
+		require.Nil(t, compareResults(expected, txResults, events, computed))
+	})
+
+	t.Run("divergent transaction result", func(t *testing.T) {
+		expected, txResults, events, computed := matchingResults()
+		computed.TransactionResults[3].ErrorMessage = "failed"
+
+		divergence := compareResults(expected, txResults, events, computed)
+		require.NotNil(t, divergence)
+		require.Equal(t, 1, divergence.ChunkIndex)
+		require.Equal(t, 3, divergence.TransactionIndex)
+		require.Equal(t, txResults[3].TransactionID, divergence.TransactionID)
+	})
+
+	t.Run("divergent events report the first transaction", func(t *testing.T) {
+		expected, txResults, events, computed := matchingResults()
+		computed.Events[0][1].Payload = []byte("different")
+		computed.Events[1][0].Payload = []byte("different")
+
+		divergence := compareResults(expected, txResults, events, computed)
+		require.NotNil(t, divergence)
+		require.Equal(t, 0, divergence.ChunkIndex)
+		require.Equal(t, 1, divergence.TransactionIndex)
+	})
+
+	t.Run("divergent end state", func(t *testing.T) {
+		expected, txResults, events, computed := matchingResults()
+		computed.StateCommitments[1] = unittest.StateCommitmentFixture()
+
+		divergence := compareResults(expected, txResults, events, computed)
+		require.NotNil(t, divergence)
+		require.Equal(t, 1, divergence.ChunkIndex)
+		require.Equal(t, -1, divergence.TransactionIndex)
+	})
+
+	t.Run("different number of chunks", func(t *testing.T) {
+		expected, txResults, events, computed := matchingResults()
+		computed.StateCommitments = computed.StateCommitments[:1]
+
+		divergence := compareResults(expected, txResults, events, computed)
+		require.NotNil(t, divergence)
+		require.Equal(t, -1, divergence.TransactionIndex)
+	})
+}
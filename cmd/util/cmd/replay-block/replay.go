@@ -0,0 +1,139 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine/execution"
+	"github.com/onflow/flow-go/engine/execution/computation/committer"
+	"github.com/onflow/flow-go/engine/execution/computation/computer"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/engine/execution/state/delta"
+	"github.com/onflow/flow-go/fvm"
+	"github.com/onflow/flow-go/fvm/programs"
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/mempool/entity"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/module/trace"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// blockReplayer re-executes historic blocks against a ledger restored from checkpoint and WAL.
+type blockReplayer struct {
+	log      zerolog.Logger
+	db       *badger.DB
+	storages *storage.All
+	ledger   ledger.Ledger
+	vmCtx    fvm.Context
+}
+
+// replay re-executes the given sealed block and compares the outcome against the execution
+// result, transaction results and events stored for it.
+// It returns the first divergence found, or nil if the re-execution matches the stored data.
+func (r *blockReplayer) replay(blockID flow.Identifier) (*Divergence, error) {
+	block, err := r.storages.Blocks.ByID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve block %v: %w", blockID, err)
+	}
+
+	var sealedHeight uint64
+	err = r.db.View(operation.RetrieveSealedHeight(&sealedHeight))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve sealed height: %w", err)
+	}
+	if block.Header.Height > sealedHeight {
+		return nil, fmt.Errorf("block %v at height %d is not sealed (sealed height: %d)",
+			blockID, block.Header.Height, sealedHeight)
+	}
+
+	startState, err := r.storages.Commits.ByBlockID(block.Header.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve start state of block %v: %w", blockID, err)
+	}
+
+	executableBlock, err := r.executableBlock(block, startState)
+	if err != nil {
+		return nil, err
+	}
+
+	expected, err := r.storages.Results.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve execution result for block %v: %w", blockID, err)
+	}
+
+	computed, err := r.execute(executableBlock, startState)
+	if err != nil {
+		return nil, fmt.Errorf("could not re-execute block %v: %w", blockID, err)
+	}
+
+	expectedTxResults := make([]flow.TransactionResult, 0, len(computed.TransactionResults))
+	for _, txResult := range computed.TransactionResults {
+		stored, err := r.storages.TransactionResults.ByBlockIDTransactionID(blockID, txResult.TransactionID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve stored result of transaction %v: %w", txResult.TransactionID, err)
+		}
+		expectedTxResults = append(expectedTxResults, *stored)
+	}
+
+	expectedEvents, err := r.storages.Events.ByBlockID(blockID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve stored events for block %v: %w", blockID, err)
+	}
+
+	r.log.Info().
+		Hex("block_id", blockID[:]).
+		Uint64("height", block.Header.Height).
+		Int("chunks", len(computed.StateCommitments)).
+		Int("transactions", len(computed.TransactionResults)).
+		Msg("block re-executed, comparing results")
+
+	return compareResults(expected, expectedTxResults, expectedEvents, computed), nil
+}
+
+// executableBlock reconstructs the complete collections of the block from the protocol database.
+func (r *blockReplayer) executableBlock(block *flow.Block, startState flow.StateCommitment) (*entity.ExecutableBlock, error) {
+	collections := make(map[flow.Identifier]*entity.CompleteCollection, len(block.Payload.Guarantees))
+	for _, guarantee := range block.Payload.Guarantees {
+		collection, err := r.storages.Collections.ByID(guarantee.CollectionID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve collection %v: %w", guarantee.CollectionID, err)
+		}
+		collections[guarantee.CollectionID] = &entity.CompleteCollection{
+			Guarantee:    guarantee,
+			Transactions: collection.Transactions,
+		}
+	}
+
+	return &entity.ExecutableBlock{
+		Block:               block,
+		CompleteCollections: collections,
+		StartState:          &startState,
+	}, nil
+}
+
+// execute runs the block through the same block computer used by execution nodes.
+func (r *blockReplayer) execute(block *entity.ExecutableBlock, startState flow.StateCommitment) (*execution.ComputationResult, error) {
+	vm := fvm.NewVirtualMachine(fvm.NewInterpreterRuntime())
+	tracer := trace.NewNoopTracer()
+
+	blockComputer, err := computer.NewBlockComputer(
+		vm,
+		r.vmCtx,
+		&metrics.NoopCollector{},
+		tracer,
+		r.log,
+		committer.NewLedgerViewCommitter(r.ledger, tracer),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create block computer: %w", err)
+	}
+
+	view := delta.NewView(state.LedgerGetRegister(r.ledger, startState))
+
+	return blockComputer.ExecuteBlock(context.Background(), block, view, programs.NewEmptyPrograms())
+}
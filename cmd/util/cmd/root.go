@@ -9,14 +9,19 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	audit_chunk_assignment "github.com/onflow/flow-go/cmd/util/cmd/audit-chunk-assignment"
 	checkpoint_list_tries "github.com/onflow/flow-go/cmd/util/cmd/checkpoint-list-tries"
 	epochs "github.com/onflow/flow-go/cmd/util/cmd/epochs/cmd"
 	export "github.com/onflow/flow-go/cmd/util/cmd/exec-data-json-export"
 	edbs "github.com/onflow/flow-go/cmd/util/cmd/execution-data-blobstore/cmd"
 	extract "github.com/onflow/flow-go/cmd/util/cmd/execution-state-extract"
 	ledger_json_exporter "github.com/onflow/flow-go/cmd/util/cmd/export-json-execution-state"
+	export_portable_state "github.com/onflow/flow-go/cmd/util/cmd/export-portable-state"
+	import_portable_state "github.com/onflow/flow-go/cmd/util/cmd/import-portable-state"
 	read_badger "github.com/onflow/flow-go/cmd/util/cmd/read-badger/cmd"
 	read_protocol_state "github.com/onflow/flow-go/cmd/util/cmd/read-protocol-state/cmd"
+	replay_block "github.com/onflow/flow-go/cmd/util/cmd/replay-block"
+	simulate_topology "github.com/onflow/flow-go/cmd/util/cmd/simulate-topology"
 	truncate_database "github.com/onflow/flow-go/cmd/util/cmd/truncate-database"
 )
 
@@ -60,6 +65,11 @@ func addCommands() {
 	rootCmd.AddCommand(ledger_json_exporter.Cmd)
 	rootCmd.AddCommand(epochs.RootCmd)
 	rootCmd.AddCommand(edbs.RootCmd)
+	rootCmd.AddCommand(replay_block.Cmd)
+	rootCmd.AddCommand(export_portable_state.Cmd)
+	rootCmd.AddCommand(import_portable_state.Cmd)
+	rootCmd.AddCommand(audit_chunk_assignment.Cmd)
+	rootCmd.AddCommand(simulate_topology.Cmd)
 }
 
 func initConfig() {
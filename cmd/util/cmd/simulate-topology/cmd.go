@@ -0,0 +1,123 @@
+package simulate
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+
+	"github.com/onflow/flow-go/engine/common/rpc/convert"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/topology"
+	"github.com/onflow/flow-go/utils/io"
+)
+
+var (
+	flagSnapshotPath    string
+	flagTopology        string
+	flagEdgeProbability float64
+	flagFormat          string
+	flagChannel         string
+)
+
+var Cmd = &cobra.Command{
+	Use:   "simulate-topology",
+	Short: "Simulates the topology of all nodes of a network and reports the connectivity of each channel",
+	Long: `Generates the fanout of every node in the current epoch of the given protocol state snapshot, as each node
+would with the given topology, and analyzes the resulting graph among the subscribers of each channel.
+
+In JSON format, the report lists for each channel the number of connected components, the diameter, the
+minimum degree and the cut nodes, whose failure would partition the channel, together with the fanout of
+every node. In DOT format, the graph of the network, or of a single channel, is written for rendering with
+Graphviz. Exits with an error if any channel is not connected.`,
+	Run: run,
+}
+
+func init() {
+	Cmd.Flags().StringVar(&flagSnapshotPath, "snapshot", "",
+		"path to the JSON-encoded protocol state snapshot, e.g. the root protocol state snapshot")
+	_ = Cmd.MarkFlagRequired("snapshot")
+
+	Cmd.Flags().StringVar(&flagTopology, "topology", string(topology.TopicBased),
+		"name of the topology of the nodes")
+
+	Cmd.Flags().Float64Var(&flagEdgeProbability, "topology-edge-probability", topology.MaximumEdgeProbability,
+		"pairwise edge probability between nodes, used by the randomized topology")
+
+	Cmd.Flags().StringVar(&flagFormat, "format", "json",
+		"output format, either json or dot")
+
+	Cmd.Flags().StringVar(&flagChannel, "channel", "",
+		"channel whose subgraph is written in DOT format, the whole network if omitted")
+}
+
+func run(*cobra.Command, []string) {
+	if flagFormat != "json" && flagFormat != "dot" {
+		log.Fatal().Str("format", flagFormat).Msg("unknown output format, must be json or dot")
+	}
+
+	bz, err := io.ReadFile(flagSnapshotPath)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", flagSnapshotPath).Msg("could not read snapshot")
+	}
+	snapshot, err := convert.BytesToInmemSnapshot(bz)
+	if err != nil {
+		log.Fatal().Err(err).Str("path", flagSnapshotPath).Msg("could not decode snapshot")
+	}
+
+	factory, err := topology.Factory(topology.Name(flagTopology))
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not get topology factory")
+	}
+
+	simulation, err := Simulate(snapshot, factory, flagEdgeProbability)
+	if err != nil {
+		log.Fatal().Err(err).Msg("could not simulate topology")
+	}
+	report := simulation.Report()
+
+	switch flagFormat {
+	case "json":
+		encoded, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not encode report")
+		}
+		_, err = os.Stdout.Write(encoded)
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not write report")
+		}
+	case "dot":
+		err = simulation.WriteDOT(os.Stdout, network.Channel(flagChannel))
+		if err != nil {
+			log.Fatal().Err(err).Msg("could not write graph")
+		}
+	}
+
+	disconnected := 0
+	for _, channel := range report.Channels {
+		if !channel.Connected() {
+			log.Error().
+				Str("channel", channel.Channel.String()).
+				Int("components", channel.Components).
+				Msg("channel is partitioned")
+			disconnected++
+			continue
+		}
+		if len(channel.CutNodes) > 0 {
+			log.Warn().
+				Str("channel", channel.Channel.String()).
+				Strs("cut_nodes", channel.CutNodes.Strings()).
+				Msg("failure of a single node partitions channel")
+		}
+	}
+
+	if disconnected > 0 {
+		log.Fatal().Int("disconnected_channels", disconnected).Msg("topology simulation found partitioned channels")
+	}
+	log.Info().
+		Int("nodes", report.Nodes).
+		Int("edges", report.Edges).
+		Int("channels", len(report.Channels)).
+		Msg("topology simulation finished")
+}
@@ -0,0 +1,353 @@
+package simulate
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/topology"
+	"github.com/onflow/flow-go/state/cluster"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+// snapshotState is a protocol state whose finalized state is the given snapshot. The topologies only read the
+// finalized state, any other method of the protocol state panics.
+type snapshotState struct {
+	protocol.State
+	snapshot protocol.Snapshot
+}
+
+func (s snapshotState) Final() protocol.Snapshot {
+	return s.snapshot
+}
+
+// Simulation is the graph of the network resulting from every node connecting to the fanout generated by its
+// topology. As connections are bidirectional, the graph is undirected.
+type Simulation struct {
+	nodes       flow.IdentityList
+	subscribers map[network.Channel]flow.IdentityList
+	fanouts     map[flow.Identifier]flow.IdentityList
+	edges       map[flow.Identifier]map[flow.Identifier]struct{}
+}
+
+// Simulate generates the fanout of every node of the network in the current epoch of the given snapshot, using the
+// topology created by the given factory for each node, and with the channels each node subscribes to.
+func Simulate(snapshot protocol.Snapshot, factory topology.FactoryFunction, edgeProbability float64) (*Simulation, error) {
+	nodes, err := snapshot.Identities(p2p.NotEjectedFilter)
+	if err != nil {
+		return nil, fmt.Errorf("could not get identities: %w", err)
+	}
+	nodes = nodes.Sort(order.Canonical)
+
+	channels, err := subscribedChannels(snapshot, nodes)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Simulation{
+		nodes:       nodes,
+		subscribers: make(map[network.Channel]flow.IdentityList),
+		fanouts:     make(map[flow.Identifier]flow.IdentityList, len(nodes)),
+		edges:       make(map[flow.Identifier]map[flow.Identifier]struct{}, len(nodes)),
+	}
+	for _, node := range nodes {
+		s.edges[node.NodeID] = make(map[flow.Identifier]struct{})
+		for _, channel := range channels[node.NodeID] {
+			s.subscribers[channel] = append(s.subscribers[channel], node)
+		}
+	}
+
+	state := snapshotState{snapshot: snapshot}
+	for _, node := range nodes {
+		top, err := factory(node.NodeID, zerolog.Nop(), state, edgeProbability)
+		if err != nil {
+			return nil, fmt.Errorf("could not create topology of node %v: %w", node.NodeID, err)
+		}
+		fanout, err := top.GenerateFanout(nodes, channels[node.NodeID])
+		if err != nil {
+			return nil, fmt.Errorf("could not generate fanout of node %v: %w", node.NodeID, err)
+		}
+		s.fanouts[node.NodeID] = fanout.Sort(order.Canonical)
+		for _, peer := range fanout {
+			if peer.NodeID == node.NodeID {
+				continue
+			}
+			s.edges[node.NodeID][peer.NodeID] = struct{}{}
+			s.edges[peer.NodeID][node.NodeID] = struct{}{}
+		}
+	}
+
+	return s, nil
+}
+
+// subscribedChannels returns the channels each of the given nodes subscribes to, which are the channels of its
+// role, and the channels of its cluster for collection nodes.
+func subscribedChannels(snapshot protocol.Snapshot, nodes flow.IdentityList) (map[flow.Identifier]network.ChannelList, error) {
+	epoch := snapshot.Epochs().Current()
+	counter, err := epoch.Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch counter: %w", err)
+	}
+	clusters, err := epoch.Clustering()
+	if err != nil {
+		return nil, fmt.Errorf("could not get clustering: %w", err)
+	}
+
+	channels := make(map[flow.Identifier]network.ChannelList, len(nodes))
+	for _, node := range nodes {
+		nodeChannels := engine.ChannelsByRole(node.Role)
+		if node.Role == flow.RoleCollection {
+			members, _, ok := clusters.ByNodeID(node.NodeID)
+			if !ok {
+				return nil, fmt.Errorf("collection node %v is not assigned to a cluster", node.NodeID)
+			}
+			chainID := cluster.CanonicalClusterID(counter, members)
+			nodeChannels = append(nodeChannels, engine.ChannelConsensusCluster(chainID), engine.ChannelSyncCluster(chainID))
+		}
+		sort.Sort(nodeChannels)
+		channels[node.NodeID] = nodeChannels
+	}
+	return channels, nil
+}
+
+// ChannelReport describes the connectivity of the subgraph of the network among the subscribers of a channel,
+// which is the graph messages on the channel are disseminated through.
+type ChannelReport struct {
+	Channel     network.Channel     `json:"channel"`
+	Subscribers int                 `json:"subscribers"`
+	Edges       int                 `json:"edges"`
+	Components  int                 `json:"components"` // number of connected components, 1 if the channel is connected
+	Diameter    int                 `json:"diameter"`   // longest shortest path between any two subscribers, -1 if not connected
+	MinDegree   int                 `json:"min_degree"`
+	CutNodes    flow.IdentifierList `json:"cut_nodes"` // subscribers whose failure partitions the channel
+}
+
+// Connected returns whether all subscribers of the channel are connected.
+func (r *ChannelReport) Connected() bool {
+	return r.Components <= 1
+}
+
+// Report is the outcome of a topology simulation.
+type Report struct {
+	Nodes    int                                     `json:"nodes"`
+	Edges    int                                     `json:"edges"`
+	Channels []*ChannelReport                        `json:"channels"`
+	Fanouts  map[flow.Identifier]flow.IdentifierList `json:"fanouts"`
+}
+
+// Report analyzes the connectivity of every channel of the simulated network.
+func (s *Simulation) Report() *Report {
+	report := &Report{
+		Nodes:   len(s.nodes),
+		Fanouts: make(map[flow.Identifier]flow.IdentifierList, len(s.fanouts)),
+	}
+	for nodeID, fanout := range s.fanouts {
+		report.Fanouts[nodeID] = fanout.NodeIDs()
+	}
+	for _, neighbors := range s.edges {
+		report.Edges += len(neighbors)
+	}
+	report.Edges /= 2
+
+	for _, channel := range s.Channels() {
+		report.Channels = append(report.Channels, s.analyze(channel, s.subscribers[channel]))
+	}
+	return report
+}
+
+// Channels returns the channels of the simulated network.
+func (s *Simulation) Channels() network.ChannelList {
+	channels := make(network.ChannelList, 0, len(s.subscribers))
+	for channel := range s.subscribers {
+		channels = append(channels, channel)
+	}
+	sort.Sort(channels)
+	return channels
+}
+
+// subgraph returns the adjacency lists of the subgraph among the given nodes.
+func (s *Simulation) subgraph(nodes flow.IdentityList) map[flow.Identifier]flow.IdentifierList {
+	members := make(map[flow.Identifier]struct{}, len(nodes))
+	for _, node := range nodes {
+		members[node.NodeID] = struct{}{}
+	}
+	adjacency := make(map[flow.Identifier]flow.IdentifierList, len(nodes))
+	for _, node := range nodes {
+		neighbors := flow.IdentifierList{}
+		for neighbor := range s.edges[node.NodeID] {
+			if _, ok := members[neighbor]; ok {
+				neighbors = append(neighbors, neighbor)
+			}
+		}
+		adjacency[node.NodeID] = neighbors
+	}
+	return adjacency
+}
+
+// analyze computes the connectivity of the subgraph among the subscribers of the given channel.
+func (s *Simulation) analyze(channel network.Channel, subscribers flow.IdentityList) *ChannelReport {
+	adjacency := s.subgraph(subscribers)
+	report := &ChannelReport{
+		Channel:     channel,
+		Subscribers: len(subscribers),
+		CutNodes:    flow.IdentifierList{},
+	}
+	if len(subscribers) == 0 {
+		return report
+	}
+
+	report.MinDegree = len(subscribers)
+	for _, neighbors := range adjacency {
+		report.Edges += len(neighbors)
+		if len(neighbors) < report.MinDegree {
+			report.MinDegree = len(neighbors)
+		}
+	}
+	report.Edges /= 2
+
+	// the eccentricity of each node is the distance to the farthest node reachable from it
+	component := make(map[flow.Identifier]int, len(subscribers))
+	for _, node := range subscribers {
+		distances := bfs(adjacency, node.NodeID)
+		if _, ok := component[node.NodeID]; !ok {
+			report.Components++
+			for reached := range distances {
+				component[reached] = report.Components
+			}
+		}
+		for _, distance := range distances {
+			if distance > report.Diameter {
+				report.Diameter = distance
+			}
+		}
+	}
+	if !report.Connected() {
+		report.Diameter = -1
+	}
+
+	report.CutNodes = cutNodes(adjacency, subscribers.NodeIDs())
+	return report
+}
+
+// bfs returns the distances of all nodes reachable from the given node.
+func bfs(adjacency map[flow.Identifier]flow.IdentifierList, from flow.Identifier) map[flow.Identifier]int {
+	distances := map[flow.Identifier]int{from: 0}
+	queue := flow.IdentifierList{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, neighbor := range adjacency[current] {
+			if _, ok := distances[neighbor]; ok {
+				continue
+			}
+			distances[neighbor] = distances[current] + 1
+			queue = append(queue, neighbor)
+		}
+	}
+	return distances
+}
+
+// cutNodes returns the articulation points of the graph, i.e. the nodes whose removal increases the number of
+// connected components, in the order of the given nodes.
+func cutNodes(adjacency map[flow.Identifier]flow.IdentifierList, nodes flow.IdentifierList) flow.IdentifierList {
+	discovered := make(map[flow.Identifier]int, len(nodes))
+	low := make(map[flow.Identifier]int, len(nodes))
+	cut := make(map[flow.Identifier]struct{})
+	time := 0
+
+	var visit func(node flow.Identifier, parent *flow.Identifier)
+	visit = func(node flow.Identifier, parent *flow.Identifier) {
+		time++
+		discovered[node] = time
+		low[node] = time
+		children := 0
+		for _, neighbor := range adjacency[node] {
+			if _, ok := discovered[neighbor]; !ok {
+				children++
+				visit(neighbor, &node)
+				if low[neighbor] < low[node] {
+					low[node] = low[neighbor]
+				}
+				if parent != nil && low[neighbor] >= discovered[node] {
+					cut[node] = struct{}{}
+				}
+			} else if parent == nil || neighbor != *parent {
+				if discovered[neighbor] < low[node] {
+					low[node] = discovered[neighbor]
+				}
+			}
+		}
+		if parent == nil && children > 1 {
+			cut[node] = struct{}{}
+		}
+	}
+
+	for _, node := range nodes {
+		if _, ok := discovered[node]; !ok {
+			visit(node, nil)
+		}
+	}
+
+	result := flow.IdentifierList{}
+	for _, node := range nodes {
+		if _, ok := cut[node]; ok {
+			result = append(result, node)
+		}
+	}
+	return result
+}
+
+// roleColors are the colors of the nodes of each role in DOT graphs.
+var roleColors = map[flow.Role]string{
+	flow.RoleCollection:   "lightblue",
+	flow.RoleConsensus:    "gold",
+	flow.RoleExecution:    "salmon",
+	flow.RoleVerification: "palegreen",
+	flow.RoleAccess:       "plum",
+}
+
+// WriteDOT writes the graph of the simulated network in the DOT language. If a channel is given, only the
+// subgraph among the subscribers of the channel is written.
+func (s *Simulation) WriteDOT(w io.Writer, channel network.Channel) error {
+	nodes := s.nodes
+	if channel != "" {
+		subscribers, ok := s.subscribers[channel]
+		if !ok {
+			return fmt.Errorf("no node subscribes to channel %s", channel)
+		}
+		nodes = subscribers
+	}
+
+	_, err := fmt.Fprintln(w, "graph topology {")
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		_, err = fmt.Fprintf(w, "  %q [label=%q, style=filled, fillcolor=%q];\n",
+			node.NodeID.String(), fmt.Sprintf("%s\n%s", node.Role, node.NodeID.String()[:8]), roleColors[node.Role])
+		if err != nil {
+			return err
+		}
+	}
+	// nodes are in canonical order, and each undirected edge is written once
+	for i, node := range nodes {
+		for _, neighbor := range nodes[i+1:] {
+			if _, ok := s.edges[node.NodeID][neighbor.NodeID]; !ok {
+				continue
+			}
+			_, err = fmt.Fprintf(w, "  %q -- %q;\n", node.NodeID.String(), neighbor.NodeID.String())
+			if err != nil {
+				return err
+			}
+		}
+	}
+	_, err = fmt.Fprintln(w, "}")
+	return err
+}
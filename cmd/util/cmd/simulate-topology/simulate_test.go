@@ -0,0 +1,91 @@
+package simulate
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/topology"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSimulate checks that the topic-based topology connects the subscribers of every channel.
+func TestSimulate(t *testing.T) {
+	participants := unittest.IdentityListFixture(40, unittest.WithAllRoles())
+	snapshot := unittest.RootSnapshotFixture(participants)
+
+	simulation, err := Simulate(snapshot, topology.TopicBasedTopologyFactory(), topology.MaximumEdgeProbability)
+	require.NoError(t, err)
+
+	report := simulation.Report()
+	assert.Equal(t, len(participants), report.Nodes)
+	assert.Len(t, report.Fanouts, len(participants))
+	require.NotEmpty(t, report.Channels)
+
+	clusterChannels := 0
+	for _, channel := range report.Channels {
+		assert.True(t, channel.Connected(), channel.Channel)
+		assert.GreaterOrEqual(t, channel.Diameter, 0, channel.Channel)
+		if engine.IsClusterChannel(channel.Channel) {
+			clusterChannels++
+		}
+	}
+	assert.Greater(t, clusterChannels, 0)
+
+	var dot bytes.Buffer
+	require.NoError(t, simulation.WriteDOT(&dot, ""))
+	assert.True(t, strings.HasPrefix(dot.String(), "graph topology {"))
+	assert.Equal(t, report.Edges, strings.Count(dot.String(), " -- "))
+
+	dot.Reset()
+	require.NoError(t, simulation.WriteDOT(&dot, engine.ConsensusCommittee))
+	consensusNodes := participants.Filter(func(identity *flow.Identity) bool { return identity.Role == flow.RoleConsensus })
+	assert.Equal(t, len(consensusNodes), strings.Count(dot.String(), "fillcolor"))
+
+	require.Error(t, simulation.WriteDOT(&dot, network.Channel("unknown")))
+}
+
+// TestAnalyze checks the connectivity analysis on a graph with known components, diameter and cut nodes.
+func TestAnalyze(t *testing.T) {
+	nodes := unittest.IdentityListFixture(6, unittest.WithRole(flow.RoleConsensus)).Sort(order.Canonical)
+	simulation := &Simulation{
+		nodes:       nodes,
+		subscribers: map[network.Channel]flow.IdentityList{engine.ConsensusCommittee: nodes},
+		edges:       make(map[flow.Identifier]map[flow.Identifier]struct{}),
+	}
+	for _, node := range nodes {
+		simulation.edges[node.NodeID] = make(map[flow.Identifier]struct{})
+	}
+	connect := func(a, b int) {
+		simulation.edges[nodes[a].NodeID][nodes[b].NodeID] = struct{}{}
+		simulation.edges[nodes[b].NodeID][nodes[a].NodeID] = struct{}{}
+	}
+
+	// a triangle 0-1-2 with a path 2-3-4 attached, and the isolated node 5
+	connect(0, 1)
+	connect(1, 2)
+	connect(2, 0)
+	connect(2, 3)
+	connect(3, 4)
+
+	report := simulation.analyze(engine.ConsensusCommittee, nodes)
+	assert.Equal(t, 2, report.Components)
+	assert.False(t, report.Connected())
+	assert.Equal(t, -1, report.Diameter)
+	assert.Equal(t, 0, report.MinDegree)
+	assert.Equal(t, 5, report.Edges)
+	assert.Equal(t, flow.IdentifierList{nodes[2].NodeID, nodes[3].NodeID}, report.CutNodes)
+
+	// without the isolated node, the graph is connected
+	report = simulation.analyze(engine.ConsensusCommittee, nodes[:5])
+	assert.True(t, report.Connected())
+	assert.Equal(t, 3, report.Diameter)
+	assert.Equal(t, 1, report.MinDegree)
+}
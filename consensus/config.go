@@ -5,6 +5,7 @@ import (
 
 	"github.com/onflow/flow-go/consensus/hotstuff"
 	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
 )
 
 // HotstuffModules is a helper structure to encapsulate dependencies to create
@@ -28,6 +29,9 @@ type ParticipantConfig struct {
 	TimeoutIncreaseFactor      float64       // the factor at which the timeout grows when timeouts occur
 	TimeoutDecreaseFactor      float64       // the factor at which the timeout grows when timeouts occur
 	BlockRateDelay             time.Duration // a delay to broadcast block proposal in order to control the block production rate
+
+	TimeoutConfigUpdate       timeout.ConfigUpdate      // changes of the timeout parameters made at runtime, applied on top of the above
+	TimeoutControllerConsumer func(*timeout.Controller) // receives the timeout controller, so its parameters can be changed at runtime
 }
 
 type Option func(*ParticipantConfig)
@@ -73,3 +77,19 @@ func WithBlockRateDelay(delay time.Duration) Option {
 		cfg.BlockRateDelay = delay
 	}
 }
+
+// WithTimeoutConfigUpdate applies changes of the timeout parameters which were made at runtime
+// and persisted, on top of the configured timeout parameters.
+func WithTimeoutConfigUpdate(update timeout.ConfigUpdate) Option {
+	return func(cfg *ParticipantConfig) {
+		cfg.TimeoutConfigUpdate = update
+	}
+}
+
+// WithTimeoutControllerConsumer registers a function which receives the timeout controller of
+// the participant once it is created, so the timeout parameters can be changed at runtime.
+func WithTimeoutControllerConsumer(consumer func(*timeout.Controller)) Option {
+	return func(cfg *ParticipantConfig) {
+		cfg.TimeoutControllerConsumer = consumer
+	}
+}
@@ -263,7 +263,7 @@ func (c *Consensus) prepareLeaderSelection(epoch protocol.Epoch) (*leader.Leader
 		return selection, nil
 	}
 
-	selection, err = leader.SelectionForConsensus(epoch)
+	selection, err = c.selectionForConsensus(epoch)
 	if err != nil {
 		return nil, fmt.Errorf("could not get leader selection for current epoch: %w", err)
 	}
@@ -289,3 +289,14 @@ func (c *Consensus) prepareLeaderSelection(epoch protocol.Epoch) (*leader.Leader
 
 	return selection, nil
 }
+
+// selectionForConsensus computes the leader selection for the given epoch. If
+// liveness-weighted leader selection is enabled for the spork, the selection is
+// weighted by the QC participation recorded for the epoch in the protocol state.
+func (c *Consensus) selectionForConsensus(epoch protocol.Epoch) (*leader.LeaderSelection, error) {
+	weighting, err := c.state.Params().LivenessWeighting()
+	if err != nil {
+		return nil, fmt.Errorf("could not get liveness weighting: %w", err)
+	}
+	return leader.SelectionForConsensusWithWeighting(epoch, weighting)
+}
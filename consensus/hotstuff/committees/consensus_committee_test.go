@@ -31,6 +31,7 @@ func TestConsensus_InvalidSigner(t *testing.T) {
 
 	state := new(protocolmock.State)
 	snapshot := new(protocolmock.Snapshot)
+	state.On("Params").Return(newMockParams(nil))
 
 	// create a mock epoch for leader selection setup in constructor
 	currEpoch := newMockEpoch(
@@ -98,6 +99,7 @@ func TestConsensus_LeaderForView(t *testing.T) {
 	// create mocks
 	state := new(protocolmock.State)
 	snapshot := new(protocolmock.Snapshot)
+	state.On("Params").Return(newMockParams(nil))
 
 	prevEpoch := newMockEpoch(
 		epochCounter-1,
@@ -218,6 +220,7 @@ func TestRemoveOldEpochs(t *testing.T) {
 	// create mocks
 	state := new(protocolmock.State)
 	snapshot := new(protocolmock.Snapshot)
+	state.On("Params").Return(newMockParams(nil))
 
 	state.On("Final").Return(snapshot)
 	epochQuery := mocks.NewEpochQuery(t, currentEpochCounter, epoch1)
@@ -267,6 +270,56 @@ func TestRemoveOldEpochs(t *testing.T) {
 	}
 }
 
+// TestConsensus_LivenessWeightedLeaderSelection tests that the leader selection is
+// weighted by the QC participation recorded for the epoch, if liveness-weighted
+// leader selection is enabled for the spork.
+func TestConsensus_LivenessWeightedLeaderSelection(t *testing.T) {
+
+	identities := unittest.IdentityListFixture(10, unittest.WithRole(flow.RoleConsensus))
+	me := identities[0].NodeID
+	offline := identities[9].NodeID
+
+	// the offline node did not sign any QC
+	participation := flow.NewQCParticipation()
+	for i := 0; i < 100; i++ {
+		participation.Add(identities[:9].NodeIDs())
+	}
+
+	epoch := new(protocolmock.Epoch)
+	epoch.On("Counter").Return(uint64(1), nil)
+	epoch.On("InitialIdentities").Return(identities, nil)
+	epoch.On("FirstView").Return(uint64(1), nil)
+	epoch.On("FinalView").Return(uint64(10000), nil)
+	epoch.On("DKG").Return(nil, nil)
+	epoch.On("RandomSource").Return(unittest.SeedFixture(seed.RandomSourceLength), nil)
+	epoch.On("QCParticipation").Return(participation, nil)
+
+	countOfflineLeaders := func(weighting *flow.LivenessWeighting) int {
+		state := new(protocolmock.State)
+		snapshot := new(protocolmock.Snapshot)
+		state.On("Params").Return(newMockParams(weighting))
+		state.On("Final").Return(snapshot)
+		snapshot.On("Epochs").Return(mocks.NewEpochQuery(t, 1, epoch))
+
+		committee, err := NewConsensusCommittee(state, me)
+		require.NoError(t, err)
+
+		count := 0
+		for view := uint64(1); view <= 10000; view++ {
+			leaderID, err := committee.LeaderForView(view)
+			require.NoError(t, err)
+			if leaderID == offline {
+				count++
+			}
+		}
+		return count
+	}
+
+	// the offline node holds 10% of the weight without, and 0.1/9.1 ≈ 1.1% with liveness weighting
+	assert.InDelta(t, 1000, countOfflineLeaders(nil), 150)
+	assert.InDelta(t, 110, countOfflineLeaders(&flow.DefaultLivenessWeighting), 60)
+}
+
 func newMockEpoch(
 	counter uint64,
 	identities flow.IdentityList,
@@ -284,5 +337,14 @@ func newMockEpoch(
 	epoch.On("DKG").Return(nil, nil)
 
 	epoch.On("RandomSource").Return(seed, nil)
+	epoch.On("QCParticipation").Return(nil, nil)
 	return epoch
 }
+
+// newMockParams returns global params with the given liveness weighting, nil
+// disables liveness-weighted leader selection.
+func newMockParams(weighting *flow.LivenessWeighting) *protocolmock.Params {
+	params := new(protocolmock.Params)
+	params.On("LivenessWeighting").Return(weighting, nil)
+	return params
+}
@@ -3,6 +3,8 @@ package leader
 import (
 	"fmt"
 
+	"github.com/onflow/flow-go/crypto/random"
+	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/flow/filter"
 	"github.com/onflow/flow-go/state/protocol"
 	"github.com/onflow/flow-go/state/protocol/seed"
@@ -13,6 +15,39 @@ import (
 // selection returned here is only valid for the input epoch, so it is necessary to
 // call this for each upcoming epoch.
 func SelectionForConsensus(epoch protocol.Epoch) (*LeaderSelection, error) {
+	return selectionForConsensus(epoch, ComputeLeaderSelection)
+}
+
+// LivenessWeightedSelectionForConsensus pre-computes and returns leaders for the consensus
+// committee in the given epoch, like SelectionForConsensus, but reduces the chance of nodes
+// to be selected as leader if they did not participate in the QCs recorded for the epoch.
+func LivenessWeightedSelectionForConsensus(epoch protocol.Epoch, weighting flow.LivenessWeighting) (*LeaderSelection, error) {
+	participation, err := epoch.QCParticipation()
+	if err != nil {
+		return nil, fmt.Errorf("could not get qc participation for epoch: %w", err)
+	}
+
+	return selectionForConsensus(epoch, func(firstView uint64, rng random.Rand, count int, identities flow.IdentityList) (*LeaderSelection, error) {
+		return ComputeLivenessWeightedLeaderSelection(firstView, rng, count, identities, participation, weighting)
+	})
+}
+
+// SelectionForConsensusWithWeighting pre-computes and returns leaders for the consensus
+// committee in the given epoch, using liveness-weighted leader selection if the given
+// weighting of the spork is not nil, and SelectionForConsensus otherwise.
+func SelectionForConsensusWithWeighting(epoch protocol.Epoch, weighting *flow.LivenessWeighting) (*LeaderSelection, error) {
+	if weighting == nil {
+		return SelectionForConsensus(epoch)
+	}
+	return LivenessWeightedSelectionForConsensus(epoch, *weighting)
+}
+
+// selectionForConsensus pre-computes leaders for the consensus committee in the given epoch
+// using the given selection function.
+func selectionForConsensus(
+	epoch protocol.Epoch,
+	compute func(firstView uint64, rng random.Rand, count int, identities flow.IdentityList) (*LeaderSelection, error),
+) (*LeaderSelection, error) {
 
 	// pre-compute leader selection for the epoch
 	identities, err := epoch.InitialIdentities()
@@ -39,7 +74,7 @@ func SelectionForConsensus(epoch protocol.Epoch) (*LeaderSelection, error) {
 		return nil, fmt.Errorf("could not get epoch final view: %w", err)
 	}
 
-	leaders, err := ComputeLeaderSelection(
+	leaders, err := compute(
 		firstView,
 		rng,
 		int(finalView-firstView+1), // add 1 because both first/final view are inclusive
@@ -96,15 +96,31 @@ func ComputeLeaderSelection(
 	identities flow.IdentityList,
 ) (*LeaderSelection, error) {
 
-	if count < 1 {
-		return nil, fmt.Errorf("number of views must be positive (got %d)", count)
-	}
-
 	weights := make([]uint64, 0, len(identities))
 	for _, id := range identities {
 		weights = append(weights, id.Weight)
 	}
 
+	return computeLeaderSelection(firstView, rng, count, identities, weights)
+}
+
+// computeLeaderSelection pre-generates `count` leader selections, where the chance of the i-th
+// identity to be selected as leader is proportional to weights[i].
+func computeLeaderSelection(
+	firstView uint64,
+	rng random.Rand,
+	count int,
+	identities flow.IdentityList,
+	weights []uint64,
+) (*LeaderSelection, error) {
+
+	if count < 1 {
+		return nil, fmt.Errorf("number of views must be positive (got %d)", count)
+	}
+	if len(weights) != len(identities) {
+		return nil, fmt.Errorf("number of weights (%d) does not match number of identities (%d)", len(weights), len(identities))
+	}
+
 	leaders, err := weightedRandomSelection(rng, count, weights)
 	if err != nil {
 		return nil, fmt.Errorf("could not select leader: %w", err)
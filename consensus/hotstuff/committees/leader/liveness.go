@@ -0,0 +1,74 @@
+package leader
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/crypto/random"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// LivenessWeights returns the leader selection weight of every identity, in the same order,
+// given their QC participation. Identities keep their full weight if no blocks were observed.
+// Only integer arithmetic is used, so all nodes compute identical weights.
+//
+// We measure QC participation (the voters listed in each block's parent QC) rather than which
+// leaders failed to produce a block: attributing a skipped view to its leader requires knowing
+// who the leader was, which under liveness-weighted selection depends on the participation in
+// the epoch before, making every epoch's selection depend on all previous selections. QC
+// participation is recorded in every header and only depends on the chain itself.
+func LivenessWeights(identities flow.IdentityList, participation *flow.QCParticipation, weighting flow.LivenessWeighting) []uint64 {
+	weights := make([]uint64, 0, len(identities))
+	for _, identity := range identities {
+		if participation == nil || participation.Blocks == 0 || identity.Weight == 0 {
+			weights = append(weights, identity.Weight)
+			continue
+		}
+
+		signed := participation.Signed[identity.NodeID]
+		if signed > participation.Blocks {
+			signed = participation.Blocks
+		}
+		missPermille := (participation.Blocks - signed) * 1000 / participation.Blocks
+		if missPermille <= weighting.ToleratedMissPermille {
+			weights = append(weights, identity.Weight)
+			continue
+		}
+
+		factor := 1000 - (missPermille-weighting.ToleratedMissPermille)*1000/(1000-weighting.ToleratedMissPermille)
+		if factor < weighting.MinWeightPermille {
+			factor = weighting.MinWeightPermille
+		}
+		// multiply first to retain precision, falling back to dividing first for huge weights
+		var weight uint64
+		if identity.Weight <= ^uint64(0)/1000 {
+			weight = identity.Weight * factor / 1000
+		} else {
+			weight = identity.Weight / 1000 * factor
+		}
+		if weight == 0 {
+			weight = 1
+		}
+		weights = append(weights, weight)
+	}
+	return weights
+}
+
+// ComputeLivenessWeightedLeaderSelection pre-generates a certain number of leader selections,
+// like ComputeLeaderSelection, but with each identity's weight reduced according to its
+// participation in recent QCs.
+func ComputeLivenessWeightedLeaderSelection(
+	firstView uint64,
+	rng random.Rand,
+	count int,
+	identities flow.IdentityList,
+	participation *flow.QCParticipation,
+	weighting flow.LivenessWeighting,
+) (*LeaderSelection, error) {
+
+	err := weighting.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid liveness weighting: %w", err)
+	}
+
+	return computeLeaderSelection(firstView, rng, count, identities, LivenessWeights(identities, participation, weighting))
+}
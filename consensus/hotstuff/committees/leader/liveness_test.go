@@ -0,0 +1,131 @@
+package leader
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// equalWeightCommittee returns consensus identities with the default weight. The identities are
+// constructed directly, as they don't require keys.
+func equalWeightCommittee(size int) flow.IdentityList {
+	identities := make(flow.IdentityList, 0, size)
+	for i := 0; i < size; i++ {
+		identities = append(identities, &flow.Identity{
+			NodeID: unittest.IdentifierFixture(),
+			Role:   flow.RoleConsensus,
+			Weight: flow.DefaultInitialWeight,
+		})
+	}
+	return identities
+}
+
+func TestLivenessWeights(t *testing.T) {
+	identities := equalWeightCommittee(4)
+	weighting := flow.LivenessWeighting{MinWeightPermille: 100, ToleratedMissPermille: 100}
+
+	participation := flow.NewQCParticipation()
+	for i := 0; i < 100; i++ {
+		signers := []flow.Identifier{identities[0].NodeID}
+		if i < 95 {
+			signers = append(signers, identities[1].NodeID) // missed 5%: tolerated
+		}
+		if i < 55 {
+			signers = append(signers, identities[2].NodeID) // missed 45%: reduced
+		}
+		participation.Add(signers) // identities[3] missed all
+	}
+
+	weights := LivenessWeights(identities, participation, weighting)
+	assert.Equal(t, []uint64{
+		flow.DefaultInitialWeight,
+		flow.DefaultInitialWeight,
+		flow.DefaultInitialWeight * 612 / 1000, // 1000 - (450-100)*1000/900
+		flow.DefaultInitialWeight * 100 / 1000,
+	}, weights)
+
+	t.Run("no observed blocks", func(t *testing.T) {
+		assert.Equal(t, []uint64{flow.DefaultInitialWeight, flow.DefaultInitialWeight, flow.DefaultInitialWeight, flow.DefaultInitialWeight},
+			LivenessWeights(identities, flow.NewQCParticipation(), weighting))
+		assert.Equal(t, LivenessWeights(identities, flow.NewQCParticipation(), weighting), LivenessWeights(identities, nil, weighting))
+	})
+
+	t.Run("weight is never reduced to zero", func(t *testing.T) {
+		small := flow.IdentityList{{NodeID: unittest.IdentifierFixture(), Weight: 1}}
+		assert.Equal(t, []uint64{1}, LivenessWeights(small, participation, weighting))
+	})
+
+	t.Run("invalid weighting", func(t *testing.T) {
+		require.Error(t, flow.LivenessWeighting{MinWeightPermille: 1001}.Validate())
+		require.Error(t, flow.LivenessWeighting{ToleratedMissPermille: 1000}.Validate())
+		require.NoError(t, flow.DefaultLivenessWeighting.Validate())
+	})
+}
+
+// TestLivenessWeightedSelectionReducesOfflineLeaders compares the number of views led by offline
+// nodes, each of which results in a timeout, with and without liveness weighting.
+func TestLivenessWeightedSelectionReducesOfflineLeaders(t *testing.T) {
+	const views = 100000
+	identities := equalWeightCommittee(10)
+	offline := map[flow.Identifier]struct{}{
+		identities[3].NodeID: {},
+		identities[7].NodeID: {},
+	}
+
+	participation := flow.NewQCParticipation()
+	online := make([]flow.Identifier, 0, len(identities))
+	for _, identity := range identities {
+		if _, ok := offline[identity.NodeID]; !ok {
+			online = append(online, identity.NodeID)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		participation.Add(online)
+	}
+
+	countOfflineLeaders := func(selection *LeaderSelection) int {
+		count := 0
+		for view := uint64(0); view < views; view++ {
+			leaderID, err := selection.LeaderForView(view)
+			require.NoError(t, err)
+			if _, ok := offline[leaderID]; ok {
+				count++
+			}
+		}
+		return count
+	}
+
+	plain, err := ComputeLeaderSelection(0, prg(t, someSeed), views, identities)
+	require.NoError(t, err)
+	weighted, err := ComputeLivenessWeightedLeaderSelection(0, prg(t, someSeed), views, identities, participation, flow.DefaultLivenessWeighting)
+	require.NoError(t, err)
+
+	plainTimeouts := countOfflineLeaders(plain)
+	weightedTimeouts := countOfflineLeaders(weighted)
+	t.Logf("views led by offline nodes: %d without weighting, %d with weighting", plainTimeouts, weightedTimeouts)
+
+	// offline nodes hold 20% of the weight without, and 0.2/8.2 ≈ 2.4% with liveness weighting
+	assert.InDelta(t, 0.2*views, plainTimeouts, 0.01*views)
+	assert.InDelta(t, 0.2/8.2*views, weightedTimeouts, 0.005*views)
+
+	// the offline nodes keep a chance to be selected, so they can recover
+	assert.Greater(t, weightedTimeouts, 0)
+}
+
+func TestLivenessWeightedSelectionIsDeterministic(t *testing.T) {
+	identities := equalWeightCommittee(10)
+	participation := flow.NewQCParticipation()
+	for i := 0; i < 10; i++ {
+		participation.Add(identities[:i].NodeIDs())
+	}
+
+	first, err := ComputeLivenessWeightedLeaderSelection(0, prg(t, someSeed), 1000, identities, participation, flow.DefaultLivenessWeighting)
+	require.NoError(t, err)
+	second, err := ComputeLivenessWeightedLeaderSelection(0, prg(t, someSeed), 1000, identities, participation, flow.DefaultLivenessWeighting)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
@@ -0,0 +1,123 @@
+package notifications
+
+import (
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// SlashingEvidenceConsumer is an implementation of the notifications consumer that persists
+// the signed messages proving a slashable offence, so they can be inspected and verified later.
+// Evidence for the same offence is only stored once. Failing to store evidence is logged, but
+// is not fatal for the node.
+type SlashingEvidenceConsumer struct {
+	NoopConsumer
+	log      zerolog.Logger
+	evidence storage.SlashingEvidence
+	headers  storage.Headers
+}
+
+func NewSlashingEvidenceConsumer(log zerolog.Logger, evidence storage.SlashingEvidence, headers storage.Headers) *SlashingEvidenceConsumer {
+	return &SlashingEvidenceConsumer{
+		log:      log.With().Str("component", "slashing_evidence_consumer").Logger(),
+		evidence: evidence,
+		headers:  headers,
+	}
+}
+
+func (c *SlashingEvidenceConsumer) OnDoubleVotingDetected(vote1 *model.Vote, vote2 *model.Vote) {
+	c.store(&flow.SlashingEvidence{
+		Type:       flow.SlashingEvidenceDoubleVote,
+		OffenderID: vote1.SignerID,
+		View:       vote1.View,
+		First:      signedVote(vote1),
+		Second:     signedVote(vote2),
+		DetectedAt: time.Now().UTC(),
+	})
+}
+
+func (c *SlashingEvidenceConsumer) OnVoteForInvalidBlockDetected(vote *model.Vote, proposal *model.Proposal) {
+	// the full header is needed to verify that the vote is for the invalid proposal, as the
+	// block ID covers fields which are not part of the hotstuff proposal
+	header, err := c.headers.ByBlockID(proposal.Block.BlockID)
+	if err != nil {
+		c.log.Error().Err(err).Hex("block_id", proposal.Block.BlockID[:]).Msg("could not retrieve invalid proposal, evidence not stored")
+		return
+	}
+
+	c.store(&flow.SlashingEvidence{
+		Type:            flow.SlashingEvidenceVoteForInvalidBlock,
+		OffenderID:      vote.SignerID,
+		View:            vote.View,
+		First:           signedVote(vote),
+		Second:          signedVote(proposal.ProposerVote()),
+		InvalidProposal: header,
+		DetectedAt:      time.Now().UTC(),
+	})
+}
+
+func (c *SlashingEvidenceConsumer) OnDoubleProposeDetected(block1 *model.Block, block2 *model.Block) {
+	// the hotstuff blocks don't include the proposer signatures, which are part of the stored headers
+	header1, err := c.headers.ByBlockID(block1.BlockID)
+	if err != nil {
+		c.log.Error().Err(err).Hex("block_id", block1.BlockID[:]).Msg("could not retrieve double proposal, evidence not stored")
+		return
+	}
+	header2, err := c.headers.ByBlockID(block2.BlockID)
+	if err != nil {
+		c.log.Error().Err(err).Hex("block_id", block2.BlockID[:]).Msg("could not retrieve double proposal, evidence not stored")
+		return
+	}
+
+	c.store(&flow.SlashingEvidence{
+		Type:       flow.SlashingEvidenceDoubleProposal,
+		OffenderID: block1.ProposerID,
+		View:       block1.View,
+		First:      proposerVote(block1, header1),
+		Second:     proposerVote(block2, header2),
+		DetectedAt: time.Now().UTC(),
+	})
+}
+
+func (c *SlashingEvidenceConsumer) store(evidence *flow.SlashingEvidence) {
+	evidenceID := evidence.ID()
+	log := c.log.With().
+		Hex("evidence_id", evidenceID[:]).
+		Str("type", evidence.Type.String()).
+		Hex("offender_id", evidence.OffenderID[:]).
+		Uint64("view", evidence.View).
+		Logger()
+
+	stored, err := c.evidence.Store(evidence)
+	if err != nil {
+		log.Error().Err(err).Msg("could not store slashing evidence")
+		return
+	}
+	if !stored {
+		log.Debug().Msg("slashing evidence already stored")
+		return
+	}
+	log.Warn().Msg("slashing evidence stored")
+}
+
+func signedVote(vote *model.Vote) flow.SignedVote {
+	return flow.SignedVote{
+		View:     vote.View,
+		BlockID:  vote.BlockID,
+		SignerID: vote.SignerID,
+		SigData:  vote.SigData,
+	}
+}
+
+func proposerVote(block *model.Block, header *flow.Header) flow.SignedVote {
+	return flow.SignedVote{
+		View:     block.View,
+		BlockID:  block.BlockID,
+		SignerID: block.ProposerID,
+		SigData:  header.ProposerSigData,
+	}
+}
@@ -0,0 +1,83 @@
+package notifications
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	storage "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestSlashingEvidenceConsumer_DoubleVote(t *testing.T) {
+	evidenceStore := &storage.SlashingEvidence{}
+	consumer := NewSlashingEvidenceConsumer(unittest.Logger(), evidenceStore, &storage.Headers{})
+
+	vote1 := unittest.VoteFixture()
+	vote2 := unittest.VoteFixture(unittest.WithVoteView(vote1.View), unittest.WithVoteSignerID(vote1.SignerID))
+
+	evidenceStore.On("Store", mock.Anything).Run(func(args mock.Arguments) {
+		evidence := args.Get(0).(*flow.SlashingEvidence)
+		require.Equal(t, flow.SlashingEvidenceDoubleVote, evidence.Type)
+		require.Equal(t, vote1.SignerID, evidence.OffenderID)
+		require.Equal(t, vote1.View, evidence.View)
+		require.Equal(t, vote1.SigData, evidence.First.SigData)
+		require.Equal(t, vote2.SigData, evidence.Second.SigData)
+	}).Return(true, nil).Once()
+
+	consumer.OnDoubleVotingDetected(vote1, vote2)
+	evidenceStore.AssertExpectations(t)
+}
+
+func TestSlashingEvidenceConsumer_DoubleProposal(t *testing.T) {
+	evidenceStore := &storage.SlashingEvidence{}
+	headers := &storage.Headers{}
+	consumer := NewSlashingEvidenceConsumer(unittest.Logger(), evidenceStore, headers)
+
+	header1 := unittest.BlockHeaderFixture()
+	header2 := unittest.BlockHeaderFixture()
+	header2.View = header1.View
+	header2.ProposerID = header1.ProposerID
+	block1 := model.BlockFromFlow(&header1, header1.View-1)
+	block2 := model.BlockFromFlow(&header2, header2.View-1)
+	headers.On("ByBlockID", block1.BlockID).Return(&header1, nil)
+	headers.On("ByBlockID", block2.BlockID).Return(&header2, nil)
+
+	evidenceStore.On("Store", mock.Anything).Run(func(args mock.Arguments) {
+		evidence := args.Get(0).(*flow.SlashingEvidence)
+		require.Equal(t, flow.SlashingEvidenceDoubleProposal, evidence.Type)
+		require.Equal(t, header1.ProposerID, evidence.OffenderID)
+		require.Equal(t, header1.ProposerSigData, evidence.First.SigData)
+		require.Equal(t, header2.ProposerSigData, evidence.Second.SigData)
+	}).Return(true, nil).Once()
+
+	consumer.OnDoubleProposeDetected(block1, block2)
+	evidenceStore.AssertExpectations(t)
+}
+
+func TestSlashingEvidenceConsumer_VoteForInvalidBlock(t *testing.T) {
+	evidenceStore := &storage.SlashingEvidence{}
+	headers := &storage.Headers{}
+	consumer := NewSlashingEvidenceConsumer(unittest.Logger(), evidenceStore, headers)
+
+	header := unittest.BlockHeaderFixture()
+	proposal := model.ProposalFromFlow(&header, header.View-1)
+	vote := unittest.VoteForBlockFixture(proposal.Block)
+	headers.On("ByBlockID", proposal.Block.BlockID).Return(&header, nil)
+
+	evidenceStore.On("Store", mock.Anything).Run(func(args mock.Arguments) {
+		evidence := args.Get(0).(*flow.SlashingEvidence)
+		require.Equal(t, flow.SlashingEvidenceVoteForInvalidBlock, evidence.Type)
+		require.Equal(t, vote.SignerID, evidence.OffenderID)
+		require.Equal(t, proposal.Block.ProposerID, evidence.Second.SignerID)
+		require.Equal(t, proposal.SigData, evidence.Second.SigData)
+		require.Equal(t, &header, evidence.InvalidProposal)
+		require.Equal(t, evidence.First.BlockID, evidence.InvalidProposal.ID())
+	}).Return(false, nil).Once()
+
+	consumer.OnVoteForInvalidBlockDetected(vote, proposal)
+	evidenceStore.AssertExpectations(t)
+}
@@ -0,0 +1,206 @@
+package notifications
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TimelineEventType is the type of an event recorded in a view timeline.
+type TimelineEventType string
+
+const (
+	TimelineEnteredView      TimelineEventType = "entered_view"
+	TimelineProposalReceived TimelineEventType = "proposal_received"
+	TimelineProposing        TimelineEventType = "proposing"
+	TimelineVoteSent         TimelineEventType = "vote_sent"
+	TimelineQCFormed         TimelineEventType = "qc_formed"
+	TimelineTimeoutStarted   TimelineEventType = "timeout_started"
+	TimelineTimeoutReached   TimelineEventType = "timeout_reached"
+	TimelineBlockFinalized   TimelineEventType = "block_finalized"
+)
+
+// TimelineEvent is a single event that happened in a view.
+type TimelineEvent struct {
+	Type TimelineEventType `json:"type"`
+	Time time.Time         `json:"time"`
+	// BlockID is the block the event refers to, if any
+	BlockID *flow.Identifier `json:"block_id,omitempty"`
+	// Duration is the duration of the started timeout, only set for TimelineTimeoutStarted
+	Duration time.Duration `json:"duration,omitempty"`
+}
+
+// ViewTimeline contains the events recorded for a single view, in the order they were observed.
+type ViewTimeline struct {
+	View   uint64          `json:"view"`
+	Leader flow.Identifier `json:"leader"`
+	Events []TimelineEvent `json:"events"`
+}
+
+// TimelineRecorder is an implementation of the notifications consumer that records a compact
+// timeline of the events of each view: entering the view, receiving or producing the proposal,
+// voting, forming a QC, timeouts and finalization.
+// Timelines are kept for the `capacity` highest views. When a new view is recorded, the lowest view
+// is evicted, so an event for a far-future view only takes a single slot. Events for views lower
+// than all recorded views are dropped once the recorder is full.
+type TimelineRecorder struct {
+	NoopConsumer
+	mu       sync.Mutex
+	now      func() time.Time
+	capacity int
+	byView   map[uint64]*ViewTimeline // recorded views
+}
+
+var _ hotstuff.Consumer = (*TimelineRecorder)(nil)
+
+// NewTimelineRecorder creates a recorder keeping the timelines of the `capacity` highest views.
+func NewTimelineRecorder(capacity uint) *TimelineRecorder {
+	if capacity == 0 {
+		capacity = 1
+	}
+	return &TimelineRecorder{
+		now:      time.Now,
+		capacity: int(capacity),
+		byView:   make(map[uint64]*ViewTimeline, capacity),
+	}
+}
+
+func (r *TimelineRecorder) OnEnteringView(view uint64, leader flow.Identifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timeline := r.timeline(view)
+	if timeline == nil {
+		return
+	}
+	timeline.Leader = leader
+	timeline.Events = append(timeline.Events, TimelineEvent{Type: TimelineEnteredView, Time: r.now()})
+}
+
+func (r *TimelineRecorder) OnReceiveProposal(_ uint64, proposal *model.Proposal) {
+	r.record(proposal.Block.View, TimelineProposalReceived, &proposal.Block.BlockID)
+}
+
+func (r *TimelineRecorder) OnProposingBlock(proposal *model.Proposal) {
+	r.record(proposal.Block.View, TimelineProposing, &proposal.Block.BlockID)
+}
+
+func (r *TimelineRecorder) OnVoting(vote *model.Vote) {
+	r.record(vote.View, TimelineVoteSent, &vote.BlockID)
+}
+
+func (r *TimelineRecorder) OnQcConstructedFromVotes(_ uint64, qc *flow.QuorumCertificate) {
+	r.record(qc.View, TimelineQCFormed, &qc.BlockID)
+}
+
+func (r *TimelineRecorder) OnStartingTimeout(info *model.TimerInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timeline := r.timeline(info.View)
+	if timeline == nil {
+		return
+	}
+	timeline.Events = append(timeline.Events, TimelineEvent{
+		Type:     TimelineTimeoutStarted,
+		Time:     r.now(),
+		Duration: info.Duration,
+	})
+}
+
+func (r *TimelineRecorder) OnReachedTimeout(info *model.TimerInfo) {
+	r.record(info.View, TimelineTimeoutReached, nil)
+}
+
+func (r *TimelineRecorder) OnFinalizedBlock(block *model.Block) {
+	r.record(block.View, TimelineBlockFinalized, &block.BlockID)
+}
+
+// Timelines returns a copy of the recorded timelines, ordered by view.
+func (r *TimelineRecorder) Timelines() []ViewTimeline {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timelines := make([]ViewTimeline, 0, len(r.byView))
+	for _, timeline := range r.byView {
+		timelines = append(timelines, copyTimeline(timeline))
+	}
+	sort.Slice(timelines, func(i, j int) bool {
+		return timelines[i].View < timelines[j].View
+	})
+	return timelines
+}
+
+// Timeline returns a copy of the timeline recorded for the given view.
+// The boolean is false if no events are recorded for the view.
+func (r *TimelineRecorder) Timeline(view uint64) (ViewTimeline, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timeline, ok := r.byView[view]
+	if !ok {
+		return ViewTimeline{}, false
+	}
+	return copyTimeline(timeline), true
+}
+
+// record appends an event to the timeline of the given view.
+func (r *TimelineRecorder) record(view uint64, eventType TimelineEventType, blockID *flow.Identifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	timeline := r.timeline(view)
+	if timeline == nil {
+		return
+	}
+
+	event := TimelineEvent{Type: eventType, Time: r.now()}
+	if blockID != nil {
+		id := *blockID
+		event.BlockID = &id
+	}
+	timeline.Events = append(timeline.Events, event)
+}
+
+// timeline returns the timeline of the given view, adding it to the recorded views if necessary.
+// If the recorder is full, the lowest view is evicted. Returns nil if the recorder is full and the
+// view is lower than all recorded views.
+// Must be called while holding the lock.
+func (r *TimelineRecorder) timeline(view uint64) *ViewTimeline {
+	timeline, ok := r.byView[view]
+	if ok {
+		return timeline
+	}
+
+	if len(r.byView) >= r.capacity {
+		// a new view is seen about once per round, so scanning the recorded views is cheap enough
+		lowest := view
+		for recorded := range r.byView {
+			if recorded < lowest {
+				lowest = recorded
+			}
+		}
+		if lowest == view {
+			return nil
+		}
+		delete(r.byView, lowest)
+	}
+
+	timeline = &ViewTimeline{View: view}
+	r.byView[view] = timeline
+	return timeline
+}
+
+func copyTimeline(timeline *ViewTimeline) ViewTimeline {
+	events := make([]TimelineEvent, len(timeline.Events))
+	copy(events, timeline.Events)
+	return ViewTimeline{
+		View:   timeline.View,
+		Leader: timeline.Leader,
+		Events: events,
+	}
+}
@@ -0,0 +1,136 @@
+package notifications
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// newTestRecorder returns a recorder with a clock advancing by one millisecond on every event.
+func newTestRecorder(capacity uint) *TimelineRecorder {
+	recorder := NewTimelineRecorder(capacity)
+	now := time.Unix(1_600_000_000, 0)
+	recorder.now = func() time.Time {
+		now = now.Add(time.Millisecond)
+		return now
+	}
+	return recorder
+}
+
+func TestTimelineRecorder(t *testing.T) {
+
+	t.Run("records events per view", func(t *testing.T) {
+		recorder := newTestRecorder(10)
+		leader := unittest.IdentifierFixture()
+		block := &model.Block{View: 5, BlockID: unittest.IdentifierFixture()}
+
+		recorder.OnEnteringView(5, leader)
+		recorder.OnStartingTimeout(&model.TimerInfo{View: 5, Duration: time.Second})
+		recorder.OnReceiveProposal(5, &model.Proposal{Block: block})
+		recorder.OnVoting(&model.Vote{View: 5, BlockID: block.BlockID})
+		recorder.OnQcConstructedFromVotes(6, &flow.QuorumCertificate{View: 5, BlockID: block.BlockID})
+		recorder.OnReachedTimeout(&model.TimerInfo{View: 6})
+		recorder.OnFinalizedBlock(block)
+
+		timeline, ok := recorder.Timeline(5)
+		require.True(t, ok)
+		assert.Equal(t, leader, timeline.Leader)
+
+		var types []TimelineEventType
+		for _, event := range timeline.Events {
+			types = append(types, event.Type)
+		}
+		assert.Equal(t, []TimelineEventType{
+			TimelineEnteredView,
+			TimelineTimeoutStarted,
+			TimelineProposalReceived,
+			TimelineVoteSent,
+			TimelineQCFormed,
+			TimelineBlockFinalized,
+		}, types)
+		assert.Equal(t, time.Second, timeline.Events[1].Duration)
+		assert.Equal(t, block.BlockID, *timeline.Events[2].BlockID)
+
+		timeline, ok = recorder.Timeline(6)
+		require.True(t, ok)
+		require.Len(t, timeline.Events, 1)
+		assert.Equal(t, TimelineTimeoutReached, timeline.Events[0].Type)
+	})
+
+	t.Run("keeps the most recent views", func(t *testing.T) {
+		recorder := newTestRecorder(3)
+		for view := uint64(1); view <= 5; view++ {
+			recorder.OnEnteringView(view, unittest.IdentifierFixture())
+		}
+
+		timelines := recorder.Timelines()
+		require.Len(t, timelines, 3)
+		assert.Equal(t, uint64(3), timelines[0].View)
+		assert.Equal(t, uint64(5), timelines[2].View)
+
+		// events for evicted views are dropped and don't evict newer views
+		recorder.OnFinalizedBlock(&model.Block{View: 2})
+		_, ok := recorder.Timeline(2)
+		assert.False(t, ok)
+		assert.Len(t, recorder.Timelines(), 3)
+	})
+
+	t.Run("far-future view does not drop later views", func(t *testing.T) {
+		recorder := newTestRecorder(3)
+		recorder.OnEnteringView(1_000_000, unittest.IdentifierFixture())
+		for view := uint64(1); view <= 5; view++ {
+			recorder.OnEnteringView(view, unittest.IdentifierFixture())
+		}
+
+		// the far-future view takes a single slot, the lowest views are evicted
+		timelines := recorder.Timelines()
+		require.Len(t, timelines, 3)
+		assert.Equal(t, uint64(4), timelines[0].View)
+		assert.Equal(t, uint64(5), timelines[1].View)
+		assert.Equal(t, uint64(1_000_000), timelines[2].View)
+
+		recorder.OnVoting(&model.Vote{View: 5, BlockID: unittest.IdentifierFixture()})
+		timeline, ok := recorder.Timeline(5)
+		require.True(t, ok)
+		assert.Len(t, timeline.Events, 2)
+	})
+
+	t.Run("returned timelines are copies", func(t *testing.T) {
+		recorder := newTestRecorder(3)
+		recorder.OnEnteringView(1, unittest.IdentifierFixture())
+
+		timeline, ok := recorder.Timeline(1)
+		require.True(t, ok)
+		recorder.OnReachedTimeout(&model.TimerInfo{View: 1})
+		assert.Len(t, timeline.Events, 1)
+	})
+}
+
+func TestChromeTrace(t *testing.T) {
+	recorder := newTestRecorder(10)
+	recorder.OnEnteringView(1, unittest.IdentifierFixture())
+	recorder.OnVoting(&model.Vote{View: 1, BlockID: unittest.IdentifierFixture()})
+	recorder.OnEnteringView(2, unittest.IdentifierFixture())
+
+	trace := NewChromeTrace(recorder.Timelines())
+
+	// one span per view and one instant event per recorded event
+	require.Len(t, trace.TraceEvents, 5)
+	view1 := trace.TraceEvents[0]
+	assert.Equal(t, "view 1", view1.Name)
+	assert.Equal(t, chromeTracePhaseComplete, view1.Phase)
+	// view 1 lasts until view 2 is entered
+	assert.Equal(t, int64(2000), view1.Duration)
+	assert.Equal(t, string(TimelineVoteSent), trace.TraceEvents[2].Name)
+	assert.Equal(t, chromeTracePhaseInstant, trace.TraceEvents[2].Phase)
+
+	_, err := json.Marshal(trace)
+	require.NoError(t, err)
+}
@@ -0,0 +1,114 @@
+package notifications
+
+import (
+	"fmt"
+	"time"
+)
+
+// ChromeTrace is a trace in the Chrome trace event format, which can be loaded into
+// chrome://tracing or https://ui.perfetto.dev for visual inspection.
+// See https://docs.google.com/document/d/1CvAClvFfyA5R-PhYUmn5OOQtYMH4h6I0nSsKchNAySU
+type ChromeTrace struct {
+	TraceEvents     []ChromeTraceEvent `json:"traceEvents"`
+	DisplayTimeUnit string             `json:"displayTimeUnit"`
+}
+
+// ChromeTraceEvent is a single event of a ChromeTrace.
+type ChromeTraceEvent struct {
+	Name      string                 `json:"name"`
+	Category  string                 `json:"cat"`
+	Phase     string                 `json:"ph"`
+	Timestamp int64                  `json:"ts"`            // [microseconds]
+	Duration  int64                  `json:"dur,omitempty"` // [microseconds], only for complete events
+	Scope     string                 `json:"s,omitempty"`   // only for instant events
+	PID       int                    `json:"pid"`
+	TID       int                    `json:"tid"`
+	Args      map[string]interface{} `json:"args,omitempty"`
+}
+
+const (
+	chromeTracePhaseComplete = "X"
+	chromeTracePhaseInstant  = "i"
+	chromeTraceScopeThread   = "t"
+
+	chromeTraceViewsThread  = 1
+	chromeTraceEventsThread = 2
+)
+
+// NewChromeTrace converts the given timelines, ordered by view, into a Chrome trace.
+// Every view is represented by a span lasting from its first event until the first event
+// of the following view (or its own last event for the most recent view), and every
+// recorded event by an instant event.
+func NewChromeTrace(timelines []ViewTimeline) *ChromeTrace {
+	trace := &ChromeTrace{
+		TraceEvents:     []ChromeTraceEvent{},
+		DisplayTimeUnit: "ms",
+	}
+
+	for i, timeline := range timelines {
+		if len(timeline.Events) == 0 {
+			continue
+		}
+
+		start, end := timelineBounds(timeline)
+		if i+1 < len(timelines) && len(timelines[i+1].Events) > 0 {
+			nextStart, _ := timelineBounds(timelines[i+1])
+			if nextStart.After(end) {
+				end = nextStart
+			}
+		}
+
+		trace.TraceEvents = append(trace.TraceEvents, ChromeTraceEvent{
+			Name:      fmt.Sprintf("view %d", timeline.View),
+			Category:  "view",
+			Phase:     chromeTracePhaseComplete,
+			Timestamp: start.UnixNano() / int64(time.Microsecond),
+			Duration:  end.Sub(start).Microseconds(),
+			PID:       1,
+			TID:       chromeTraceViewsThread,
+			Args: map[string]interface{}{
+				"view":   timeline.View,
+				"leader": timeline.Leader.String(),
+			},
+		})
+
+		for _, event := range timeline.Events {
+			args := map[string]interface{}{
+				"view": timeline.View,
+			}
+			if event.BlockID != nil {
+				args["block_id"] = event.BlockID.String()
+			}
+			if event.Duration > 0 {
+				args["duration"] = event.Duration.String()
+			}
+			trace.TraceEvents = append(trace.TraceEvents, ChromeTraceEvent{
+				Name:      string(event.Type),
+				Category:  "hotstuff",
+				Phase:     chromeTracePhaseInstant,
+				Timestamp: event.Time.UnixNano() / int64(time.Microsecond),
+				Scope:     chromeTraceScopeThread,
+				PID:       1,
+				TID:       chromeTraceEventsThread,
+				Args:      args,
+			})
+		}
+	}
+
+	return trace
+}
+
+// timelineBounds returns the time of the earliest and latest event of a non-empty timeline.
+func timelineBounds(timeline ViewTimeline) (time.Time, time.Time) {
+	start := timeline.Events[0].Time
+	end := start
+	for _, event := range timeline.Events[1:] {
+		if event.Time.Before(start) {
+			start = event.Time
+		}
+		if event.Time.After(end) {
+			end = event.Time
+		}
+	}
+	return start, end
+}
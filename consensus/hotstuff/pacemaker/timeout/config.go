@@ -18,6 +18,8 @@ type Config struct {
 	ReplicaTimeout float64
 	// MinReplicaTimeout is the minimum the timeout can decrease to [MILLISECONDS]
 	MinReplicaTimeout float64
+	// MaxReplicaTimeout is the maximum the timeout can increase to [MILLISECONDS]
+	MaxReplicaTimeout float64
 	// VoteAggregationTimeoutFraction is the FRACTION of ReplicaTimeout which the Primary
 	// will maximally wait to collect enough votes before building a block (with an old qc)
 	VoteAggregationTimeoutFraction float64
@@ -101,6 +103,7 @@ func NewConfig(
 	tc := Config{
 		ReplicaTimeout:                 float64(startReplicaTimeout.Milliseconds()),
 		MinReplicaTimeout:              float64(minReplicaTimeout.Milliseconds()),
+		MaxReplicaTimeout:              timeoutCap,
 		VoteAggregationTimeoutFraction: voteAggregationTimeoutFraction,
 		TimeoutIncrease:                timeoutIncrease,
 		TimeoutDecrease:                timeoutDecrease,
@@ -91,3 +91,55 @@ func TestStandardTimeoutDecreaseFactor(t *testing.T) {
 	numericalError = math.Abs(expected - 1.0)
 	require.True(t, numericalError < 1e-15)
 }
+
+func TestConfigUpdate(t *testing.T) {
+	c, err := NewConfig(2200*time.Millisecond, 1200*time.Millisecond, 0.73, 1.5, 0.85, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, timeoutCap, c.MaxReplicaTimeout)
+
+	// an empty update doesn't change the configuration
+	updated, err := ConfigUpdate{}.Apply(c)
+	require.NoError(t, err)
+	require.Equal(t, c, updated)
+
+	// only the given parameters are changed
+	minTimeout := 1500 * time.Millisecond
+	maxTimeout := 2000 * time.Millisecond
+	blockRateDelay := 500 * time.Millisecond
+	updated, err = ConfigUpdate{
+		MinReplicaTimeout: &minTimeout,
+		MaxReplicaTimeout: &maxTimeout,
+		BlockRateDelay:    &blockRateDelay,
+	}.Apply(c)
+	require.NoError(t, err)
+	require.Equal(t, float64(1500), updated.MinReplicaTimeout)
+	require.Equal(t, float64(2000), updated.MaxReplicaTimeout)
+	require.Equal(t, float64(500), updated.BlockRateDelayMS)
+	require.Equal(t, c.VoteAggregationTimeoutFraction, updated.VoteAggregationTimeoutFraction)
+	require.Equal(t, c.TimeoutIncrease, updated.TimeoutIncrease)
+	require.Equal(t, c.TimeoutDecrease, updated.TimeoutDecrease)
+	// the current replica timeout is moved into the new range
+	require.Equal(t, float64(2000), updated.ReplicaTimeout)
+
+	// should not allow maxReplicaTimeout < minReplicaTimeout
+	maxTimeout = 1000 * time.Millisecond
+	_, err = ConfigUpdate{MaxReplicaTimeout: &maxTimeout}.Apply(c)
+	require.Error(t, err)
+
+	// invariants of NewConfig are enforced
+	timeoutIncrease := 0.9
+	_, err = ConfigUpdate{TimeoutIncrease: &timeoutIncrease}.Apply(c)
+	require.Error(t, err)
+	negativeDelay := -1 * time.Millisecond
+	_, err = ConfigUpdate{BlockRateDelay: &negativeDelay}.Apply(c)
+	require.Error(t, err)
+
+	// later updates take precedence when merging
+	first := 100 * time.Millisecond
+	second := 200 * time.Millisecond
+	merged := ConfigUpdate{BlockRateDelay: &first, MinReplicaTimeout: &first}.Merge(ConfigUpdate{BlockRateDelay: &second})
+	require.Equal(t, second, *merged.BlockRateDelay)
+	require.Equal(t, first, *merged.MinReplicaTimeout)
+	require.False(t, merged.IsEmpty())
+	require.True(t, ConfigUpdate{}.IsEmpty())
+}
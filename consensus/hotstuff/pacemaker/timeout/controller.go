@@ -2,6 +2,7 @@ package timeout
 
 import (
 	"math"
+	"sync"
 	"time"
 
 	"github.com/onflow/flow-go/consensus/hotstuff/model"
@@ -11,7 +12,10 @@ import (
 // - on timeout: increase timeout by multiplicative factor `timeoutIncrease` (user-specified)
 //   this results in exponential growing timeout duration on multiple subsequent timeouts
 // - on progress: decrease timeout by subtrahend `timeoutDecrease`
+// The timeout parameters can be changed at runtime through Update, all other methods
+// are expected to be called from the HotStuff event loop only.
 type Controller struct {
+	mu             sync.RWMutex // protects cfg
 	cfg            Config
 	timer          *time.Timer
 	timerInfo      *model.TimerInfo
@@ -30,11 +34,11 @@ func NewController(timeoutConfig Config) *Controller {
 	startChannel := make(chan time.Time)
 	close(startChannel)
 
-	tc := Controller{
+	tc := &Controller{
 		cfg:            timeoutConfig,
 		timeoutChannel: startChannel,
 	}
-	return &tc
+	return tc
 }
 
 func DefaultController() *Controller {
@@ -87,27 +91,61 @@ func (t *Controller) computeTimeoutDuration(mode model.TimeoutMode) time.Duratio
 
 // ReplicaTimeout returns the duration of the current view before we time out
 func (t *Controller) ReplicaTimeout() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	return time.Duration(t.cfg.ReplicaTimeout * 1e6)
 }
 
 // VoteCollectionTimeout returns the duration of Vote aggregation _after_ receiving a block
 // during which the primary tries to aggregate votes for the view where it is leader
 func (t *Controller) VoteCollectionTimeout() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	// time.Duration expects an int64 as input which specifies the duration in units of nanoseconds (1E-9)
 	return time.Duration(t.cfg.ReplicaTimeout * 1e6 * t.cfg.VoteAggregationTimeoutFraction)
 }
 
 // OnTimeout indicates to the Controller that the timeout was reached
 func (t *Controller) OnTimeout() {
-	t.cfg.ReplicaTimeout = math.Min(t.cfg.ReplicaTimeout*t.cfg.TimeoutIncrease, timeoutCap)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.cfg.ReplicaTimeout = math.Min(t.cfg.ReplicaTimeout*t.cfg.TimeoutIncrease, t.cfg.MaxReplicaTimeout)
 }
 
 // OnProgressBeforeTimeout indicates to the Controller that progress was made _before_ the timeout was reached
 func (t *Controller) OnProgressBeforeTimeout() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
 	t.cfg.ReplicaTimeout = math.Max(t.cfg.ReplicaTimeout*t.cfg.TimeoutDecrease, t.cfg.MinReplicaTimeout)
 }
 
 // BlockRateDelay is a delay to broadcast the proposal in order to control block production rate
 func (t *Controller) BlockRateDelay() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
 	return time.Duration(t.cfg.BlockRateDelayMS * float64(time.Millisecond))
 }
+
+// Config returns a copy of the current timeout configuration.
+func (t *Controller) Config() Config {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.cfg
+}
+
+// Update applies the given changes to the timeout parameters. The current replica timeout is
+// kept, but moved into the new range of minimum and maximum replica timeout if necessary.
+// The changes take effect with the next started timeout.
+// Returns a model.ConfigurationError if the resulting configuration is invalid, in which case
+// the configuration is not changed.
+func (t *Controller) Update(update ConfigUpdate) (Config, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cfg, err := update.Apply(t.cfg)
+	if err != nil {
+		return t.cfg, err
+	}
+	t.cfg = cfg
+	return cfg, nil
+}
@@ -167,3 +167,31 @@ func Test_BlockRateDelay(t *testing.T) {
 	tc := NewController(c)
 	assert.Equal(t, time.Second, tc.BlockRateDelay())
 }
+
+// Test_UpdateConfig verifies that the timeout parameters can be changed at runtime
+func Test_UpdateConfig(t *testing.T) {
+	tc := initTimeoutController(t)
+
+	maxTimeout := 200 * time.Millisecond
+	blockRateDelay := 50 * time.Millisecond
+	cfg, err := tc.Update(ConfigUpdate{
+		MaxReplicaTimeout: &maxTimeout,
+		BlockRateDelay:    &blockRateDelay,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, cfg, tc.Config())
+	assert.Equal(t, blockRateDelay, tc.BlockRateDelay())
+
+	// timeout increase is bounded by the max replica timeout
+	for i := 0; i < 10; i++ {
+		tc.OnTimeout()
+	}
+	assert.Equal(t, maxTimeout, tc.ReplicaTimeout())
+
+	// an invalid update doesn't change the configuration
+	invalidMin := 300 * time.Millisecond
+	_, err = tc.Update(ConfigUpdate{MinReplicaTimeout: &invalidMin})
+	require.Error(t, err)
+	assert.Equal(t, cfg.MinReplicaTimeout, tc.Config().MinReplicaTimeout)
+	assert.Equal(t, maxTimeout, tc.ReplicaTimeout())
+}
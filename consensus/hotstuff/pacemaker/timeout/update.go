@@ -0,0 +1,105 @@
+package timeout
+
+import (
+	"math"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+)
+
+// ConfigUpdate describes changes to the timeout parameters of a running Controller.
+// Only the non-nil fields are changed.
+type ConfigUpdate struct {
+	MinReplicaTimeout              *time.Duration `json:"min_replica_timeout,omitempty"`
+	MaxReplicaTimeout              *time.Duration `json:"max_replica_timeout,omitempty"`
+	VoteAggregationTimeoutFraction *float64       `json:"vote_aggregation_timeout_fraction,omitempty"`
+	TimeoutIncrease                *float64       `json:"timeout_increase,omitempty"`
+	TimeoutDecrease                *float64       `json:"timeout_decrease,omitempty"`
+	BlockRateDelay                 *time.Duration `json:"block_rate_delay,omitempty"`
+}
+
+// IsEmpty returns true if the update doesn't change any parameter.
+func (u ConfigUpdate) IsEmpty() bool {
+	return u == ConfigUpdate{}
+}
+
+// Merge returns an update applying the changes of both updates, where the changes of
+// `other` take precedence.
+func (u ConfigUpdate) Merge(other ConfigUpdate) ConfigUpdate {
+	merged := u
+	if other.MinReplicaTimeout != nil {
+		merged.MinReplicaTimeout = other.MinReplicaTimeout
+	}
+	if other.MaxReplicaTimeout != nil {
+		merged.MaxReplicaTimeout = other.MaxReplicaTimeout
+	}
+	if other.VoteAggregationTimeoutFraction != nil {
+		merged.VoteAggregationTimeoutFraction = other.VoteAggregationTimeoutFraction
+	}
+	if other.TimeoutIncrease != nil {
+		merged.TimeoutIncrease = other.TimeoutIncrease
+	}
+	if other.TimeoutDecrease != nil {
+		merged.TimeoutDecrease = other.TimeoutDecrease
+	}
+	if other.BlockRateDelay != nil {
+		merged.BlockRateDelay = other.BlockRateDelay
+	}
+	return merged
+}
+
+// Apply returns the configuration resulting from applying the update to the given configuration.
+// The resulting configuration is subject to the same invariants as configurations created by
+// NewConfig, and in addition the maximum replica timeout must not be smaller than the minimum.
+// The current replica timeout is moved into the range of minimum and maximum replica timeout.
+// Returns a model.ConfigurationError if the resulting configuration is invalid.
+func (u ConfigUpdate) Apply(cfg Config) (Config, error) {
+	minReplicaTimeout := time.Duration(cfg.MinReplicaTimeout * float64(time.Millisecond))
+	if u.MinReplicaTimeout != nil {
+		minReplicaTimeout = *u.MinReplicaTimeout
+	}
+	maxReplicaTimeout := time.Duration(cfg.MaxReplicaTimeout * float64(time.Millisecond))
+	if u.MaxReplicaTimeout != nil {
+		maxReplicaTimeout = *u.MaxReplicaTimeout
+	}
+	voteAggregationTimeoutFraction := cfg.VoteAggregationTimeoutFraction
+	if u.VoteAggregationTimeoutFraction != nil {
+		voteAggregationTimeoutFraction = *u.VoteAggregationTimeoutFraction
+	}
+	timeoutIncrease := cfg.TimeoutIncrease
+	if u.TimeoutIncrease != nil {
+		timeoutIncrease = *u.TimeoutIncrease
+	}
+	timeoutDecrease := cfg.TimeoutDecrease
+	if u.TimeoutDecrease != nil {
+		timeoutDecrease = *u.TimeoutDecrease
+	}
+	blockRateDelay := time.Duration(cfg.BlockRateDelayMS * float64(time.Millisecond))
+	if u.BlockRateDelay != nil {
+		blockRateDelay = *u.BlockRateDelay
+	}
+
+	if maxReplicaTimeout < minReplicaTimeout {
+		return Config{}, model.NewConfigurationErrorf("maxReplicaTimeout (%dms) cannot be smaller than minReplicaTimeout (%dms)",
+			maxReplicaTimeout.Milliseconds(), minReplicaTimeout.Milliseconds())
+	}
+	if float64(maxReplicaTimeout.Milliseconds()) > timeoutCap {
+		return Config{}, model.NewConfigurationErrorf("maxReplicaTimeout cannot be larger than %vms", timeoutCap)
+	}
+
+	updated, err := NewConfig(
+		maxReplicaTimeout,
+		minReplicaTimeout,
+		voteAggregationTimeoutFraction,
+		timeoutIncrease,
+		timeoutDecrease,
+		blockRateDelay,
+	)
+	if err != nil {
+		return Config{}, err
+	}
+	updated.MaxReplicaTimeout = float64(maxReplicaTimeout.Milliseconds())
+	updated.ReplicaTimeout = math.Min(math.Max(cfg.ReplicaTimeout, updated.MinReplicaTimeout), updated.MaxReplicaTimeout)
+
+	return updated, nil
+}
@@ -1,9 +1,13 @@
 package persister
 
 import (
+	"errors"
+
 	"github.com/dgraph-io/badger/v2"
 
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 )
 
@@ -46,3 +50,44 @@ func (p *Persister) PutStarted(view uint64) error {
 func (p *Persister) PutVoted(view uint64) error {
 	return operation.RetryOnConflict(p.db.Update, operation.UpdateVotedView(p.chainID, view))
 }
+
+// GetTimeoutConfigUpdate returns the persisted runtime changes of the timeout parameters.
+// It returns an empty update if the timeout parameters have never been changed at runtime.
+func (p *Persister) GetTimeoutConfigUpdate() (timeout.ConfigUpdate, error) {
+	var stored operation.TimeoutConfigUpdate
+	err := p.db.View(operation.RetrieveTimeoutConfigUpdate(p.chainID, &stored))
+	if errors.Is(err, storage.ErrNotFound) {
+		return timeout.ConfigUpdate{}, nil
+	}
+	if err != nil {
+		return timeout.ConfigUpdate{}, err
+	}
+	return timeout.ConfigUpdate{
+		MinReplicaTimeout:              stored.MinReplicaTimeout,
+		MaxReplicaTimeout:              stored.MaxReplicaTimeout,
+		VoteAggregationTimeoutFraction: stored.VoteAggregationTimeoutFraction,
+		TimeoutIncrease:                stored.TimeoutIncrease,
+		TimeoutDecrease:                stored.TimeoutDecrease,
+		BlockRateDelay:                 stored.BlockRateDelay,
+	}, nil
+}
+
+// PutTimeoutConfigUpdate persists the runtime changes of the timeout parameters, replacing
+// any previously persisted changes.
+func (p *Persister) PutTimeoutConfigUpdate(update timeout.ConfigUpdate) error {
+	stored := operation.TimeoutConfigUpdate{
+		MinReplicaTimeout:              update.MinReplicaTimeout,
+		MaxReplicaTimeout:              update.MaxReplicaTimeout,
+		VoteAggregationTimeoutFraction: update.VoteAggregationTimeoutFraction,
+		TimeoutIncrease:                update.TimeoutIncrease,
+		TimeoutDecrease:                update.TimeoutDecrease,
+		BlockRateDelay:                 update.BlockRateDelay,
+	}
+	return operation.RetryOnConflict(p.db.Update, func(tx *badger.Txn) error {
+		err := operation.UpdateTimeoutConfigUpdate(p.chainID, &stored)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return operation.InsertTimeoutConfigUpdate(p.chainID, &stored)(tx)
+		}
+		return err
+	})
+}
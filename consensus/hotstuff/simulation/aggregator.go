@@ -0,0 +1,151 @@
+package simulation
+
+import (
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/module/mempool"
+)
+
+// voteAggregator is a synchronous and deterministic implementation of hotstuff.VoteAggregator.
+// The production aggregator processes votes on worker goroutines, which makes the order of
+// events depend on the Go scheduler. Signatures are not aggregated, the QC only lists the signers.
+type voteAggregator struct {
+	participants flow.IdentityList
+	threshold    uint64
+	notifier     hotstuff.Consumer
+	onQC         func(*flow.QuorumCertificate)
+
+	lowestRetainedView uint64
+	blocks             map[flow.Identifier]*model.Block
+	votes              map[flow.Identifier]map[flow.Identifier]*model.Vote // block ID -> signer ID -> vote
+	votesByView        map[uint64]map[flow.Identifier]*model.Vote          // view -> signer ID -> first vote
+	qcs                map[flow.Identifier]struct{}                        // blocks a QC was built for
+}
+
+var _ hotstuff.VoteAggregator = (*voteAggregator)(nil)
+
+func newVoteAggregator(participants flow.IdentityList, notifier hotstuff.Consumer, onQC func(*flow.QuorumCertificate)) *voteAggregator {
+	return &voteAggregator{
+		participants: participants,
+		threshold:    hotstuff.ComputeWeightThresholdForBuildingQC(participants.TotalWeight()),
+		notifier:     notifier,
+		onQC:         onQC,
+		blocks:       make(map[flow.Identifier]*model.Block),
+		votes:        make(map[flow.Identifier]map[flow.Identifier]*model.Vote),
+		votesByView:  make(map[uint64]map[flow.Identifier]*model.Vote),
+		qcs:          make(map[flow.Identifier]struct{}),
+	}
+}
+
+func (a *voteAggregator) Start(irrecoverable.SignalerContext) {}
+
+func (a *voteAggregator) Ready() <-chan struct{} { return closedChannel() }
+
+func (a *voteAggregator) Done() <-chan struct{} { return closedChannel() }
+
+func (a *voteAggregator) AddVote(vote *model.Vote) {
+	if vote.View < a.lowestRetainedView {
+		return
+	}
+
+	byView, ok := a.votesByView[vote.View]
+	if !ok {
+		byView = make(map[flow.Identifier]*model.Vote)
+		a.votesByView[vote.View] = byView
+	}
+	if first, ok := byView[vote.SignerID]; ok {
+		if first.BlockID != vote.BlockID {
+			a.notifier.OnDoubleVotingDetected(first, vote)
+		}
+		return
+	}
+	byView[vote.SignerID] = vote
+
+	byBlock, ok := a.votes[vote.BlockID]
+	if !ok {
+		byBlock = make(map[flow.Identifier]*model.Vote)
+		a.votes[vote.BlockID] = byBlock
+	}
+	byBlock[vote.SignerID] = vote
+
+	a.tryBuildQC(vote.BlockID)
+}
+
+func (a *voteAggregator) AddBlock(proposal *model.Proposal) error {
+	if proposal.Block.View < a.lowestRetainedView {
+		return mempool.NewDecreasingPruningHeightErrorf("block view %d is below lowest retained view %d",
+			proposal.Block.View, a.lowestRetainedView)
+	}
+	a.blocks[proposal.Block.BlockID] = proposal.Block
+	a.AddVote(proposal.ProposerVote())
+	a.tryBuildQC(proposal.Block.BlockID)
+	return nil
+}
+
+func (a *voteAggregator) InvalidBlock(proposal *model.Proposal) error {
+	if proposal.Block.View < a.lowestRetainedView {
+		return mempool.NewDecreasingPruningHeightErrorf("block view %d is below lowest retained view %d",
+			proposal.Block.View, a.lowestRetainedView)
+	}
+	return nil
+}
+
+func (a *voteAggregator) PruneUpToView(view uint64) {
+	if view <= a.lowestRetainedView {
+		return
+	}
+	a.lowestRetainedView = view
+	for blockID, block := range a.blocks {
+		if block.View < view {
+			delete(a.blocks, blockID)
+			delete(a.votes, blockID)
+			delete(a.qcs, blockID)
+		}
+	}
+	for v := range a.votesByView {
+		if v < view {
+			delete(a.votesByView, v)
+		}
+	}
+}
+
+// tryBuildQC builds a QC for the block, if the block is known and enough weight voted for it.
+// Every QC is only built once.
+func (a *voteAggregator) tryBuildQC(blockID flow.Identifier) {
+	block, ok := a.blocks[blockID]
+	if !ok {
+		return
+	}
+	if _, ok := a.qcs[blockID]; ok {
+		return
+	}
+
+	votes := a.votes[blockID]
+	var weight uint64
+	signerIDs := make([]flow.Identifier, 0, len(votes))
+	// iterate over the participants rather than the map, to get a deterministic signer order
+	for _, participant := range a.participants {
+		if _, ok := votes[participant.NodeID]; ok {
+			weight += participant.Weight
+			signerIDs = append(signerIDs, participant.NodeID)
+		}
+	}
+	if weight < a.threshold {
+		return
+	}
+
+	a.qcs[blockID] = struct{}{}
+	a.onQC(&flow.QuorumCertificate{
+		View:      block.View,
+		BlockID:   blockID,
+		SignerIDs: signerIDs,
+	})
+}
+
+func closedChannel() <-chan struct{} {
+	ch := make(chan struct{})
+	close(ch)
+	return ch
+}
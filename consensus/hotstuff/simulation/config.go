@@ -0,0 +1,244 @@
+package simulation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees/leader"
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// ByzantineBehavior is a deviation from the protocol a replica can be configured with.
+type ByzantineBehavior int
+
+const (
+	// Equivocation makes the replica propose two conflicting blocks whenever it is leader,
+	// sending one to half of the other replicas and the other block to the remaining replicas.
+	Equivocation ByzantineBehavior = iota + 1
+	// ProposalWithholding makes the replica never send its proposals to any other replica.
+	ProposalWithholding
+	// VoteWithholding makes the replica never send its votes to the next leader.
+	VoteWithholding
+	// Crash makes the replica not take part in the protocol at all, as if it crashed before the
+	// simulation started. All messages from and to the replica are lost.
+	Crash
+)
+
+func (b ByzantineBehavior) String() string {
+	switch b {
+	case Equivocation:
+		return "equivocation"
+	case ProposalWithholding:
+		return "proposal_withholding"
+	case VoteWithholding:
+		return "vote_withholding"
+	case Crash:
+		return "crash"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(b))
+	}
+}
+
+// Partition splits the replicas into groups which cannot communicate with each other during
+// the time window [Start, End) of the simulation. Replicas not listed in any group form one
+// additional group. Whether a message is delivered is decided when it is sent.
+type Partition struct {
+	Start  time.Duration
+	End    time.Duration
+	Groups [][]int // replica indices
+}
+
+// NetworkConfig describes the behaviour of the simulated network between replicas.
+// Messages a replica sends to itself are always delivered without delay.
+type NetworkConfig struct {
+	// MinDelay and MaxDelay bound the uniformly distributed delay of every message.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// DropRate is the probability a message is lost.
+	DropRate float64
+	// Partitions are the network partitions applied during the simulation.
+	Partitions []Partition
+}
+
+// Config contains the parameters of a simulation. All randomness of a simulation is derived
+// from Seed, so running the same configuration twice results in identical executions.
+type Config struct {
+	Seed     int64
+	Replicas int
+	Timeouts timeout.Config
+	Network  NetworkConfig
+	// Byzantine maps replica indices to the protocol deviations of the replica. The safety and
+	// liveness invariants are only checked for honest replicas.
+	Byzantine map[int][]ByzantineBehavior
+	// TargetHeight is the finalized height all honest replicas have to reach for the simulation
+	// to complete successfully.
+	TargetHeight uint64
+	// MaxDuration is the virtual time after which the simulation fails with a liveness violation,
+	// if the honest replicas did not reach TargetHeight.
+	MaxDuration time.Duration
+	// SyncRetryInterval is the virtual time after which a replica requests a missing block again.
+	SyncRetryInterval time.Duration
+	// Consumers optionally returns an additional notification consumer for the replica with the
+	// given index, for example to record its timeline.
+	Consumers func(index int) hotstuff.Consumer
+	// LeaderSelection optionally computes the leader selection for the given participants,
+	// which are ordered by replica index. By default, leaders are selected round-robin.
+	LeaderSelection func(participants flow.IdentityList) (*leader.LeaderSelection, error)
+	Log             zerolog.Logger
+}
+
+// Option modifies the simulation configuration.
+type Option func(*Config)
+
+// DefaultConfig returns a configuration for four honest replicas on a reliable network with
+// moderate message delays.
+func DefaultConfig() Config {
+	timeouts, err := timeout.NewConfig(
+		2*time.Second,
+		1*time.Second,
+		0.5,
+		1.5,
+		0.8,
+		0,
+	)
+	if err != nil {
+		// we check in a unit test that this does not happen
+		panic(fmt.Sprintf("default simulation timeout config is invalid: %v", err))
+	}
+
+	return Config{
+		Seed:     1,
+		Replicas: 4,
+		Timeouts: timeouts,
+		Network: NetworkConfig{
+			MinDelay: 10 * time.Millisecond,
+			MaxDelay: 100 * time.Millisecond,
+		},
+		Byzantine:         make(map[int][]ByzantineBehavior),
+		TargetHeight:      20,
+		MaxDuration:       10 * time.Minute,
+		SyncRetryInterval: time.Second,
+		Log:               zerolog.Nop(),
+	}
+}
+
+func WithSeed(seed int64) Option {
+	return func(cfg *Config) {
+		cfg.Seed = seed
+	}
+}
+
+func WithReplicas(replicas int) Option {
+	return func(cfg *Config) {
+		cfg.Replicas = replicas
+	}
+}
+
+func WithTimeouts(timeouts timeout.Config) Option {
+	return func(cfg *Config) {
+		cfg.Timeouts = timeouts
+	}
+}
+
+func WithMessageDelay(min time.Duration, max time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.Network.MinDelay = min
+		cfg.Network.MaxDelay = max
+	}
+}
+
+func WithDropRate(rate float64) Option {
+	return func(cfg *Config) {
+		cfg.Network.DropRate = rate
+	}
+}
+
+func WithPartition(start time.Duration, end time.Duration, groups ...[]int) Option {
+	return func(cfg *Config) {
+		cfg.Network.Partitions = append(cfg.Network.Partitions, Partition{
+			Start:  start,
+			End:    end,
+			Groups: groups,
+		})
+	}
+}
+
+func WithByzantine(index int, behaviors ...ByzantineBehavior) Option {
+	return func(cfg *Config) {
+		cfg.Byzantine[index] = append(cfg.Byzantine[index], behaviors...)
+	}
+}
+
+func WithTargetHeight(height uint64) Option {
+	return func(cfg *Config) {
+		cfg.TargetHeight = height
+	}
+}
+
+func WithMaxDuration(duration time.Duration) Option {
+	return func(cfg *Config) {
+		cfg.MaxDuration = duration
+	}
+}
+
+func WithConsumers(consumers func(index int) hotstuff.Consumer) Option {
+	return func(cfg *Config) {
+		cfg.Consumers = consumers
+	}
+}
+
+func WithLeaderSelection(selection func(participants flow.IdentityList) (*leader.LeaderSelection, error)) Option {
+	return func(cfg *Config) {
+		cfg.LeaderSelection = selection
+	}
+}
+
+func WithLog(log zerolog.Logger) Option {
+	return func(cfg *Config) {
+		cfg.Log = log
+	}
+}
+
+// validate checks the configuration for consistency.
+func (cfg *Config) validate() error {
+	if cfg.Replicas < 1 {
+		return fmt.Errorf("at least one replica is required, got %d", cfg.Replicas)
+	}
+	if cfg.Network.MinDelay < 0 || cfg.Network.MaxDelay < cfg.Network.MinDelay {
+		return fmt.Errorf("invalid message delay range [%v, %v]", cfg.Network.MinDelay, cfg.Network.MaxDelay)
+	}
+	if cfg.Network.DropRate < 0 || cfg.Network.DropRate >= 1 {
+		return fmt.Errorf("drop rate must be in range [0,1), got %v", cfg.Network.DropRate)
+	}
+	for _, partition := range cfg.Network.Partitions {
+		if partition.End < partition.Start {
+			return fmt.Errorf("partition ends (%v) before it starts (%v)", partition.End, partition.Start)
+		}
+		for _, group := range partition.Groups {
+			for _, index := range group {
+				if index < 0 || index >= cfg.Replicas {
+					return fmt.Errorf("partition contains unknown replica %d", index)
+				}
+			}
+		}
+	}
+	for index := range cfg.Byzantine {
+		if index < 0 || index >= cfg.Replicas {
+			return fmt.Errorf("byzantine behaviour configured for unknown replica %d", index)
+		}
+	}
+	if len(cfg.Byzantine) == cfg.Replicas {
+		return fmt.Errorf("at least one replica has to be honest")
+	}
+	if cfg.TargetHeight == 0 {
+		return fmt.Errorf("target height must be positive")
+	}
+	if cfg.SyncRetryInterval <= 0 {
+		return fmt.Errorf("sync retry interval must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package simulation
+
+import (
+	"math/rand"
+	"time"
+)
+
+// network decides whether and when messages between replicas are delivered.
+type network struct {
+	cfg NetworkConfig
+	rng *rand.Rand
+}
+
+func newNetwork(cfg NetworkConfig, rng *rand.Rand) *network {
+	return &network{
+		cfg: cfg,
+		rng: rng,
+	}
+}
+
+// route returns the delay of a message sent at virtual time `now` from replica `from` to
+// replica `to`. The boolean is false if the message is lost.
+func (n *network) route(now time.Duration, from int, to int) (time.Duration, bool) {
+	if from == to {
+		return 0, true
+	}
+	if n.partitioned(now, from, to) {
+		return 0, false
+	}
+	if n.cfg.DropRate > 0 && n.rng.Float64() < n.cfg.DropRate {
+		return 0, false
+	}
+	delay := n.cfg.MinDelay
+	if spread := n.cfg.MaxDelay - n.cfg.MinDelay; spread > 0 {
+		delay += time.Duration(n.rng.Int63n(int64(spread) + 1))
+	}
+	return delay, true
+}
+
+// partitioned returns true if the replicas are in different groups of a partition active at `now`.
+func (n *network) partitioned(now time.Duration, from int, to int) bool {
+	for _, partition := range n.cfg.Partitions {
+		if now < partition.Start || now >= partition.End {
+			continue
+		}
+		if groupOf(partition, from) != groupOf(partition, to) {
+			return true
+		}
+	}
+	return false
+}
+
+// groupOf returns the index of the partition group containing the replica, or -1 for
+// replicas not listed in any group.
+func groupOf(partition Partition, replica int) int {
+	for i, group := range partition.Groups {
+		for _, index := range group {
+			if index == replica {
+				return i
+			}
+		}
+	}
+	return -1
+}
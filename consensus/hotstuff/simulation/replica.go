@@ -0,0 +1,454 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/blockproducer"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees/leader"
+	"github.com/onflow/flow-go/consensus/hotstuff/eventhandler"
+	"github.com/onflow/flow-go/consensus/hotstuff/forks"
+	"github.com/onflow/flow-go/consensus/hotstuff/forks/finalizer"
+	"github.com/onflow/flow-go/consensus/hotstuff/forks/forkchoice"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications/pubsub"
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker"
+	"github.com/onflow/flow-go/consensus/hotstuff/pacemaker/timeout"
+	"github.com/onflow/flow-go/consensus/hotstuff/validator"
+	"github.com/onflow/flow-go/consensus/hotstuff/voter"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+)
+
+// replica is a single HotStuff participant of the simulation. It runs the production event
+// handler, pacemaker, forks, voter and validator. Crypto, storage, networking and vote
+// aggregation are replaced by deterministic in-memory implementations.
+type replica struct {
+	sim       *Simulation
+	index     int
+	nodeID    flow.Identifier
+	behaviors map[ByzantineBehavior]bool
+
+	headers   map[flow.Identifier]*flow.Header   // blocks processed by the replica
+	pending   map[flow.Identifier][]*flow.Header // blocks waiting for their parent, by parent ID
+	requested map[flow.Identifier]time.Duration  // missing blocks, by the virtual time they were last requested
+	finalized []*flow.Header                     // finalized blocks by height, starting with the root block
+	timerGen  uint64                             // generation of the currently active timeout
+
+	aggregator *voteAggregator
+	forks      *forks.Forks
+	handler    *eventhandler.EventHandler
+}
+
+var _ module.Builder = (*replica)(nil)
+var _ module.Finalizer = (*replica)(nil)
+var _ hotstuff.Communicator = (*replica)(nil)
+
+func newReplica(sim *Simulation, index int, behaviors []ByzantineBehavior) (*replica, error) {
+	r := &replica{
+		sim:       sim,
+		index:     index,
+		nodeID:    sim.participants[index].NodeID,
+		behaviors: make(map[ByzantineBehavior]bool),
+		headers:   map[flow.Identifier]*flow.Header{sim.root.ID(): sim.root},
+		pending:   make(map[flow.Identifier][]*flow.Header),
+		requested: make(map[flow.Identifier]time.Duration),
+		finalized: []*flow.Header{sim.root},
+	}
+	for _, behavior := range behaviors {
+		r.behaviors[behavior] = true
+	}
+
+	log := sim.cfg.Log.With().Int("replica", index).Hex("node_id", r.nodeID[:]).Logger()
+
+	notifier := pubsub.NewDistributor()
+	notifier.AddConsumer(&replicaConsumer{replica: r})
+	if sim.cfg.Consumers != nil {
+		if consumer := sim.cfg.Consumers(index); consumer != nil {
+			notifier.AddConsumer(consumer)
+		}
+	}
+
+	committee := &staticCommittee{participants: sim.participants, leaders: sim.leaders, self: r.nodeID}
+	signer := &fakeSigner{self: r.nodeID}
+	persist := &memoryPersister{}
+
+	controller := timeout.NewController(sim.cfg.Timeouts)
+	paceMaker, err := pacemaker.New(1, controller, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("could not create pacemaker: %w", err)
+	}
+
+	producer, err := blockproducer.New(signer, committee, r)
+	if err != nil {
+		return nil, fmt.Errorf("could not create block producer: %w", err)
+	}
+
+	rootBlock := model.BlockFromFlow(sim.root, 0)
+	rootQC := &flow.QuorumCertificate{
+		View:      rootBlock.View,
+		BlockID:   rootBlock.BlockID,
+		SignerIDs: sim.participants.NodeIDs(),
+	}
+	forkalizer, err := finalizer.New(&forks.BlockQC{Block: rootBlock, QC: rootQC}, r, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("could not create finalizer: %w", err)
+	}
+	choice, err := forkchoice.NewNewestForkChoice(forkalizer, notifier)
+	if err != nil {
+		return nil, fmt.Errorf("could not create fork choice: %w", err)
+	}
+	r.forks = forks.New(forkalizer, choice)
+
+	r.aggregator = newVoteAggregator(sim.participants, notifier, func(qc *flow.QuorumCertificate) {
+		// the production aggregator reports QCs asynchronously, hence we don't call into the
+		// event handler while it might be processing the vote
+		sim.scheduler.Schedule(0, fmt.Sprintf("qc %d view=%d block=%x", index, qc.View, qc.BlockID), func() error {
+			return r.handler.OnQCConstructed(qc)
+		})
+	})
+
+	r.handler, err = eventhandler.NewEventHandler(
+		log,
+		paceMaker,
+		producer,
+		r.forks,
+		persist,
+		r,
+		committee,
+		r.aggregator,
+		voter.New(signer, r.forks, persist, committee, 0),
+		validator.New(committee, r.forks, &fakeVerifier{}),
+		notifier,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create event handler: %w", err)
+	}
+
+	return r, nil
+}
+
+// honest returns true if the replica follows the protocol.
+func (r *replica) honest() bool {
+	return len(r.behaviors) == 0
+}
+
+// finalizedHeight returns the height of the latest block finalized by the replica.
+func (r *replica) finalizedHeight() uint64 {
+	return r.finalized[len(r.finalized)-1].Height
+}
+
+// onProposal processes a block received from replica `from`. Blocks are forwarded to the
+// event handler once their parent is known, missing ancestors are requested from the sender.
+func (r *replica) onProposal(from int, header *flow.Header) error {
+	blockID := header.ID()
+	if _, ok := r.headers[blockID]; ok {
+		return nil
+	}
+	if _, ok := r.headers[header.ParentID]; !ok {
+		r.pending[header.ParentID] = append(r.pending[header.ParentID], header)
+		r.requestBlock(from, header.ParentID)
+		return nil
+	}
+
+	queue := []*flow.Header{header}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+
+		nextID := next.ID()
+		if _, ok := r.headers[nextID]; ok {
+			continue
+		}
+		parent := r.headers[next.ParentID]
+		r.headers[nextID] = next
+		delete(r.requested, nextID)
+
+		err := r.handler.OnReceiveProposal(model.ProposalFromFlow(next, parent.View))
+		if err != nil {
+			return fmt.Errorf("could not process proposal %x: %w", nextID, err)
+		}
+
+		queue = append(queue, r.pending[nextID]...)
+		delete(r.pending, nextID)
+	}
+	return nil
+}
+
+// requestBlock asks replica `from` for a missing block, in place of the synchronization engine.
+// Requests for the same block are repeated at most once per SyncRetryInterval.
+func (r *replica) requestBlock(from int, blockID flow.Identifier) {
+	sim := r.sim
+	if requestedAt, ok := r.requested[blockID]; ok && sim.scheduler.Now()-requestedAt < sim.cfg.SyncRetryInterval {
+		return
+	}
+	r.requested[blockID] = sim.scheduler.Now()
+
+	peer := sim.replicas[from]
+	sim.send(r.index, from, fmt.Sprintf("sync request %d->%d block=%x", r.index, from, blockID), func() error {
+		header, ok := peer.headers[blockID]
+		if !ok {
+			return nil
+		}
+		sim.send(from, r.index, fmt.Sprintf("sync response %d->%d block=%x", from, r.index, blockID), func() error {
+			return r.onProposal(from, header)
+		})
+		return nil
+	})
+}
+
+// BuildOn implements module.Builder. Payloads are represented by a random payload hash.
+func (r *replica) BuildOn(parentID flow.Identifier, setter func(*flow.Header) error) (*flow.Header, error) {
+	parent, ok := r.headers[parentID]
+	if !ok {
+		return nil, fmt.Errorf("parent block not found (parent: %x)", parentID)
+	}
+	header := &flow.Header{
+		ChainID:     parent.ChainID,
+		ParentID:    parentID,
+		Height:      parent.Height + 1,
+		PayloadHash: r.sim.randomID(),
+		Timestamp:   r.sim.time(),
+	}
+	err := setter(header)
+	if err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// MakeValid implements module.Finalizer.
+func (r *replica) MakeValid(flow.Identifier) error {
+	return nil
+}
+
+// MakeFinal implements module.Finalizer. It checks that the finalized blocks form a chain
+// and reports them to the simulation for checking the safety invariant.
+func (r *replica) MakeFinal(blockID flow.Identifier) error {
+	header, ok := r.headers[blockID]
+	if !ok {
+		return fmt.Errorf("finalized block %x is unknown", blockID)
+	}
+	latest := r.finalized[len(r.finalized)-1]
+	if header.ParentID != latest.ID() || header.Height != latest.Height+1 {
+		r.sim.reportViolation(fmt.Errorf("%w: replica %d finalized block %x at height %d, which does not extend its latest finalized block %x at height %d",
+			ErrSafetyViolation, r.index, blockID, header.Height, latest.ID(), latest.Height))
+		return nil
+	}
+	r.finalized = append(r.finalized, header)
+	r.sim.onFinalized(r, header)
+	return nil
+}
+
+// SendVote implements hotstuff.Communicator.
+func (r *replica) SendVote(blockID flow.Identifier, view uint64, sigData []byte, recipientID flow.Identifier) error {
+	if r.behaviors[VoteWithholding] {
+		return nil
+	}
+	recipient, ok := r.sim.replicaByID(recipientID)
+	if !ok {
+		return fmt.Errorf("unknown vote recipient %x", recipientID)
+	}
+	vote := &model.Vote{
+		View:     view,
+		BlockID:  blockID,
+		SignerID: r.nodeID,
+		SigData:  sigData,
+	}
+	r.sim.send(r.index, recipient.index, fmt.Sprintf("vote %d->%d view=%d block=%x", r.index, recipient.index, view, blockID), func() error {
+		recipient.aggregator.AddVote(vote)
+		return nil
+	})
+	return nil
+}
+
+// BroadcastProposal implements hotstuff.Communicator.
+func (r *replica) BroadcastProposal(header *flow.Header) error {
+	return r.BroadcastProposalWithDelay(header, 0)
+}
+
+// BroadcastProposalWithDelay implements hotstuff.Communicator. The event handler computes the
+// delay from the real time spent building the block, hence the configured block rate delay is
+// used instead to keep the simulation deterministic.
+func (r *replica) BroadcastProposalWithDelay(header *flow.Header, _ time.Duration) error {
+	parent, ok := r.headers[header.ParentID]
+	if !ok {
+		return fmt.Errorf("parent for proposal not found (parent: %x)", header.ParentID)
+	}
+	header.ChainID = parent.ChainID
+	header.Height = parent.Height + 1
+
+	delay := time.Duration(r.sim.cfg.Timeouts.BlockRateDelayMS * float64(time.Millisecond))
+	r.sim.scheduler.Schedule(delay, fmt.Sprintf("broadcast %d view=%d block=%x", r.index, header.View, header.ID()), func() error {
+		r.broadcast(header)
+		return nil
+	})
+	return nil
+}
+
+// broadcast sends the proposal to all replicas, according to the replica's behaviour.
+func (r *replica) broadcast(header *flow.Header) {
+	sim := r.sim
+
+	// the replica always processes its own proposal
+	r.sendProposal(r.index, header)
+	if r.behaviors[ProposalWithholding] {
+		return
+	}
+
+	var conflicting *flow.Header
+	if r.behaviors[Equivocation] {
+		// a second block for the same view, differing only in its payload
+		copied := *header
+		copied.PayloadHash = sim.randomID()
+		conflicting = &copied
+		r.sendProposal(r.index, conflicting)
+	}
+
+	others := make([]int, 0, len(sim.replicas)-1)
+	for _, other := range sim.replicas {
+		if other.index != r.index {
+			others = append(others, other.index)
+		}
+	}
+	for i, to := range others {
+		if conflicting != nil && i >= len(others)/2 {
+			r.sendProposal(to, conflicting)
+			continue
+		}
+		r.sendProposal(to, header)
+	}
+}
+
+func (r *replica) sendProposal(to int, header *flow.Header) {
+	recipient := r.sim.replicas[to]
+	r.sim.send(r.index, to, fmt.Sprintf("proposal %d->%d view=%d block=%x", r.index, to, header.View, header.ID()), func() error {
+		return recipient.onProposal(r.index, header)
+	})
+}
+
+// scheduleTimeout schedules the timeout of the pacemaker in virtual time. Starting a new
+// timeout invalidates the previously scheduled one.
+func (r *replica) scheduleTimeout(info *model.TimerInfo) {
+	r.timerGen++
+	gen := r.timerGen
+	r.sim.scheduler.Schedule(info.Duration, fmt.Sprintf("timeout %d view=%d", r.index, info.View), func() error {
+		if gen != r.timerGen {
+			return nil
+		}
+		return r.handler.OnLocalTimeout()
+	})
+}
+
+// replicaConsumer connects the notifications of the HotStuff components to the simulation.
+type replicaConsumer struct {
+	notifications.NoopConsumer
+	replica *replica
+}
+
+func (c *replicaConsumer) OnStartingTimeout(info *model.TimerInfo) {
+	c.replica.scheduleTimeout(info)
+}
+
+func (c *replicaConsumer) OnFinalizedBlock(block *model.Block) {
+	c.replica.aggregator.PruneUpToView(block.View)
+}
+
+func (c *replicaConsumer) OnDoubleProposeDetected(*model.Block, *model.Block) {
+	c.replica.sim.doubleProposals++
+}
+
+// staticCommittee is a hotstuff.Committee with a fixed set of participants and a pre-computed
+// or round-robin leader selection.
+type staticCommittee struct {
+	participants flow.IdentityList
+	leaders      *leader.LeaderSelection // nil for round-robin leader selection
+	self         flow.Identifier
+}
+
+var _ hotstuff.Committee = (*staticCommittee)(nil)
+
+func (c *staticCommittee) Identities(_ flow.Identifier, selector flow.IdentityFilter) (flow.IdentityList, error) {
+	return c.participants.Filter(selector), nil
+}
+
+func (c *staticCommittee) Identity(_ flow.Identifier, participantID flow.Identifier) (*flow.Identity, error) {
+	identity, ok := c.participants.ByNodeID(participantID)
+	if !ok {
+		return nil, model.NewInvalidSignerErrorf("id %v is not a valid node id", participantID)
+	}
+	return identity, nil
+}
+
+func (c *staticCommittee) LeaderForView(view uint64) (flow.Identifier, error) {
+	if c.leaders != nil {
+		return c.leaders.LeaderForView(view)
+	}
+	return c.participants[view%uint64(len(c.participants))].NodeID, nil
+}
+
+func (c *staticCommittee) Self() flow.Identifier {
+	return c.self
+}
+
+func (c *staticCommittee) DKG(flow.Identifier) (hotstuff.DKG, error) {
+	return nil, errors.New("the simulation does not support random beacon DKG")
+}
+
+// fakeSigner creates proposals and votes whose signature is the signer's node ID.
+type fakeSigner struct {
+	self flow.Identifier
+}
+
+var _ hotstuff.Signer = (*fakeSigner)(nil)
+
+func (s *fakeSigner) CreateProposal(block *model.Block) (*model.Proposal, error) {
+	return &model.Proposal{Block: block, SigData: s.self[:]}, nil
+}
+
+func (s *fakeSigner) CreateVote(block *model.Block) (*model.Vote, error) {
+	return &model.Vote{
+		View:     block.View,
+		BlockID:  block.BlockID,
+		SignerID: s.self,
+		SigData:  s.self[:],
+	}, nil
+}
+
+// fakeVerifier accepts all signatures. Byzantine replicas of the simulation don't forge messages.
+type fakeVerifier struct{}
+
+var _ hotstuff.Verifier = (*fakeVerifier)(nil)
+
+func (v *fakeVerifier) VerifyVote(*flow.Identity, []byte, *model.Block) error {
+	return nil
+}
+
+func (v *fakeVerifier) VerifyQC(flow.IdentityList, []byte, *model.Block) error {
+	return nil
+}
+
+// memoryPersister is an in-memory hotstuff.Persister.
+type memoryPersister struct {
+	started uint64
+	voted   uint64
+}
+
+var _ hotstuff.Persister = (*memoryPersister)(nil)
+
+func (p *memoryPersister) GetStarted() (uint64, error) { return p.started, nil }
+
+func (p *memoryPersister) GetVoted() (uint64, error) { return p.voted, nil }
+
+func (p *memoryPersister) PutStarted(view uint64) error {
+	p.started = view
+	return nil
+}
+
+func (p *memoryPersister) PutVoted(view uint64) error {
+	p.voted = view
+	return nil
+}
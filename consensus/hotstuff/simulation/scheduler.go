@@ -0,0 +1,84 @@
+package simulation
+
+import (
+	"container/heap"
+	"time"
+)
+
+// event is an action scheduled for a point in virtual time.
+type event struct {
+	at          time.Duration // virtual time since the start of the simulation
+	seq         uint64        // tie breaker, events scheduled for the same time run in scheduling order
+	description string
+	run         func() error
+}
+
+// scheduler is a virtual clock with a queue of scheduled events. Events are executed one at a
+// time in order of their scheduled time, which makes the execution of a simulation independent
+// of the real time and the Go scheduler.
+type scheduler struct {
+	now    time.Duration
+	seq    uint64
+	events eventQueue
+}
+
+func newScheduler() *scheduler {
+	return &scheduler{}
+}
+
+// Now returns the current virtual time.
+func (s *scheduler) Now() time.Duration {
+	return s.now
+}
+
+// Schedule adds an event to run after the given delay (relative to the current virtual time).
+func (s *scheduler) Schedule(delay time.Duration, description string, run func() error) {
+	if delay < 0 {
+		delay = 0
+	}
+	s.seq++
+	heap.Push(&s.events, &event{
+		at:          s.now + delay,
+		seq:         s.seq,
+		description: description,
+		run:         run,
+	})
+}
+
+// Next removes the next event from the queue and advances the virtual clock to its scheduled time.
+// Returns nil if no events are scheduled.
+func (s *scheduler) Next() *event {
+	if s.events.Len() == 0 {
+		return nil
+	}
+	next := heap.Pop(&s.events).(*event)
+	s.now = next.at
+	return next
+}
+
+// eventQueue implements heap.Interface ordered by scheduled time and sequence number.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x interface{}) {
+	*q = append(*q, x.(*event))
+}
+
+func (q *eventQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*q = old[:n-1]
+	return item
+}
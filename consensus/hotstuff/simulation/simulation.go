@@ -0,0 +1,259 @@
+// Package simulation runs multiple HotStuff replicas in a single goroutine under a virtual clock.
+// Message delays, message loss, network partitions and Byzantine replicas are derived from a
+// seed, so every execution, including any consensus bug it uncovers, can be reproduced exactly
+// by running the simulation again with the same configuration.
+//
+// The replicas run the production event handler, pacemaker, forks, voter and validator, while
+// crypto, storage, networking and vote aggregation are replaced by deterministic in-memory
+// implementations. During the simulation the safety invariant (honest replicas never finalize
+// conflicting blocks) is checked on every finalized block, and the liveness invariant (all
+// honest replicas reach the target finalized height within the maximum duration) at the end.
+package simulation
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"math/rand"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/committees/leader"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+var (
+	// ErrSafetyViolation is returned if honest replicas finalized conflicting blocks.
+	ErrSafetyViolation = errors.New("safety violation")
+	// ErrLivenessViolation is returned if the honest replicas did not reach the target height in time.
+	ErrLivenessViolation = errors.New("liveness violation")
+)
+
+// genesisTime is the wall-clock time corresponding to the start of every simulation.
+var genesisTime = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Result summarizes a completed simulation.
+type Result struct {
+	// Duration is the virtual time the simulation took.
+	Duration time.Duration
+	// Events is the number of processed events (message deliveries, timeouts, ...).
+	Events uint64
+	// FinalizedHeights contains the latest finalized height of every replica, by replica index.
+	FinalizedHeights []uint64
+	// FinalizedBlocks contains the IDs of the blocks finalized by honest replicas, by height.
+	FinalizedBlocks []flow.Identifier
+	// DoubleProposals is the number of double proposals detected by all replicas.
+	DoubleProposals uint64
+	// Fingerprint is a hash over all processed events and the virtual time they were processed at.
+	// Two simulations with the same configuration have the same fingerprint.
+	Fingerprint flow.Identifier
+}
+
+// Simulation runs HotStuff replicas under a virtual clock.
+type Simulation struct {
+	cfg          Config
+	log          zerolog.Logger
+	rng          *rand.Rand
+	scheduler    *scheduler
+	network      *network
+	root         *flow.Header
+	participants flow.IdentityList
+	leaders      *leader.LeaderSelection // nil for round-robin leader selection
+	replicas     []*replica
+	byNodeID     map[flow.Identifier]*replica
+
+	finalized       map[uint64]flow.Identifier // blocks finalized by honest replicas, by height
+	violation       error
+	doubleProposals uint64
+	events          uint64
+	fingerprint     hash.Hash
+}
+
+// New creates a simulation with the default configuration modified by the given options.
+func New(options ...Option) (*Simulation, error) {
+	cfg := DefaultConfig()
+	for _, option := range options {
+		option(&cfg)
+	}
+	err := cfg.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid simulation config: %w", err)
+	}
+
+	rng := rand.New(rand.NewSource(cfg.Seed))
+	sim := &Simulation{
+		cfg:         cfg,
+		log:         cfg.Log.With().Str("component", "hotstuff_simulation").Int64("seed", cfg.Seed).Logger(),
+		rng:         rng,
+		scheduler:   newScheduler(),
+		network:     newNetwork(cfg.Network, rng),
+		byNodeID:    make(map[flow.Identifier]*replica),
+		finalized:   make(map[uint64]flow.Identifier),
+		fingerprint: sha256.New(),
+	}
+
+	sim.root = &flow.Header{
+		ChainID:   "simulation",
+		ParentID:  flow.ZeroID,
+		Height:    0,
+		View:      0,
+		Timestamp: genesisTime,
+	}
+	for i := 0; i < cfg.Replicas; i++ {
+		sim.participants = append(sim.participants, &flow.Identity{
+			NodeID: sim.randomID(),
+			Role:   flow.RoleConsensus,
+			Weight: flow.DefaultInitialWeight,
+		})
+	}
+
+	if cfg.LeaderSelection != nil {
+		sim.leaders, err = cfg.LeaderSelection(sim.participants)
+		if err != nil {
+			return nil, fmt.Errorf("could not compute leader selection: %w", err)
+		}
+	}
+
+	for i := 0; i < cfg.Replicas; i++ {
+		r, err := newReplica(sim, i, cfg.Byzantine[i])
+		if err != nil {
+			return nil, fmt.Errorf("could not create replica %d: %w", i, err)
+		}
+		sim.replicas = append(sim.replicas, r)
+		sim.byNodeID[r.nodeID] = r
+	}
+
+	return sim, nil
+}
+
+// Run executes the simulation until all honest replicas have finalized the target height.
+// It returns an error wrapping ErrSafetyViolation or ErrLivenessViolation if an invariant is
+// violated, or any other error if a replica failed to process an event.
+func (s *Simulation) Run() (*Result, error) {
+	for _, r := range s.replicas {
+		r := r
+		if r.behaviors[Crash] {
+			continue
+		}
+		s.scheduler.Schedule(0, fmt.Sprintf("start %d", r.index), func() error {
+			return r.handler.Start()
+		})
+	}
+
+	for {
+		next := s.scheduler.Next()
+		if next == nil {
+			return s.result(), fmt.Errorf("%w: no more events to process at %v", ErrLivenessViolation, s.scheduler.Now())
+		}
+		if next.at > s.cfg.MaxDuration {
+			return s.result(), fmt.Errorf("%w: honest replicas did not finalize height %d within %v (finalized heights: %v)",
+				ErrLivenessViolation, s.cfg.TargetHeight, s.cfg.MaxDuration, s.result().FinalizedHeights)
+		}
+
+		s.events++
+		_, _ = fmt.Fprintf(s.fingerprint, "%d %s\n", next.at, next.description)
+
+		err := next.run()
+		if err != nil {
+			return s.result(), fmt.Errorf("event %q at %v failed: %w", next.description, next.at, err)
+		}
+		if s.violation != nil {
+			return s.result(), s.violation
+		}
+		if s.reachedTarget() {
+			s.log.Info().
+				Dur("duration", s.scheduler.Now()).
+				Uint64("events", s.events).
+				Msg("simulation completed")
+			return s.result(), nil
+		}
+	}
+}
+
+// send delivers a message from replica `from` to replica `to` through the simulated network.
+func (s *Simulation) send(from int, to int, description string, deliver func() error) {
+	if s.replicas[from].behaviors[Crash] || s.replicas[to].behaviors[Crash] {
+		s.log.Debug().Str("message", description).Msg("message to or from crashed replica dropped")
+		return
+	}
+	delay, ok := s.network.route(s.scheduler.Now(), from, to)
+	if !ok {
+		s.log.Debug().Str("message", description).Msg("message dropped")
+		return
+	}
+	s.scheduler.Schedule(delay, description, deliver)
+}
+
+// onFinalized checks the safety invariant for a block finalized by the given replica.
+func (s *Simulation) onFinalized(r *replica, header *flow.Header) {
+	if !r.honest() {
+		return
+	}
+	blockID := header.ID()
+	finalized, ok := s.finalized[header.Height]
+	if !ok {
+		s.finalized[header.Height] = blockID
+		return
+	}
+	if finalized != blockID {
+		s.reportViolation(fmt.Errorf("%w: replica %d finalized block %x at height %d, but block %x was finalized at the same height by another honest replica",
+			ErrSafetyViolation, r.index, blockID, header.Height, finalized))
+	}
+}
+
+// reportViolation records the first invariant violation, which stops the simulation.
+func (s *Simulation) reportViolation(err error) {
+	if s.violation == nil {
+		s.violation = err
+	}
+}
+
+// reachedTarget returns true if all honest replicas finalized the target height.
+func (s *Simulation) reachedTarget() bool {
+	for _, r := range s.replicas {
+		if r.honest() && r.finalizedHeight() < s.cfg.TargetHeight {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Simulation) result() *Result {
+	result := &Result{
+		Duration:        s.scheduler.Now(),
+		Events:          s.events,
+		DoubleProposals: s.doubleProposals,
+	}
+	for _, r := range s.replicas {
+		result.FinalizedHeights = append(result.FinalizedHeights, r.finalizedHeight())
+	}
+	for height := uint64(1); ; height++ {
+		blockID, ok := s.finalized[height]
+		if !ok {
+			break
+		}
+		result.FinalizedBlocks = append(result.FinalizedBlocks, blockID)
+	}
+	copy(result.Fingerprint[:], s.fingerprint.Sum(nil))
+	return result
+}
+
+// replicaByID returns the replica with the given node ID.
+func (s *Simulation) replicaByID(nodeID flow.Identifier) (*replica, bool) {
+	r, ok := s.byNodeID[nodeID]
+	return r, ok
+}
+
+// randomID returns an identifier derived from the simulation's seed.
+func (s *Simulation) randomID() flow.Identifier {
+	var id flow.Identifier
+	_, _ = s.rng.Read(id[:])
+	return id
+}
+
+// time returns the wall-clock time corresponding to the current virtual time.
+func (s *Simulation) time() time.Time {
+	return genesisTime.Add(s.scheduler.Now())
+}
@@ -0,0 +1,197 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/committees/leader"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/consensus/hotstuff/notifications"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol/seed"
+)
+
+func TestDefaultConfig(t *testing.T) {
+	cfg := DefaultConfig()
+	require.NoError(t, cfg.validate())
+}
+
+func TestHappyPath(t *testing.T) {
+	sim, err := New(WithSeed(42))
+	require.NoError(t, err)
+
+	result, err := sim.Run()
+	require.NoError(t, err)
+
+	for _, height := range result.FinalizedHeights {
+		assert.GreaterOrEqual(t, height, uint64(20))
+	}
+	assert.GreaterOrEqual(t, len(result.FinalizedBlocks), 20)
+	assert.Zero(t, result.DoubleProposals)
+}
+
+// TestDeterminism verifies that a simulation is reproducible from its seed, even with
+// message loss and partitions.
+func TestDeterminism(t *testing.T) {
+	run := func(seed int64) *Result {
+		sim, err := New(
+			WithSeed(seed),
+			WithReplicas(7),
+			WithDropRate(0.1),
+			WithPartition(5*time.Second, 15*time.Second, []int{0, 1, 2}, []int{3, 4, 5, 6}),
+		)
+		require.NoError(t, err)
+		result, err := sim.Run()
+		require.NoError(t, err)
+		return result
+	}
+
+	first := run(7)
+	second := run(7)
+	assert.Equal(t, first, second)
+
+	other := run(8)
+	assert.NotEqual(t, first.Fingerprint, other.Fingerprint)
+}
+
+func TestPartitionHeals(t *testing.T) {
+	// no group holds a super-majority, so finalization stalls until the partition heals
+	sim, err := New(
+		WithReplicas(4),
+		WithPartition(0, 30*time.Second, []int{0, 1}, []int{2, 3}),
+		WithTargetHeight(10),
+	)
+	require.NoError(t, err)
+
+	result, err := sim.Run()
+	require.NoError(t, err)
+	assert.Greater(t, result.Duration, 30*time.Second)
+}
+
+func TestPermanentPartitionViolatesLiveness(t *testing.T) {
+	sim, err := New(
+		WithReplicas(4),
+		WithPartition(0, time.Hour, []int{0, 1}, []int{2, 3}),
+		WithMaxDuration(time.Minute),
+	)
+	require.NoError(t, err)
+
+	_, err = sim.Run()
+	require.ErrorIs(t, err, ErrLivenessViolation)
+}
+
+func TestByzantineReplicas(t *testing.T) {
+	t.Run("equivocating leader", func(t *testing.T) {
+		sim, err := New(
+			WithReplicas(7),
+			WithByzantine(0, Equivocation),
+			WithByzantine(1, Equivocation),
+		)
+		require.NoError(t, err)
+
+		result, err := sim.Run()
+		require.NoError(t, err)
+		assert.Greater(t, result.DoubleProposals, uint64(0))
+	})
+
+	t.Run("withholding leader", func(t *testing.T) {
+		sim, err := New(
+			WithReplicas(7),
+			WithByzantine(6, ProposalWithholding, VoteWithholding),
+		)
+		require.NoError(t, err)
+
+		_, err = sim.Run()
+		require.NoError(t, err)
+	})
+
+	// Finalization requires certified blocks from four consecutive views. With round-robin
+	// leader selection among four replicas, a single withholding leader prevents this forever.
+	t.Run("withholding leader among four replicas", func(t *testing.T) {
+		sim, err := New(
+			WithReplicas(4),
+			WithByzantine(3, ProposalWithholding, VoteWithholding),
+			WithMaxDuration(time.Minute),
+		)
+		require.NoError(t, err)
+
+		result, err := sim.Run()
+		require.ErrorIs(t, err, ErrLivenessViolation)
+		assert.Equal(t, []uint64{0, 0, 0}, result.FinalizedHeights[:3])
+	})
+}
+
+// participationRecorder records the QC participation of the blocks finalized by a replica.
+type participationRecorder struct {
+	notifications.NoopConsumer
+	participation *flow.QCParticipation
+}
+
+func (r *participationRecorder) OnFinalizedBlock(block *model.Block) {
+	r.participation.Add(block.QC.SignerIDs)
+}
+
+// TestLivenessWeightedLeaderSelection runs the simulation with crashed replicas, first with a
+// leader selection based on the replicas' weights only, then with the leader selection weighted
+// by the QC participation recorded during the first run, as the consensus committee does with
+// the participation of the previous epoch.
+func TestLivenessWeightedLeaderSelection(t *testing.T) {
+	randomSource := make([]byte, seed.RandomSourceLength)
+	run := func(selection func(flow.IdentityList) (*leader.LeaderSelection, error), consumers func(int) hotstuff.Consumer) *Result {
+		options := []Option{
+			WithReplicas(7),
+			WithByzantine(5, Crash),
+			WithByzantine(6, Crash),
+			WithTargetHeight(50),
+			WithLeaderSelection(selection),
+		}
+		if consumers != nil {
+			options = append(options, WithConsumers(consumers))
+		}
+		sim, err := New(options...)
+		require.NoError(t, err)
+		result, err := sim.Run()
+		require.NoError(t, err)
+		return result
+	}
+
+	recorder := &participationRecorder{participation: flow.NewQCParticipation()}
+	plain := run(func(participants flow.IdentityList) (*leader.LeaderSelection, error) {
+		rng, err := seed.PRGFromRandomSource(randomSource, seed.ProtocolConsensusLeaderSelection)
+		require.NoError(t, err)
+		return leader.ComputeLeaderSelection(0, rng, 10000, participants)
+	}, func(index int) hotstuff.Consumer {
+		if index == 0 {
+			return recorder
+		}
+		return nil
+	})
+	require.Greater(t, recorder.participation.Blocks, uint64(0))
+
+	livenessWeighted := run(func(participants flow.IdentityList) (*leader.LeaderSelection, error) {
+		rng, err := seed.PRGFromRandomSource(randomSource, seed.ProtocolConsensusLeaderSelection)
+		require.NoError(t, err)
+		return leader.ComputeLivenessWeightedLeaderSelection(0, rng, 10000, participants, recorder.participation, flow.DefaultLivenessWeighting)
+	}, nil)
+
+	// crashed leaders cost a timeout each, so preferring live leaders finalizes faster
+	assert.Less(t, livenessWeighted.Duration, plain.Duration)
+}
+
+func TestInvalidConfig(t *testing.T) {
+	_, err := New(WithReplicas(0))
+	require.Error(t, err)
+
+	_, err = New(WithDropRate(1))
+	require.Error(t, err)
+
+	_, err = New(WithByzantine(4, Equivocation))
+	require.Error(t, err)
+
+	_, err = New(WithPartition(0, time.Second, []int{0, 9}))
+	require.Error(t, err)
+}
@@ -0,0 +1,155 @@
+// Package slashing implements the verification of evidence for protocol violations
+// committed by HotStuff participants.
+package slashing
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+// ErrInvalidEvidence is returned if slashing evidence does not prove a protocol violation.
+var ErrInvalidEvidence = errors.New("invalid slashing evidence")
+
+// VerifyEvidence checks that the given evidence proves a protocol violation by the offender.
+// The identities must be the consensus committee of the epoch containing the evidence's view,
+// and the verifier must check signatures using the keys of that epoch.
+// It checks that:
+//   - the offender is a committee member with positive weight
+//   - the two signed messages are consistent with the type of the evidence, i.e. they were
+//     signed by the offender for different blocks in the evidence's view
+//   - both signatures are cryptographically valid
+//   - for a vote for an invalid block, the voted proposal is invalid, which is checked by
+//     re-validating it with the validator. The parent of the proposal is read from the headers.
+//
+// Returns:
+//   - nil if the evidence is valid
+//   - an error wrapping ErrInvalidEvidence if the evidence is invalid
+//   - any other error if the signatures could not be verified, which is unexpected
+func VerifyEvidence(evidence *flow.SlashingEvidence, identities flow.IdentityList, verifier hotstuff.Verifier, validator hotstuff.Validator, headers storage.Headers) error {
+	offender, ok := identities.ByNodeID(evidence.OffenderID)
+	if !ok {
+		return fmt.Errorf("offender %v is not a committee member: %w", evidence.OffenderID, ErrInvalidEvidence)
+	}
+	if offender.Weight == 0 {
+		return fmt.Errorf("offender %v has no weight: %w", evidence.OffenderID, ErrInvalidEvidence)
+	}
+
+	err := checkConsistency(evidence)
+	if err != nil {
+		return fmt.Errorf("inconsistent %s evidence: %v: %w", evidence.Type, err, ErrInvalidEvidence)
+	}
+
+	first := offender
+	second := offender
+	if evidence.Type == flow.SlashingEvidenceVoteForInvalidBlock {
+		// the second message is the proposal of the invalid block, signed by its proposer
+		proposer, ok := identities.ByNodeID(evidence.Second.SignerID)
+		if !ok {
+			return fmt.Errorf("proposer %v is not a committee member: %w", evidence.Second.SignerID, ErrInvalidEvidence)
+		}
+		second = proposer
+	}
+
+	err = verifySignedVote(first, evidence.First, verifier)
+	if err != nil {
+		return fmt.Errorf("could not verify first message: %w", err)
+	}
+	err = verifySignedVote(second, evidence.Second, verifier)
+	if err != nil {
+		return fmt.Errorf("could not verify second message: %w", err)
+	}
+
+	if evidence.Type == flow.SlashingEvidenceVoteForInvalidBlock {
+		err = verifyInvalidProposal(evidence.InvalidProposal, validator, headers)
+		if err != nil {
+			return fmt.Errorf("could not verify invalid proposal: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// verifyInvalidProposal re-validates the given proposal, and returns an error wrapping
+// ErrInvalidEvidence unless the validation proves that the proposal is invalid.
+func verifyInvalidProposal(header *flow.Header, validator hotstuff.Validator, headers storage.Headers) error {
+	parent, err := headers.ByBlockID(header.ParentID)
+	if errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("unknown parent %v of proposal: %w", header.ParentID, ErrInvalidEvidence)
+	}
+	if err != nil {
+		return fmt.Errorf("could not retrieve parent %v of proposal: %w", header.ParentID, err)
+	}
+
+	err = validator.ValidateProposal(model.ProposalFromFlow(header, parent.View))
+	if model.IsInvalidBlockError(err) {
+		return nil
+	}
+	if err == nil {
+		return fmt.Errorf("proposal %v is valid: %w", header.ID(), ErrInvalidEvidence)
+	}
+	if errors.Is(err, model.ErrUnverifiableBlock) || model.IsMissingBlockError(err) {
+		return fmt.Errorf("proposal %v cannot be validated: %v: %w", header.ID(), err, ErrInvalidEvidence)
+	}
+	return fmt.Errorf("unexpected error validating proposal %v: %w", header.ID(), err)
+}
+
+// checkConsistency checks that the signed messages of the evidence constitute the violation
+// described by the evidence type, without checking signatures.
+func checkConsistency(evidence *flow.SlashingEvidence) error {
+	if evidence.First.View != evidence.View || evidence.Second.View != evidence.View {
+		return fmt.Errorf("messages are not for view %d", evidence.View)
+	}
+
+	switch evidence.Type {
+	case flow.SlashingEvidenceDoubleVote, flow.SlashingEvidenceDoubleProposal:
+		if evidence.First.SignerID != evidence.OffenderID || evidence.Second.SignerID != evidence.OffenderID {
+			return fmt.Errorf("messages are not signed by the offender")
+		}
+		if evidence.First.BlockID == evidence.Second.BlockID {
+			return fmt.Errorf("messages are for the same block")
+		}
+	case flow.SlashingEvidenceVoteForInvalidBlock:
+		if evidence.First.SignerID != evidence.OffenderID {
+			return fmt.Errorf("vote is not signed by the offender")
+		}
+		if evidence.First.BlockID != evidence.Second.BlockID {
+			return fmt.Errorf("vote is not for the invalid proposal")
+		}
+		if evidence.InvalidProposal == nil {
+			return fmt.Errorf("invalid proposal is missing")
+		}
+		if evidence.InvalidProposal.ID() != evidence.First.BlockID {
+			return fmt.Errorf("vote is not for the invalid proposal")
+		}
+		if evidence.InvalidProposal.View != evidence.View ||
+			evidence.InvalidProposal.ProposerID != evidence.Second.SignerID ||
+			!bytes.Equal(evidence.InvalidProposal.ProposerSigData, evidence.Second.SigData) {
+			return fmt.Errorf("invalid proposal does not match the proposer's message")
+		}
+	default:
+		return fmt.Errorf("unknown evidence type")
+	}
+
+	return nil
+}
+
+func verifySignedVote(signer *flow.Identity, vote flow.SignedVote, verifier hotstuff.Verifier) error {
+	block := &model.Block{
+		View:    vote.View,
+		BlockID: vote.BlockID,
+	}
+	err := verifier.VerifyVote(signer, vote.SigData, block)
+	if errors.Is(err, model.ErrInvalidFormat) || errors.Is(err, model.ErrInvalidSignature) || model.IsInvalidSignerError(err) {
+		return fmt.Errorf("invalid signature of %v for block %v: %v: %w", signer.NodeID, vote.BlockID, err, ErrInvalidEvidence)
+	}
+	if err != nil {
+		return fmt.Errorf("unexpected error verifying signature of %v: %w", signer.NodeID, err)
+	}
+	return nil
+}
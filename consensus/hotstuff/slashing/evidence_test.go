@@ -0,0 +1,192 @@
+package slashing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/mocks"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	storagemock "github.com/onflow/flow-go/storage/mock"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestVerifyEvidence(t *testing.T) {
+	// identities are constructed without keys, as signatures are checked by the mocked verifier
+	var identities flow.IdentityList
+	for i := 0; i < 4; i++ {
+		identities = append(identities, &flow.Identity{
+			NodeID: unittest.IdentifierFixture(),
+			Role:   flow.RoleConsensus,
+			Weight: flow.DefaultInitialWeight,
+		})
+	}
+	offender := identities[0]
+
+	evidenceFixture := func() *flow.SlashingEvidence {
+		return unittest.SlashingEvidenceFixture(func(evidence *flow.SlashingEvidence) {
+			evidence.OffenderID = offender.NodeID
+			evidence.First.SignerID = offender.NodeID
+			evidence.Second.SignerID = offender.NodeID
+		})
+	}
+
+	validVerifier := func() *mocks.Verifier {
+		verifier := &mocks.Verifier{}
+		verifier.On("VerifyVote", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+		return verifier
+	}
+
+	t.Run("valid double vote", func(t *testing.T) {
+		evidence := evidenceFixture()
+		verifier := validVerifier()
+
+		require.NoError(t, VerifyEvidence(evidence, identities, verifier, nil, nil))
+		verifier.AssertNumberOfCalls(t, "VerifyVote", 2)
+	})
+
+	// voteForInvalidBlock returns evidence of a vote for an invalid proposal of the second identity, whose
+	// parent is known to the returned headers
+	voteForInvalidBlock := func() (*flow.SlashingEvidence, *storagemock.Headers) {
+		parent := unittest.BlockHeaderFixture()
+		proposal := unittest.BlockHeaderWithParentFixture(&parent)
+		proposal.ProposerID = identities[1].NodeID
+
+		evidence := evidenceFixture()
+		evidence.Type = flow.SlashingEvidenceVoteForInvalidBlock
+		evidence.View = proposal.View
+		evidence.First.View = proposal.View
+		evidence.First.BlockID = proposal.ID()
+		evidence.Second = flow.SignedVote{
+			View:     proposal.View,
+			BlockID:  proposal.ID(),
+			SignerID: proposal.ProposerID,
+			SigData:  proposal.ProposerSigData,
+		}
+		evidence.InvalidProposal = &proposal
+
+		headers := &storagemock.Headers{}
+		headers.On("ByBlockID", parent.ID()).Return(&parent, nil)
+		return evidence, headers
+	}
+
+	invalidBlockValidator := func(evidence *flow.SlashingEvidence) *mocks.Validator {
+		validator := &mocks.Validator{}
+		validator.On("ValidateProposal", mock.Anything).Return(model.InvalidBlockError{
+			BlockID: evidence.InvalidProposal.ID(),
+			View:    evidence.View,
+			Err:     errors.New("invalid proposal"),
+		})
+		return validator
+	}
+
+	t.Run("valid vote for invalid block", func(t *testing.T) {
+		proposer := identities[1]
+		evidence, headers := voteForInvalidBlock()
+		validator := invalidBlockValidator(evidence)
+
+		verifier := &mocks.Verifier{}
+		verifier.On("VerifyVote", offender, evidence.First.SigData, mock.Anything).Return(nil).Once()
+		verifier.On("VerifyVote", proposer, evidence.Second.SigData, mock.Anything).Return(nil).Once()
+
+		require.NoError(t, VerifyEvidence(evidence, identities, verifier, validator, headers))
+		verifier.AssertExpectations(t)
+		validator.AssertCalled(t, "ValidateProposal", mock.MatchedBy(func(proposal *model.Proposal) bool {
+			return proposal.Block.BlockID == evidence.First.BlockID
+		}))
+	})
+
+	t.Run("vote is not for the invalid proposal", func(t *testing.T) {
+		evidence, headers := voteForInvalidBlock()
+		// the rebuilt header of a proposal does not have the ID of the voted block
+		evidence.InvalidProposal.Height++
+
+		err := VerifyEvidence(evidence, identities, validVerifier(), invalidBlockValidator(evidence), headers)
+		require.True(t, errors.Is(err, ErrInvalidEvidence))
+	})
+
+	t.Run("vote for valid block", func(t *testing.T) {
+		evidence, headers := voteForInvalidBlock()
+		validator := &mocks.Validator{}
+		validator.On("ValidateProposal", mock.Anything).Return(nil)
+
+		err := VerifyEvidence(evidence, identities, validVerifier(), validator, headers)
+		require.True(t, errors.Is(err, ErrInvalidEvidence))
+	})
+
+	t.Run("vote for block with unknown parent", func(t *testing.T) {
+		evidence, _ := voteForInvalidBlock()
+		headers := &storagemock.Headers{}
+		headers.On("ByBlockID", mock.Anything).Return(nil, storage.ErrNotFound)
+
+		err := VerifyEvidence(evidence, identities, validVerifier(), invalidBlockValidator(evidence), headers)
+		require.True(t, errors.Is(err, ErrInvalidEvidence))
+	})
+
+	t.Run("vote for unverifiable block", func(t *testing.T) {
+		evidence, headers := voteForInvalidBlock()
+		validator := &mocks.Validator{}
+		validator.On("ValidateProposal", mock.Anything).Return(model.ErrUnverifiableBlock)
+
+		err := VerifyEvidence(evidence, identities, validVerifier(), validator, headers)
+		require.True(t, errors.Is(err, ErrInvalidEvidence))
+	})
+
+	t.Run("offender is not a committee member", func(t *testing.T) {
+		evidence := evidenceFixture()
+		evidence.OffenderID = unittest.IdentifierFixture()
+
+		err := VerifyEvidence(evidence, identities, validVerifier(), nil, nil)
+		require.True(t, errors.Is(err, ErrInvalidEvidence))
+	})
+
+	t.Run("offender has no weight", func(t *testing.T) {
+		evidence := evidenceFixture()
+		unweighted := identities.Copy()
+		unweighted[0].Weight = 0
+
+		err := VerifyEvidence(evidence, unweighted, validVerifier(), nil, nil)
+		require.True(t, errors.Is(err, ErrInvalidEvidence))
+	})
+
+	t.Run("votes for the same block", func(t *testing.T) {
+		evidence := evidenceFixture()
+		evidence.Second.BlockID = evidence.First.BlockID
+
+		err := VerifyEvidence(evidence, identities, validVerifier(), nil, nil)
+		require.True(t, errors.Is(err, ErrInvalidEvidence))
+	})
+
+	t.Run("votes for different views", func(t *testing.T) {
+		evidence := evidenceFixture()
+		evidence.Second.View++
+
+		err := VerifyEvidence(evidence, identities, validVerifier(), nil, nil)
+		require.True(t, errors.Is(err, ErrInvalidEvidence))
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		evidence := evidenceFixture()
+		verifier := &mocks.Verifier{}
+		verifier.On("VerifyVote", mock.Anything, evidence.First.SigData, mock.Anything).Return(nil)
+		verifier.On("VerifyVote", mock.Anything, evidence.Second.SigData, mock.Anything).Return(model.ErrInvalidSignature)
+
+		err := VerifyEvidence(evidence, identities, verifier, nil, nil)
+		require.True(t, errors.Is(err, ErrInvalidEvidence))
+	})
+
+	t.Run("unexpected verification error", func(t *testing.T) {
+		evidence := evidenceFixture()
+		exception := errors.New("exception")
+		verifier := &mocks.Verifier{}
+		verifier.On("VerifyVote", mock.Anything, mock.Anything, mock.Anything).Return(exception)
+
+		err := VerifyEvidence(evidence, identities, verifier, nil, nil)
+		require.True(t, errors.Is(err, exception))
+		require.False(t, errors.Is(err, ErrInvalidEvidence))
+	})
+}
@@ -75,6 +75,7 @@ func (n *Network) Register(channel network.Channel, engine network.MessageProces
 		cancel:  cancel,
 		net:     n,
 		channel: channel,
+		engine:  engine,
 		queue:   make(chan message, 1024),
 	}
 
@@ -146,6 +147,40 @@ func (n *Network) unicast(event interface{}, channel network.Channel, targetID f
 	return nil
 }
 
+// request is called when the attached Engine to the channel is sending a request to a single target Engine attached
+// to the same channel on another node, which serves the request synchronously.
+func (n *Network) request(ctx context.Context, event interface{}, channel network.Channel, targetID flow.Identifier) (interface{}, error) {
+	net, found := n.hub.networks[targetID]
+	if !found {
+		return nil, fmt.Errorf("could not find target network on hub: %x", targetID)
+	}
+	con, found := net.conduits[channel]
+	if !found {
+		return nil, fmt.Errorf("invalid channel (%d) for target ID (%x)", targetID, channel)
+	}
+	processor, ok := con.engine.(network.RequestProcessor)
+	if !ok {
+		return nil, network.NewRemoteRequestError(fmt.Sprintf("no engine serves requests on channel %s", channel))
+	}
+
+	sender, receiver := n.node, net.node
+	block, delay := n.hub.filter(channel, event, sender, receiver)
+	// a blocked request is never answered
+	if block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	// sleep in order to simulate the network delay
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(delay):
+	}
+
+	return processor.ProcessRequest(channel, n.originID, event)
+}
+
 // publish is called when the attached Engine is sending an event to a group of Engines attached to the
 // same channel on other nodes based on selector.
 // In this test helper implementation, publish uses submit method under the hood.
@@ -166,6 +201,7 @@ type Conduit struct {
 	cancel  context.CancelFunc
 	net     *Network
 	channel network.Channel
+	engine  network.MessageProcessor
 	queue   chan message
 }
 
@@ -197,6 +233,16 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.net.multicast(event, c.channel, num, targetIDs...)
 }
 
+func (c *Conduit) Request(ctx context.Context, request interface{}, targetID flow.Identifier) (interface{}, error) {
+	if c.ctx.Err() != nil {
+		return nil, fmt.Errorf("conduit closed")
+	}
+	return c.net.request(ctx, request, c.channel, targetID)
+}
+
+func (c *Conduit) ReportMisbehavior(originID flow.Identifier, misbehavior network.Misbehavior) {
+}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit closed")
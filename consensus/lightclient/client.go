@@ -0,0 +1,314 @@
+// Package lightclient verifies finalized block headers without trusting the node serving them.
+//
+// Starting from a trusted root snapshot, the client verifies chains of headers. Every header contains
+// the QC certifying its parent, which is validated against the consensus committee and the random
+// beacon keys of the epoch the parent belongs to. A header is considered finalized if it is followed
+// by two children with consecutive views and the last child is certified, which is the finalization
+// rule of HotStuff. The committees of future epochs are learned from EpochSetup and EpochCommit service
+// events, which are provided as proofs (see flow.ServiceEventProof) referencing the block sealing them.
+//
+// Limitations: the client uses the initial identities of each epoch, so it does not observe nodes
+// ejected during an epoch, and it does not support epoch fallback, where the committee of the
+// last epoch continues beyond the epoch's final view.
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+)
+
+var (
+	// ErrUnknownEpoch is returned if a header belongs to an epoch whose committee is not known,
+	// typically because the service event proofs for the epoch have not been provided.
+	ErrUnknownEpoch = errors.New("no committed epoch known for view")
+	// ErrInvalidHeader is returned if a header does not extend the chain or contains an invalid QC.
+	ErrInvalidHeader = errors.New("invalid header")
+	// ErrInvalidProof is returned if a service event proof is invalid.
+	ErrInvalidProof = errors.New("invalid service event proof")
+)
+
+// VerifierFactory creates the signature verifier for QCs of the given epoch committee.
+type VerifierFactory func(committee hotstuff.Committee) hotstuff.Verifier
+
+// Client verifies finalized headers, starting from a trusted root snapshot.
+// It is safe for concurrent use.
+type Client struct {
+	mu          sync.RWMutex
+	newVerifier VerifierFactory
+	state       *epochState
+	finalized   *flow.Header
+	proofs      map[flow.Identifier]*flow.ServiceEventProof // pending proofs, by ID of the sealing block
+}
+
+// epochState contains the epochs known to the client. It is replaced rather than modified,
+// so a tentative state can be computed while verifying headers.
+type epochState struct {
+	previous *epoch // the epoch before current, nil if unknown
+	current  *epoch // the epoch of the latest verified header
+	next     *epoch // the set up or committed next epoch, nil if not set up
+}
+
+// NewClient creates a light client trusting the head of the given snapshot.
+func NewClient(root protocol.Snapshot, newVerifier VerifierFactory) (*Client, error) {
+	head, err := root.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get root head: %w", err)
+	}
+
+	current, err := epochFromProtocol(root.Epochs().Current(), newVerifier)
+	if err != nil {
+		return nil, fmt.Errorf("could not get current epoch: %w", err)
+	}
+	state := &epochState{current: current}
+
+	state.previous, err = epochFromProtocol(root.Epochs().Previous(), newVerifier)
+	if errors.Is(err, protocol.ErrNoPreviousEpoch) {
+		state.previous = nil
+	} else if err != nil {
+		return nil, fmt.Errorf("could not get previous epoch: %w", err)
+	}
+
+	next := root.Epochs().Next()
+	state.next, err = epochFromProtocol(next, newVerifier)
+	if errors.Is(err, protocol.ErrNextEpochNotSetup) {
+		state.next = nil
+	} else if errors.Is(err, protocol.ErrEpochNotCommitted) {
+		// the next epoch is set up, but the commit event must be provided as proof
+		state.next, err = setupEpochFromProtocol(next)
+		if err != nil {
+			return nil, fmt.Errorf("could not get next epoch: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("could not get next epoch: %w", err)
+	}
+
+	return &Client{
+		newVerifier: newVerifier,
+		state:       state,
+		finalized:   head,
+		proofs:      make(map[flow.Identifier]*flow.ServiceEventProof),
+	}, nil
+}
+
+// Finalized returns the latest verified finalized header.
+func (c *Client) Finalized() *flow.Header {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.finalized
+}
+
+// EpochCounter returns the counter of the epoch of the latest verified finalized header.
+func (c *Client) EpochCounter() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state.current.counter
+}
+
+// AddServiceEventProof adds a proof for service events sealed in the given block. The service events are
+// applied once the block is verified to be finalized, hence proofs must be added before verifying the
+// headers containing the sealing block. Returns ErrInvalidProof if the proof is inconsistent or the
+// block is already finalized.
+func (c *Client) AddServiceEventProof(proof *flow.ServiceEventProof) error {
+	_, err := proof.ServiceEvents()
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidProof, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if proof.Header.Height <= c.finalized.Height {
+		return fmt.Errorf("%w: block at height %d is not above the latest finalized height %d",
+			ErrInvalidProof, proof.Header.Height, c.finalized.Height)
+	}
+	c.proofs[proof.Header.ID()] = proof
+	return nil
+}
+
+// VerifyHeaders verifies a chain of headers extending the latest finalized header and returns the new latest
+// finalized header. Headers following the last finalized header must be provided again in subsequent calls.
+// The returned header is unchanged if none of the headers could be proven to be finalized yet.
+// Expected errors:
+//   - ErrInvalidHeader if the headers don't extend the finalized header or contain an invalid QC
+//   - ErrUnknownEpoch if the headers reach an epoch, whose service event proofs were not provided
+//   - ErrInvalidProof if a proof for one of the headers contains invalid service events
+func (c *Client) VerifyHeaders(headers []*flow.Header) (*flow.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// verify all QCs, tracking the epoch state after each header
+	states := make([]*epochState, len(headers))
+	state := c.state
+	parent := c.finalized
+	for i, header := range headers {
+		err := c.verifyChild(state, parent, header)
+		if err != nil {
+			return c.finalized, err
+		}
+		state, err = c.advance(state, header)
+		if err != nil {
+			return c.finalized, err
+		}
+		states[i] = state
+		parent = header
+	}
+
+	// find the latest header b with a direct 2-chain b <- b' <- b'' on top, where b'' is certified by a QC
+	// contained in a following header, which finalizes b
+	for i := len(headers) - 4; i >= 0; i-- {
+		if headers[i+1].View != headers[i].View+1 || headers[i+2].View != headers[i].View+2 {
+			continue
+		}
+		c.finalized = headers[i]
+		c.state = states[i]
+		for blockID, proof := range c.proofs {
+			if proof.Header.Height <= c.finalized.Height {
+				delete(c.proofs, blockID)
+			}
+		}
+		break
+	}
+
+	return c.finalized, nil
+}
+
+// verifyChild verifies that the header is a child of the parent and contains a valid QC for the parent.
+func (c *Client) verifyChild(state *epochState, parent *flow.Header, header *flow.Header) error {
+	parentID := parent.ID()
+	if header.ParentID != parentID {
+		return fmt.Errorf("%w: header %x does not extend block %x", ErrInvalidHeader, header.ID(), parentID)
+	}
+	if header.Height != parent.Height+1 || header.View <= parent.View {
+		return fmt.Errorf("%w: header %x has inconsistent height %d or view %d", ErrInvalidHeader, header.ID(), header.Height, header.View)
+	}
+	if header.ChainID != parent.ChainID {
+		return fmt.Errorf("%w: header %x is on chain %s instead of %s", ErrInvalidHeader, header.ID(), header.ChainID, parent.ChainID)
+	}
+
+	e, err := state.epochForView(parent.View)
+	if err != nil {
+		return err
+	}
+	qc := &flow.QuorumCertificate{
+		View:      parent.View,
+		BlockID:   header.ParentID,
+		SignerIDs: header.ParentVoterIDs,
+		SigData:   header.ParentVoterSigData,
+	}
+	// the parent's own QC is not required for validating the QC certifying the parent
+	block := &model.Block{
+		BlockID:     parentID,
+		View:        parent.View,
+		ProposerID:  parent.ProposerID,
+		PayloadHash: parent.PayloadHash,
+		Timestamp:   parent.Timestamp,
+	}
+	err = e.validator.ValidateQC(qc, block)
+	if model.IsInvalidBlockError(err) {
+		return fmt.Errorf("%w: invalid QC for block %x: %s", ErrInvalidHeader, parentID, err)
+	}
+	if err != nil {
+		return fmt.Errorf("could not validate QC for block %x: %w", parentID, err)
+	}
+	return nil
+}
+
+// advance returns the epoch state after the given header, applying proven service events sealed in the
+// header and switching to the next epoch when the header is its first block.
+func (c *Client) advance(state *epochState, header *flow.Header) (*epochState, error) {
+	proof, ok := c.proofs[header.ID()]
+	if ok {
+		var err error
+		state, err = c.applyServiceEvents(state, proof)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if state.current.containsView(header.View) {
+		return state, nil
+	}
+	if state.next == nil || !state.next.isCommitted() || !state.next.containsView(header.View) {
+		return nil, fmt.Errorf("%w: view %d of block %x is after epoch %d", ErrUnknownEpoch, header.View, header.ID(), state.current.counter)
+	}
+	return &epochState{
+		previous: state.current,
+		current:  state.next,
+	}, nil
+}
+
+// applyServiceEvents returns the epoch state after applying the service events of the proof.
+func (c *Client) applyServiceEvents(state *epochState, proof *flow.ServiceEventProof) (*epochState, error) {
+	events, err := proof.ServiceEvents()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err)
+	}
+
+	updated := *state
+	for _, event := range events {
+		switch ev := event.Event.(type) {
+		case *flow.EpochSetup:
+			if updated.next != nil {
+				return nil, fmt.Errorf("%w: epoch %d is already set up", ErrInvalidProof, ev.Counter)
+			}
+			if ev.Counter != updated.current.counter+1 || ev.FirstView != updated.current.finalView+1 || ev.FinalView < ev.FirstView {
+				return nil, fmt.Errorf("%w: setup for epoch %d (views %d-%d) does not extend epoch %d (final view %d)", ErrInvalidProof,
+					ev.Counter, ev.FirstView, ev.FinalView, updated.current.counter, updated.current.finalView)
+			}
+			updated.next = newSetupEpoch(ev)
+		case *flow.EpochCommit:
+			if updated.next == nil || updated.next.isCommitted() {
+				return nil, fmt.Errorf("%w: commit for epoch %d, which is not set up or already committed", ErrInvalidProof, ev.Counter)
+			}
+			updated.next, err = updated.next.committed(ev, c.newVerifier)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s", ErrInvalidProof, err)
+			}
+		default:
+			// other service events don't change the consensus committee
+		}
+	}
+	return &updated, nil
+}
+
+// epochForView returns the committed epoch containing the given view.
+func (s *epochState) epochForView(view uint64) (*epoch, error) {
+	for _, e := range []*epoch{s.current, s.previous, s.next} {
+		if e != nil && e.isCommitted() && e.containsView(view) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnknownEpoch, view)
+}
+
+// setupEpochFromProtocol returns the given set up, but not yet committed epoch of a protocol state snapshot.
+func setupEpochFromProtocol(from protocol.Epoch) (*epoch, error) {
+	counter, err := from.Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch counter: %w", err)
+	}
+	firstView, err := from.FirstView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch first view: %w", err)
+	}
+	finalView, err := from.FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch final view: %w", err)
+	}
+	participants, err := from.InitialIdentities()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch initial identities: %w", err)
+	}
+	return &epoch{
+		counter:      counter,
+		firstView:    firstView,
+		finalView:    finalView,
+		participants: participants,
+	}, nil
+}
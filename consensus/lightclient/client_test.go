@@ -0,0 +1,286 @@
+package lightclient
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/model"
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/order"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// fakeVerifier accepts QCs whose signature data is the ID of the certified block, so tests
+// don't require BLS signatures.
+type fakeVerifier struct{}
+
+var _ hotstuff.Verifier = (*fakeVerifier)(nil)
+
+func (fakeVerifier) VerifyVote(*flow.Identity, []byte, *model.Block) error {
+	return nil
+}
+
+func (fakeVerifier) VerifyQC(_ flow.IdentityList, sigData []byte, block *model.Block) error {
+	if !bytes.Equal(sigData, block.BlockID[:]) {
+		return model.ErrInvalidSignature
+	}
+	return nil
+}
+
+func (fakeVerifier) VerifyTC(flow.IdentityList, []byte, uint64, []uint64) error {
+	return nil
+}
+
+func newFakeVerifier(hotstuff.Committee) hotstuff.Verifier {
+	return fakeVerifier{}
+}
+
+// chainFixture builds headers signed by the consensus committee.
+type chainFixture struct {
+	t         *testing.T
+	committee flow.IdentityList
+}
+
+func newChainFixture(t *testing.T) *chainFixture {
+	committee := make(flow.IdentityList, 0, 4)
+	for i := 0; i < 4; i++ {
+		committee = append(committee, &flow.Identity{
+			NodeID: unittest.IdentifierFixture(),
+			Role:   flow.RoleConsensus,
+			Weight: flow.DefaultInitialWeight,
+		})
+	}
+	return &chainFixture{t: t, committee: committee.Sort(order.Canonical)}
+}
+
+// child returns a child of the parent with the given view, whose QC for the parent is signed by all
+// committee members.
+func (c *chainFixture) child(parent *flow.Header, view uint64) *flow.Header {
+	parentID := parent.ID()
+	return &flow.Header{
+		ChainID:            parent.ChainID,
+		ParentID:           parentID,
+		Height:             parent.Height + 1,
+		PayloadHash:        unittest.IdentifierFixture(),
+		View:               view,
+		ParentVoterIDs:     c.committee.NodeIDs(),
+		ParentVoterSigData: parentID[:],
+		ProposerID:         c.committee[int(view)%len(c.committee)].NodeID,
+	}
+}
+
+// extend returns a chain of children extending the parent with the given views.
+func (c *chainFixture) extend(parent *flow.Header, views ...uint64) []*flow.Header {
+	headers := make([]*flow.Header, 0, len(views))
+	for _, view := range views {
+		parent = c.child(parent, view)
+		headers = append(headers, parent)
+	}
+	return headers
+}
+
+// dkg returns the DKG of the committee. The keys are never used by the fake verifier.
+func (c *chainFixture) dkg() *inmem.EncodableDKG {
+	participants := make(map[flow.Identifier]flow.DKGParticipant, len(c.committee))
+	for i, identity := range c.committee {
+		participants[identity.NodeID] = flow.DKGParticipant{Index: uint(i)}
+	}
+	return &inmem.EncodableDKG{Participants: participants}
+}
+
+// rootSnapshot returns a snapshot with the given head in epoch 1, spanning views 0 to 200.
+func (c *chainFixture) rootSnapshot(head *flow.Header) *inmem.Snapshot {
+	return inmem.SnapshotFromEncodable(inmem.EncodableSnapshot{
+		Head: head,
+		Epochs: inmem.EncodableEpochs{
+			Current: inmem.EncodableEpoch{
+				Counter:           1,
+				FirstView:         0,
+				FinalView:         200,
+				InitialIdentities: c.committee,
+				DKG:               c.dkg(),
+			},
+		},
+	})
+}
+
+// epochProof returns the header of a block sealing the setup and commit events for epoch 2,
+// spanning views 201 to 400, together with the proof.
+func (c *chainFixture) epochProof(parent *flow.Header, view uint64) (*flow.Header, *flow.ServiceEventProof) {
+	keys := make([]crypto.PublicKey, 0, len(c.committee))
+	for range c.committee {
+		keys = append(keys, unittest.KeyFixture(crypto.ECDSAP256).PublicKey())
+	}
+	setup := &flow.EpochSetup{
+		Counter:      2,
+		FirstView:    201,
+		FinalView:    400,
+		Participants: c.committee,
+		RandomSource: unittest.SeedFixture(flow.EpochSetupRandomSourceLength),
+	}
+	commit := &flow.EpochCommit{
+		Counter:            2,
+		DKGGroupKey:        unittest.KeyFixture(crypto.ECDSAP256).PublicKey(),
+		DKGParticipantKeys: keys,
+	}
+	result := unittest.ExecutionResultFixture(func(result *flow.ExecutionResult) {
+		result.ServiceEvents = flow.ServiceEventList{setup.ServiceEvent(), commit.ServiceEvent()}
+	})
+	payload := unittest.PayloadFixture(unittest.WithSeals(unittest.Seal.Fixture(unittest.Seal.WithResult(result))))
+
+	header := c.child(parent, view)
+	header.PayloadHash = payload.Hash()
+	return header, &flow.ServiceEventProof{Header: header, Payload: &payload, Results: []*flow.ExecutionResult{result}}
+}
+
+func TestVerifyHeaders(t *testing.T) {
+	chain := newChainFixture(t)
+	header := unittest.BlockHeaderFixture()
+	root := &header
+	root.Height = 10
+	root.View = 100
+
+	t.Run("finalizes the first block of a direct 2-chain with certified tail", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+
+		headers := chain.extend(root, 101, 102, 103, 104)
+		finalized, err := client.VerifyHeaders(headers)
+		require.NoError(t, err)
+		assert.Equal(t, headers[0], finalized)
+		assert.Equal(t, headers[0], client.Finalized())
+
+		// the remaining headers are finalized once they are extended
+		finalized, err = client.VerifyHeaders(append(headers[1:], chain.extend(headers[3], 105)...))
+		require.NoError(t, err)
+		assert.Equal(t, headers[1], finalized)
+	})
+
+	t.Run("views with gaps don't finalize", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+
+		headers := chain.extend(root, 101, 103, 104, 105)
+		finalized, err := client.VerifyHeaders(headers)
+		require.NoError(t, err)
+		assert.Equal(t, root, finalized)
+
+		// without a child of the third block, its QC is unknown
+		finalized, err = client.VerifyHeaders(headers[:3])
+		require.NoError(t, err)
+		assert.Equal(t, root, finalized)
+
+		finalized, err = client.VerifyHeaders(append(headers, chain.extend(headers[3], 106)...))
+		require.NoError(t, err)
+		assert.Equal(t, headers[1], finalized)
+	})
+
+	t.Run("broken chain", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+
+		headers := chain.extend(root, 101, 102, 103, 104)
+		headers[2] = chain.child(headers[0], 103)
+		_, err = client.VerifyHeaders(headers)
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+		assert.Equal(t, root, client.Finalized())
+	})
+
+	t.Run("insufficient signer weight", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+
+		headers := chain.extend(root, 101, 102, 103, 104)
+		headers[3].ParentVoterIDs = headers[3].ParentVoterIDs[:2]
+		_, err = client.VerifyHeaders(headers)
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+		assert.Equal(t, root, client.Finalized())
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+
+		headers := chain.extend(root, 101, 102, 103, 104)
+		headers[1].ParentVoterSigData = unittest.RandomBytes(32)
+		_, err = client.VerifyHeaders(headers)
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+	})
+
+	t.Run("unknown signer", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+
+		headers := chain.extend(root, 101, 102, 103, 104)
+		headers[1].ParentVoterIDs[0] = unittest.IdentifierFixture()
+		_, err = client.VerifyHeaders(headers)
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+	})
+}
+
+func TestEpochTransition(t *testing.T) {
+	chain := newChainFixture(t)
+	header := unittest.BlockHeaderFixture()
+	root := &header
+	root.Height = 10
+	root.View = 150
+
+	sealing, proof := chain.epochProof(root, 151)
+	headers := append([]*flow.Header{sealing}, chain.extend(sealing, 152, 199, 200, 201, 202, 203, 204)...)
+
+	t.Run("follows the epoch transition with proof", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+		require.NoError(t, client.AddServiceEventProof(proof))
+
+		finalized, err := client.VerifyHeaders(headers)
+		require.NoError(t, err)
+		assert.Equal(t, headers[4], finalized) // view 201, in epoch 2
+		assert.Equal(t, uint64(2), client.EpochCounter())
+
+		// proofs for finalized blocks are rejected
+		err = client.AddServiceEventProof(proof)
+		assert.ErrorIs(t, err, ErrInvalidProof)
+	})
+
+	t.Run("missing proof", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+
+		_, err = client.VerifyHeaders(headers)
+		assert.ErrorIs(t, err, ErrUnknownEpoch)
+		assert.Equal(t, root, client.Finalized())
+		assert.Equal(t, uint64(1), client.EpochCounter())
+	})
+
+	t.Run("proof with tampered events", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+
+		tampered := *proof
+		result := *proof.Results[0]
+		result.ServiceEvents = result.ServiceEvents[:1]
+		tampered.Results = []*flow.ExecutionResult{&result}
+		err = client.AddServiceEventProof(&tampered)
+		assert.ErrorIs(t, err, ErrInvalidProof)
+	})
+
+	t.Run("proof with tampered payload", func(t *testing.T) {
+		client, err := NewClient(chain.rootSnapshot(root), newFakeVerifier)
+		require.NoError(t, err)
+
+		tampered := *proof
+		payload := unittest.PayloadFixture(unittest.WithSeals(proof.Payload.Seals...))
+		payload.Guarantees = append(payload.Guarantees, unittest.CollectionGuaranteeFixture())
+		tampered.Payload = &payload
+		err = client.AddServiceEventProof(&tampered)
+		assert.True(t, errors.Is(err, ErrInvalidProof))
+	})
+}
@@ -0,0 +1,113 @@
+package lightclient
+
+import (
+	"fmt"
+
+	"github.com/onflow/flow-go/consensus/hotstuff/committees"
+	"github.com/onflow/flow-go/consensus/hotstuff/validator"
+	"github.com/onflow/flow-go/model/encodable"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/state/protocol/inmem"
+)
+
+// epoch contains the information the light client requires to verify QCs within an epoch.
+// Epochs are immutable, committing an epoch returns a new instance.
+type epoch struct {
+	counter      uint64
+	firstView    uint64
+	finalView    uint64
+	participants flow.IdentityList    // all initial identities of the epoch
+	validator    *validator.Validator // nil until the epoch is committed
+}
+
+// newSetupEpoch returns the epoch specified by an EpochSetup service event.
+func newSetupEpoch(setup *flow.EpochSetup) *epoch {
+	return &epoch{
+		counter:      setup.Counter,
+		firstView:    setup.FirstView,
+		finalView:    setup.FinalView,
+		participants: setup.Participants,
+	}
+}
+
+// epochFromProtocol returns the given committed epoch of a protocol state snapshot.
+func epochFromProtocol(from protocol.Epoch, newVerifier VerifierFactory) (*epoch, error) {
+	counter, err := from.Counter()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch counter: %w", err)
+	}
+	firstView, err := from.FirstView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch first view: %w", err)
+	}
+	finalView, err := from.FinalView()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch final view: %w", err)
+	}
+	participants, err := from.InitialIdentities()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch initial identities: %w", err)
+	}
+	e := &epoch{
+		counter:      counter,
+		firstView:    firstView,
+		finalView:    finalView,
+		participants: participants,
+	}
+
+	dkg, err := from.DKG()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch dkg: %w", err)
+	}
+	return e.withDKG(dkg, newVerifier)
+}
+
+// committed returns the epoch committed by the given EpochCommit service event.
+func (e *epoch) committed(commit *flow.EpochCommit, newVerifier VerifierFactory) (*epoch, error) {
+	if commit.Counter != e.counter {
+		return nil, fmt.Errorf("commit for epoch %d does not match set up epoch %d", commit.Counter, e.counter)
+	}
+
+	lookup, err := flow.ToDKGParticipantLookup(e.participants.Filter(filter.IsValidDKGParticipant), commit.DKGParticipantKeys)
+	if err != nil {
+		return nil, fmt.Errorf("could not construct dkg lookup: %w", err)
+	}
+	dkg, err := inmem.DKGFromEncodable(inmem.EncodableDKG{
+		GroupKey: encodable.RandomBeaconPubKey{
+			PublicKey: commit.DKGGroupKey,
+		},
+		Participants: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not construct dkg: %w", err)
+	}
+	return e.withDKG(dkg, newVerifier)
+}
+
+// withDKG returns a copy of the epoch, which validates QCs using the given DKG.
+func (e *epoch) withDKG(dkg protocol.DKG, newVerifier VerifierFactory) (*epoch, error) {
+	committee, err := committees.NewStaticCommitteeWithDKG(
+		e.participants.Filter(filter.IsVotingConsensusCommitteeMember),
+		flow.ZeroID,
+		dkg,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create committee for epoch %d: %w", e.counter, err)
+	}
+
+	committed := *e
+	committed.validator = validator.New(committee, nil, newVerifier(committee))
+	return &committed, nil
+}
+
+// isCommitted returns true if QCs within the epoch can be validated.
+func (e *epoch) isCommitted() bool {
+	return e.validator != nil
+}
+
+// containsView returns true if the view belongs to the epoch.
+func (e *epoch) containsView(view uint64) bool {
+	return e.firstView <= view && view <= e.finalView
+}
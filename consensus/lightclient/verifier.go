@@ -0,0 +1,16 @@
+//go:build relic
+// +build relic
+
+package lightclient
+
+import (
+	"github.com/onflow/flow-go/consensus/hotstuff"
+	"github.com/onflow/flow-go/consensus/hotstuff/signature"
+	"github.com/onflow/flow-go/consensus/hotstuff/verification"
+)
+
+// NewCombinedVerifier is the VerifierFactory for QCs with combined staking and random beacon signatures,
+// as produced by consensus nodes.
+func NewCombinedVerifier(committee hotstuff.Committee) hotstuff.Verifier {
+	return verification.NewCombinedVerifier(committee, signature.NewConsensusSigDataPacker(committee))
+}
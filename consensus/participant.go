@@ -88,8 +88,25 @@ func NewParticipant(
 		return nil, fmt.Errorf("could not initialize timeout config: %w", err)
 	}
 
+	// apply the changes of the timeout parameters made at runtime
+	timeoutConfig, err = cfg.TimeoutConfigUpdate.Apply(timeoutConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not apply timeout config update: %w", err)
+	}
+	metrics.SetTimeoutConfig(
+		time.Duration(timeoutConfig.MinReplicaTimeout)*time.Millisecond,
+		time.Duration(timeoutConfig.MaxReplicaTimeout)*time.Millisecond,
+		time.Duration(timeoutConfig.BlockRateDelayMS)*time.Millisecond,
+		timeoutConfig.VoteAggregationTimeoutFraction,
+		timeoutConfig.TimeoutIncrease,
+		timeoutConfig.TimeoutDecrease,
+	)
+
 	// initialize the pacemaker
 	controller := timeout.NewController(timeoutConfig)
+	if cfg.TimeoutControllerConsumer != nil {
+		cfg.TimeoutControllerConsumer(controller)
+	}
 	pacemaker, err := pacemaker.New(started+1, controller, modules.Notifier)
 	if err != nil {
 		return nil, fmt.Errorf("could not initialize flow pacemaker: %w", err)
@@ -111,6 +111,7 @@ func (suite *Suite) RunTest(
 			transactions,
 			receipts,
 			results,
+			nil,
 			suite.chainID,
 			suite.metrics,
 			nil,
@@ -287,6 +288,7 @@ func (suite *Suite) TestSendTransactionToRandomCollectionNode() {
 			transactions,
 			nil,
 			nil,
+			nil,
 			suite.chainID,
 			metrics,
 			connFactory,
@@ -556,6 +558,7 @@ func (suite *Suite) TestGetSealedTransaction() {
 			transactions,
 			receipts,
 			results,
+			nil,
 			suite.chainID,
 			suite.metrics,
 			connFactory,
@@ -570,7 +573,7 @@ func (suite *Suite) TestGetSealedTransaction() {
 		handler := access.NewHandler(backend, suite.chainID.Chain())
 
 		rpcEng := rpc.New(suite.log, suite.state, rpc.Config{}, nil, nil, blocks, headers, collections, transactions,
-			receipts, results, suite.chainID, metrics, 0, 0, false, false, nil, nil)
+			receipts, results, nil, suite.chainID, metrics, 0, 0, false, false, nil, nil)
 
 		// create the ingest engine
 		ingestEng, err := ingestion.New(suite.log, suite.net, suite.state, suite.me, suite.request, blocks, headers, collections,
@@ -640,6 +643,7 @@ func (suite *Suite) TestExecuteScript() {
 			transactions,
 			receipts,
 			results,
+			nil,
 			suite.chainID,
 			suite.metrics,
 			connFactory,
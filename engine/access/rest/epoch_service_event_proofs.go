@@ -0,0 +1,18 @@
+package rest
+
+import (
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+)
+
+// GetEpochServiceEventProofs returns the proofs for the EpochSetup and EpochCommit service events of an epoch,
+// which light clients use to learn the epoch's consensus committee.
+func GetEpochServiceEventProofs(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetEpochServiceEventProofsRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	return backend.GetEpochServiceEventProofs(r.Context(), req.Counter)
+}
@@ -0,0 +1,63 @@
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	mocks "github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func getEpochServiceEventProofsReq(counter string) *http.Request {
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/v1/epochs/%s/service_event_proofs", counter), nil)
+	return req
+}
+
+func TestGetEpochServiceEventProofs(t *testing.T) {
+
+	t.Run("get proofs", func(t *testing.T) {
+		result := unittest.ExecutionResultFixture()
+		payload := unittest.PayloadFixture(unittest.WithSeals(unittest.Seal.Fixture(unittest.Seal.WithResult(result))))
+		header := unittest.BlockHeaderFixture()
+		header.PayloadHash = payload.Hash()
+		proof := &flow.ServiceEventProof{Header: &header, Payload: &payload, Results: []*flow.ExecutionResult{result}}
+
+		backend := &mock.API{}
+		backend.Mock.
+			On("GetEpochServiceEventProofs", mocks.Anything, uint64(2)).
+			Return([]*flow.ServiceEventProof{proof}, nil).
+			Once()
+
+		expected, err := json.Marshal([]*flow.ServiceEventProof{proof})
+		require.NoError(t, err)
+
+		assertOKResponse(t, getEpochServiceEventProofsReq("2"), string(expected), backend)
+		mocks.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get proofs for unknown epoch", func(t *testing.T) {
+		backend := &mock.API{}
+		backend.Mock.
+			On("GetEpochServiceEventProofs", mocks.Anything, uint64(5)).
+			Return(nil, status.Error(codes.NotFound, "epoch 5 has not reached phase EpochPhaseSetup")).
+			Once()
+
+		assertResponse(t, getEpochServiceEventProofsReq("5"), http.StatusNotFound,
+			`{"code":404,"message":"Flow resource not found: epoch 5 has not reached phase EpochPhaseSetup"}`, backend)
+		mocks.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get with invalid counter", func(t *testing.T) {
+		backend := &mock.API{}
+		assertResponse(t, getEpochServiceEventProofsReq("foo"), http.StatusBadRequest,
+			`{"code":400,"message":"value must be an unsigned 64 bit integer"}`, backend)
+	})
+}
@@ -0,0 +1,31 @@
+/*
+ * Access API
+ *
+ * No description provided (generated by Swagger Codegen https://github.com/swagger-api/swagger-codegen)
+ *
+ * API version: 1.0.0
+ * Generated by: Swagger Codegen (https://github.com/swagger-api/swagger-codegen.git)
+ */
+package models
+
+import (
+	"time"
+)
+
+type SlashingEvidence struct {
+	Id string `json:"id"`
+
+	Type_ string `json:"type"`
+
+	OffenderId string `json:"offender_id"`
+
+	View string `json:"view"`
+
+	First *SignedVote `json:"first"`
+
+	Second *SignedVote `json:"second"`
+
+	InvalidProposal *BlockHeader `json:"invalid_proposal,omitempty"`
+
+	DetectedAt time.Time `json:"detected_at"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"github.com/onflow/flow-go/engine/access/rest/util"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func (s *SlashingEvidence) Build(evidence *flow.SlashingEvidence) {
+	var first, second SignedVote
+	first.Build(&evidence.First)
+	second.Build(&evidence.Second)
+
+	s.Id = evidence.ID().String()
+	s.Type_ = evidence.Type.String()
+	s.OffenderId = evidence.OffenderID.String()
+	s.View = util.FromUint64(evidence.View)
+	s.First = &first
+	s.Second = &second
+	s.DetectedAt = evidence.DetectedAt
+
+	if evidence.InvalidProposal != nil {
+		var header BlockHeader
+		header.Build(evidence.InvalidProposal)
+		s.InvalidProposal = &header
+	}
+}
+
+func (v *SignedVote) Build(vote *flow.SignedVote) {
+	v.View = util.FromUint64(vote.View)
+	v.BlockId = vote.BlockID.String()
+	v.SignerId = vote.SignerID.String()
+	v.Signature = util.ToBase64(vote.SigData)
+}
@@ -0,0 +1,34 @@
+package rest
+
+import (
+	"encoding/json"
+
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+)
+
+// GetProtocolStateSnapshotByHeight returns the encoded protocol state snapshot at the given sealed height,
+// which can be used to bootstrap a node.
+func GetProtocolStateSnapshotByHeight(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	req, err := r.GetProtocolStateSnapshotRequest()
+	if err != nil {
+		return nil, NewBadRequestError(err)
+	}
+
+	height := req.Height
+	if height == request.SealedHeight {
+		header, err := backend.GetLatestBlockHeader(r.Context(), true)
+		if err != nil {
+			return nil, err
+		}
+		height = header.Height
+	}
+
+	snapshot, err := backend.GetProtocolStateSnapshotByHeight(r.Context(), height)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.RawMessage(snapshot), nil
+}
@@ -0,0 +1,69 @@
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	mocks "github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/access/mock"
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func getProtocolStateSnapshotReq(height string) *http.Request {
+	req, _ := http.NewRequest("GET", fmt.Sprintf("/v1/protocol_state_snapshots/%s", height), nil)
+	return req
+}
+
+func TestGetProtocolStateSnapshotByHeight(t *testing.T) {
+	snapshot := []byte(`{"Head":{"Height":10}}`)
+
+	t.Run("get by height", func(t *testing.T) {
+		backend := &mock.API{}
+		backend.Mock.
+			On("GetProtocolStateSnapshotByHeight", mocks.Anything, uint64(10)).
+			Return(snapshot, nil).
+			Once()
+
+		assertOKResponse(t, getProtocolStateSnapshotReq("10"), string(snapshot), backend)
+		mocks.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get at sealed height", func(t *testing.T) {
+		backend := &mock.API{}
+		backend.Mock.
+			On("GetLatestBlockHeader", mocks.Anything, true).
+			Return(&flow.Header{Height: 10}, nil).
+			Once()
+		backend.Mock.
+			On("GetProtocolStateSnapshotByHeight", mocks.Anything, uint64(10)).
+			Return(snapshot, nil).
+			Once()
+
+		assertOKResponse(t, getProtocolStateSnapshotReq("sealed"), string(snapshot), backend)
+		mocks.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get unsealed height", func(t *testing.T) {
+		backend := &mock.API{}
+		backend.Mock.
+			On("GetProtocolStateSnapshotByHeight", mocks.Anything, uint64(20)).
+			Return(nil, status.Error(codes.InvalidArgument, "block at height 20 is not sealed")).
+			Once()
+
+		assertResponse(t, getProtocolStateSnapshotReq("20"), http.StatusBadRequest,
+			`{"code":400,"message":"Invalid Flow argument: block at height 20 is not sealed"}`, backend)
+		mocks.AssertExpectationsForObjects(t, backend)
+	})
+
+	t.Run("get with invalid height", func(t *testing.T) {
+		backend := &mock.API{}
+		assertResponse(t, getProtocolStateSnapshotReq("final"), http.StatusBadRequest,
+			`{"code":400,"message":"snapshots are only available for sealed heights"}`, backend)
+		assertResponse(t, getProtocolStateSnapshotReq("foo"), http.StatusBadRequest,
+			`{"code":400,"message":"invalid height format"}`, backend)
+	})
+}
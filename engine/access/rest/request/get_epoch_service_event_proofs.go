@@ -0,0 +1,27 @@
+package request
+
+import (
+	"github.com/onflow/flow-go/engine/access/rest/util"
+)
+
+const counterVar = "counter"
+
+type GetEpochServiceEventProofs struct {
+	Counter uint64
+}
+
+func (g *GetEpochServiceEventProofs) Build(r *Request) error {
+	return g.Parse(
+		r.GetVar(counterVar),
+	)
+}
+
+func (g *GetEpochServiceEventProofs) Parse(rawCounter string) error {
+	counter, err := util.ToUint64(rawCounter)
+	if err != nil {
+		return err
+	}
+	g.Counter = counter
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package request
+
+import (
+	"fmt"
+)
+
+type GetProtocolStateSnapshot struct {
+	Height uint64
+}
+
+func (g *GetProtocolStateSnapshot) Build(r *Request) error {
+	return g.Parse(
+		r.GetVar(heightQuery),
+	)
+}
+
+func (g *GetProtocolStateSnapshot) Parse(rawHeight string) error {
+	var height Height
+	err := height.Parse(rawHeight)
+	if err != nil {
+		return err
+	}
+	g.Height = height.Flow()
+
+	if g.Height == EmptyHeight {
+		return fmt.Errorf("must provide a height")
+	}
+	if g.Height == FinalHeight {
+		return fmt.Errorf("snapshots are only available for sealed heights")
+	}
+
+	return nil
+}
@@ -66,6 +66,18 @@ func (rd *Request) GetExecutionResultRequest() (GetExecutionResult, error) {
 	return req, err
 }
 
+func (rd *Request) GetProtocolStateSnapshotRequest() (GetProtocolStateSnapshot, error) {
+	var req GetProtocolStateSnapshot
+	err := req.Build(rd)
+	return req, err
+}
+
+func (rd *Request) GetEpochServiceEventProofsRequest() (GetEpochServiceEventProofs, error) {
+	var req GetEpochServiceEventProofs
+	err := req.Build(rd)
+	return req, err
+}
+
 func (rd *Request) GetTransactionRequest() (GetTransaction, error) {
 	var req GetTransaction
 	err := req.Build(rd)
@@ -101,4 +101,19 @@ var Routes = []route{{
 	Pattern: "/events",
 	Name:    "getEvents",
 	Handler: GetEvents,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/slashing_evidence",
+	Name:    "getSlashingEvidence",
+	Handler: GetSlashingEvidence,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/protocol_state_snapshots/{height}",
+	Name:    "getProtocolStateSnapshotByHeight",
+	Handler: GetProtocolStateSnapshotByHeight,
+}, {
+	Method:  http.MethodGet,
+	Pattern: "/epochs/{counter}/service_event_proofs",
+	Name:    "getEpochServiceEventProofs",
+	Handler: GetEpochServiceEventProofs,
 }}
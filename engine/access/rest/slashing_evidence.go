@@ -0,0 +1,22 @@
+package rest
+
+import (
+	"github.com/onflow/flow-go/access"
+	"github.com/onflow/flow-go/engine/access/rest/models"
+	"github.com/onflow/flow-go/engine/access/rest/request"
+)
+
+// GetSlashingEvidence returns the evidence of protocol violations observed by the node.
+func GetSlashingEvidence(r *request.Request, backend access.API, _ models.LinkGenerator) (interface{}, error) {
+	evidence, err := backend.GetSlashingEvidence(r.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]models.SlashingEvidence, len(evidence))
+	for i, e := range evidence {
+		response[i].Build(e)
+	}
+
+	return response, nil
+}
@@ -52,6 +52,8 @@ var SnapshotHistoryLimitErr = fmt.Errorf("reached the snapshot history limit")
 // Block details related calls are handled by backendBlockDetails.
 // Event related calls are handled by backendEvents.
 // Account related calls are handled by backendAccounts.
+// Slashing evidence related calls are handled by backendSlashingEvidence.
+// Service event proof related calls are handled by backendServiceEventProofs.
 //
 // All remaining calls are handled by the base Backend in this file.
 type Backend struct {
@@ -62,6 +64,8 @@ type Backend struct {
 	backendBlockDetails
 	backendAccounts
 	backendExecutionResults
+	backendSlashingEvidence
+	backendServiceEventProofs
 
 	state                protocol.State
 	chainID              flow.ChainID
@@ -81,6 +85,7 @@ func New(
 	transactions storage.Transactions,
 	executionReceipts storage.ExecutionReceipts,
 	executionResults storage.ExecutionResults,
+	slashingEvidence storage.SlashingEvidence,
 	chainID flow.ChainID,
 	transactionMetrics module.TransactionMetrics,
 	connFactory ConnectionFactory,
@@ -148,6 +153,14 @@ func New(
 		backendExecutionResults: backendExecutionResults{
 			executionResults: executionResults,
 		},
+		backendSlashingEvidence: backendSlashingEvidence{
+			slashingEvidence: slashingEvidence,
+		},
+		backendServiceEventProofs: backendServiceEventProofs{
+			state:            state,
+			blocks:           blocks,
+			executionResults: executionResults,
+		},
 		collections:          collections,
 		executionReceipts:    executionReceipts,
 		connFactory:          connFactory,
@@ -247,6 +260,21 @@ func (b *Backend) GetLatestProtocolStateSnapshot(_ context.Context) ([]byte, err
 	return convert.SnapshotToBytes(validSnapshot)
 }
 
+// GetProtocolStateSnapshotByHeight returns the snapshot at the finalized block with the given height.
+// The block must be sealed, and the sealing segment of the snapshot must not span an epoch or epoch
+// phase transition, so that the snapshot can be used to bootstrap a node.
+func (b *Backend) GetProtocolStateSnapshotByHeight(_ context.Context, height uint64) ([]byte, error) {
+	snapshot, err := protocol.SnapshotAtSealedHeight(b.state, height)
+	if errors.Is(err, protocol.ErrHeightNotSealed) || errors.Is(err, protocol.ErrSealingSegmentSpansTransition) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid snapshot height: %v", err)
+	}
+	if err != nil {
+		return nil, convertStorageError(err)
+	}
+
+	return convert.SnapshotToBytes(snapshot)
+}
+
 // getValidSnapshot will return a valid snapshot that has a sealing segment which
 // 1. does not contain any blocks that span an epoch transition
 // 2. does not contain any blocks that span an epoch phase transition
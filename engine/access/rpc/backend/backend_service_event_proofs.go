@@ -0,0 +1,150 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+)
+
+type backendServiceEventProofs struct {
+	state            protocol.State
+	blocks           storage.Blocks
+	executionResults storage.ExecutionResults
+}
+
+// GetEpochServiceEventProofs returns proofs for the EpochSetup and EpochCommit service events of the epoch with
+// the given counter, which allow light clients to follow the transition into the epoch. Returns one proof if both
+// service events were sealed in the same block, and two proofs otherwise, ordered by height.
+func (b *backendServiceEventProofs) GetEpochServiceEventProofs(ctx context.Context, counter uint64) ([]*flow.ServiceEventProof, error) {
+	setupHeight, err := b.firstHeightWithEpochPhase(counter, flow.EpochPhaseSetup)
+	if err != nil {
+		return nil, err
+	}
+	commitHeight, err := b.firstHeightWithEpochPhase(counter, flow.EpochPhaseCommitted)
+	if err != nil {
+		return nil, err
+	}
+
+	// service events take effect at the child of the block sealing them
+	setupProof, err := b.serviceEventProof(setupHeight-1, counter)
+	if err != nil {
+		return nil, err
+	}
+	if commitHeight == setupHeight {
+		return []*flow.ServiceEventProof{setupProof}, nil
+	}
+	commitProof, err := b.serviceEventProof(commitHeight-1, counter)
+	if err != nil {
+		return nil, err
+	}
+	return []*flow.ServiceEventProof{setupProof, commitProof}, nil
+}
+
+// firstHeightWithEpochPhase returns the lowest finalized height, at which the epoch with the given counter has
+// reached at least the given phase, as seen from the preceding epoch.
+func (b *backendServiceEventProofs) firstHeightWithEpochPhase(counter uint64, phase flow.EpochPhase) (uint64, error) {
+	root, err := b.state.Params().Root()
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "could not get root block: %v", err)
+	}
+	final, err := b.state.Final().Head()
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "could not get finalized block: %v", err)
+	}
+
+	reached := func(height uint64) (bool, error) {
+		snapshot := b.state.AtHeight(height)
+		current, err := snapshot.Epochs().Current().Counter()
+		if err != nil {
+			return false, fmt.Errorf("could not get epoch counter at height %d: %w", height, err)
+		}
+		if current != counter-1 {
+			return current >= counter, nil
+		}
+		currentPhase, err := snapshot.Phase()
+		if err != nil {
+			return false, fmt.Errorf("could not get epoch phase at height %d: %w", height, err)
+		}
+		return currentPhase >= phase, nil
+	}
+
+	atRoot, err := reached(root.Height)
+	if err != nil {
+		return 0, status.Errorf(codes.Internal, "could not check epoch %d at root: %v", counter, err)
+	}
+	if atRoot {
+		return 0, status.Errorf(codes.NotFound, "epoch %d reached phase %s before the root block", counter, phase)
+	}
+
+	var searchErr error
+	offset := sort.Search(int(final.Height-root.Height), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		ok, err := reached(root.Height + uint64(i) + 1)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return ok
+	})
+	if searchErr != nil {
+		return 0, status.Errorf(codes.Internal, "could not search for epoch %d phase %s: %v", counter, phase, searchErr)
+	}
+	if offset == int(final.Height-root.Height) {
+		return 0, status.Errorf(codes.NotFound, "epoch %d has not reached phase %s", counter, phase)
+	}
+	return root.Height + uint64(offset) + 1, nil
+}
+
+// serviceEventProof returns the proof for the epoch service events of the given epoch sealed in the block at
+// the given height.
+func (b *backendServiceEventProofs) serviceEventProof(height uint64, counter uint64) (*flow.ServiceEventProof, error) {
+	block, err := b.blocks.ByHeight(height)
+	if err != nil {
+		return nil, convertStorageError(err)
+	}
+
+	proof := &flow.ServiceEventProof{
+		Header:  block.Header,
+		Payload: block.Payload,
+	}
+	for _, seal := range block.Payload.Seals {
+		result, err := b.executionResults.ByID(seal.ResultID)
+		if err != nil {
+			return nil, convertStorageError(err)
+		}
+		if emitsEpochServiceEvent(result, counter) {
+			proof.Results = append(proof.Results, result)
+		}
+	}
+	if len(proof.Results) == 0 {
+		return nil, status.Errorf(codes.Internal, "no service events for epoch %d sealed in block at height %d", counter, height)
+	}
+
+	return proof, nil
+}
+
+// emitsEpochServiceEvent returns true if the result emits an EpochSetup or EpochCommit event for the given epoch.
+func emitsEpochServiceEvent(result *flow.ExecutionResult, counter uint64) bool {
+	for _, event := range result.ServiceEvents {
+		switch ev := event.Event.(type) {
+		case *flow.EpochSetup:
+			if ev.Counter == counter {
+				return true
+			}
+		case *flow.EpochCommit:
+			if ev.Counter == counter {
+				return true
+			}
+		}
+	}
+	return false
+}
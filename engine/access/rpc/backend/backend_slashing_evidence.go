@@ -0,0 +1,29 @@
+package backend
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+)
+
+type backendSlashingEvidence struct {
+	slashingEvidence storage.SlashingEvidence
+}
+
+// GetSlashingEvidence returns all evidence of protocol violations observed by this node.
+func (b *backendSlashingEvidence) GetSlashingEvidence(ctx context.Context) ([]*flow.SlashingEvidence, error) {
+	if b.slashingEvidence == nil {
+		return nil, status.Errorf(codes.Unimplemented, "slashing evidence is not available on this node")
+	}
+
+	evidence, err := b.slashingEvidence.All()
+	if err != nil {
+		return nil, convertStorageError(err)
+	}
+
+	return evidence, nil
+}
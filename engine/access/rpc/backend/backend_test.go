@@ -98,6 +98,7 @@ func (suite *Suite) TestPing() {
 		nil,
 		nil,
 		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -130,6 +131,7 @@ func (suite *Suite) TestGetLatestFinalizedBlockHeader() {
 		nil,
 		nil,
 		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -193,6 +195,7 @@ func (suite *Suite) TestGetLatestProtocolStateSnapshot_NoTransitionSpan() {
 			nil,
 			nil,
 			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			nil,
@@ -264,6 +267,7 @@ func (suite *Suite) TestGetLatestProtocolStateSnapshot_TransitionSpans() {
 			nil,
 			nil,
 			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			nil,
@@ -328,6 +332,7 @@ func (suite *Suite) TestGetLatestProtocolStateSnapshot_PhaseTransitionSpan() {
 			nil,
 			nil,
 			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			nil,
@@ -403,6 +408,7 @@ func (suite *Suite) TestGetLatestProtocolStateSnapshot_EpochTransitionSpan() {
 			nil,
 			nil,
 			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			nil,
@@ -462,6 +468,7 @@ func (suite *Suite) TestGetLatestProtocolStateSnapshot_HistoryLimit() {
 			nil,
 			nil,
 			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			nil,
@@ -479,6 +486,66 @@ func (suite *Suite) TestGetLatestProtocolStateSnapshot_HistoryLimit() {
 	})
 }
 
+// TestGetProtocolStateSnapshotByHeight tests our GetProtocolStateSnapshotByHeight endpoint, which only
+// returns snapshots at sealed heights whose sealing segment does not span a phase transition.
+func (suite *Suite) TestGetProtocolStateSnapshotByHeight() {
+	identities := unittest.CompleteIdentitySet()
+	rootSnapshot := unittest.RootSnapshotFixture(identities)
+	util.RunWithFullProtocolState(suite.T(), rootSnapshot, func(db *badger.DB, state *bprotocol.MutableState) {
+		epochBuilder := unittest.NewEpochBuilder(suite.T(), state).BuildEpoch().CompleteEpoch()
+
+		// get heights of each phase in built epochs
+		epoch1, ok := epochBuilder.EpochHeights(1)
+		require.True(suite.T(), ok)
+
+		backend := New(
+			state,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			nil,
+			suite.chainID,
+			metrics.NewNoopCollector(),
+			nil,
+			false,
+			DefaultMaxHeightRange,
+			nil,
+			nil,
+			suite.log,
+			DefaultSnapshotHistoryLimit,
+		)
+
+		suite.Run("snapshot within staking phase", func() {
+			// the sealing segment B(S_P) <- C(S_A) <- D(S_B) is within the staking phase
+			height := epoch1.Range()[3]
+			bytes, err := backend.GetProtocolStateSnapshotByHeight(context.Background(), height)
+			suite.Require().NoError(err)
+
+			expectedSnapshotBytes, err := convert.SnapshotToBytes(state.AtHeight(height))
+			suite.Require().NoError(err)
+			suite.Require().Equal(expectedSnapshotBytes, bytes)
+		})
+
+		suite.Run("snapshot spanning phase transition", func() {
+			// the sealing segment C(S_A) <- D(S_B) |setup| <- E(S_C) spans the epoch setup phase
+			_, err := backend.GetProtocolStateSnapshotByHeight(context.Background(), epoch1.Range()[4])
+			suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+		})
+
+		suite.Run("unsealed height", func() {
+			sealed, err := state.Sealed().Head()
+			suite.Require().NoError(err)
+			_, err = backend.GetProtocolStateSnapshotByHeight(context.Background(), sealed.Height+1)
+			suite.Require().Equal(codes.InvalidArgument, status.Code(err))
+		})
+	})
+}
+
 func (suite *Suite) TestGetLatestSealedBlockHeader() {
 	// setup the mocks
 	suite.state.On("Sealed").Return(suite.snapshot, nil).Maybe()
@@ -496,6 +563,7 @@ func (suite *Suite) TestGetLatestSealedBlockHeader() {
 		nil,
 		nil,
 		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -540,6 +608,7 @@ func (suite *Suite) TestGetTransaction() {
 		suite.transactions,
 		nil,
 		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -579,6 +648,7 @@ func (suite *Suite) TestGetCollection() {
 		suite.transactions,
 		nil,
 		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -661,6 +731,7 @@ func (suite *Suite) TestTransactionStatusTransition() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		connFactory, // the connection factory should be used to get the execution node client
@@ -780,6 +851,7 @@ func (suite *Suite) TestTransactionExpiredStatusTransition() {
 		suite.transactions,
 		nil,
 		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -938,6 +1010,7 @@ func (suite *Suite) TestTransactionPendingToFinalizedStatusTransition() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		connFactory, // the connection factory should be used to get the execution node client
@@ -995,6 +1068,7 @@ func (suite *Suite) TestTransactionResultUnknown() {
 		suite.transactions,
 		nil,
 		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -1042,6 +1116,7 @@ func (suite *Suite) TestGetLatestFinalizedBlock() {
 		nil,
 		nil,
 		nil,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -1170,6 +1245,7 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 			nil,
 			suite.receipts,
 			suite.results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1201,6 +1277,7 @@ func (suite *Suite) TestGetEventsForBlockIDs() {
 			nil,
 			receipts,
 			nil,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1259,6 +1336,7 @@ func (suite *Suite) TestGetExecutionResultByID() {
 			nil,
 			suite.receipts,
 			results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1288,6 +1366,7 @@ func (suite *Suite) TestGetExecutionResultByID() {
 			nil,
 			nil,
 			results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1350,6 +1429,7 @@ func (suite *Suite) TestGetExecutionResultByBlockID() {
 			nil,
 			suite.receipts,
 			results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1380,6 +1460,7 @@ func (suite *Suite) TestGetExecutionResultByBlockID() {
 			nil,
 			nil,
 			results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1529,6 +1610,7 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil,
 			suite.receipts,
 			suite.results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1567,6 +1649,7 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil,
 			suite.receipts,
 			suite.results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1604,6 +1687,7 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil,
 			suite.receipts,
 			suite.results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1640,6 +1724,7 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil,
 			suite.receipts,
 			suite.results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1676,6 +1761,7 @@ func (suite *Suite) TestGetEventsForHeightRange() {
 			nil,
 			suite.receipts,
 			suite.results,
+			nil,
 			suite.chainID,
 			metrics.NewNoopCollector(),
 			connFactory, // the connection factory should be used to get the execution node client
@@ -1752,6 +1838,7 @@ func (suite *Suite) TestGetAccount() {
 		nil,
 		suite.receipts,
 		suite.results,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		connFactory, // the connection factory should be used to get the execution node client
@@ -1832,6 +1919,7 @@ func (suite *Suite) TestGetAccountAtBlockHeight() {
 		nil,
 		suite.receipts,
 		suite.results,
+		nil,
 		flow.Testnet,
 		metrics.NewNoopCollector(),
 		connFactory, // the connection factory should be used to get the execution node client
@@ -1869,6 +1957,7 @@ func (suite *Suite) TestGetNetworkParameters() {
 		nil,
 		nil,
 		nil,
+		nil,
 		flow.Mainnet,
 		metrics.NewNoopCollector(),
 		nil,
@@ -2035,6 +2124,7 @@ func (suite *Suite) TestExecuteScriptOnExecutionNode() {
 		nil,
 		suite.receipts,
 		suite.results,
+		nil,
 		flow.Mainnet,
 		metrics.NewNoopCollector(),
 		connFactory, // the connection factory should be used to get the execution node client
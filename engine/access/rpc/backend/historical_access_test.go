@@ -46,6 +46,7 @@ func (suite *Suite) TestHistoricalTransactionResult() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -103,6 +104,7 @@ func (suite *Suite) TestHistoricalTransaction() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
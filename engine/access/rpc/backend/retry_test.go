@@ -51,6 +51,7 @@ func (suite *Suite) TestTransactionRetry() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		nil,
@@ -131,6 +132,7 @@ func (suite *Suite) TestSuccessfulTransactionsDontRetry() {
 		suite.transactions,
 		suite.receipts,
 		suite.results,
+		nil,
 		suite.chainID,
 		metrics.NewNoopCollector(),
 		connFactory,
@@ -76,6 +76,7 @@ func New(log zerolog.Logger,
 	transactions storage.Transactions,
 	executionReceipts storage.ExecutionReceipts,
 	executionResults storage.ExecutionResults,
+	slashingEvidence storage.SlashingEvidence,
 	chainID flow.ChainID,
 	transactionMetrics module.TransactionMetrics,
 	collectionGRPCPort uint,
@@ -144,6 +145,7 @@ func New(log zerolog.Logger,
 		transactions,
 		executionReceipts,
 		executionResults,
+		slashingEvidence,
 		chainID,
 		transactionMetrics,
 		connectionFactory,
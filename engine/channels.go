@@ -105,6 +105,25 @@ func PublicChannels() network.ChannelList {
 	}
 }
 
+// EncryptedChannels returns all channels whose messages are encrypted end-to-end between the sender and each of
+// their recipients, so that they can not be read by the other nodes subscribed to the channel. Once encryption is enabled
+// on a node, its networking layer encrypts and decrypts the messages on these channels, and drops the messages which
+// are not encrypted. Until then, messages on these channels are exchanged in plaintext, as with nodes which do not
+// support encryption, hence encryption must be enabled on all the nodes at the same spork.
+//
+// Admin commands are served by the admin server of each node rather than exchanged over network channels, hence there
+// is no admin traffic on the network to encrypt. Channels carrying sensitive admin traffic must be added here.
+func EncryptedChannels() network.ChannelList {
+	return network.ChannelList{
+		DKGCommittee,
+	}
+}
+
+// IsEncryptedChannel returns true if the messages on the channel are encrypted end-to-end.
+func IsEncryptedChannel(channel network.Channel) bool {
+	return EncryptedChannels().Contains(channel)
+}
+
 // channels
 const (
 
@@ -238,6 +257,16 @@ func IsClusterChannel(channel network.Channel) bool {
 	return ok
 }
 
+// IsConsensusChannel returns true if channel carries the messages of the consensus committee, or of the
+// consensus of a collection cluster.
+func IsConsensusChannel(channel network.Channel) bool {
+	if channel == ConsensusCommittee {
+		return true
+	}
+	prefix, ok := clusterChannelPrefix(channel)
+	return ok && prefix == consensusClusterPrefix
+}
+
 // TopicFromChannel returns the unique LibP2P topic form the channel.
 // The channel is made up of name string suffixed with root block id.
 // The root block id is used to prevent cross talks between nodes on different sporks.
@@ -88,6 +88,32 @@ func TestIsClusterChannel(t *testing.T) {
 	require.False(t, ok)
 }
 
+// TestIsConsensusChannel verifies the correctness of IsConsensusChannel method against consensus,
+// cluster consensus, and other channels.
+func TestIsConsensusChannel(t *testing.T) {
+	require.True(t, IsConsensusChannel(ConsensusCommittee))
+	require.True(t, IsConsensusChannel(ChannelConsensusCluster("some-consensus-cluster-id")))
+
+	require.False(t, IsConsensusChannel(ChannelSyncCluster("some-sync-cluster-id")))
+	require.False(t, IsConsensusChannel(ProvideChunks))
+	require.False(t, IsConsensusChannel("non-cluster-channel-id"))
+}
+
+// TestIsEncryptedChannel verifies the correctness of IsEncryptedChannel method against encrypted channels and
+// other channels, and that all encrypted channels exist.
+func TestIsEncryptedChannel(t *testing.T) {
+	require.True(t, IsEncryptedChannel(DKGCommittee))
+
+	require.False(t, IsEncryptedChannel(ConsensusCommittee))
+	require.False(t, IsEncryptedChannel(PublicSyncCommittee))
+	require.False(t, IsEncryptedChannel("non-existing-channel"))
+
+	for _, channel := range EncryptedChannels() {
+		require.True(t, Exists(channel), channel)
+		require.False(t, PublicChannels().Contains(channel), channel)
+	}
+}
+
 // TestUniqueChannels_Uniqueness verifies that non-cluster channels returned by
 // UniqueChannels are unique based on their set of involved roles.
 // We use the identifier of RoleList to determine their uniqueness.
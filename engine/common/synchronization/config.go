@@ -5,14 +5,16 @@ import (
 )
 
 type Config struct {
-	PollInterval time.Duration
-	ScanInterval time.Duration
+	PollInterval   time.Duration
+	ScanInterval   time.Duration
+	RequestTimeout time.Duration
 }
 
 func DefaultConfig() *Config {
 	return &Config{
-		PollInterval: 8 * time.Second,
-		ScanInterval: 2 * time.Second,
+		PollInterval:   8 * time.Second,
+		ScanInterval:   2 * time.Second,
+		RequestTimeout: 10 * time.Second,
 	}
 }
 
@@ -32,3 +34,11 @@ func WithScanInterval(interval time.Duration) OptionFunc {
 		cfg.ScanInterval = interval
 	}
 }
+
+// WithRequestTimeout sets a custom timeout for the range and batch requests,
+// after which a request without response is given up.
+func WithRequestTimeout(timeout time.Duration) OptionFunc {
+	return func(cfg *Config) {
+		cfg.RequestTimeout = timeout
+	}
+}
@@ -3,6 +3,7 @@
 package synchronization
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/onflow/flow-go/engine/common/fifoqueue"
 	"github.com/onflow/flow-go/model/events"
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter/id"
 	"github.com/onflow/flow-go/model/messages"
 	"github.com/onflow/flow-go/module"
 	identifier "github.com/onflow/flow-go/module/id"
@@ -43,6 +45,7 @@ type Engine struct {
 
 	pollInterval         time.Duration
 	scanInterval         time.Duration
+	requestTimeout       time.Duration
 	core                 module.SyncCore
 	participantsProvider identifier.IdentifierProvider
 	finalizedHeader      *FinalizedHeaderCache
@@ -89,6 +92,7 @@ func New(
 		core:                 core,
 		pollInterval:         opt.PollInterval,
 		scanInterval:         opt.ScanInterval,
+		requestTimeout:       opt.RequestTimeout,
 		finalizedHeader:      finalizedHeader,
 		participantsProvider: participantsProvider,
 	}
@@ -225,6 +229,12 @@ func (e *Engine) Process(channel network.Channel, originID flow.Identifier, even
 	return nil
 }
 
+// ProcessRequest serves the given range or batch request, sent by the node with the given origin ID through the
+// request protocol of the network, and returns the response.
+func (e *Engine) ProcessRequest(channel network.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	return e.requestHandler.ProcessRequest(channel, originID, request)
+}
+
 // process processes events for the synchronization engine.
 // Error returns:
 //  * IncompatibleInputTypeError if input has unexpected type
@@ -373,27 +383,34 @@ func (e *Engine) pollHeight() {
 }
 
 // sendRequests sends a request for each range and batch using consensus participants from last finalized snapshot.
+// Each request is sent to a random subset of the participants through the request protocol of the network, and
+// the responses are processed like the block responses received on the channel of the engine. Participants which
+// do not support the request protocol yet are sent the request as a message on the channel of the engine.
 func (e *Engine) sendRequests(participants flow.IdentifierList, ranges []flow.Range, batches []flow.Batch) {
 	var errs *multierror.Error
 
+	participants = participants.Filter(id.Not(id.Is(e.me.NodeID())))
+
 	for _, ran := range ranges {
 		req := &messages.RangeRequest{
 			Nonce:      rand.Uint64(),
 			FromHeight: ran.From,
 			ToHeight:   ran.To,
 		}
-		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, participants...)
-		if err != nil {
-			errs = multierror.Append(errs, fmt.Errorf("could not submit range request: %w", err))
+		targetIDs := participants.Sample(synccore.DefaultBlockRequestNodes)
+		if len(targetIDs) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("could not submit range request: %w", network.EmptyTargetList))
 			continue
 		}
+		for _, targetID := range targetIDs {
+			e.request(req, targetID, metrics.MessageRangeRequest)
+		}
 		e.log.Info().
 			Uint64("range_from", req.FromHeight).
 			Uint64("range_to", req.ToHeight).
 			Uint64("range_nonce", req.Nonce).
 			Msg("range requested")
 		e.core.RangeRequested(ran)
-		e.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageRangeRequest)
 	}
 
 	for _, batch := range batches {
@@ -401,20 +418,58 @@ func (e *Engine) sendRequests(participants flow.IdentifierList, ranges []flow.Ra
 			Nonce:    rand.Uint64(),
 			BlockIDs: batch.BlockIDs,
 		}
-		err := e.con.Multicast(req, synccore.DefaultBlockRequestNodes, participants...)
-		if err != nil {
-			errs = multierror.Append(errs, fmt.Errorf("could not submit batch request: %w", err))
+		targetIDs := participants.Sample(synccore.DefaultBlockRequestNodes)
+		if len(targetIDs) == 0 {
+			errs = multierror.Append(errs, fmt.Errorf("could not submit batch request: %w", network.EmptyTargetList))
 			continue
 		}
+		for _, targetID := range targetIDs {
+			e.request(req, targetID, metrics.MessageBatchRequest)
+		}
 		e.log.Debug().
 			Strs("block_ids", flow.IdentifierList(batch.BlockIDs).Strings()).
 			Uint64("range_nonce", req.Nonce).
 			Msg("batch requested")
 		e.core.BatchRequested(batch)
-		e.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBatchRequest)
 	}
 
 	if err := errs.ErrorOrNil(); err != nil {
 		e.log.Warn().Err(err).Msg("sending range and batch requests failed")
 	}
 }
+
+// request sends the range or batch request to the target in the background, and queues the block response
+// of the target for processing. If the target does not support the request protocol, e.g. during a rolling
+// upgrade, the request is unicast to the target instead, which sends its response on the channel of the engine.
+func (e *Engine) request(req interface{}, targetID flow.Identifier, messageType string) {
+	e.unit.Launch(func() {
+		ctx, cancel := context.WithTimeout(e.unit.Ctx(), e.requestTimeout)
+		defer cancel()
+
+		e.metrics.MessageSent(metrics.EngineSynchronization, messageType)
+		res, err := e.con.Request(ctx, req, targetID)
+		if network.IsRequestNotSupportedError(err) {
+			err = e.con.Unicast(req, targetID)
+			if err != nil {
+				e.log.Debug().Err(err).Hex("target_id", targetID[:]).Str("request", messageType).Msg("could not unicast block request")
+			}
+			return
+		}
+		if err != nil {
+			e.log.Debug().Err(err).Hex("target_id", targetID[:]).Str("request", messageType).Msg("block request failed")
+			return
+		}
+
+		response, ok := res.(*messages.BlockResponse)
+		if !ok {
+			e.log.Warn().Hex("target_id", targetID[:]).Msgf("target responded to %s with unexpected response %T", messageType, res)
+			e.con.ReportMisbehavior(targetID, network.ProtocolViolation)
+			return
+		}
+
+		err = e.responseMessageHandler.Process(targetID, response)
+		if err != nil {
+			e.log.Warn().Err(err).Hex("target_id", targetID[:]).Msg("could not queue block response")
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package synchronization
 
 import (
+	"errors"
 	"io/ioutil"
 	"math/rand"
 	"testing"
@@ -445,29 +446,126 @@ func (ss *SyncSuite) TestSendRequests() {
 
 	ranges := unittest.RangeListFixture(1)
 	batches := unittest.BatchListFixture(1)
+	targetIDs := ss.participants[1:].NodeIDs()
 
-	// should submit and mark requested all ranges
-	ss.con.On("Multicast", mock.AnythingOfType("*messages.RangeRequest"), synccore.DefaultBlockRequestNodes, mock.Anything, mock.Anything).Return(nil).Run(
+	// should request all ranges from the other participants
+	res := &messages.BlockResponse{
+		Nonce:  rand.Uint64(),
+		Blocks: []*flow.Block{},
+	}
+	ss.con.On("Request", mock.Anything, mock.AnythingOfType("*messages.RangeRequest"), mock.Anything).Return(res, nil).Run(
+		func(args mock.Arguments) {
+			req := args.Get(1).(*messages.RangeRequest)
+			ss.Assert().Equal(ranges[0].From, req.FromHeight)
+			ss.Assert().Equal(ranges[0].To, req.ToHeight)
+			ss.Assert().Contains(targetIDs, args.Get(2).(flow.Identifier))
+		},
+	).Times(len(targetIDs))
+	ss.core.On("RangeRequested", ranges[0])
+
+	// should request all batches from the other participants
+	ss.con.On("Request", mock.Anything, mock.AnythingOfType("*messages.BatchRequest"), mock.Anything).Return(&messages.SyncResponse{}, nil).Run(
+		func(args mock.Arguments) {
+			req := args.Get(1).(*messages.BatchRequest)
+			ss.Assert().Equal(batches[0].BlockIDs, req.BlockIDs)
+			ss.Assert().Contains(targetIDs, args.Get(2).(flow.Identifier))
+		},
+	).Times(len(targetIDs))
+	ss.core.On("BatchRequested", batches[0])
+
+	// responses of unexpected type should be reported
+	ss.con.On("ReportMisbehavior", mock.Anything, netint.ProtocolViolation).Times(len(targetIDs))
+
+	// include my node ID, which should never be requested
+	ss.e.sendRequests(ss.participants.NodeIDs(), ranges, batches)
+
+	// wait for the requests sent in the background
+	unittest.AssertClosesBefore(ss.T(), ss.e.unit.Done(), time.Second)
+	ss.con.AssertExpectations(ss.T())
+	ss.core.AssertExpectations(ss.T())
+
+	// the responses to the range requests should be queued for processing
+	for range targetIDs {
+		msg, ok := ss.e.pendingBlockResponses.Get()
+		require.True(ss.T(), ok)
+		ss.Assert().Equal(res, msg.Payload)
+		ss.Assert().Contains(targetIDs, msg.OriginID)
+	}
+	_, ok := ss.e.pendingBlockResponses.Get()
+	ss.Assert().False(ok)
+}
+
+// TestSendRequests_NotSupported tests that range and batch requests are unicast to participants which do not support
+// the request protocol, instead of being dropped.
+func (ss *SyncSuite) TestSendRequests_NotSupported() {
+
+	ranges := unittest.RangeListFixture(1)
+	batches := unittest.BatchListFixture(1)
+	targetIDs := ss.participants[1:].NodeIDs()
+
+	notSupported := netint.NewRequestNotSupportedError(errors.New("protocol not supported"))
+	ss.con.On("Request", mock.Anything, mock.Anything, mock.Anything).Return(nil, notSupported).Times(2 * len(targetIDs))
+
+	ss.con.On("Unicast", mock.AnythingOfType("*messages.RangeRequest"), mock.Anything).Return(nil).Run(
 		func(args mock.Arguments) {
 			req := args.Get(0).(*messages.RangeRequest)
 			ss.Assert().Equal(ranges[0].From, req.FromHeight)
 			ss.Assert().Equal(ranges[0].To, req.ToHeight)
+			ss.Assert().Contains(targetIDs, args.Get(1).(flow.Identifier))
 		},
-	)
+	).Times(len(targetIDs))
 	ss.core.On("RangeRequested", ranges[0])
 
-	// should submit and mark requested all batches
-	ss.con.On("Multicast", mock.AnythingOfType("*messages.BatchRequest"), synccore.DefaultBlockRequestNodes, mock.Anything, mock.Anything, mock.Anything).Return(nil).Run(
+	ss.con.On("Unicast", mock.AnythingOfType("*messages.BatchRequest"), mock.Anything).Return(nil).Run(
 		func(args mock.Arguments) {
 			req := args.Get(0).(*messages.BatchRequest)
 			ss.Assert().Equal(batches[0].BlockIDs, req.BlockIDs)
+			ss.Assert().Contains(targetIDs, args.Get(1).(flow.Identifier))
 		},
-	)
+	).Times(len(targetIDs))
 	ss.core.On("BatchRequested", batches[0])
 
-	// exclude my node ID
-	ss.e.sendRequests(ss.participants[1:].NodeIDs(), ranges, batches)
+	ss.e.sendRequests(ss.participants.NodeIDs(), ranges, batches)
+
+	// wait for the requests sent in the background
+	unittest.AssertClosesBefore(ss.T(), ss.e.unit.Done(), time.Second)
 	ss.con.AssertExpectations(ss.T())
+	ss.core.AssertExpectations(ss.T())
+}
+
+// TestProcessRequest tests that range and batch requests received through the request protocol are answered with
+// the blocks available, even if there are none.
+func (ss *SyncSuite) TestProcessRequest() {
+	originID := unittest.IdentifierFixture()
+
+	block := unittest.BlockFixture()
+	block.Header.Height = ss.head.Height
+	ss.heights[block.Header.Height] = &block
+	ss.blockIDs[block.ID()] = &block
+
+	rangeReq := &messages.RangeRequest{
+		Nonce:      rand.Uint64(),
+		FromHeight: ss.head.Height,
+		ToHeight:   ss.head.Height + 1,
+	}
+	res, err := ss.e.ProcessRequest(engine.SyncCommittee, originID, rangeReq)
+	require.NoError(ss.T(), err)
+	ss.Assert().Equal(&messages.BlockResponse{Nonce: rangeReq.Nonce, Blocks: []*flow.Block{&block}}, res)
+
+	batchReq := &messages.BatchRequest{
+		Nonce:    rand.Uint64(),
+		BlockIDs: unittest.IdentifierListFixture(1),
+	}
+	res, err = ss.e.ProcessRequest(engine.SyncCommittee, originID, batchReq)
+	require.NoError(ss.T(), err)
+	ss.Assert().Equal(&messages.BlockResponse{Nonce: batchReq.Nonce, Blocks: []*flow.Block{}}, res)
+
+	_, err = ss.e.ProcessRequest(engine.SyncCommittee, originID, &messages.SyncRequest{})
+	require.Error(ss.T(), err)
+	require.True(ss.T(), engine.IsIncompatibleInputTypeError(err))
+
+	// responses to requests through the request protocol should never be unicast
+	ss.con.AssertNotCalled(ss.T(), "Unicast", mock.Anything, mock.Anything)
 }
 
 // test a synchronization engine can be started and stopped
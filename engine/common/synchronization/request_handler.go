@@ -181,15 +181,72 @@ func (r *RequestHandler) onSyncRequest(originID flow.Identifier, req *messages.S
 	return nil
 }
 
+// ProcessRequest serves the given range or batch request, sent by the node with the given origin ID through the
+// request protocol of the network. Unlike requests delivered through Process, the response is returned to the
+// requester even if it contains no blocks, so that the requester does not wait for it until its timeout.
+func (r *RequestHandler) ProcessRequest(channel network.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	var res *messages.BlockResponse
+	var err error
+	switch req := request.(type) {
+	case *messages.RangeRequest:
+		r.metrics.MessageReceived(metrics.EngineSynchronization, metrics.MessageRangeRequest)
+		r.log.Debug().Str("origin_id", originID.String()).Msg("received new range request")
+		res, err = r.rangeResponse(req)
+	case *messages.BatchRequest:
+		r.metrics.MessageReceived(metrics.EngineSynchronization, metrics.MessageBatchRequest)
+		r.log.Debug().Str("origin_id", originID.String()).Msg("received new batch request")
+		res, err = r.batchResponse(req)
+	default:
+		r.log.Warn().Msgf("%v delivered unsupported request %T through %v", originID, request, channel)
+		return nil, fmt.Errorf("unsupported request %T: %w", request, engine.IncompatibleInputTypeError)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not serve request: %w", err)
+	}
+
+	r.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBlockResponse)
+	return res, nil
+}
+
 // onRangeRequest processes a request for a range of blocks by height.
 func (r *RequestHandler) onRangeRequest(originID flow.Identifier, req *messages.RangeRequest) error {
 	r.log.Debug().Str("origin_id", originID.String()).Msg("received new range request")
+
+	res, err := r.rangeResponse(req)
+	if err != nil {
+		return err
+	}
+
+	// if there are no blocks to send, skip network message
+	if len(res.Blocks) == 0 {
+		r.log.Debug().Msg("skipping empty range response")
+		return nil
+	}
+
+	// send the response
+	err = r.responseSender.SendResponse(res, originID)
+	if err != nil {
+		r.log.Warn().Err(err).Hex("origin_id", originID[:]).Msg("sending range response failed")
+		return nil
+	}
+	r.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBlockResponse)
+
+	return nil
+}
+
+// rangeResponse returns the response to a request for a range of blocks by height, which contains the finalized
+// blocks of the range up to the first unknown height.
+func (r *RequestHandler) rangeResponse(req *messages.RangeRequest) (*messages.BlockResponse, error) {
+	res := &messages.BlockResponse{
+		Nonce: req.Nonce,
+	}
+
 	// get the latest final state to know if we can fulfill the request
 	head := r.finalizedHeader.Get()
 
 	// if we don't have anything to send, we can bail right away
 	if head.Height < req.FromHeight || req.FromHeight > req.ToHeight {
-		return nil
+		return res, nil
 	}
 
 	// enforce client-side max request size
@@ -199,7 +256,7 @@ func (r *RequestHandler) onRangeRequest(originID flow.Identifier, req *messages.
 	}
 
 	// get all of the blocks, one by one
-	blocks := make([]*flow.Block, 0, req.ToHeight-req.FromHeight+1)
+	res.Blocks = make([]*flow.Block, 0, req.ToHeight-req.FromHeight+1)
 	for height := req.FromHeight; height <= req.ToHeight; height++ {
 		block, err := r.blocks.ByHeight(height)
 		if errors.Is(err, storage.ErrNotFound) {
@@ -207,25 +264,33 @@ func (r *RequestHandler) onRangeRequest(originID flow.Identifier, req *messages.
 			break
 		}
 		if err != nil {
-			return fmt.Errorf("could not get block for height (%d): %w", height, err)
+			return nil, fmt.Errorf("could not get block for height (%d): %w", height, err)
 		}
-		blocks = append(blocks, block)
+		res.Blocks = append(res.Blocks, block)
+	}
+
+	return res, nil
+}
+
+// onBatchRequest processes a request for a specific block by block ID.
+func (r *RequestHandler) onBatchRequest(originID flow.Identifier, req *messages.BatchRequest) error {
+	r.log.Debug().Str("origin_id", originID.String()).Msg("received new batch request")
+
+	res, err := r.batchResponse(req)
+	if err != nil {
+		return err
 	}
 
 	// if there are no blocks to send, skip network message
-	if len(blocks) == 0 {
-		r.log.Debug().Msg("skipping empty range response")
+	if len(res.Blocks) == 0 {
+		r.log.Debug().Msg("skipping empty batch response")
 		return nil
 	}
 
 	// send the response
-	res := &messages.BlockResponse{
-		Nonce:  req.Nonce,
-		Blocks: blocks,
-	}
-	err := r.responseSender.SendResponse(res, originID)
+	err = r.responseSender.SendResponse(res, originID)
 	if err != nil {
-		r.log.Warn().Err(err).Hex("origin_id", originID[:]).Msg("sending range response failed")
+		r.log.Warn().Err(err).Hex("origin_id", originID[:]).Msg("sending batch response failed")
 		return nil
 	}
 	r.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBlockResponse)
@@ -233,12 +298,16 @@ func (r *RequestHandler) onRangeRequest(originID flow.Identifier, req *messages.
 	return nil
 }
 
-// onBatchRequest processes a request for a specific block by block ID.
-func (r *RequestHandler) onBatchRequest(originID flow.Identifier, req *messages.BatchRequest) error {
-	r.log.Debug().Str("origin_id", originID.String()).Msg("received new batch request")
+// batchResponse returns the response to a request for specific blocks by block ID, which contains the known
+// blocks of the request.
+func (r *RequestHandler) batchResponse(req *messages.BatchRequest) (*messages.BlockResponse, error) {
+	res := &messages.BlockResponse{
+		Nonce: req.Nonce,
+	}
+
 	// we should bail and send nothing on empty request
 	if len(req.BlockIDs) == 0 {
-		return nil
+		return res, nil
 	}
 
 	// deduplicate the block IDs in the batch request
@@ -253,7 +322,7 @@ func (r *RequestHandler) onBatchRequest(originID flow.Identifier, req *messages.
 	}
 
 	// try to get all the blocks by ID
-	blocks := make([]*flow.Block, 0, len(blockIDs))
+	res.Blocks = make([]*flow.Block, 0, len(blockIDs))
 	for blockID := range blockIDs {
 		block, err := r.blocks.ByID(blockID)
 		if errors.Is(err, storage.ErrNotFound) {
@@ -261,30 +330,12 @@ func (r *RequestHandler) onBatchRequest(originID flow.Identifier, req *messages.
 			continue
 		}
 		if err != nil {
-			return fmt.Errorf("could not get block by ID (%s): %w", blockID, err)
+			return nil, fmt.Errorf("could not get block by ID (%s): %w", blockID, err)
 		}
-		blocks = append(blocks, block)
+		res.Blocks = append(res.Blocks, block)
 	}
 
-	// if there are no blocks to send, skip network message
-	if len(blocks) == 0 {
-		r.log.Debug().Msg("skipping empty batch response")
-		return nil
-	}
-
-	// send the response
-	res := &messages.BlockResponse{
-		Nonce:  req.Nonce,
-		Blocks: blocks,
-	}
-	err := r.responseSender.SendResponse(res, originID)
-	if err != nil {
-		r.log.Warn().Err(err).Hex("origin_id", originID[:]).Msg("sending batch response failed")
-		return nil
-	}
-	r.metrics.MessageSent(metrics.EngineSynchronization, metrics.MessageBlockResponse)
-
-	return nil
+	return res, nil
 }
 
 // processAvailableRequests is processor of pending events which drives events from networking layer to business logic.
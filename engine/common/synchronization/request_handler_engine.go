@@ -51,6 +51,7 @@ type RequestHandlerEngine struct {
 }
 
 var _ network.MessageProcessor = (*RequestHandlerEngine)(nil)
+var _ network.RequestProcessor = (*RequestHandlerEngine)(nil)
 
 func NewRequestHandlerEngine(
 	logger zerolog.Logger,
@@ -86,6 +87,10 @@ func (r *RequestHandlerEngine) Process(channel network.Channel, originID flow.Id
 	return r.requestHandler.Process(channel, originID, event)
 }
 
+func (r *RequestHandlerEngine) ProcessRequest(channel network.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	return r.requestHandler.ProcessRequest(channel, originID, request)
+}
+
 func (r *RequestHandlerEngine) Ready() <-chan struct{} {
 	return r.requestHandler.Ready()
 }
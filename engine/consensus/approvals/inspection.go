@@ -0,0 +1,184 @@
+package approvals
+
+import (
+	"sort"
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// The types in this file describe the state of the AssignmentCollectorTree for diagnostics,
+// e.g. to find out which verifiers or execution results are blocking sealing.
+
+// TreeInspection describes the AssignmentCollectorTree.
+type TreeInspection struct {
+	LowestHeight        uint64                 `json:"lowest_height"`
+	LastSealedHeight    uint64                 `json:"last_sealed_height"`
+	LastFinalizedHeight uint64                 `json:"last_finalized_height"`
+	Collectors          []*CollectorInspection `json:"collectors"` // ordered by height of the executed block
+}
+
+// CollectorInspection describes the assignment collector for one execution result.
+type CollectorInspection struct {
+	ResultID flow.Identifier `json:"result_id"`
+	BlockID  flow.Identifier `json:"block_id"`
+	Height   uint64          `json:"height"`
+	Status   string          `json:"status"`
+	// IncorporatedResults are the known incorporations of the result, each with its own verifier assignment.
+	IncorporatedResults []*IncorporatedResultInspection `json:"incorporated_results"`
+	// CachedApprovals is the number of approvals cached until the collector starts verifying approvals.
+	CachedApprovals int `json:"cached_approvals,omitempty"`
+}
+
+// IncorporatedResultInspection describes the approvals collected for a result incorporated in a specific block.
+type IncorporatedResultInspection struct {
+	IncorporatedBlockID flow.Identifier `json:"incorporated_block_id"`
+	// IncorporatedHeight is unknown (zero) while the collector is caching approvals.
+	IncorporatedHeight uint64 `json:"incorporated_height,omitempty"`
+	// Sealable is true if all chunks received sufficient approvals, so a candidate seal was created.
+	Sealable bool `json:"sealable"`
+	// EmergencySealable is true if the incorporating block is far enough behind the latest finalized
+	// block for the result to be emergency sealed (provided emergency sealing is active).
+	EmergencySealable bool               `json:"emergency_sealable"`
+	Chunks            []*ChunkInspection `json:"chunks,omitempty"`
+}
+
+// ChunkInspection describes the approvals collected for one chunk of an incorporated result.
+type ChunkInspection struct {
+	Index             uint64              `json:"index"`
+	Approvals         uint                `json:"approvals"`
+	RequiredApprovals uint                `json:"required_approvals"`
+	Approved          bool                `json:"approved"`
+	AssignedVerifiers flow.IdentifierList `json:"assigned_verifiers"`
+	MissingVerifiers  flow.IdentifierList `json:"missing_verifiers"`
+	// Requests is the number of times missing approvals were requested, NextRequest is the end of the
+	// blackout period before approvals are requested again. Both are empty if no request was made.
+	Requests    uint       `json:"requests"`
+	NextRequest *time.Time `json:"next_request,omitempty"`
+}
+
+// collectorInspector is implemented by the states of an AssignmentCollector, which can describe themselves.
+type collectorInspector interface {
+	inspect(lastFinalizedHeight uint64) *CollectorInspection
+}
+
+// Inspect returns a description of the tree and all assignment collectors it contains.
+func (t *AssignmentCollectorTree) Inspect() *TreeInspection {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	inspection := &TreeInspection{
+		LowestHeight:        t.forest.LowestLevel,
+		LastSealedHeight:    t.lastSealedHeight,
+		LastFinalizedHeight: t.lastFinalizedHeight,
+	}
+	// the forest doesn't track its highest level, hence we iterate levels until we have seen all vertices
+	for level, seen := t.forest.LowestLevel, uint64(0); seen < t.forest.GetSize(); level++ {
+		iter := t.forest.GetVerticesAtLevel(level)
+		for iter.HasNext() {
+			collector := iter.NextVertex().(*assignmentCollectorVertex).collector
+			inspection.Collectors = append(inspection.Collectors, inspectCollector(collector, t.lastFinalizedHeight))
+			seen++
+		}
+	}
+	return inspection
+}
+
+// inspectCollector describes the collector using its own description, if available.
+func inspectCollector(collector AssignmentCollectorState, lastFinalizedHeight uint64) *CollectorInspection {
+	if inspector, ok := collector.(collectorInspector); ok {
+		return inspector.inspect(lastFinalizedHeight)
+	}
+	return &CollectorInspection{
+		ResultID: collector.ResultID(),
+		BlockID:  collector.BlockID(),
+		Height:   collector.Block().Height,
+		Status:   collector.ProcessingStatus().String(),
+	}
+}
+
+func (asm *AssignmentCollectorStateMachine) inspect(lastFinalizedHeight uint64) *CollectorInspection {
+	return inspectCollector(asm.atomicLoadCollector(), lastFinalizedHeight)
+}
+
+func (ac *CachingAssignmentCollector) inspect(uint64) *CollectorInspection {
+	inspection := ac.baseInspection(ac.ProcessingStatus())
+	for _, incorporatedResult := range ac.GetIncorporatedResults() {
+		inspection.IncorporatedResults = append(inspection.IncorporatedResults, &IncorporatedResultInspection{
+			IncorporatedBlockID: incorporatedResult.IncorporatedBlockID,
+		})
+	}
+	inspection.CachedApprovals = len(ac.GetApprovals())
+	return inspection
+}
+
+func (ac *VerifyingAssignmentCollector) inspect(lastFinalizedHeight uint64) *CollectorInspection {
+	inspection := ac.baseInspection(ac.ProcessingStatus())
+	for _, collector := range ac.allCollectors() {
+		incorporated := &IncorporatedResultInspection{
+			IncorporatedBlockID: collector.IncorporatedBlockID(),
+			IncorporatedHeight:  collector.IncorporatedBlock().Height,
+			EmergencySealable:   ac.emergencySealable(collector, lastFinalizedHeight),
+		}
+		incorporated.Chunks = collector.inspectChunks()
+		incorporated.Sealable = true
+		for _, chunk := range incorporated.Chunks {
+			item, requested := ac.requestTracker.Get(ac.ResultID(), collector.IncorporatedBlockID(), chunk.Index)
+			if requested {
+				chunk.Requests = item.Requests
+				nextRequest := item.NextTimeout
+				chunk.NextRequest = &nextRequest
+			}
+			incorporated.Sealable = incorporated.Sealable && chunk.Approved
+		}
+		inspection.IncorporatedResults = append(inspection.IncorporatedResults, incorporated)
+	}
+	return inspection
+}
+
+func (oc *OrphanAssignmentCollector) inspect(uint64) *CollectorInspection {
+	return oc.baseInspection(oc.ProcessingStatus())
+}
+
+func (cb *AssignmentCollectorBase) baseInspection(status ProcessingStatus) *CollectorInspection {
+	return &CollectorInspection{
+		ResultID: cb.ResultID(),
+		BlockID:  cb.BlockID(),
+		Height:   cb.Block().Height,
+		Status:   status.String(),
+	}
+}
+
+// inspectChunks describes the approvals collected for each chunk.
+func (c *ApprovalCollector) inspectChunks() []*ChunkInspection {
+	chunks := make([]*ChunkInspection, 0, len(c.chunkCollectors))
+	for index, collector := range c.chunkCollectors {
+		chunk := collector.inspect(uint64(index))
+		chunk.Approved = c.aggregatedSignatures.HasSignature(chunk.Index)
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+// inspect describes the approvals collected for the chunk with the given index.
+func (c *ChunkApprovalCollector) inspect(index uint64) *ChunkInspection {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	chunk := &ChunkInspection{
+		Index:             index,
+		Approvals:         c.chunkApprovals.NumberSignatures(),
+		RequiredApprovals: c.requiredApprovalsForSealConstruction,
+		AssignedVerifiers: make(flow.IdentifierList, 0, len(c.assignment)),
+		MissingVerifiers:  make(flow.IdentifierList, 0, len(c.assignment)),
+	}
+	for verifierID := range c.assignment {
+		chunk.AssignedVerifiers = append(chunk.AssignedVerifiers, verifierID)
+		if !c.chunkApprovals.HasSigned(verifierID) {
+			chunk.MissingVerifiers = append(chunk.MissingVerifiers, verifierID)
+		}
+	}
+	sort.Sort(chunk.AssignedVerifiers)
+	sort.Sort(chunk.MissingVerifiers)
+	return chunk
+}
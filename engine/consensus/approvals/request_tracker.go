@@ -141,6 +141,14 @@ func (rt *RequestTracker) set(resultID, executedBlockID, incorporatedBlockID flo
 	return nil
 }
 
+// Get returns the tracker item for a specific chunk, and whether approvals for the chunk were requested before.
+func (rt *RequestTracker) Get(resultID, incorporatedBlockID flow.Identifier, chunkIndex uint64) (RequestTrackerItem, bool) {
+	rt.lock.Lock()
+	defer rt.lock.Unlock()
+	item, ok := rt.index[resultID][incorporatedBlockID][chunkIndex]
+	return item, ok
+}
+
 // GetAllIds returns all result IDs that we are indexing
 func (rt *RequestTracker) GetAllIds() []flow.Identifier {
 	rt.lock.Lock()
@@ -406,3 +406,46 @@ func (s *AssignmentCollectorTestSuite) TestCheckEmergencySealing() {
 
 	s.SealsPL.AssertExpectations(s.T())
 }
+
+// TestInspect tests that the inspection reports the approvals, missing verifiers and approval
+// requests for each chunk of each incorporated result.
+func (s *AssignmentCollectorTestSuite) TestInspect() {
+	err := s.collector.ProcessIncorporatedResult(s.IncorporatedResult)
+	require.NoError(s.T(), err)
+	s.PublicKey.On("Verify", mock.Anything, mock.Anything, mock.Anything).Return(true, nil)
+
+	// the first chunk is approved by a single verifier
+	approval := unittest.ResultApprovalFixture(unittest.WithChunk(s.Chunks[0].Index),
+		unittest.WithApproverID(s.VerID),
+		unittest.WithBlockID(s.Block.ID()),
+		unittest.WithExecutionResultID(s.IncorporatedResult.Result.ID()))
+	err = s.collector.ProcessApproval(approval)
+	require.NoError(s.T(), err)
+
+	// approvals for the second chunk were requested
+	_, _, err = s.RequestTracker.TryUpdate(s.IncorporatedResult.Result, s.IncorporatedBlock.ID(), s.Chunks[1].Index)
+	require.NoError(s.T(), err)
+
+	inspection := s.collector.inspect(s.IncorporatedBlock.Height + DefaultEmergencySealingThreshold)
+	require.Equal(s.T(), s.IncorporatedResult.Result.ID(), inspection.ResultID)
+	require.Equal(s.T(), VerifyingApprovals.String(), inspection.Status)
+	require.Len(s.T(), inspection.IncorporatedResults, 1)
+
+	incorporated := inspection.IncorporatedResults[0]
+	require.Equal(s.T(), s.IncorporatedBlock.ID(), incorporated.IncorporatedBlockID)
+	require.True(s.T(), incorporated.EmergencySealable)
+	require.False(s.T(), incorporated.Sealable)
+	require.Len(s.T(), incorporated.Chunks, len(s.Chunks))
+
+	first := incorporated.Chunks[0]
+	require.Equal(s.T(), uint(1), first.Approvals)
+	require.Equal(s.T(), uint(len(s.AuthorizedVerifiers)), first.RequiredApprovals)
+	require.Len(s.T(), first.AssignedVerifiers, len(s.AuthorizedVerifiers))
+	require.Len(s.T(), first.MissingVerifiers, len(s.AuthorizedVerifiers)-1)
+	require.NotContains(s.T(), first.MissingVerifiers, s.VerID)
+	require.Nil(s.T(), first.NextRequest)
+
+	second := incorporated.Chunks[1]
+	require.Zero(s.T(), second.Approvals)
+	require.NotNil(s.T(), second.NextRequest)
+}
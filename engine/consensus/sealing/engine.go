@@ -54,6 +54,7 @@ type Engine struct {
 	unit                       *engine.Unit
 	workerPool                 *workerpool.WorkerPool
 	core                       consensus.SealingCore
+	inspectCore                func() *Inspection // describes the core's state for diagnostics
 	log                        zerolog.Logger
 	me                         module.Local
 	headers                    storage.Headers
@@ -145,6 +146,7 @@ func NewEngine(log zerolog.Logger,
 		return nil, fmt.Errorf("could not repopulate assignment collectors tree: %w", err)
 	}
 	e.core = core
+	e.inspectCore = core.Inspect
 
 	return e, nil
 }
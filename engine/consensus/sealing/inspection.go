@@ -0,0 +1,32 @@
+package sealing
+
+import (
+	"github.com/onflow/flow-go/engine/consensus/approvals"
+)
+
+// Inspection describes the state of the sealing pipeline, so operators can find out which
+// execution results or verifiers are blocking sealing.
+type Inspection struct {
+	EmergencySealingActive               bool   `json:"emergency_sealing_active"`
+	EmergencySealingThreshold            uint64 `json:"emergency_sealing_threshold"`
+	RequiredApprovalsForSealConstruction uint   `json:"required_approvals_for_seal_construction"`
+	ApprovalRequestsThreshold            uint64 `json:"approval_requests_threshold"`
+	*approvals.TreeInspection
+}
+
+// Inspect returns a description of the sealing core and the assignment collectors for all unsealed results.
+// Concurrency safe.
+func (c *Core) Inspect() *Inspection {
+	return &Inspection{
+		EmergencySealingActive:               c.config.EmergencySealingActive,
+		EmergencySealingThreshold:            approvals.DefaultEmergencySealingThreshold,
+		RequiredApprovalsForSealConstruction: c.config.RequiredApprovalsForSealConstruction,
+		ApprovalRequestsThreshold:            c.config.ApprovalRequestsThreshold,
+		TreeInspection:                       c.collectorTree.Inspect(),
+	}
+}
+
+// Inspect returns a description of the sealing pipeline. Concurrency safe.
+func (e *Engine) Inspect() *Inspection {
+	return e.inspectCore()
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	"github.com/onflow/flow-go/fvm"
 	"github.com/onflow/flow-go/fvm/blueprints"
+	"github.com/onflow/flow-go/fvm/handler"
 	"github.com/onflow/flow-go/fvm/programs"
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/ledger"
@@ -374,8 +375,11 @@ func (e *blockComputer) executeTransaction(
 	}
 
 	txResult := flow.TransactionResult{
-		TransactionID:   tx.ID,
-		ComputationUsed: tx.ComputationUsed,
+		TransactionID:       tx.ID,
+		ComputationUsed:     tx.ComputationUsed,
+		StorageBytesWritten: tx.MeteringUsage.StorageBytesWritten,
+		EventCount:          tx.MeteringUsage.EventCount,
+		EventBytes:          tx.MeteringUsage.EventBytes,
 	}
 
 	if tx.Err != nil {
@@ -403,6 +407,8 @@ func (e *blockComputer) executeTransaction(
 		Str("block_id", res.ExecutableBlock.ID().String()).
 		Str("traceID", traceID).
 		Uint64("computation_used", txResult.ComputationUsed).
+		Uint64("storage_bytes_written", txResult.StorageBytesWritten).
+		Uint64("event_bytes", txResult.EventBytes).
 		Int64("timeSpentInMS", time.Since(startedAt).Milliseconds()).
 		Logger()
 
@@ -416,6 +422,9 @@ func (e *blockComputer) executeTransaction(
 	}
 
 	e.metrics.ExecutionTransactionExecuted(time.Since(startedAt), tx.ComputationUsed, len(tx.Events), tx.Err != nil)
+	for _, kind := range handler.AllMeteredKinds {
+		e.metrics.ExecutionTransactionMeteredUsage(kind.String(), tx.MeteringUsage.Used(kind))
+	}
 	return nil
 }
 
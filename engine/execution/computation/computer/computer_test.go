@@ -67,6 +67,9 @@ func TestBlockExecutor_ExecuteBlock(t *testing.T) {
 			Return(nil).
 			Times(2) // 1 collection + system collection
 
+		metrics.On("ExecutionTransactionMeteredUsage", mock.Anything, mock.Anything).
+			Return(nil)
+
 		metrics.On("ExecutionTransactionExecuted", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 			Return(nil).
 			Times(2 + 1) // 2 txs in collection + system chunk tx
@@ -651,6 +654,9 @@ func Test_ExecutingSystemCollection(t *testing.T) {
 		Return(nil).
 		Times(1) // system collection
 
+	metrics.On("ExecutionTransactionMeteredUsage", mock.Anything, mock.Anything).
+		Return(nil)
+
 	metrics.On("ExecutionTransactionExecuted", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
 		Return(nil).
 		Times(1) // system chunk tx
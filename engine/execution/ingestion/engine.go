@@ -16,6 +16,7 @@ import (
 	"github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/execution"
 	"github.com/onflow/flow-go/engine/execution/computation"
+	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
 	"github.com/onflow/flow-go/engine/execution/provider"
 	"github.com/onflow/flow-go/engine/execution/state"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
@@ -65,6 +66,7 @@ type Engine struct {
 	syncFast               bool                // sync fast allows execution node to skip fetching collection during state syncing, and rely on state syncing to catch up
 	checkAuthorizedAtBlock func(blockID flow.Identifier) (bool, error)
 	pauseExecution         bool
+	stopControl            *stop.StopControl
 }
 
 func New(
@@ -90,6 +92,7 @@ func New(
 	syncFast bool,
 	checkAuthorizedAtBlock func(blockID flow.Identifier) (bool, error),
 	pauseExecution bool,
+	stopControl *stop.StopControl,
 ) (*Engine, error) {
 	log := logger.With().Str("engine", "ingestion").Logger()
 
@@ -121,8 +124,16 @@ func New(
 		syncFast:               syncFast,
 		checkAuthorizedAtBlock: checkAuthorizedAtBlock,
 		pauseExecution:         pauseExecution,
+		stopControl:            stopControl,
 	}
 
+	stopControl.SetOnUnblock(func() {
+		err := eng.executeQueuedBlocks()
+		if err != nil {
+			eng.log.Error().Err(err).Msg("failed to execute queued blocks after execution was unblocked")
+		}
+	})
+
 	// move to state syncing engine
 	syncConduit, err := net.Register(engine.SyncExecution, &eng)
 	if err != nil {
@@ -661,6 +672,8 @@ func (e *Engine) onBlockExecuted(executed *entity.ExecutableBlock, finalState fl
 	e.metrics.ExecutionStorageStateCommitment(int64(len(finalState)))
 	e.metrics.ExecutionLastExecutedBlockHeight(executed.Block.Header.Height)
 
+	defer e.stopControl.BlockExecuted(executed.Block.Header.Height)
+
 	// e.checkStateSyncStop(executed.Block.Header.Height)
 
 	err := e.mempool.Run(
@@ -762,6 +775,15 @@ func (e *Engine) executeBlockIfComplete(eb *entity.ExecutableBlock) bool {
 	// 	e.syncDeltas.Rem(eb.Block.ID())
 	// }
 
+	// the block stays in the queue, and will be executed once execution is resumed or the stop height is raised
+	if !e.stopControl.ShouldExecute(eb.Height()) {
+		e.log.Debug().
+			Hex("block_id", logging.Entity(eb)).
+			Uint64("height", eb.Height()).
+			Msg("block execution is paused or stopped, skipping block")
+		return false
+	}
+
 	// if don't have the delta, then check if everything is ready for executing
 	// the block
 	if eb.IsComplete() {
@@ -781,6 +803,20 @@ func (e *Engine) executeBlockIfComplete(eb *entity.ExecutableBlock) bool {
 	return false
 }
 
+// executeQueuedBlocks executes the blocks at the head of every execution queue which are complete.
+// It is used to continue execution after it was paused, or after the stop height was raised or removed.
+func (e *Engine) executeQueuedBlocks() error {
+	return e.mempool.Run(func(
+		blockByCollection *stdmap.BlockByCollectionBackdata,
+		executionQueues *stdmap.QueuesBackdata,
+	) error {
+		for _, queue := range executionQueues.All() {
+			e.executeBlockIfComplete(queue.Head.Item.(*entity.ExecutableBlock))
+		}
+		return nil
+	})
+}
+
 // OnCollection is a callback for handling the collections requested by the
 // collection requester.
 func (e *Engine) OnCollection(originID flow.Identifier, entity flow.Entity) {
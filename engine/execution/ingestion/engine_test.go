@@ -17,6 +17,7 @@ import (
 	engineCommon "github.com/onflow/flow-go/engine"
 	"github.com/onflow/flow-go/engine/execution"
 	computation "github.com/onflow/flow-go/engine/execution/computation/mock"
+	"github.com/onflow/flow-go/engine/execution/ingestion/stop"
 	provider "github.com/onflow/flow-go/engine/execution/provider/mock"
 	"github.com/onflow/flow-go/engine/execution/state/delta"
 	state "github.com/onflow/flow-go/engine/execution/state/mock"
@@ -148,6 +149,10 @@ func runWithEngine(t *testing.T, f func(testingContext)) {
 		return stateProtocol.IsNodeAuthorizedAt(protocolState.AtBlockID(blockID), myIdentity.NodeID)
 	}
 
+	db, _ := unittest.TempBadgerDB(t)
+	stopControl, err := stop.NewStopControl(log, db, nil)
+	require.NoError(t, err)
+
 	engine, err = New(
 		log,
 		net,
@@ -171,6 +176,7 @@ func runWithEngine(t *testing.T, f func(testingContext)) {
 		false,
 		checkAuthorizedAtBlock,
 		false,
+		stopControl,
 	)
 	require.NoError(t, err)
 
@@ -1156,6 +1162,10 @@ func newIngestionEngine(t *testing.T, ps *mocks.ProtocolState, es *mocks.Executi
 		return stateProtocol.IsNodeAuthorizedAt(ps.AtBlockID(blockID), myIdentity.NodeID)
 	}
 
+	db, _ := unittest.TempBadgerDB(t)
+	stopControl, err := stop.NewStopControl(log, db, nil)
+	require.NoError(t, err)
+
 	engine, err = New(
 		log,
 		net,
@@ -1179,6 +1189,7 @@ func newIngestionEngine(t *testing.T, ps *mocks.ProtocolState, es *mocks.Executi
 		false,
 		checkAuthorizedAtBlock,
 		false,
+		stopControl,
 	)
 
 	require.NoError(t, err)
@@ -0,0 +1,204 @@
+package stop
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// StopControl decides whether the ingestion engine may execute a block. It supports
+// pausing and resuming execution, as well as setting a stop height: blocks above the
+// stop height are never executed, and once the block at the stop height has been executed
+// the onStop callback is invoked (which is used to shut the node down cleanly).
+// Both settings are persisted in the database, so they survive restarts.
+type StopControl struct {
+	sync.RWMutex
+	log        zerolog.Logger
+	db         *badger.DB
+	stopHeight uint64 // 0 means no stop height is set
+	paused     bool
+	onStop     func()
+	onUnblock  func()
+}
+
+// NewStopControl creates a new StopControl, restoring the settings persisted in the database.
+// onStop is called once the block at the stop height has been executed.
+func NewStopControl(log zerolog.Logger, db *badger.DB, onStop func()) (*StopControl, error) {
+	s := &StopControl{
+		log:       log.With().Str("component", "stop_control").Logger(),
+		db:        db,
+		onStop:    onStop,
+		onUnblock: func() {},
+	}
+
+	err := db.View(operation.RetrieveExecutionStopHeight(&s.stopHeight))
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("could not retrieve stop height: %w", err)
+	}
+
+	err = db.View(operation.RetrieveExecutionPaused(&s.paused))
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("could not retrieve paused flag: %w", err)
+	}
+
+	s.log.Info().
+		Uint64("stop_height", s.stopHeight).
+		Bool("paused", s.paused).
+		Msg("stop control initialized")
+
+	return s, nil
+}
+
+// SetOnUnblock sets the callback invoked when execution is resumed, or the stop height is raised or removed,
+// so that the engine can execute the blocks which were queued while they could not be executed.
+func (s *StopControl) SetOnUnblock(onUnblock func()) {
+	s.Lock()
+	defer s.Unlock()
+	s.onUnblock = onUnblock
+}
+
+// ShouldExecute returns whether a block at the given height may be executed.
+func (s *StopControl) ShouldExecute(height uint64) bool {
+	s.RLock()
+	defer s.RUnlock()
+
+	if s.paused {
+		return false
+	}
+	return s.stopHeight == 0 || height <= s.stopHeight
+}
+
+// StopHeight returns the current stop height, or 0 if none is set.
+func (s *StopControl) StopHeight() uint64 {
+	s.RLock()
+	defer s.RUnlock()
+	return s.stopHeight
+}
+
+// IsPaused returns whether execution is paused.
+func (s *StopControl) IsPaused() bool {
+	s.RLock()
+	defer s.RUnlock()
+	return s.paused
+}
+
+// SetStopHeight sets the height after which no block is executed. A height of 0 removes the stop height.
+// It returns an error if the given height has already been executed.
+// Raising or removing the stop height executes the blocks which were queued above the previous stop height.
+func (s *StopControl) SetStopHeight(height uint64, executedHeight uint64) error {
+	s.Lock()
+	raised := s.stopHeight != 0 && (height == 0 || height > s.stopHeight)
+	err := s.setStopHeight(height, executedHeight)
+	paused := s.paused
+	onUnblock := s.onUnblock
+	s.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	if raised && !paused {
+		onUnblock()
+	}
+	return nil
+}
+
+// setStopHeight validates and persists the stop height. Must be called while holding the lock.
+func (s *StopControl) setStopHeight(height uint64, executedHeight uint64) error {
+	if height != 0 && height <= executedHeight {
+		return fmt.Errorf("stop height (%d) must be above the highest executed height (%d)", height, executedHeight)
+	}
+
+	err := operation.RetryOnConflict(s.db.Update, func(tx *badger.Txn) error {
+		err := operation.UpdateExecutionStopHeight(height)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return operation.InsertExecutionStopHeight(height)(tx)
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist stop height: %w", err)
+	}
+
+	s.log.Info().
+		Uint64("stop_height", height).
+		Uint64("executed_height", executedHeight).
+		Msg("stop height set")
+
+	s.stopHeight = height
+	return nil
+}
+
+// Pause stops the execution of any further blocks. Blocks currently being executed are finished.
+func (s *StopControl) Pause() error {
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.setPaused(true)
+	if err != nil {
+		return err
+	}
+
+	s.log.Info().Msg("execution paused")
+	return nil
+}
+
+// Resume resumes the execution of blocks, executing the blocks queued while execution was paused.
+func (s *StopControl) Resume() error {
+	s.Lock()
+	err := s.setPaused(false)
+	onUnblock := s.onUnblock
+	s.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	s.log.Info().Msg("execution resumed")
+	onUnblock()
+	return nil
+}
+
+// BlockExecuted must be called after a block has been executed.
+// It invokes the onStop callback if the block is at the stop height.
+func (s *StopControl) BlockExecuted(height uint64) {
+	s.RLock()
+	stopHeight := s.stopHeight
+	s.RUnlock()
+
+	if stopHeight == 0 || height != stopHeight {
+		return
+	}
+
+	s.log.Warn().
+		Uint64("stop_height", stopHeight).
+		Msg("block at stop height executed, stopping execution")
+
+	if s.onStop != nil {
+		s.onStop()
+	}
+}
+
+// setPaused persists the paused flag.
+// No errors are expected during normal operation.
+func (s *StopControl) setPaused(paused bool) error {
+	err := operation.RetryOnConflict(s.db.Update, func(tx *badger.Txn) error {
+		err := operation.UpdateExecutionPaused(paused)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return operation.InsertExecutionPaused(paused)(tx)
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("could not persist paused flag: %w", err)
+	}
+
+	s.paused = paused
+	return nil
+}
@@ -0,0 +1,120 @@
+package stop
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestStopControl(t *testing.T) {
+
+	t.Run("executes everything by default", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			sc, err := NewStopControl(unittest.Logger(), db, nil)
+			require.NoError(t, err)
+
+			require.True(t, sc.ShouldExecute(1))
+			require.True(t, sc.ShouldExecute(1_000_000))
+			require.Equal(t, uint64(0), sc.StopHeight())
+			require.False(t, sc.IsPaused())
+		})
+	})
+
+	t.Run("does not execute above stop height", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			stopped := false
+			sc, err := NewStopControl(unittest.Logger(), db, func() { stopped = true })
+			require.NoError(t, err)
+
+			require.NoError(t, sc.SetStopHeight(20, 10))
+
+			require.True(t, sc.ShouldExecute(20))
+			require.False(t, sc.ShouldExecute(21))
+
+			sc.BlockExecuted(19)
+			require.False(t, stopped)
+
+			sc.BlockExecuted(20)
+			require.True(t, stopped)
+
+			// removing the stop height allows execution again
+			require.NoError(t, sc.SetStopHeight(0, 20))
+			require.True(t, sc.ShouldExecute(21))
+		})
+	})
+
+	t.Run("stop height must be above executed height", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			sc, err := NewStopControl(unittest.Logger(), db, nil)
+			require.NoError(t, err)
+
+			require.Error(t, sc.SetStopHeight(10, 10))
+			require.Equal(t, uint64(0), sc.StopHeight())
+		})
+	})
+
+	t.Run("pause and resume", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			resumed := false
+			sc, err := NewStopControl(unittest.Logger(), db, nil)
+			require.NoError(t, err)
+			sc.SetOnUnblock(func() { resumed = true })
+
+			require.NoError(t, sc.Pause())
+			require.False(t, sc.ShouldExecute(1))
+
+			require.NoError(t, sc.Resume())
+			require.True(t, sc.ShouldExecute(1))
+			require.True(t, resumed)
+		})
+	})
+
+	t.Run("raising stop height executes queued blocks", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			unblocked := 0
+			sc, err := NewStopControl(unittest.Logger(), db, nil)
+			require.NoError(t, err)
+			sc.SetOnUnblock(func() { unblocked++ })
+
+			// setting the first stop height or lowering it does not unblock any block
+			require.NoError(t, sc.SetStopHeight(20, 10))
+			require.NoError(t, sc.SetStopHeight(15, 10))
+			require.Equal(t, 0, unblocked)
+
+			require.NoError(t, sc.SetStopHeight(30, 10))
+			require.Equal(t, 1, unblocked)
+			require.True(t, sc.ShouldExecute(30))
+
+			require.NoError(t, sc.SetStopHeight(0, 10))
+			require.Equal(t, 2, unblocked)
+
+			// blocks are not unblocked while execution is paused, but once it is resumed
+			require.NoError(t, sc.SetStopHeight(20, 10))
+			require.NoError(t, sc.Pause())
+			require.NoError(t, sc.SetStopHeight(30, 10))
+			require.Equal(t, 2, unblocked)
+			require.NoError(t, sc.Resume())
+			require.Equal(t, 3, unblocked)
+		})
+	})
+
+	t.Run("settings are persisted", func(t *testing.T) {
+		unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+			sc, err := NewStopControl(unittest.Logger(), db, nil)
+			require.NoError(t, err)
+
+			require.NoError(t, sc.SetStopHeight(20, 10))
+			require.NoError(t, sc.SetStopHeight(30, 10))
+			require.NoError(t, sc.Pause())
+
+			restarted, err := NewStopControl(unittest.Logger(), db, nil)
+			require.NoError(t, err)
+
+			require.Equal(t, uint64(30), restarted.StopHeight())
+			require.True(t, restarted.IsPaused())
+		})
+	})
+}
@@ -0,0 +1,256 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/engine/execution/state"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// Config is the retention policy of the chunk data pack pruner.
+// Chunk data packs of a sealed block are only pruned once the block falls outside of all configured retention windows.
+type Config struct {
+	RetainBlocks    uint64        // number of sealed blocks below the latest sealed block whose chunk data packs are kept
+	RetainDuration  time.Duration // chunk data packs of blocks younger than this are kept, 0 disables the age based retention
+	Interval        time.Duration // interval between background pruning runs, 0 disables the background pruner
+	MaxBlocksPerRun uint64        // maximum number of blocks pruned in a single run, 0 means no limit
+}
+
+func DefaultConfig() Config {
+	return Config{
+		RetainBlocks:    10_000,
+		RetainDuration:  0,
+		Interval:        0,
+		MaxBlocksPerRun: 1_000,
+	}
+}
+
+// Headers is the headers storage used by the pruner, which also removes entries from the index of chunk IDs
+// to block IDs. Removing these entries is not part of the storage.Headers interface, as only execution nodes prune it.
+type Headers interface {
+	storage.Headers
+	RemoveChunkBlockIndexByChunkID(chunkID flow.Identifier) error
+}
+
+// Result describes a single run of the pruner.
+type Result struct {
+	PrunedHeight   uint64 `json:"pruned_height"`    // height up to which chunk data packs have been pruned after the run
+	Blocks         uint64 `json:"blocks"`           // number of blocks pruned in the run
+	ChunkDataPacks int    `json:"chunk_data_packs"` // number of chunk data packs removed in the run
+}
+
+// Pruner removes the chunk data packs, and the index from their chunk IDs to the block ID, of sealed blocks
+// which fall outside of the retention policy. Once a block is sealed, verification nodes no longer request its
+// chunk data packs, so they are only kept for a while to serve late requests and debugging.
+// Chunk locators are only stored by verification nodes, hence there is nothing to prune for them here.
+//
+// Blocks are pruned in order of height, and the height up to which chunk data packs have been pruned is
+// persisted, so pruning resumes where it left off after a restart.
+type Pruner struct {
+	mu             sync.Mutex
+	unit           *engine.Unit
+	log            zerolog.Logger
+	config         Config
+	metrics        module.ExecutionMetrics
+	db             *badger.DB
+	state          protocol.State
+	execState      state.ReadOnlyExecutionState
+	headers        Headers
+	results        storage.ExecutionResults
+	chunkDataPacks storage.ChunkDataPacks
+	prunedHeight   uint64
+}
+
+func New(
+	log zerolog.Logger,
+	config Config,
+	metrics module.ExecutionMetrics,
+	db *badger.DB,
+	state protocol.State,
+	execState state.ReadOnlyExecutionState,
+	headers Headers,
+	results storage.ExecutionResults,
+	chunkDataPacks storage.ChunkDataPacks,
+) (*Pruner, error) {
+	p := &Pruner{
+		unit:           engine.NewUnit(),
+		log:            log.With().Str("component", "chunk_data_pack_pruner").Logger(),
+		config:         config,
+		metrics:        metrics,
+		db:             db,
+		state:          state,
+		execState:      execState,
+		headers:        headers,
+		results:        results,
+		chunkDataPacks: chunkDataPacks,
+	}
+
+	err := db.View(operation.RetrieveChunkDataPacksPrunedHeight(&p.prunedHeight))
+	if errors.Is(err, storage.ErrNotFound) {
+		// nothing has been pruned yet, start with the blocks above the root block
+		root, err := state.Params().Root()
+		if err != nil {
+			return nil, fmt.Errorf("could not get root block: %w", err)
+		}
+		p.prunedHeight = root.Height
+		err = operation.RetryOnConflict(db.Update, operation.InsertChunkDataPacksPrunedHeight(p.prunedHeight))
+		if err != nil {
+			return nil, fmt.Errorf("could not initialize pruned height: %w", err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("could not retrieve pruned height: %w", err)
+	}
+
+	return p, nil
+}
+
+// Ready starts the background pruner, if enabled.
+func (p *Pruner) Ready() <-chan struct{} {
+	if p.config.Interval > 0 {
+		p.unit.LaunchPeriodically(func() {
+			_, err := p.Prune()
+			if err != nil {
+				p.log.Error().Err(err).Msg("could not prune chunk data packs")
+			}
+		}, p.config.Interval, p.config.Interval)
+	}
+	return p.unit.Ready()
+}
+
+func (p *Pruner) Done() <-chan struct{} {
+	return p.unit.Done()
+}
+
+// PrunedHeight returns the height up to which chunk data packs have been pruned.
+func (p *Pruner) PrunedHeight() uint64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.prunedHeight
+}
+
+// Prune removes the chunk data packs of all blocks which fall outside of the retention policy,
+// up to the configured maximum number of blocks per run.
+func (p *Pruner) Prune() (*Result, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	start := time.Now()
+
+	limit, err := p.pruneLimit()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.config.MaxBlocksPerRun > 0 && limit > p.prunedHeight+p.config.MaxBlocksPerRun {
+		limit = p.prunedHeight + p.config.MaxBlocksPerRun
+	}
+
+	result := &Result{}
+	for height := p.prunedHeight + 1; height <= limit; height++ {
+		header, err := p.headers.ByHeight(height)
+		if err != nil {
+			return nil, fmt.Errorf("could not get block at height %d: %w", height, err)
+		}
+
+		// block timestamps increase with height, so all following blocks are retained as well
+		if p.config.RetainDuration > 0 && time.Since(header.Timestamp) < p.config.RetainDuration {
+			break
+		}
+
+		removed, err := p.pruneBlock(header.ID())
+		if err != nil {
+			return nil, fmt.Errorf("could not prune chunk data packs of block at height %d: %w", height, err)
+		}
+
+		err = operation.RetryOnConflict(p.db.Update, operation.UpdateChunkDataPacksPrunedHeight(height))
+		if err != nil {
+			return nil, fmt.Errorf("could not update pruned height: %w", err)
+		}
+
+		p.prunedHeight = height
+		result.Blocks++
+		result.ChunkDataPacks += removed
+	}
+	result.PrunedHeight = p.prunedHeight
+
+	p.metrics.ExecutionChunkDataPacksPruned(time.Since(start), result.PrunedHeight, result.ChunkDataPacks)
+
+	if result.Blocks > 0 {
+		p.log.Info().
+			Uint64("pruned_height", result.PrunedHeight).
+			Uint64("blocks", result.Blocks).
+			Int("chunk_data_packs", result.ChunkDataPacks).
+			Dur("duration", time.Since(start)).
+			Msg("chunk data packs pruned")
+	}
+
+	return result, nil
+}
+
+// pruneLimit returns the highest height whose chunk data packs may be pruned according to the number of
+// blocks to retain. Only executed blocks are pruned, as unexecuted blocks will still get chunk data packs.
+func (p *Pruner) pruneLimit() (uint64, error) {
+	sealed, err := p.state.Sealed().Head()
+	if err != nil {
+		return 0, fmt.Errorf("could not get sealed block: %w", err)
+	}
+
+	if sealed.Height < p.config.RetainBlocks {
+		return 0, nil
+	}
+	limit := sealed.Height - p.config.RetainBlocks
+
+	executedHeight, _, err := p.execState.GetHighestExecutedBlockID(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("could not get highest executed block: %w", err)
+	}
+	if executedHeight < limit {
+		limit = executedHeight
+	}
+
+	return limit, nil
+}
+
+// pruneBlock removes the chunk data packs of the execution result of the given block,
+// and returns the number of chunk data packs removed.
+func (p *Pruner) pruneBlock(blockID flow.Identifier) (int, error) {
+	result, err := p.results.ByBlockID(blockID)
+	if errors.Is(err, storage.ErrNotFound) {
+		// the block might not have been executed by this node, e.g. when it was state synced
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not get execution result: %w", err)
+	}
+
+	removed := 0
+	for _, chunk := range result.Chunks {
+		chunkID := chunk.ID()
+
+		err = p.chunkDataPacks.Remove(chunkID)
+		if err == nil {
+			removed++
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			return 0, fmt.Errorf("could not remove chunk data pack %v: %w", chunkID, err)
+		}
+
+		err = p.headers.RemoveChunkBlockIndexByChunkID(chunkID)
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return 0, fmt.Errorf("could not remove block index of chunk %v: %w", chunkID, err)
+		}
+	}
+
+	return removed, nil
+}
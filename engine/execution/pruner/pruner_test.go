@@ -0,0 +1,191 @@
+package pruner
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	statemock "github.com/onflow/flow-go/engine/execution/state/mock"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	protocolmock "github.com/onflow/flow-go/state/protocol/mock"
+	"github.com/onflow/flow-go/storage"
+	bstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/storage/badger/operation"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// prunerFixture stores blocks at heights 1 to 10, each with an execution result of two chunks,
+// and the chunk data packs and chunk block indices of these chunks.
+type prunerFixture struct {
+	db             *badger.DB
+	headers        *bstorage.Headers
+	results        *bstorage.ExecutionResults
+	chunkDataPacks *bstorage.ChunkDataPacks
+	blocks         []*flow.Header // indexed by height
+	chunkIDs       [][]flow.Identifier
+	sealedHeight   uint64
+	executedHeight uint64
+}
+
+func newPrunerFixture(t *testing.T, db *badger.DB, blockAge func(height uint64) time.Duration) *prunerFixture {
+	collector := metrics.NewNoopCollector()
+	transactions := bstorage.NewTransactions(collector, db)
+	f := &prunerFixture{
+		db:             db,
+		headers:        bstorage.NewHeaders(collector, db),
+		results:        bstorage.NewExecutionResults(collector, db),
+		chunkDataPacks: bstorage.NewChunkDataPacks(collector, db, bstorage.NewCollections(db, transactions), 100),
+		blocks:         make([]*flow.Header, 11),
+		chunkIDs:       make([][]flow.Identifier, 11),
+		sealedHeight:   10,
+		executedHeight: 10,
+	}
+
+	for height := uint64(1); height <= 10; height++ {
+		header := unittest.BlockHeaderFixture()
+		header.Height = height
+		header.Timestamp = time.Now().Add(-blockAge(height))
+		blockID := header.ID()
+		require.NoError(t, db.Update(operation.InsertHeader(blockID, &header)))
+		require.NoError(t, db.Update(operation.IndexBlockHeight(height, blockID)))
+
+		result := unittest.ExecutionResultFixture()
+		result.BlockID = blockID
+		result.Chunks = unittest.ChunkListFixture(2, blockID)
+		require.NoError(t, f.results.Store(result))
+		require.NoError(t, f.results.Index(blockID, result.ID()))
+
+		for _, chunk := range result.Chunks {
+			chunkID := chunk.ID()
+			require.NoError(t, f.chunkDataPacks.Store(&flow.ChunkDataPack{ChunkID: chunkID}))
+			require.NoError(t, f.headers.IndexByChunkID(blockID, chunkID))
+			f.chunkIDs[height] = append(f.chunkIDs[height], chunkID)
+		}
+		f.blocks[height] = &header
+	}
+
+	return f
+}
+
+func (f *prunerFixture) newPruner(t *testing.T, config Config) *Pruner {
+	root := unittest.BlockHeaderFixture()
+	root.Height = 0
+
+	params := new(protocolmock.Params)
+	params.On("Root").Return(&root, nil)
+
+	sealed := new(protocolmock.Snapshot)
+	sealed.On("Head").Return(func() *flow.Header { return f.blocks[f.sealedHeight] }, nil)
+
+	state := new(protocolmock.State)
+	state.On("Params").Return(params)
+	state.On("Sealed").Return(sealed)
+
+	execState := new(statemock.ReadOnlyExecutionState)
+	execState.On("GetHighestExecutedBlockID", mock.Anything).Return(
+		func(context.Context) uint64 { return f.executedHeight },
+		func(context.Context) flow.Identifier { return f.blocks[f.executedHeight].ID() },
+		nil,
+	)
+
+	p, err := New(unittest.Logger(), config, metrics.NewNoopCollector(), f.db, state, execState, f.headers, f.results, f.chunkDataPacks)
+	require.NoError(t, err)
+	return p
+}
+
+// requirePruned checks that the chunk data packs and chunk block indices of exactly the blocks up to the given height are removed.
+func (f *prunerFixture) requirePruned(t *testing.T, prunedHeight uint64) {
+	for height := uint64(1); height <= 10; height++ {
+		for _, chunkID := range f.chunkIDs[height] {
+			_, cdpErr := f.chunkDataPacks.ByChunkID(chunkID)
+			_, indexErr := f.headers.IDByChunkID(chunkID)
+			if height <= prunedHeight {
+				require.True(t, errors.Is(cdpErr, storage.ErrNotFound), "chunk data pack at height %d should be pruned", height)
+				require.True(t, errors.Is(indexErr, storage.ErrNotFound), "chunk block index at height %d should be pruned", height)
+			} else {
+				require.NoError(t, cdpErr, "chunk data pack at height %d should be retained", height)
+				require.NoError(t, indexErr, "chunk block index at height %d should be retained", height)
+			}
+		}
+	}
+}
+
+func noAge(uint64) time.Duration { return 0 }
+
+func TestPruner_RetainBlocks(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		f := newPrunerFixture(t, db, noAge)
+		p := f.newPruner(t, Config{RetainBlocks: 3})
+
+		result, err := p.Prune()
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), result.PrunedHeight)
+		require.Equal(t, uint64(7), result.Blocks)
+		require.Equal(t, 14, result.ChunkDataPacks)
+		f.requirePruned(t, 7)
+
+		// pruning again without new sealed blocks is a no-op
+		result, err = p.Prune()
+		require.NoError(t, err)
+		require.Equal(t, uint64(7), result.PrunedHeight)
+		require.Equal(t, uint64(0), result.Blocks)
+	})
+}
+
+func TestPruner_OnlyExecutedBlocks(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		f := newPrunerFixture(t, db, noAge)
+		f.executedHeight = 5
+		p := f.newPruner(t, Config{RetainBlocks: 0})
+
+		result, err := p.Prune()
+		require.NoError(t, err)
+		require.Equal(t, uint64(5), result.PrunedHeight)
+		f.requirePruned(t, 5)
+	})
+}
+
+func TestPruner_RetainDuration(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		f := newPrunerFixture(t, db, func(height uint64) time.Duration {
+			if height <= 4 {
+				return 2 * time.Hour
+			}
+			return 0
+		})
+		p := f.newPruner(t, Config{RetainDuration: time.Hour})
+
+		result, err := p.Prune()
+		require.NoError(t, err)
+		require.Equal(t, uint64(4), result.PrunedHeight)
+		f.requirePruned(t, 4)
+	})
+}
+
+func TestPruner_MaxBlocksPerRunAndRestart(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		f := newPrunerFixture(t, db, noAge)
+		config := Config{RetainBlocks: 2, MaxBlocksPerRun: 3}
+		p := f.newPruner(t, config)
+
+		result, err := p.Prune()
+		require.NoError(t, err)
+		require.Equal(t, uint64(3), result.PrunedHeight)
+		f.requirePruned(t, 3)
+
+		// a restarted pruner resumes at the persisted height
+		restarted := f.newPruner(t, config)
+		require.Equal(t, uint64(3), restarted.PrunedHeight())
+
+		result, err = restarted.Prune()
+		require.NoError(t, err)
+		require.Equal(t, uint64(6), result.PrunedHeight)
+		f.requirePruned(t, 6)
+	})
+}
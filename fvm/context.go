@@ -8,6 +8,7 @@ import (
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/module"
+	"github.com/onflow/flow-go/storage"
 )
 
 // A Context defines a set of execution parameters used by the virtual machine.
@@ -21,6 +22,8 @@ type Context struct {
 	MaxStateValueSize             uint64
 	MaxStateInteractionSize       uint64
 	EventCollectionByteSizeLimit  uint64
+	StorageBytesWrittenLimit      uint64
+	EventCountLimit               uint64
 	MaxNumOfTxRetries             uint8
 	BlockHeader                   *flow.Header
 	ServiceAccountEnabled         bool
@@ -151,6 +154,23 @@ func WithEventCollectionSizeLimit(limit uint64) Option {
 	}
 }
 
+// WithStorageBytesWrittenLimit sets the limit of bytes a transaction can write to the ledger.
+// A limit of zero means unlimited.
+func WithStorageBytesWrittenLimit(limit uint64) Option {
+	return func(ctx Context) Context {
+		ctx.StorageBytesWrittenLimit = limit
+		return ctx
+	}
+}
+
+// WithEventCountLimit sets the limit of events a transaction can emit. A limit of zero means unlimited.
+func WithEventCountLimit(limit uint64) Option {
+	return func(ctx Context) Context {
+		ctx.EventCountLimit = limit
+		return ctx
+	}
+}
+
 // WithBlockHeader sets the block header for a virtual machine context.
 //
 // The VM uses the header to provide current block information to the Cadence runtime,
@@ -270,3 +290,24 @@ func WithTransactionFeesEnabled(enabled bool) Option {
 		return ctx
 	}
 }
+
+// NodeOptions returns the options used by nodes executing transactions on the given chain.
+// Tools re-executing historic blocks should use the same options to get the same results.
+func NodeOptions(chainID flow.ChainID, headers storage.Headers) []Option {
+	opts := []Option{
+		WithChain(chainID.Chain()),
+		WithBlocks(NewBlockFinder(headers)),
+		WithAccountStorageLimit(true),
+	}
+	if chainID == flow.Testnet || chainID == flow.Canary || chainID == flow.Mainnet {
+		opts = append(opts,
+			WithTransactionFeesEnabled(true),
+		)
+	}
+	if chainID == flow.Testnet || chainID == flow.Canary || chainID == flow.Localnet || chainID == flow.Benchnet {
+		opts = append(opts,
+			WithRestrictedDeployment(false),
+		)
+	}
+	return opts
+}
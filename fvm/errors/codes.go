@@ -60,6 +60,7 @@ const (
 	ErrCodeStateKeySizeLimitError             ErrorCode = 1107
 	ErrCodeStateValueSizeLimitError           ErrorCode = 1108
 	ErrCodeTransactionFeeDeductionFailedError ErrorCode = 1109
+	ErrCodeMeteringLimitExceededError         ErrorCode = 1110
 
 	// accounts errors 1200 - 1250
 	// ErrCodeAccountError              ErrorCode = 1200 - reserved
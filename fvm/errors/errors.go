@@ -84,6 +84,12 @@ func HandleRuntimeError(err error) error {
 		return NewUnknownFailure(externalErr)
 	}
 
+	// Metering limits are also enforced on writes to the storage, which the runtime wraps in storage errors.
+	var limitErr *MeteringLimitExceededError
+	if As(innerErr, &limitErr) {
+		return limitErr
+	}
+
 	// All other errors are non-fatal Cadence errors.
 	return NewCadenceRuntimeError(&runErr)
 }
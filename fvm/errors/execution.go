@@ -201,6 +201,33 @@ func (e *LedgerIntractionLimitExceededError) Code() ErrorCode {
 	return ErrCodeLedgerIntractionLimitExceededError
 }
 
+// MeteringLimitExceededError is returned when a tx goes over the limit of one of the metered resource kinds
+// (e.g. bytes of storage written or number of events)
+type MeteringLimitExceededError struct {
+	kind  string
+	used  uint64
+	limit uint64
+}
+
+// NewMeteringLimitExceededError constructs a MeteringLimitExceededError
+func NewMeteringLimitExceededError(kind string, used, limit uint64) *MeteringLimitExceededError {
+	return &MeteringLimitExceededError{kind: kind, used: used, limit: limit}
+}
+
+func (e *MeteringLimitExceededError) Error() string {
+	return fmt.Sprintf("%s %s usage has exceeded the limit (used: %d, limit %d)", e.Code().String(), e.kind, e.used, e.limit)
+}
+
+// Code returns the error code for this error
+func (e *MeteringLimitExceededError) Code() ErrorCode {
+	return ErrCodeMeteringLimitExceededError
+}
+
+// Kind returns the name of the metered kind which went over its limit
+func (e *MeteringLimitExceededError) Kind() string {
+	return e.kind
+}
+
 // OperationNotSupportedError is generated when an operation (e.g. getting block info) is
 // not supported in the current environment.
 type OperationNotSupportedError struct {
@@ -2829,6 +2829,178 @@ func TestEventLimits(t *testing.T) {
 	})
 }
 
+func TestMeteringLimits(t *testing.T) {
+
+	t.Parallel()
+
+	rt := fvm.NewInterpreterRuntime()
+	chain := flow.Mainnet.Chain()
+	vm := fvm.NewVirtualMachine(rt)
+
+	ctx := fvm.NewContext(
+		zerolog.Nop(),
+		fvm.WithChain(chain),
+		fvm.WithTransactionProcessors(
+			fvm.NewTransactionInvoker(zerolog.Nop()),
+		),
+	)
+
+	ledger := testutil.RootBootstrappedLedger(vm, ctx)
+	programs := programs.NewEmptyPrograms()
+
+	txBody := flow.NewTransactionBody().
+		SetScript([]byte(`
+		transaction {
+			prepare(signer: AuthAccount) {
+				var i = 0
+				while i < 5 {
+					signer.save(i, to: StoragePath(identifier: "value".concat(i.toString()))!)
+					i = i + 1
+				}
+			}
+		}`)).
+		AddAuthorizer(chain.ServiceAddress())
+
+	run := func(t *testing.T, ctx fvm.Context, payer flow.Address) *fvm.TransactionProcedure {
+		txBody.Payer = payer
+		tx := fvm.Transaction(txBody, 0)
+		err := vm.Run(ctx, tx, ledger.NewChild(), programs)
+		require.NoError(t, err)
+		return tx
+	}
+
+	t.Run("Without limits", func(t *testing.T) {
+		tx := run(t, ctx, unittest.RandomAddressFixture())
+		require.NoError(t, tx.Err)
+
+		assert.Greater(t, tx.MeteringUsage.StorageBytesWritten, uint64(0))
+	})
+
+	t.Run("Storage bytes written limit", func(t *testing.T) {
+		limited := fvm.NewContextFromParent(ctx, fvm.WithStorageBytesWrittenLimit(10))
+
+		tx := run(t, limited, unittest.RandomAddressFixture())
+		require.Error(t, tx.Err)
+		assert.Equal(t, (&errors.MeteringLimitExceededError{}).Code(), tx.Err.Code())
+
+		// the limits are not enforced for the service account
+		tx = run(t, limited, chain.ServiceAddress())
+		require.NoError(t, tx.Err)
+	})
+
+	t.Run("Event count limit", func(t *testing.T) {
+		limited := fvm.NewContextFromParent(ctx, fvm.WithEventCountLimit(1))
+
+		txBody := flow.NewTransactionBody().
+			SetScript([]byte(`
+			transaction {
+				prepare(signer: AuthAccount) {
+					let acct1 = AuthAccount(payer: signer)
+					let acct2 = AuthAccount(payer: signer)
+				}
+			}`)).
+			AddAuthorizer(chain.ServiceAddress())
+
+		txBody.Payer = unittest.RandomAddressFixture()
+		tx := fvm.Transaction(txBody, 0)
+		err := vm.Run(limited, tx, ledger.NewChild(), programs)
+		require.NoError(t, err)
+		require.Error(t, tx.Err)
+		assert.Equal(t, (&errors.MeteringLimitExceededError{}).Code(), tx.Err.Code())
+		assert.Empty(t, tx.Events, "events of the failed transaction are discarded")
+
+		txBody.Payer = chain.ServiceAddress()
+		tx = fvm.Transaction(txBody, 0)
+		err = vm.Run(limited, tx, ledger.NewChild(), programs)
+		require.NoError(t, err)
+		require.NoError(t, tx.Err)
+		assert.Greater(t, len(tx.Events), 1)
+	})
+
+	t.Run("Event bytes limit", func(t *testing.T) {
+		// the event byte size is limited by the event collection size limit, which predates metering and keeps its own error
+		limited := fvm.NewContextFromParent(ctx, fvm.WithEventCollectionSizeLimit(10))
+
+		txBody := flow.NewTransactionBody().
+			SetScript([]byte(`
+			transaction {
+				prepare(signer: AuthAccount) {
+					let acct = AuthAccount(payer: signer)
+				}
+			}`)).
+			AddAuthorizer(chain.ServiceAddress())
+
+		txBody.Payer = unittest.RandomAddressFixture()
+		tx := fvm.Transaction(txBody, 0)
+		err := vm.Run(limited, tx, ledger.NewChild(), programs)
+		require.NoError(t, err)
+		require.Error(t, tx.Err)
+		var limitErr *errors.EventLimitExceededError
+		assert.True(t, errors.As(tx.Err, &limitErr))
+	})
+}
+
+func TestMeteringLimits_TransactionFees(t *testing.T) {
+
+	t.Parallel()
+
+	run := func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, view state.View, programs *programs.Programs, script string) *fvm.TransactionProcedure {
+		privateKeys, err := testutil.GenerateAccountPrivateKeys(1)
+		require.NoError(t, err)
+		accounts, err := testutil.CreateAccounts(vm, view, programs, privateKeys, chain)
+		require.NoError(t, err)
+
+		txBody := flow.NewTransactionBody().
+			SetScript([]byte(script)).
+			AddAuthorizer(accounts[0]).
+			SetProposalKey(chain.ServiceAddress(), 0, 0).
+			SetPayer(accounts[0])
+
+		err = testutil.SignPayload(txBody, chain.ServiceAddress(), unittest.ServiceAccountPrivateKey)
+		require.NoError(t, err)
+		err = testutil.SignEnvelope(txBody, accounts[0], privateKeys[0])
+		require.NoError(t, err)
+
+		// the deduction of fees writes to the storage and emits events, which goes over these limits
+		ctx = fvm.NewContextFromParent(ctx,
+			fvm.WithStorageBytesWrittenLimit(1),
+			fvm.WithEventCountLimit(1),
+		)
+
+		tx := fvm.Transaction(txBody, 0)
+		err = vm.Run(ctx, tx, view, programs)
+		require.NoError(t, err, "the limits must not fail the deduction of transaction fees")
+		return tx
+	}
+
+	vmTest := newVMTest().
+		withBootstrapProcedureOptions(fvm.WithTransactionFee(fvm.DefaultTransactionFees)).
+		withContextOptions(fvm.WithTransactionFeesEnabled(true))
+
+	t.Run("Transaction within limits", vmTest.run(
+		func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, view state.View, programs *programs.Programs) {
+			tx := run(t, vm, chain, ctx, view, programs, `
+			transaction {
+				prepare(signer: AuthAccount) {}
+			}`)
+			require.NoError(t, tx.Err)
+			assert.NotEmpty(t, tx.Events, "fee deduction events are emitted")
+		}))
+
+	t.Run("Transaction over limits", vmTest.run(
+		func(t *testing.T, vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, view state.View, programs *programs.Programs) {
+			tx := run(t, vm, chain, ctx, view, programs, `
+			transaction {
+				prepare(signer: AuthAccount) {
+					signer.save(1, to: /storage/value)
+				}
+			}`)
+			require.Error(t, tx.Err)
+			assert.Equal(t, (&errors.MeteringLimitExceededError{}).Code(), tx.Err.Code())
+			assert.NotEmpty(t, tx.Events, "fees are deducted from the failed transaction")
+		}))
+}
+
 func TestBlockContext_ExecuteTransaction_FailingTransactions(t *testing.T) {
 	getBalance := func(vm *fvm.VirtualMachine, chain flow.Chain, ctx fvm.Context, view state.View, address flow.Address) uint64 {
 
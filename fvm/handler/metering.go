@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"github.com/onflow/flow-go/fvm/errors"
+)
+
+// MeteredKind is a dimension of resource usage tracked by the FVM.
+type MeteredKind uint8
+
+const (
+	// MeteredComputation is the computation reported by the Cadence runtime
+	MeteredComputation MeteredKind = iota
+	// MeteredStorageBytesWritten is the total byte size of the keys and values written to the ledger
+	MeteredStorageBytesWritten
+	// MeteredEventCount is the number of emitted events
+	MeteredEventCount
+	// MeteredEventBytes is the total byte size of the emitted event payloads
+	MeteredEventBytes
+
+	numMeteredKinds
+)
+
+// AllMeteredKinds lists every kind tracked by a MeteringHandler
+var AllMeteredKinds = []MeteredKind{
+	MeteredComputation,
+	MeteredStorageBytesWritten,
+	MeteredEventCount,
+	MeteredEventBytes,
+}
+
+func (k MeteredKind) String() string {
+	switch k {
+	case MeteredComputation:
+		return "computation"
+	case MeteredStorageBytesWritten:
+		return "storage_bytes_written"
+	case MeteredEventCount:
+		return "event_count"
+	case MeteredEventBytes:
+		return "event_bytes"
+	default:
+		return "unknown"
+	}
+}
+
+// MeteringLimits holds the limit of every metered kind. A limit of zero means unlimited.
+type MeteringLimits struct {
+	Computation         uint64
+	StorageBytesWritten uint64
+	EventCount          uint64
+	EventBytes          uint64
+}
+
+// Limit returns the limit configured for the given kind
+func (l MeteringLimits) Limit(kind MeteredKind) uint64 {
+	switch kind {
+	case MeteredComputation:
+		return l.Computation
+	case MeteredStorageBytesWritten:
+		return l.StorageBytesWritten
+	case MeteredEventCount:
+		return l.EventCount
+	case MeteredEventBytes:
+		return l.EventBytes
+	default:
+		return 0
+	}
+}
+
+// MeteringUsage holds the amount used of every metered kind.
+type MeteringUsage struct {
+	Computation         uint64
+	StorageBytesWritten uint64
+	EventCount          uint64
+	EventBytes          uint64
+}
+
+// Used returns the usage recorded for the given kind
+func (u MeteringUsage) Used(kind MeteredKind) uint64 {
+	switch kind {
+	case MeteredComputation:
+		return u.Computation
+	case MeteredStorageBytesWritten:
+		return u.StorageBytesWritten
+	case MeteredEventCount:
+		return u.EventCount
+	case MeteredEventBytes:
+		return u.EventBytes
+	default:
+		return 0
+	}
+}
+
+// Add returns the sum of two usages
+func (u MeteringUsage) Add(other MeteringUsage) MeteringUsage {
+	return MeteringUsage{
+		Computation:         u.Computation + other.Computation,
+		StorageBytesWritten: u.StorageBytesWritten + other.StorageBytesWritten,
+		EventCount:          u.EventCount + other.EventCount,
+		EventBytes:          u.EventBytes + other.EventBytes,
+	}
+}
+
+// MeteringHandler meters every kind of resource usage separately and enforces the per-kind limits.
+//
+// Computation is both metered and limited by the Cadence runtime through the ComputationMeteringHandler,
+// so the MeteringHandler only records it for reporting purposes.
+//
+// Memory is not metered, as the Cadence runtime does not report the memory used by a transaction.
+type MeteringHandler struct {
+	limits        MeteringLimits
+	used          [numMeteredKinds]uint64
+	enforceLimits bool
+}
+
+// NewMeteringHandler constructs a new MeteringHandler. If enforceLimits is false
+// usage is recorded but never rejected, which is used for the service account.
+func NewMeteringHandler(limits MeteringLimits, enforceLimits bool) *MeteringHandler {
+	return &MeteringHandler{
+		limits:        limits,
+		enforceLimits: enforceLimits,
+	}
+}
+
+// Meter adds the given amount to the usage of the given kind.
+// It returns a MeteringLimitExceededError if the new usage goes over the configured limit,
+// in which case the usage is not updated.
+func (h *MeteringHandler) Meter(kind MeteredKind, amount uint64) error {
+	if kind >= numMeteredKinds {
+		return errors.NewValueErrorf(kind.String(), "unknown metered kind")
+	}
+
+	used := h.used[kind] + amount
+	limit := h.limits.Limit(kind)
+	if h.enforceLimits && kind != MeteredComputation && limit > 0 && used > limit {
+		return errors.NewMeteringLimitExceededError(kind.String(), used, limit)
+	}
+
+	h.used[kind] = used
+	return nil
+}
+
+// Record adds the given amount to the usage of the given kind without checking the limit. It is used while the
+// transaction limits are disabled, e.g. during the deduction of transaction fees.
+func (h *MeteringHandler) Record(kind MeteredKind, amount uint64) {
+	if kind >= numMeteredKinds {
+		return
+	}
+	h.used[kind] += amount
+}
+
+// SetUsed overrides the usage of the given kind without checking the limit.
+func (h *MeteringHandler) SetUsed(kind MeteredKind, used uint64) {
+	if kind >= numMeteredKinds {
+		return
+	}
+	h.used[kind] = used
+}
+
+// Used returns the usage of the given kind
+func (h *MeteringHandler) Used(kind MeteredKind) uint64 {
+	if kind >= numMeteredKinds {
+		return 0
+	}
+	return h.used[kind]
+}
+
+// Limit returns the limit of the given kind
+func (h *MeteringHandler) Limit(kind MeteredKind) uint64 {
+	return h.limits.Limit(kind)
+}
+
+// Usage returns a snapshot of the usage of all kinds
+func (h *MeteringHandler) Usage() MeteringUsage {
+	return MeteringUsage{
+		Computation:         h.used[MeteredComputation],
+		StorageBytesWritten: h.used[MeteredStorageBytesWritten],
+		EventCount:          h.used[MeteredEventCount],
+		EventBytes:          h.used[MeteredEventBytes],
+	}
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/fvm/errors"
+)
+
+func TestMeteringHandler(t *testing.T) {
+	limits := MeteringLimits{
+		Computation:         100,
+		StorageBytesWritten: 300,
+		EventCount:          2,
+		EventBytes:          0,
+	}
+
+	t.Run("Get Limit", func(t *testing.T) {
+		h := NewMeteringHandler(limits, true)
+
+		require.Equal(t, uint64(100), h.Limit(MeteredComputation))
+		require.Equal(t, uint64(300), h.Limit(MeteredStorageBytesWritten))
+		require.Equal(t, uint64(2), h.Limit(MeteredEventCount))
+		require.Equal(t, uint64(0), h.Limit(MeteredEventBytes))
+	})
+
+	t.Run("kinds are metered separately", func(t *testing.T) {
+		h := NewMeteringHandler(limits, true)
+
+		require.NoError(t, h.Meter(MeteredStorageBytesWritten, 20))
+		require.NoError(t, h.Meter(MeteredStorageBytesWritten, 20))
+		require.NoError(t, h.Meter(MeteredEventCount, 1))
+
+		require.Equal(t, MeteringUsage{
+			StorageBytesWritten: 40,
+			EventCount:          1,
+		}, h.Usage())
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		h := NewMeteringHandler(limits, true)
+
+		require.NoError(t, h.Meter(MeteredEventCount, 2))
+
+		err := h.Meter(MeteredEventCount, 1)
+		require.Error(t, err)
+
+		var limitErr *errors.MeteringLimitExceededError
+		require.ErrorAs(t, err, &limitErr)
+		require.Equal(t, MeteredEventCount.String(), limitErr.Kind())
+
+		// usage is not updated when the limit is exceeded
+		require.Equal(t, uint64(2), h.Used(MeteredEventCount))
+	})
+
+	t.Run("zero limit is unlimited", func(t *testing.T) {
+		h := NewMeteringHandler(limits, true)
+
+		require.NoError(t, h.Meter(MeteredEventBytes, 1_000_000))
+		require.Equal(t, uint64(1_000_000), h.Used(MeteredEventBytes))
+	})
+
+	t.Run("computation limit is not enforced", func(t *testing.T) {
+		h := NewMeteringHandler(limits, true)
+
+		require.NoError(t, h.Meter(MeteredComputation, 1000))
+		require.Equal(t, uint64(1000), h.Used(MeteredComputation))
+	})
+
+	t.Run("limits are not enforced if disabled", func(t *testing.T) {
+		h := NewMeteringHandler(limits, false)
+
+		require.NoError(t, h.Meter(MeteredStorageBytesWritten, 1000))
+		require.Equal(t, uint64(1000), h.Used(MeteredStorageBytesWritten))
+	})
+
+	t.Run("usage add", func(t *testing.T) {
+		u := MeteringUsage{Computation: 1, StorageBytesWritten: 3, EventCount: 4, EventBytes: 5}
+
+		sum := u.Add(u)
+		for _, kind := range AllMeteredKinds {
+			require.Equal(t, 2*u.Used(kind), sum.Used(kind))
+		}
+	})
+}
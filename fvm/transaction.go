@@ -8,6 +8,7 @@ import (
 	"github.com/opentracing/opentracing-go"
 
 	"github.com/onflow/flow-go/fvm/errors"
+	"github.com/onflow/flow-go/fvm/handler"
 	"github.com/onflow/flow-go/fvm/programs"
 	"github.com/onflow/flow-go/fvm/state"
 	"github.com/onflow/flow-go/model/flow"
@@ -33,6 +34,7 @@ type TransactionProcedure struct {
 	Events          []flow.Event
 	ServiceEvents   []flow.Event
 	ComputationUsed uint64
+	MeteringUsage   handler.MeteringUsage
 	Err             errors.Error
 	Retried         int
 	TraceSpan       opentracing.Span
@@ -43,6 +43,7 @@ type TransactionEnv struct {
 	accountKeys        *handler.AccountKeyHandler
 	metrics            *handler.MetricsHandler
 	computationHandler handler.ComputationMeteringHandler
+	meteringHandler    *handler.MeteringHandler
 	eventHandler       *handler.EventHandler
 	addressGenerator   flow.AddressGenerator
 	rng                *rand.Rand
@@ -80,6 +81,7 @@ func NewTransactionEnvironment(
 	accountKeys := handler.NewAccountKeyHandler(accounts)
 	metrics := handler.NewMetricsHandler(ctx.Metrics)
 	computationHandler := handler.NewComputationMeteringHandler(computationLimit(ctx, tx))
+	meteringHandler := handler.NewMeteringHandler(meteringLimits(ctx, tx), tx.Payer != ctx.Chain.ServiceAddress())
 
 	env := &TransactionEnv{
 		vm:                 vm,
@@ -93,6 +95,7 @@ func NewTransactionEnvironment(
 		uuidGenerator:      uuidGenerator,
 		eventHandler:       eventHandler,
 		computationHandler: computationHandler,
+		meteringHandler:    meteringHandler,
 		tx:                 tx,
 		txIndex:            txIndex,
 		txID:               tx.ID(),
@@ -124,6 +127,15 @@ func computationLimit(ctx Context, tx *flow.TransactionBody) uint64 {
 	return tx.GasLimit
 }
 
+func meteringLimits(ctx Context, tx *flow.TransactionBody) handler.MeteringLimits {
+	return handler.MeteringLimits{
+		Computation:         computationLimit(ctx, tx),
+		StorageBytesWritten: ctx.StorageBytesWrittenLimit,
+		EventCount:          ctx.EventCountLimit,
+		EventBytes:          ctx.EventCollectionByteSizeLimit,
+	}
+}
+
 func (e *TransactionEnv) TxIndex() uint32 {
 	return e.txIndex
 }
@@ -244,6 +256,12 @@ func (e *TransactionEnv) SetValue(owner, key, value []byte) error {
 	if err != nil {
 		return fmt.Errorf("setting value failed: %w", err)
 	}
+
+	// only successful writes are metered. Going over the limit fails the transaction, which discards the write.
+	err = e.meter(handler.MeteredStorageBytesWritten, uint64(len(key)+len(value)))
+	if err != nil {
+		return fmt.Errorf("setting value failed: %w", err)
+	}
 	return nil
 }
 
@@ -516,7 +534,34 @@ func (e *TransactionEnv) EmitEvent(event cadence.Event) error {
 		defer sp.Finish()
 	}
 
-	return e.eventHandler.EmitEvent(event, e.txID, e.txIndex, e.tx.Payer)
+	sizeBefore := e.eventHandler.EventCollection().TotalByteSize()
+	err := e.eventHandler.EmitEvent(event, e.txID, e.txIndex, e.tx.Payer)
+	if err != nil {
+		return err
+	}
+
+	// the event handler enforces the event byte size limit, so the usage is only recorded here
+	e.meteringHandler.SetUsed(
+		handler.MeteredEventBytes,
+		e.meteringHandler.Used(handler.MeteredEventBytes)+e.eventHandler.EventCollection().TotalByteSize()-sizeBefore,
+	)
+
+	// only emitted events are metered. Going over the limit fails the transaction, which discards its events.
+	err = e.meter(handler.MeteredEventCount, 1)
+	if err != nil {
+		return fmt.Errorf("emitting event failed: %w", err)
+	}
+	return nil
+}
+
+// meter records the usage of the given kind, and enforces its limit unless the limits of the transaction are
+// disabled, e.g. while deducting transaction fees, which must not fail on the limits of the transaction.
+func (e *TransactionEnv) meter(kind handler.MeteredKind, amount uint64) error {
+	if !e.sth.EnforceInteractionLimits() {
+		e.meteringHandler.Record(kind, amount)
+		return nil
+	}
+	return e.meteringHandler.Meter(kind, amount)
 }
 
 func (e *TransactionEnv) Events() []flow.Event {
@@ -556,6 +601,13 @@ func (e *TransactionEnv) GetComputationUsed() uint64 {
 	return e.computationHandler.Used()
 }
 
+// MeteringUsage returns the usage of every metered kind.
+// Computation is taken from the computation metering handler.
+func (e *TransactionEnv) MeteringUsage() handler.MeteringUsage {
+	e.meteringHandler.SetUsed(handler.MeteredComputation, e.computationHandler.Used())
+	return e.meteringHandler.Usage()
+}
+
 func (e *TransactionEnv) SetAccountFrozen(address common.Address, frozen bool) error {
 
 	flowAddress := flow.Address(address)
@@ -43,6 +43,7 @@ require (
 	github.com/ipfs/go-ipfs-provider v0.7.0
 	github.com/ipfs/go-log v1.0.5
 	github.com/ipld/go-ipld-prime v0.14.1 // indirect
+	github.com/klauspost/compress v1.11.7
 	github.com/libp2p/go-addr-util v0.1.0
 	github.com/libp2p/go-libp2p v0.16.0
 	github.com/libp2p/go-libp2p-core v0.11.0
@@ -57,6 +58,7 @@ require (
 	github.com/multiformats/go-multiaddr v0.4.1
 	github.com/multiformats/go-multiaddr-dns v0.3.1
 	github.com/multiformats/go-multihash v0.1.0
+	github.com/multiformats/go-multistream v0.2.2
 	github.com/onflow/atree v0.2.0
 	github.com/onflow/cadence v0.23.0
 	github.com/onflow/flow v0.2.3-0.20220131193101-d4e2ca43a621
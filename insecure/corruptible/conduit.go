@@ -60,6 +60,21 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return nil
 }
 
+// Request is not supported by corruptible conduits, as the master of a conduit handles events without being able to
+// return the response of their targets.
+func (c *Conduit) Request(_ context.Context, _ interface{}, _ flow.Identifier) (interface{}, error) {
+	if c.ctx.Err() != nil {
+		return nil, fmt.Errorf("conduit for channel %s closed", c.channel)
+	}
+
+	return nil, fmt.Errorf("corruptible conduit does not support requests on channel %s", c.channel)
+}
+
+// ReportMisbehavior is a no-op, as the events of a corruptible conduit are handled by its master
+// instead of the networking layer.
+func (c *Conduit) ReportMisbehavior(originID flow.Identifier, misbehavior network.Misbehavior) {
+}
+
 // Close informs the conduit master that the engine is not going to use this conduit anymore.
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
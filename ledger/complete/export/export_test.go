@@ -0,0 +1,142 @@
+package export
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// randomTrie returns a trie holding n registers with execution state keys
+// and n registers with keys of a different structure.
+func randomTrie(t *testing.T, n int) *trie.MTrie {
+	keys := utils.RandomUniqueKeys(n, 3, 1, 16)
+	values := utils.RandomValues(n, 0, 32)
+
+	paths := utils.RandomPaths(2 * n)
+	payloads := make([]ledger.Payload, 0, 2*n)
+	for i := range keys {
+		payloads = append(payloads, *ledger.NewPayload(keys[i], values[i]))
+	}
+	for _, payload := range utils.RandomPayloads(n, 1, 32) {
+		payloads = append(payloads, *payload)
+	}
+
+	tr, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads, true)
+	require.NoError(t, err)
+	return tr
+}
+
+func TestExportImport(t *testing.T) {
+
+	t.Run("round trip", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			// more registers than fit into a single import batch
+			tr := randomTrie(t, importBatchSize)
+
+			manifest, err := ExportTrie(tr, dir)
+			require.NoError(t, err)
+			require.Equal(t, FormatVersion, manifest.Version)
+			require.Equal(t, tr.AllocatedRegCount(), manifest.RegisterCount)
+			require.Equal(t, tr.RootHash().String(), manifest.RootHash)
+
+			imported, importedManifest, err := ImportTrie(dir)
+			require.NoError(t, err)
+			require.Equal(t, manifest, importedManifest)
+			require.Equal(t, tr.RootHash(), imported.RootHash())
+			require.ElementsMatch(t, tr.AllPayloads(), imported.AllPayloads())
+		})
+	})
+
+	t.Run("empty trie", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			tr := trie.NewEmptyMTrie()
+
+			manifest, err := ExportTrie(tr, dir)
+			require.NoError(t, err)
+			require.Equal(t, uint64(0), manifest.RegisterCount)
+
+			imported, _, err := ImportTrie(dir)
+			require.NoError(t, err)
+			require.Equal(t, tr.RootHash(), imported.RootHash())
+		})
+	})
+
+	t.Run("modified registers are detected", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			tr := randomTrie(t, 10)
+			manifest, err := ExportTrie(tr, dir)
+			require.NoError(t, err)
+
+			// rewrite the registers file with a changed value and update the checksum,
+			// so only the root hash check can detect the modification
+			registersPath := filepath.Join(dir, RegistersFileName)
+			file, err := os.Open(registersPath)
+			require.NoError(t, err)
+			decoder := json.NewDecoder(file)
+			var registers []Register
+			for decoder.More() {
+				var register Register
+				require.NoError(t, decoder.Decode(&register))
+				registers = append(registers, register)
+			}
+			require.NoError(t, file.Close())
+
+			registers[3].Value = "00ff"
+
+			file, err = os.Create(registersPath)
+			require.NoError(t, err)
+			encoder := json.NewEncoder(file)
+			for _, register := range registers {
+				require.NoError(t, encoder.Encode(register))
+			}
+			require.NoError(t, file.Close())
+
+			data, err := os.ReadFile(registersPath)
+			require.NoError(t, err)
+			checksum := sha256.Sum256(data)
+			manifest.RegistersChecksum = hex.EncodeToString(checksum[:])
+			require.NoError(t, writeManifest(manifest, filepath.Join(dir, ManifestFileName)))
+
+			_, _, err = ImportTrie(dir)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "root hash mismatch")
+		})
+	})
+
+	t.Run("checksum mismatch", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			manifest, err := ExportTrie(randomTrie(t, 10), dir)
+			require.NoError(t, err)
+
+			manifest.RegistersChecksum = "00"
+			require.NoError(t, writeManifest(manifest, filepath.Join(dir, ManifestFileName)))
+
+			_, _, err = ImportTrie(dir)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), "checksum mismatch")
+		})
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		unittest.RunWithTempDir(t, func(dir string) {
+			manifest, err := ExportTrie(randomTrie(t, 10), dir)
+			require.NoError(t, err)
+
+			manifest.Version = FormatVersion + 1
+			require.NoError(t, writeManifest(manifest, filepath.Join(dir, ManifestFileName)))
+
+			_, _, err = ImportTrie(dir)
+			require.Error(t, err)
+		})
+	})
+}
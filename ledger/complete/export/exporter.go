@@ -0,0 +1,112 @@
+package export
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/onflow/flow-go/ledger/complete/mtrie/node"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+// ExportTrie writes all registers of the given trie into the output directory, followed by the manifest.
+// Registers are streamed to disk while traversing the trie, so the export doesn't hold a copy
+// of the register set in memory.
+func ExportTrie(t *trie.MTrie, outputDir string) (*Manifest, error) {
+	err := os.MkdirAll(outputDir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("could not create output dir %s: %w", outputDir, err)
+	}
+
+	file, err := os.Create(filepath.Join(outputDir, RegistersFileName))
+	if err != nil {
+		return nil, fmt.Errorf("could not create registers file: %w", err)
+	}
+	defer file.Close()
+
+	count, checksum, err := writeRegisters(t, file)
+	if err != nil {
+		return nil, fmt.Errorf("could not write registers: %w", err)
+	}
+
+	err = file.Sync()
+	if err != nil {
+		return nil, fmt.Errorf("could not sync registers file: %w", err)
+	}
+
+	manifest := &Manifest{
+		Version:           FormatVersion,
+		RootHash:          t.RootHash().String(),
+		RegisterCount:     count,
+		RegistersFile:     RegistersFileName,
+		RegistersChecksum: checksum,
+	}
+
+	err = writeManifest(manifest, filepath.Join(outputDir, ManifestFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	return manifest, nil
+}
+
+// writeRegisters writes the registers of the trie to the writer, in ascending order of path.
+// It returns the number of registers written and the hex-encoded SHA-256 checksum of the written data.
+func writeRegisters(t *trie.MTrie, w io.Writer) (uint64, string, error) {
+	hasher := sha256.New()
+	buffered := bufio.NewWriter(io.MultiWriter(w, hasher))
+	encoder := json.NewEncoder(buffered)
+
+	var count uint64
+	err := walkLeaves(t.RootNode(), func(n *node.Node) error {
+		err := encoder.Encode(newRegister(*n.Path(), n.Payload()))
+		if err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	if err != nil {
+		return 0, "", err
+	}
+
+	err = buffered.Flush()
+	if err != nil {
+		return 0, "", err
+	}
+
+	return count, hex.EncodeToString(hasher.Sum(nil)), nil
+}
+
+// walkLeaves calls fn for every leaf of the sub-trie with root n, from the left-most to the right-most leaf.
+// As paths are the position of the leaf in the trie, this visits the leaves in ascending order of path.
+func walkLeaves(n *node.Node, fn func(*node.Node) error) error {
+	if n == nil {
+		return nil
+	}
+	if n.IsLeaf() {
+		return fn(n)
+	}
+	err := walkLeaves(n.LeftChild(), fn)
+	if err != nil {
+		return err
+	}
+	return walkLeaves(n.RightChild(), fn)
+}
+
+func writeManifest(manifest *Manifest, path string) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode manifest: %w", err)
+	}
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		return fmt.Errorf("could not write manifest: %w", err)
+	}
+	return nil
+}
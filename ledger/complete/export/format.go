@@ -0,0 +1,150 @@
+// Package export implements a portable, versioned export format for the execution state
+// stored in an mtrie, and an importer which rebuilds the trie from it.
+//
+// Unlike checkpoints, which are a binary serialization of the trie nodes and can only be
+// loaded by the code base that wrote them, an export is a plain description of the
+// register set and can be consumed by external tools.
+//
+// An export is a directory containing two files:
+//
+//   - manifest.json: a JSON encoded Manifest, describing the format version, the root hash
+//     of the exported trie, the number of registers and the SHA-256 checksum of the registers file.
+//   - registers.ndjson: one JSON encoded Register per line, in ascending order of path.
+//
+// All binary fields are hex-encoded. Registers of the execution state have a key
+// consisting of an owner, a controller and a key part, which are exported as the
+// `owner`, `controller` and `key` fields. Keys with any other structure are exported
+// as the list of their key parts in the `key_parts` field instead.
+package export
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/onflow/flow-go/ledger"
+)
+
+const (
+	// FormatVersion is the version of the export format written by this package.
+	FormatVersion uint16 = 1
+
+	ManifestFileName  = "manifest.json"
+	RegistersFileName = "registers.ndjson"
+)
+
+// key part types used by the execution state for register keys
+const (
+	keyPartOwner      = uint16(0)
+	keyPartController = uint16(1)
+	keyPartKey        = uint16(2)
+)
+
+// Manifest describes an export.
+type Manifest struct {
+	Version           uint16 `json:"version"`
+	RootHash          string `json:"root_hash"`
+	RegisterCount     uint64 `json:"register_count"`
+	RegistersFile     string `json:"registers_file"`
+	RegistersChecksum string `json:"registers_checksum"` // hex-encoded SHA-256 of the registers file
+}
+
+// KeyPart is a single key part of a register key which doesn't follow the
+// owner, controller, key structure.
+type KeyPart struct {
+	Type  uint16 `json:"type"`
+	Value string `json:"value"`
+}
+
+// Register is a single line of the registers file.
+type Register struct {
+	Path       string    `json:"path"`
+	Owner      string    `json:"owner,omitempty"`
+	Controller string    `json:"controller,omitempty"`
+	Key        string    `json:"key,omitempty"`
+	KeyParts   []KeyPart `json:"key_parts,omitempty"`
+	Value      string    `json:"value"`
+}
+
+// isRegisterKey returns true if the key has the owner, controller, key structure of execution state registers.
+func isRegisterKey(key ledger.Key) bool {
+	return len(key.KeyParts) == 3 &&
+		key.KeyParts[0].Type == keyPartOwner &&
+		key.KeyParts[1].Type == keyPartController &&
+		key.KeyParts[2].Type == keyPartKey
+}
+
+// newRegister converts a trie leaf into its exported form.
+func newRegister(path ledger.Path, payload *ledger.Payload) Register {
+	register := Register{
+		Path:  hex.EncodeToString(path[:]),
+		Value: hex.EncodeToString(payload.Value),
+	}
+
+	if isRegisterKey(payload.Key) {
+		register.Owner = hex.EncodeToString(payload.Key.KeyParts[0].Value)
+		register.Controller = hex.EncodeToString(payload.Key.KeyParts[1].Value)
+		register.Key = hex.EncodeToString(payload.Key.KeyParts[2].Value)
+		return register
+	}
+
+	register.KeyParts = make([]KeyPart, 0, len(payload.Key.KeyParts))
+	for _, kp := range payload.Key.KeyParts {
+		register.KeyParts = append(register.KeyParts, KeyPart{
+			Type:  kp.Type,
+			Value: hex.EncodeToString(kp.Value),
+		})
+	}
+	return register
+}
+
+// toLeaf converts an exported register back into the path and payload of a trie leaf.
+func (r *Register) toLeaf() (ledger.Path, *ledger.Payload, error) {
+	pathBytes, err := hex.DecodeString(r.Path)
+	if err != nil {
+		return ledger.DummyPath, nil, fmt.Errorf("could not decode path: %w", err)
+	}
+	path, err := ledger.ToPath(pathBytes)
+	if err != nil {
+		return ledger.DummyPath, nil, fmt.Errorf("invalid path: %w", err)
+	}
+
+	value, err := hex.DecodeString(r.Value)
+	if err != nil {
+		return ledger.DummyPath, nil, fmt.Errorf("could not decode value: %w", err)
+	}
+
+	var keyParts []ledger.KeyPart
+	if len(r.KeyParts) > 0 {
+		if r.Owner != "" || r.Controller != "" || r.Key != "" {
+			return ledger.DummyPath, nil, fmt.Errorf("register has both key parts and owner, controller or key set")
+		}
+		keyParts = make([]ledger.KeyPart, 0, len(r.KeyParts))
+		for i, kp := range r.KeyParts {
+			kpValue, err := hex.DecodeString(kp.Value)
+			if err != nil {
+				return ledger.DummyPath, nil, fmt.Errorf("could not decode key part %d: %w", i, err)
+			}
+			keyParts = append(keyParts, ledger.NewKeyPart(kp.Type, kpValue))
+		}
+	} else {
+		owner, err := hex.DecodeString(r.Owner)
+		if err != nil {
+			return ledger.DummyPath, nil, fmt.Errorf("could not decode owner: %w", err)
+		}
+		controller, err := hex.DecodeString(r.Controller)
+		if err != nil {
+			return ledger.DummyPath, nil, fmt.Errorf("could not decode controller: %w", err)
+		}
+		key, err := hex.DecodeString(r.Key)
+		if err != nil {
+			return ledger.DummyPath, nil, fmt.Errorf("could not decode key: %w", err)
+		}
+		keyParts = []ledger.KeyPart{
+			ledger.NewKeyPart(keyPartOwner, owner),
+			ledger.NewKeyPart(keyPartController, controller),
+			ledger.NewKeyPart(keyPartKey, key),
+		}
+	}
+
+	return path, ledger.NewPayload(ledger.NewKey(keyParts), value), nil
+}
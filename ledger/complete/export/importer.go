@@ -0,0 +1,146 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+)
+
+// importBatchSize is the number of registers added to the trie at once while importing
+const importBatchSize = 10_000
+
+// ReadManifest reads and validates the manifest of the export in the given directory.
+func ReadManifest(inputDir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(inputDir, ManifestFileName))
+	if err != nil {
+		return nil, fmt.Errorf("could not read manifest: %w", err)
+	}
+
+	var manifest Manifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode manifest: %w", err)
+	}
+
+	if manifest.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported export format version %d (supported: %d)", manifest.Version, FormatVersion)
+	}
+	if manifest.RegistersFile == "" || filepath.Base(manifest.RegistersFile) != manifest.RegistersFile {
+		return nil, fmt.Errorf("invalid registers file name %q", manifest.RegistersFile)
+	}
+
+	return &manifest, nil
+}
+
+// ImportTrie rebuilds the trie from the export in the given directory.
+// It returns an error if the registers file doesn't match the checksum or the number of registers
+// in the manifest, or if the root hash of the rebuilt trie differs from the root hash in the manifest.
+func ImportTrie(inputDir string) (*trie.MTrie, *Manifest, error) {
+	manifest, err := ReadManifest(inputDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(filepath.Join(inputDir, manifest.RegistersFile))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open registers file: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	t, count, err := readRegisters(io.TeeReader(file, hasher))
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read registers: %w", err)
+	}
+
+	checksum := hex.EncodeToString(hasher.Sum(nil))
+	if checksum != manifest.RegistersChecksum {
+		return nil, nil, fmt.Errorf("registers file checksum mismatch (manifest: %s, actual: %s)", manifest.RegistersChecksum, checksum)
+	}
+
+	if count != manifest.RegisterCount {
+		return nil, nil, fmt.Errorf("register count mismatch (manifest: %d, actual: %d)", manifest.RegisterCount, count)
+	}
+
+	rootHash := t.RootHash().String()
+	if rootHash != manifest.RootHash {
+		return nil, nil, fmt.Errorf("root hash mismatch (manifest: %s, rebuilt trie: %s)", manifest.RootHash, rootHash)
+	}
+
+	return t, manifest, nil
+}
+
+// readRegisters reads all registers from the reader and adds them to an empty trie in batches.
+// Registers must be ordered by strictly ascending path, which also guarantees that there are no duplicates.
+func readRegisters(r io.Reader) (*trie.MTrie, uint64, error) {
+	t := trie.NewEmptyMTrie()
+
+	paths := make([]ledger.Path, 0, importBatchSize)
+	payloads := make([]ledger.Payload, 0, importBatchSize)
+
+	flush := func() error {
+		if len(paths) == 0 {
+			return nil
+		}
+		updated, err := trie.NewTrieWithUpdatedRegisters(t, paths, payloads, false)
+		if err != nil {
+			return fmt.Errorf("could not add registers to trie: %w", err)
+		}
+		t = updated
+		paths = paths[:0]
+		payloads = payloads[:0]
+		return nil
+	}
+
+	decoder := json.NewDecoder(bufio.NewReader(r))
+
+	var count uint64
+	var previous *ledger.Path
+	for {
+		var register Register
+		err := decoder.Decode(&register)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("could not decode register %d: %w", count, err)
+		}
+
+		path, payload, err := register.toLeaf()
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid register %d: %w", count, err)
+		}
+
+		if previous != nil && bytes.Compare(previous[:], path[:]) >= 0 {
+			return nil, 0, fmt.Errorf("register %d is out of order: path %x is not greater than previous path %x", count, path[:], previous[:])
+		}
+		previous = &path
+
+		paths = append(paths, path)
+		payloads = append(payloads, *payload)
+		count++
+
+		if len(paths) == importBatchSize {
+			err = flush()
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+
+	err := flush()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return t, count, nil
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/ledger/common/encoding"
 	"github.com/onflow/flow-go/ledger/common/hash"
 	"github.com/onflow/flow-go/ledger/common/pathfinder"
+	"github.com/onflow/flow-go/ledger/complete/export"
 	"github.com/onflow/flow-go/ledger/complete/mtrie"
 	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
 	"github.com/onflow/flow-go/ledger/complete/wal"
@@ -414,6 +415,27 @@ func (l *Ledger) DumpTrieAsJSON(state ledger.State, writer io.Writer) error {
 	return trie.DumpAsJSON(writer)
 }
 
+// ExportPortableAt exports all registers of the trie at the given state into the output directory,
+// using the portable export format of package export, and returns the manifest of the export
+func (l *Ledger) ExportPortableAt(state ledger.State, outputDir string) (*export.Manifest, error) {
+	t, err := l.forest.GetTrie(ledger.RootHash(state))
+	if err != nil {
+		return nil, fmt.Errorf("cannot find the target trie: %w", err)
+	}
+
+	manifest, err := export.ExportTrie(t, outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot export trie: %w", err)
+	}
+
+	l.logger.Info().
+		Str("root_hash", manifest.RootHash).
+		Uint64("register_count", manifest.RegisterCount).
+		Msgf("trie successfully exported to: %v", outputDir)
+
+	return manifest, nil
+}
+
 // this operation should only be used for exporting
 func (l *Ledger) keepOnlyOneTrie(state ledger.State) error {
 	// don't write things to WALs
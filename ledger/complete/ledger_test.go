@@ -20,6 +20,7 @@ import (
 	"github.com/onflow/flow-go/ledger/common/proof"
 	"github.com/onflow/flow-go/ledger/common/utils"
 	"github.com/onflow/flow-go/ledger/complete"
+	"github.com/onflow/flow-go/ledger/complete/export"
 	"github.com/onflow/flow-go/ledger/complete/wal"
 	"github.com/onflow/flow-go/ledger/complete/wal/fixtures"
 	"github.com/onflow/flow-go/ledger/partial/ptrie"
@@ -644,6 +645,35 @@ func Test_ExportCheckpointAt(t *testing.T) {
 	})
 }
 
+func Test_ExportPortableAt(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dbDir string) {
+		unittest.RunWithTempDir(t, func(exportDir string) {
+
+			diskWal, err := wal.NewDiskWAL(zerolog.Nop(), nil, metrics.NewNoopCollector(), dbDir, 100, pathfinder.PathByteSize, wal.SegmentSize)
+			require.NoError(t, err)
+			led, err := complete.NewLedger(diskWal, 100, &metrics.NoopCollector{}, zerolog.Logger{}, complete.DefaultPathFinderVersion)
+			require.NoError(t, err)
+
+			state := led.InitialState()
+			u := utils.UpdateFixture()
+			u.SetState(state)
+
+			state, _, err = led.Set(u)
+			require.NoError(t, err)
+
+			manifest, err := led.ExportPortableAt(state, exportDir)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(len(u.Keys())), manifest.RegisterCount)
+
+			imported, _, err := export.ImportTrie(exportDir)
+			require.NoError(t, err)
+			assert.Equal(t, ledger.RootHash(state), imported.RootHash())
+
+			<-diskWal.Done()
+		})
+	})
+}
+
 func TestWALUpdateIsRunInParallel(t *testing.T) {
 
 	// The idea of this test is - WAL update should be run in parallel
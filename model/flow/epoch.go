@@ -428,6 +428,10 @@ type EventIDs struct {
 	SetupID Identifier
 	// CommitID is the ID of the EpochCommit event for the respective Epoch
 	CommitID Identifier
+	// ParticipationID is the ID of the QC participation recorded for the respective
+	// Epoch, or ZeroID if none was recorded. During the setup phase, it references the
+	// participation recorded before the phase, the record is updated once the Epoch is committed.
+	ParticipationID Identifier
 }
 
 func NewEpochStatus(previousSetup, previousCommit, currentSetup, currentCommit, nextSetup, nextCommit Identifier) (*EpochStatus, error) {
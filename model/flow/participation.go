@@ -0,0 +1,106 @@
+package flow
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/onflow/flow-go/model/encoding/rlp"
+)
+
+// QCParticipation summarizes how reliably consensus participants contributed to the QCs of a
+// range of blocks. It is recorded by the protocol state during the setup phase of every epoch
+// and weighs the leader selection of the next epoch, if liveness-weighted leader selection is
+// enabled for the spork.
+type QCParticipation struct {
+	// Blocks is the number of blocks observed.
+	Blocks uint64
+	// Signed is the number of observed blocks whose parent QC was signed by the node, by node ID.
+	Signed map[Identifier]uint64
+}
+
+// NewQCParticipation returns an empty participation record.
+func NewQCParticipation() *QCParticipation {
+	return &QCParticipation{
+		Signed: make(map[Identifier]uint64),
+	}
+}
+
+// Add records one block, whose parent QC was signed by the given nodes.
+func (p *QCParticipation) Add(signerIDs []Identifier) {
+	p.Blocks++
+	for _, signerID := range signerIDs {
+		p.Signed[signerID]++
+	}
+}
+
+// Copy returns a deep copy of the participation record.
+func (p *QCParticipation) Copy() *QCParticipation {
+	cpy := &QCParticipation{
+		Blocks: p.Blocks,
+		Signed: make(map[Identifier]uint64, len(p.Signed)),
+	}
+	for nodeID, signed := range p.Signed {
+		cpy.Signed[nodeID] = signed
+	}
+	return cpy
+}
+
+// ID returns the hash of the participation record.
+func (p *QCParticipation) ID() Identifier {
+	return MakeID(p)
+}
+
+// Fingerprint returns a canonical encoding of the participation record, with the signers
+// ordered by node ID.
+func (p *QCParticipation) Fingerprint() []byte {
+	type signerCount struct {
+		NodeID Identifier
+		Signed uint64
+	}
+	signed := make([]signerCount, 0, len(p.Signed))
+	for nodeID, count := range p.Signed {
+		signed = append(signed, signerCount{NodeID: nodeID, Signed: count})
+	}
+	sort.Slice(signed, func(i, j int) bool {
+		return bytes.Compare(signed[i].NodeID[:], signed[j].NodeID[:]) < 0
+	})
+
+	return rlp.NewMarshaler().MustMarshal(struct {
+		Blocks uint64
+		Signed []signerCount
+	}{
+		Blocks: p.Blocks,
+		Signed: signed,
+	})
+}
+
+// LivenessWeighting specifies how a consensus node's leader selection weight is reduced
+// depending on the fraction of QCs it did not contribute to. All fractions are given in
+// permille. It is a global protocol parameter, so all nodes of a spork select the same leaders.
+type LivenessWeighting struct {
+	// MinWeightPermille is the lower bound for the reduced weight, relative to the node's
+	// weight. Nodes with non-zero weight always keep a chance to be selected, so they can
+	// recover once they are online again.
+	MinWeightPermille uint64
+	// ToleratedMissPermille is the fraction of missed QCs that does not reduce the weight.
+	// Above it, the weight decreases linearly, down to MinWeightPermille for nodes that missed all QCs.
+	ToleratedMissPermille uint64
+}
+
+// DefaultLivenessWeighting tolerates 10% missed QCs and reduces the weight of offline nodes to 10%.
+var DefaultLivenessWeighting = LivenessWeighting{
+	MinWeightPermille:     100,
+	ToleratedMissPermille: 100,
+}
+
+// Validate returns an error if the weighting parameters are out of range.
+func (w LivenessWeighting) Validate() error {
+	if w.MinWeightPermille > 1000 {
+		return fmt.Errorf("minimum weight must be at most 1000 permille (got %d)", w.MinWeightPermille)
+	}
+	if w.ToleratedMissPermille >= 1000 {
+		return fmt.Errorf("tolerated miss rate must be below 1000 permille (got %d)", w.ToleratedMissPermille)
+	}
+	return nil
+}
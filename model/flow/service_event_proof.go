@@ -0,0 +1,50 @@
+package flow
+
+import (
+	"fmt"
+)
+
+// ServiceEventProof proves that service events were emitted by execution results sealed in a block.
+// The payload is committed to by the header's payload hash, hence a client knowing that the header
+// is finalized can trust the service events without trusting the party providing the proof.
+// Note that the protocol state applies service events at the child of the sealing block.
+type ServiceEventProof struct {
+	// Header is the header of the block whose payload contains the seals.
+	Header *Header
+	// Payload is the payload of the block.
+	Payload *Payload
+	// Results are the sealed execution results emitting the service events.
+	Results []*ExecutionResult
+}
+
+// ServiceEvents returns the service events emitted by the proof's results, in the order the results
+// are sealed by the block. Returns an error if the payload does not match the header, or if any of the
+// results is not sealed by the payload.
+func (p *ServiceEventProof) ServiceEvents() (ServiceEventList, error) {
+	if p.Header == nil || p.Payload == nil {
+		return nil, fmt.Errorf("incomplete service event proof")
+	}
+	if p.Payload.Hash() != p.Header.PayloadHash {
+		return nil, fmt.Errorf("payload hash %x does not match header payload hash %x", p.Payload.Hash(), p.Header.PayloadHash)
+	}
+
+	results := make(map[Identifier]*ExecutionResult, len(p.Results))
+	for _, result := range p.Results {
+		results[result.ID()] = result
+	}
+
+	var events ServiceEventList
+	for _, seal := range p.Payload.Seals {
+		result, ok := results[seal.ResultID]
+		if !ok {
+			continue
+		}
+		events = append(events, result.ServiceEvents...)
+		delete(results, seal.ResultID)
+	}
+	for resultID := range results {
+		return nil, fmt.Errorf("result %x is not sealed by block %x", resultID, p.Header.ID())
+	}
+
+	return events, nil
+}
@@ -0,0 +1,79 @@
+package flow
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+)
+
+// SlashingEvidenceType is the kind of protocol violation proven by a SlashingEvidence.
+type SlashingEvidenceType uint8
+
+const (
+	// SlashingEvidenceDoubleVote proves that a replica voted for two different blocks in the same view.
+	SlashingEvidenceDoubleVote SlashingEvidenceType = iota + 1
+	// SlashingEvidenceDoubleProposal proves that a leader proposed two different blocks in the same view.
+	SlashingEvidenceDoubleProposal
+	// SlashingEvidenceVoteForInvalidBlock proves that a replica voted for a block proposal which is invalid.
+	SlashingEvidenceVoteForInvalidBlock
+)
+
+func (t SlashingEvidenceType) String() string {
+	switch t {
+	case SlashingEvidenceDoubleVote:
+		return "double_vote"
+	case SlashingEvidenceDoubleProposal:
+		return "double_proposal"
+	case SlashingEvidenceVoteForInvalidBlock:
+		return "vote_for_invalid_block"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(t))
+	}
+}
+
+// SignedVote is a HotStuff vote for a block, together with the signature of the voter.
+// A block proposal is represented by the proposer's vote for the proposed block,
+// which is the signature included in the block header.
+type SignedVote struct {
+	View     uint64
+	BlockID  Identifier
+	SignerID Identifier
+	SigData  []byte
+}
+
+// SlashingEvidence holds the signed messages proving that a consensus participant violated the protocol:
+//   - SlashingEvidenceDoubleVote: First and Second are votes of the offender for different blocks in the same view.
+//   - SlashingEvidenceDoubleProposal: First and Second are the proposer votes of two different blocks
+//     proposed by the offender in the same view.
+//   - SlashingEvidenceVoteForInvalidBlock: First is the vote of the offender, Second is the proposer vote of
+//     the voted block, and InvalidProposal is the header of the invalid proposal.
+type SlashingEvidence struct {
+	Type            SlashingEvidenceType
+	OffenderID      Identifier
+	View            uint64
+	First           SignedVote
+	Second          SignedVote
+	InvalidProposal *Header // only set for SlashingEvidenceVoteForInvalidBlock
+	DetectedAt      time.Time
+}
+
+// ID returns the identifier of the evidence. It only depends on the violation, not on the
+// order in which the conflicting messages were observed or when they were observed, so the
+// same violation reported several times results in the same ID.
+func (e *SlashingEvidence) ID() Identifier {
+	first, second := e.First.BlockID, e.Second.BlockID
+	if bytes.Compare(first[:], second[:]) > 0 {
+		first, second = second, first
+	}
+	return MakeID(struct {
+		Type       SlashingEvidenceType
+		OffenderID Identifier
+		View       uint64
+		BlockIDs   []Identifier
+	}{
+		Type:       e.Type,
+		OffenderID: e.OffenderID,
+		View:       e.View,
+		BlockIDs:   []Identifier{first, second},
+	})
+}
@@ -13,6 +13,12 @@ type TransactionResult struct {
 	ErrorMessage string
 	// Computation used
 	ComputationUsed uint64
+	// Bytes of storage written
+	StorageBytesWritten uint64
+	// Number of events emitted
+	EventCount uint64
+	// Byte size of the events emitted
+	EventBytes uint64
 }
 
 // String returns the string representation of this error.
@@ -0,0 +1,26 @@
+package libp2p
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// PeerRecord holds what a node knows about a peer of the unstaked network, which it keeps across restarts to
+// discover the network again without relying on its bootstrap peers only.
+type PeerRecord struct {
+	// NodeID is the Flow identifier the peer ID translates to.
+	NodeID flow.Identifier
+	// PeerID is the libp2p peer ID of the peer, which is derived from its networking key.
+	PeerID string
+	// Addrs are the multiaddresses the peer is reachable at.
+	Addrs []string
+	// Score is the quality of the peer, between 0 and 1, which rises with successful interactions with the peer
+	// and drops with failed ones.
+	Score float64
+	// Successes and Failures are the numbers of successful and failed interactions with the peer.
+	Successes uint64
+	Failures  uint64
+	// LastSuccess is the time of the last successful interaction with the peer, zero if there was none.
+	LastSuccess time.Time
+}
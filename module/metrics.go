@@ -60,6 +60,43 @@ type NetworkMetrics interface {
 	// MessageProcessingFinished tracks the time a queue worker blocked by an engine for processing an incoming message on specified topic (i.e., channel).
 	MessageProcessingFinished(topic string, duration time.Duration)
 
+	// UnicastBytesSent tracks the number of bytes of a message sent to the given peer of the given role through a unicast stream on the given channel
+	UnicastBytesSent(sizeBytes int, channel string, peerID string, role string)
+
+	// UnicastBytesReceived tracks the number of bytes of a message received from the given peer of the given role through a unicast stream on the given channel
+	UnicastBytesReceived(sizeBytes int, channel string, peerID string, role string)
+
+	// PubSubBytesSent tracks the number of bytes of a message published on the given channel
+	PubSubBytesSent(sizeBytes int, channel string)
+
+	// PubSubBytesReceived tracks the number of bytes of a message received on the given channel from the given peer of the
+	// given role, which relayed the message to this node
+	PubSubBytesReceived(sizeBytes int, channel string, peerID string, role string)
+
+	// OutboundMessageDelayed tracks the time an outbound message on the given channel waited for the egress rate limit of the channel
+	OutboundMessageDelayed(channel string, delay time.Duration)
+
+	// OutboundMessageDropped counts the number of outbound messages on the given channel dropped due to the egress rate limit of the channel
+	OutboundMessageDropped(channel string)
+
+	// Message send queue metrics
+	// OutboundMessageAdded increments the metric tracking the number of messages in the outbound queues with the given priority
+	OutboundMessageAdded(priority int)
+
+	// OutboundMessageRemoved decrements the metric tracking the number of messages in the outbound queues with the given priority
+	OutboundMessageRemoved(priority int)
+
+	// OutboundQueueDuration tracks the time spent by a message with the given priority in an outbound queue
+	OutboundQueueDuration(duration time.Duration, priority int)
+
+	// OutboundQueueMessageDropped counts the number of outbound messages on the given channel with the given priority dropped
+	// as their outbound queue was full
+	OutboundQueueMessageDropped(channel string, priority int)
+
+	// UnknownMessageVersion counts the number of messages of the given type received on the given channel, which were
+	// dropped as they are encoded in a schema version newer than the one known to this node
+	UnknownMessageVersion(channel string, messageType string)
+
 	// OutboundConnections updates the metric tracking the number of outbound connections of this node
 	OutboundConnections(connectionCount uint)
 
@@ -134,6 +171,9 @@ type HotstuffMetrics interface {
 	// SetTimeout sets the current timeout duration
 	SetTimeout(duration time.Duration)
 
+	// SetTimeoutConfig reports the parameters of the timeout controller, which can be changed at runtime.
+	SetTimeoutConfig(minTimeout, maxTimeout, blockRateDelay time.Duration, voteAggregationFraction, increaseFactor, decreaseFactor float64)
+
 	// CommitteeProcessingDuration measures the time which the HotStuff's core logic
 	// spends in the hotstuff.Committee component, i.e. the time determining consensus
 	// committee relations.
@@ -380,6 +420,10 @@ type ExecutionMetrics interface {
 	// ExecutionTransactionExecuted reports the total time and computation spent on executing a single transaction
 	ExecutionTransactionExecuted(dur time.Duration, compUsed uint64, eventCounts int, failed bool)
 
+	// ExecutionTransactionMeteredUsage reports the usage of a single metered resource kind
+	// (e.g. storage bytes written, event count) by a single transaction
+	ExecutionTransactionMeteredUsage(kind string, used uint64)
+
 	// ExecutionScriptExecuted reports the time spent on executing an script
 	ExecutionScriptExecuted(dur time.Duration, compUsed uint64)
 
@@ -395,6 +439,10 @@ type ExecutionMetrics interface {
 	ExecutionBlockDataUploadStarted()
 
 	ExecutionBlockDataUploadFinished(dur time.Duration)
+
+	// ExecutionChunkDataPacksPruned reports the duration of a run of the chunk data pack pruner,
+	// the height up to which chunk data packs have been pruned and the number of chunk data packs removed
+	ExecutionChunkDataPacksPruned(dur time.Duration, prunedHeight uint64, chunkDataPacks int)
 }
 
 type TransactionMetrics interface {
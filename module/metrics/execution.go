@@ -52,6 +52,7 @@ type ExecutionCollector struct {
 	transactionExecutionTime         prometheus.Histogram
 	transactionComputationUsed       prometheus.Histogram
 	transactionEmittedEvents         prometheus.Histogram
+	transactionMeteredUsage          *prometheus.HistogramVec
 	scriptExecutionTime              prometheus.Histogram
 	scriptComputationUsed            prometheus.Histogram
 	numberOfAccounts                 prometheus.Gauge
@@ -60,6 +61,9 @@ type ExecutionCollector struct {
 	executionStateDiskUsage          prometheus.Gauge
 	blockDataUploadsInProgress       prometheus.Gauge
 	blockDataUploadsDuration         prometheus.Histogram
+	chunkDataPacksPrunedHeight       prometheus.Gauge
+	chunkDataPacksPruned             prometheus.Counter
+	chunkDataPacksPruneDuration      prometheus.Histogram
 }
 
 func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
@@ -291,6 +295,14 @@ func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
 		Help:      "the total number of events emitted by a transaction",
 	})
 
+	transactionMeteredUsage := promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemRuntime,
+		Name:      "transaction_metered_usage",
+		Help:      "the amount used of each metered resource kind by a transaction",
+		Buckets:   []float64{1, 10, 100, 1000, 10000, 100000, 1000000},
+	}, []string{LabelMeteredKind})
+
 	scriptExecutionTime := promauto.NewHistogram(prometheus.HistogramOpts{
 		Namespace: namespaceExecution,
 		Subsystem: subsystemRuntime,
@@ -329,6 +341,28 @@ func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
 		Buckets:   []float64{1, 100, 500, 1000, 2000},
 	})
 
+	chunkDataPacksPrunedHeight := promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemChunkDataPackPruner,
+		Name:      "pruned_height",
+		Help:      "the height up to which chunk data packs have been pruned",
+	})
+
+	chunkDataPacksPruned := promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemChunkDataPackPruner,
+		Name:      "chunk_data_packs_pruned_total",
+		Help:      "the number of chunk data packs removed by the pruner",
+	})
+
+	chunkDataPacksPruneDuration := promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespaceExecution,
+		Subsystem: subsystemChunkDataPackPruner,
+		Name:      "prune_duration_ms",
+		Help:      "the duration of a single run of the chunk data pack pruner",
+		Buckets:   []float64{10, 100, 1000, 10000, 60000},
+	})
+
 	ec := &ExecutionCollector{
 		tracer: tracer,
 
@@ -363,11 +397,15 @@ func NewExecutionCollector(tracer module.Tracer) *ExecutionCollector {
 		transactionExecutionTime:    transactionExecutionTime,
 		transactionComputationUsed:  transactionComputationUsed,
 		transactionEmittedEvents:    transactionEmittedEvents,
+		transactionMeteredUsage:     transactionMeteredUsage,
 		scriptExecutionTime:         scriptExecutionTime,
 		scriptComputationUsed:       scriptComputationUsed,
 		totalChunkDataPackRequests:  totalChunkDataPackRequests,
 		blockDataUploadsInProgress:  blockDataUploadsInProgress,
 		blockDataUploadsDuration:    blockDataUploadsDuration,
+		chunkDataPacksPrunedHeight:  chunkDataPacksPrunedHeight,
+		chunkDataPacksPruned:        chunkDataPacksPruned,
+		chunkDataPacksPruneDuration: chunkDataPacksPruneDuration,
 
 		stateReadsPerBlock: promauto.NewHistogram(prometheus.HistogramOpts{
 			Namespace: namespaceExecution,
@@ -496,6 +534,11 @@ func (ec *ExecutionCollector) ExecutionTransactionExecuted(dur time.Duration, co
 	}
 }
 
+// ExecutionTransactionMeteredUsage reports the usage of a single metered resource kind by a transaction
+func (ec *ExecutionCollector) ExecutionTransactionMeteredUsage(kind string, used uint64) {
+	ec.transactionMeteredUsage.WithLabelValues(kind).Observe(float64(used))
+}
+
 // ScriptExecuted reports the time spent executing a single script
 func (ec *ExecutionCollector) ExecutionScriptExecuted(dur time.Duration, compUsed uint64) {
 	ec.totalExecutedScriptsCounter.Inc()
@@ -620,6 +663,13 @@ func (ec *ExecutionCollector) ExecutionBlockDataUploadFinished(dur time.Duration
 	ec.blockDataUploadsDuration.Observe(float64(dur.Milliseconds()))
 }
 
+// ExecutionChunkDataPacksPruned reports a run of the chunk data pack pruner
+func (ec *ExecutionCollector) ExecutionChunkDataPacksPruned(dur time.Duration, prunedHeight uint64, chunkDataPacks int) {
+	ec.chunkDataPacksPrunedHeight.Set(float64(prunedHeight))
+	ec.chunkDataPacksPruned.Add(float64(chunkDataPacks))
+	ec.chunkDataPacksPruneDuration.Observe(float64(dur.Milliseconds()))
+}
+
 // TransactionParsed reports the time spent parsing a single transaction
 func (ec *ExecutionCollector) RuntimeTransactionParsed(dur time.Duration) {
 	ec.transactionParseTime.Observe(float64(dur))
@@ -33,6 +33,7 @@ type HotstuffCollector struct {
 	skips                         prometheus.Counter
 	timeouts                      prometheus.Counter
 	timeoutDuration               prometheus.Gauge
+	timeoutConfig                 *prometheus.GaugeVec
 	committeeComputationsDuration prometheus.Histogram
 	signerComputationsDuration    prometheus.Histogram
 	validatorComputationsDuration prometheus.Histogram
@@ -110,6 +111,14 @@ func NewHotstuffCollector(chain flow.ChainID) *HotstuffCollector {
 			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
 		}),
 
+		timeoutConfig: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name:        "timeout_config",
+			Namespace:   namespaceConsensus,
+			Subsystem:   subsystemHotstuff,
+			Help:        "the parameters of the timeout controller; durations in seconds, factors unitless",
+			ConstLabels: prometheus.Labels{LabelChain: chain.String()},
+		}, []string{"parameter"}),
+
 		committeeComputationsDuration: promauto.NewHistogram(prometheus.HistogramOpts{
 			Name:        "committee_computations_seconds",
 			Namespace:   namespaceConsensus,
@@ -190,6 +199,16 @@ func (hc *HotstuffCollector) SetTimeout(duration time.Duration) {
 	hc.timeoutDuration.Set(duration.Seconds()) // unit: seconds; with float64 precision
 }
 
+// SetTimeoutConfig reports the parameters of the timeout controller.
+func (hc *HotstuffCollector) SetTimeoutConfig(minTimeout, maxTimeout, blockRateDelay time.Duration, voteAggregationFraction, increaseFactor, decreaseFactor float64) {
+	hc.timeoutConfig.WithLabelValues("min_timeout_seconds").Set(minTimeout.Seconds())
+	hc.timeoutConfig.WithLabelValues("max_timeout_seconds").Set(maxTimeout.Seconds())
+	hc.timeoutConfig.WithLabelValues("block_rate_delay_seconds").Set(blockRateDelay.Seconds())
+	hc.timeoutConfig.WithLabelValues("vote_aggregation_fraction").Set(voteAggregationFraction)
+	hc.timeoutConfig.WithLabelValues("increase_factor").Set(increaseFactor)
+	hc.timeoutConfig.WithLabelValues("decrease_factor").Set(decreaseFactor)
+}
+
 // CommitteeProcessingDuration measures the time which the HotStuff's core logic
 // spends in the hotstuff.Committee component, i.e. the time determining consensus
 // committee relations.
@@ -13,6 +13,20 @@ const (
 	LabelNodeInfo    = "nodeinfo"
 	LabelNodeVersion = "nodeversion"
 	LabelPriority    = "priority"
+	LabelMeteredKind = "metered_kind"
+	LabelPeer        = "peer"
+	LabelDirection   = "direction"
+	LabelProtocol    = "protocol"
+)
+
+const (
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+)
+
+const (
+	ProtocolUnicast = "unicast"
+	ProtocolPubSub  = "pubsub"
 )
 
 const (
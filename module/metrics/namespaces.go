@@ -48,12 +48,13 @@ const (
 
 // Execution Subsystems
 const (
-	subsystemStateStorage      = "state_storage"
-	subsystemMTrie             = "mtrie"
-	subsystemIngestion         = "ingestion"
-	subsystemRuntime           = "runtime"
-	subsystemProvider          = "provider"
-	subsystemBlockDataUploader = "block_data_uploader"
+	subsystemStateStorage        = "state_storage"
+	subsystemMTrie               = "mtrie"
+	subsystemIngestion           = "ingestion"
+	subsystemRuntime             = "runtime"
+	subsystemProvider            = "provider"
+	subsystemBlockDataUploader   = "block_data_uploader"
+	subsystemChunkDataPackPruner = "chunk_data_pack_pruner"
 )
 
 // Verification Subsystems
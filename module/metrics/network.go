@@ -31,6 +31,15 @@ type NetworkCollector struct {
 	dnsCacheHitCount             prometheus.Counter
 	dnsCacheInvalidationCount    prometheus.Counter
 	dnsLookupRequestDroppedCount prometheus.Counter
+	channelBytes                 *prometheus.CounterVec
+	peerBytes                    *prometheus.CounterVec
+	roleBytes                    *prometheus.CounterVec
+	egressDelay                  *prometheus.HistogramVec
+	egressDropped                *prometheus.CounterVec
+	unknownMessageVersion        *prometheus.CounterVec
+	outboundQueueSize            *prometheus.GaugeVec
+	outboundQueueDuration        *prometheus.HistogramVec
+	outboundQueueDropped         *prometheus.CounterVec
 
 	prefix string
 }
@@ -127,6 +136,61 @@ func NewNetworkCollector(opts ...NetworkCollectorOpt) *NetworkCollector {
 		},
 	)
 
+	nc.channelBytes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      nc.prefix + "channel_bytes_total",
+			Help:      "the number of bytes of messages sent and received on a channel, per protocol (unicast or pubsub)",
+		}, []string{LabelDirection, LabelProtocol, LabelChannel},
+	)
+
+	nc.peerBytes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      nc.prefix + "peer_bytes_total",
+			Help:      "the number of bytes of messages sent to and received from a peer, per protocol (unicast or pubsub)",
+		}, []string{LabelDirection, LabelProtocol, LabelPeer},
+	)
+
+	nc.roleBytes = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      nc.prefix + "role_bytes_total",
+			Help:      "the number of bytes of messages sent to and received from peers of a role, per protocol (unicast or pubsub)",
+		}, []string{LabelDirection, LabelProtocol, LabelNodeRole},
+	)
+
+	nc.egressDelay = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      nc.prefix + "egress_rate_limit_delay_seconds",
+			Help:      "the time outbound messages waited for the egress rate limit of their channel",
+			Buckets:   []float64{0.01, 0.1, 0.5, 1, 2, 5}, // 10ms, 100ms, 500ms, 1s, 2s, 5s
+		}, []string{LabelChannel},
+	)
+
+	nc.egressDropped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      nc.prefix + "egress_rate_limit_dropped_total",
+			Help:      "the number of outbound messages dropped as they exceeded the egress rate limit of their channel",
+		}, []string{LabelChannel},
+	)
+
+	nc.unknownMessageVersion = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemGossip,
+			Name:      nc.prefix + "unknown_message_version_total",
+			Help:      "the number of inbound messages dropped as they are encoded in an unknown schema version of their type",
+		}, []string{LabelChannel, LabelMessage},
+	)
+
 	nc.queueSize = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: namespaceNetwork,
@@ -146,6 +210,34 @@ func NewNetworkCollector(opts ...NetworkCollectorOpt) *NetworkCollector {
 		}, []string{LabelPriority},
 	)
 
+	nc.outboundQueueSize = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemQueue,
+			Name:      nc.prefix + "outbound_message_queue_size",
+			Help:      "the number of elements in the outbound message queues",
+		}, []string{LabelPriority},
+	)
+
+	nc.outboundQueueDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemQueue,
+			Name:      nc.prefix + "outbound_message_queue_duration_seconds",
+			Help:      "duration [seconds; measured with float64 precision] of how long a message spent in an outbound queue before it was sent.",
+			Buckets:   []float64{0.01, 0.1, 0.5, 1, 2, 5}, // 10ms, 100ms, 500ms, 1s, 2s, 5s
+		}, []string{LabelPriority},
+	)
+
+	nc.outboundQueueDropped = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: namespaceNetwork,
+			Subsystem: subsystemQueue,
+			Name:      nc.prefix + "outbound_message_queue_dropped_total",
+			Help:      "the number of outbound messages dropped as their outbound queue was full",
+		}, []string{LabelChannel, LabelPriority},
+	)
+
 	nc.numMessagesProcessing = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Namespace: namespaceNetwork,
@@ -241,6 +333,71 @@ func (nc *NetworkCollector) MessageProcessingFinished(topic string, duration tim
 	nc.inboundProcessTime.WithLabelValues(topic).Add(duration.Seconds())
 }
 
+// UnicastBytesSent tracks the number of bytes of a message sent to the given peer of the given role through a unicast stream on the given channel
+func (nc *NetworkCollector) UnicastBytesSent(sizeBytes int, channel string, peerID string, role string) {
+	nc.channelBytes.WithLabelValues(DirectionOutbound, ProtocolUnicast, channel).Add(float64(sizeBytes))
+	nc.peerBytes.WithLabelValues(DirectionOutbound, ProtocolUnicast, peerID).Add(float64(sizeBytes))
+	nc.roleBytes.WithLabelValues(DirectionOutbound, ProtocolUnicast, role).Add(float64(sizeBytes))
+}
+
+// UnicastBytesReceived tracks the number of bytes of a message received from the given peer of the given role through a unicast stream on the given channel
+func (nc *NetworkCollector) UnicastBytesReceived(sizeBytes int, channel string, peerID string, role string) {
+	nc.channelBytes.WithLabelValues(DirectionInbound, ProtocolUnicast, channel).Add(float64(sizeBytes))
+	nc.peerBytes.WithLabelValues(DirectionInbound, ProtocolUnicast, peerID).Add(float64(sizeBytes))
+	nc.roleBytes.WithLabelValues(DirectionInbound, ProtocolUnicast, role).Add(float64(sizeBytes))
+}
+
+// PubSubBytesSent tracks the number of bytes of a message published on the given channel. As published messages are
+// sent to many peers, they are not attributed to any peer.
+func (nc *NetworkCollector) PubSubBytesSent(sizeBytes int, channel string) {
+	nc.channelBytes.WithLabelValues(DirectionOutbound, ProtocolPubSub, channel).Add(float64(sizeBytes))
+}
+
+// PubSubBytesReceived tracks the number of bytes of a message received on the given channel from the given peer of the
+// given role, which relayed the message to this node
+func (nc *NetworkCollector) PubSubBytesReceived(sizeBytes int, channel string, peerID string, role string) {
+	nc.channelBytes.WithLabelValues(DirectionInbound, ProtocolPubSub, channel).Add(float64(sizeBytes))
+	nc.peerBytes.WithLabelValues(DirectionInbound, ProtocolPubSub, peerID).Add(float64(sizeBytes))
+	nc.roleBytes.WithLabelValues(DirectionInbound, ProtocolPubSub, role).Add(float64(sizeBytes))
+}
+
+// OutboundMessageDelayed tracks the time an outbound message on the given channel waited for the egress rate limit of the channel
+func (nc *NetworkCollector) OutboundMessageDelayed(channel string, delay time.Duration) {
+	nc.egressDelay.WithLabelValues(channel).Observe(delay.Seconds())
+}
+
+// OutboundMessageDropped counts the number of outbound messages on the given channel dropped due to the egress rate limit of the channel
+func (nc *NetworkCollector) OutboundMessageDropped(channel string) {
+	nc.egressDropped.WithLabelValues(channel).Inc()
+}
+
+// OutboundMessageAdded increments the metric tracking the number of messages in the outbound queues with the given priority
+func (nc *NetworkCollector) OutboundMessageAdded(priority int) {
+	nc.outboundQueueSize.WithLabelValues(strconv.Itoa(priority)).Inc()
+}
+
+// OutboundMessageRemoved decrements the metric tracking the number of messages in the outbound queues with the given priority
+func (nc *NetworkCollector) OutboundMessageRemoved(priority int) {
+	nc.outboundQueueSize.WithLabelValues(strconv.Itoa(priority)).Dec()
+}
+
+// OutboundQueueDuration tracks the time spent by a message with the given priority in an outbound queue
+func (nc *NetworkCollector) OutboundQueueDuration(duration time.Duration, priority int) {
+	nc.outboundQueueDuration.WithLabelValues(strconv.Itoa(priority)).Observe(duration.Seconds())
+}
+
+// OutboundQueueMessageDropped counts the number of outbound messages on the given channel with the given priority dropped
+// as their outbound queue was full
+func (nc *NetworkCollector) OutboundQueueMessageDropped(channel string, priority int) {
+	nc.outboundQueueDropped.WithLabelValues(channel, strconv.Itoa(priority)).Inc()
+}
+
+// UnknownMessageVersion counts the number of messages of the given type received on the given channel, which were
+// dropped as they are encoded in a schema version newer than the one known to this node
+func (nc *NetworkCollector) UnknownMessageVersion(channel string, messageType string) {
+	nc.unknownMessageVersion.WithLabelValues(channel, messageType).Inc()
+}
+
 // OutboundConnections updates the metric tracking the number of outbound connections of this node
 func (nc *NetworkCollector) OutboundConnections(connectionCount uint) {
 	nc.outboundConnectionCount.Set(float64(connectionCount))
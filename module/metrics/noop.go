@@ -25,75 +25,90 @@ func (nc *NoopCollector) MessageProcessingStarted(topic string)
 func (nc *NoopCollector) MessageProcessingFinished(topic string, duration time.Duration)         {}
 func (nc *NoopCollector) DirectMessageStarted(topic string)                                      {}
 func (nc *NoopCollector) DirectMessageFinished(topic string)                                     {}
-func (nc *NoopCollector) MessageSent(engine string, message string)                              {}
-func (nc *NoopCollector) MessageReceived(engine string, message string)                          {}
-func (nc *NoopCollector) MessageHandled(engine string, message string)                           {}
-func (nc *NoopCollector) OutboundConnections(_ uint)                                             {}
-func (nc *NoopCollector) InboundConnections(_ uint)                                              {}
-func (nc *NoopCollector) DNSLookupDuration(duration time.Duration)                               {}
-func (nc *NoopCollector) OnDNSCacheMiss()                                                        {}
-func (nc *NoopCollector) OnDNSCacheInvalidated()                                                 {}
-func (nc *NoopCollector) OnDNSCacheHit()                                                         {}
-func (nc *NoopCollector) OnDNSLookupRequestDropped()                                             {}
-func (nc *NoopCollector) UnstakedOutboundConnections(_ uint)                                     {}
-func (nc *NoopCollector) UnstakedInboundConnections(_ uint)                                      {}
-func (nc *NoopCollector) RanGC(duration time.Duration)                                           {}
-func (nc *NoopCollector) BadgerLSMSize(sizeBytes int64)                                          {}
-func (nc *NoopCollector) BadgerVLogSize(sizeBytes int64)                                         {}
-func (nc *NoopCollector) BadgerNumReads(n int64)                                                 {}
-func (nc *NoopCollector) BadgerNumWrites(n int64)                                                {}
-func (nc *NoopCollector) BadgerNumBytesRead(n int64)                                             {}
-func (nc *NoopCollector) BadgerNumBytesWritten(n int64)                                          {}
-func (nc *NoopCollector) BadgerNumGets(n int64)                                                  {}
-func (nc *NoopCollector) BadgerNumPuts(n int64)                                                  {}
-func (nc *NoopCollector) BadgerNumBlockedPuts(n int64)                                           {}
-func (nc *NoopCollector) BadgerNumMemtableGets(n int64)                                          {}
-func (nc *NoopCollector) FinalizedHeight(height uint64)                                          {}
-func (nc *NoopCollector) SealedHeight(height uint64)                                             {}
-func (nc *NoopCollector) BlockProposed(*flow.Block)                                              {}
-func (nc *NoopCollector) BlockFinalized(*flow.Block)                                             {}
-func (nc *NoopCollector) BlockSealed(*flow.Block)                                                {}
-func (nc *NoopCollector) BlockProposalDuration(duration time.Duration)                           {}
-func (nc *NoopCollector) CommittedEpochFinalView(view uint64)                                    {}
-func (nc *NoopCollector) CurrentEpochCounter(counter uint64)                                     {}
-func (nc *NoopCollector) CurrentEpochPhase(phase flow.EpochPhase)                                {}
-func (nc *NoopCollector) CurrentEpochFinalView(view uint64)                                      {}
-func (nc *NoopCollector) CurrentDKGPhase1FinalView(view uint64)                                  {}
-func (nc *NoopCollector) CurrentDKGPhase2FinalView(view uint64)                                  {}
-func (nc *NoopCollector) CurrentDKGPhase3FinalView(view uint64)                                  {}
-func (nc *NoopCollector) EpochEmergencyFallbackTriggered()                                       {}
-func (nc *NoopCollector) CacheEntries(resource string, entries uint)                             {}
-func (nc *NoopCollector) CacheHit(resource string)                                               {}
-func (nc *NoopCollector) CacheNotFound(resource string)                                          {}
-func (nc *NoopCollector) CacheMiss(resource string)                                              {}
-func (nc *NoopCollector) MempoolEntries(resource string, entries uint)                           {}
-func (nc *NoopCollector) Register(resource string, entriesFunc module.EntriesFunc) error         { return nil }
-func (nc *NoopCollector) HotStuffBusyDuration(duration time.Duration, event string)              {}
-func (nc *NoopCollector) HotStuffIdleDuration(duration time.Duration)                            {}
-func (nc *NoopCollector) HotStuffWaitDuration(duration time.Duration, event string)              {}
-func (nc *NoopCollector) SetCurView(view uint64)                                                 {}
-func (nc *NoopCollector) SetQCView(view uint64)                                                  {}
-func (nc *NoopCollector) CountSkipped()                                                          {}
-func (nc *NoopCollector) CountTimeout()                                                          {}
-func (nc *NoopCollector) SetTimeout(duration time.Duration)                                      {}
-func (nc *NoopCollector) CommitteeProcessingDuration(duration time.Duration)                     {}
-func (nc *NoopCollector) SignerProcessingDuration(duration time.Duration)                        {}
-func (nc *NoopCollector) ValidatorProcessingDuration(duration time.Duration)                     {}
-func (nc *NoopCollector) PayloadProductionDuration(duration time.Duration)                       {}
-func (nc *NoopCollector) TransactionIngested(txID flow.Identifier)                               {}
-func (nc *NoopCollector) ClusterBlockProposed(*cluster.Block)                                    {}
-func (nc *NoopCollector) ClusterBlockFinalized(*cluster.Block)                                   {}
-func (nc *NoopCollector) StartCollectionToFinalized(collectionID flow.Identifier)                {}
-func (nc *NoopCollector) FinishCollectionToFinalized(collectionID flow.Identifier)               {}
-func (nc *NoopCollector) StartBlockToSeal(blockID flow.Identifier)                               {}
-func (nc *NoopCollector) FinishBlockToSeal(blockID flow.Identifier)                              {}
-func (nc *NoopCollector) EmergencySeal()                                                         {}
-func (nc *NoopCollector) OnReceiptProcessingDuration(duration time.Duration)                     {}
-func (nc *NoopCollector) OnApprovalProcessingDuration(duration time.Duration)                    {}
-func (nc *NoopCollector) CheckSealingDuration(duration time.Duration)                            {}
-func (nc *NoopCollector) OnExecutionResultReceivedAtAssignerEngine()                             {}
-func (nc *NoopCollector) OnVerifiableChunkReceivedAtVerifierEngine()                             {}
-func (nc *NoopCollector) OnResultApprovalDispatchedInNetworkByVerifier()                         {}
+func (nc *NoopCollector) UnicastBytesSent(sizeBytes int, channel string, peerID string, role string) {
+}
+func (nc *NoopCollector) UnicastBytesReceived(sizeBytes int, channel string, peerID string, role string) {
+}
+func (nc *NoopCollector) PubSubBytesSent(sizeBytes int, channel string) {}
+func (nc *NoopCollector) PubSubBytesReceived(sizeBytes int, channel string, peerID string, role string) {
+}
+func (nc *NoopCollector) OutboundMessageDelayed(channel string, delay time.Duration)     {}
+func (nc *NoopCollector) OutboundMessageDropped(channel string)                          {}
+func (nc *NoopCollector) OutboundMessageAdded(priority int)                              {}
+func (nc *NoopCollector) OutboundMessageRemoved(priority int)                            {}
+func (nc *NoopCollector) OutboundQueueDuration(duration time.Duration, priority int)     {}
+func (nc *NoopCollector) OutboundQueueMessageDropped(channel string, priority int)       {}
+func (nc *NoopCollector) UnknownMessageVersion(channel string, messageType string)       {}
+func (nc *NoopCollector) MessageSent(engine string, message string)                      {}
+func (nc *NoopCollector) MessageReceived(engine string, message string)                  {}
+func (nc *NoopCollector) MessageHandled(engine string, message string)                   {}
+func (nc *NoopCollector) OutboundConnections(_ uint)                                     {}
+func (nc *NoopCollector) InboundConnections(_ uint)                                      {}
+func (nc *NoopCollector) DNSLookupDuration(duration time.Duration)                       {}
+func (nc *NoopCollector) OnDNSCacheMiss()                                                {}
+func (nc *NoopCollector) OnDNSCacheInvalidated()                                         {}
+func (nc *NoopCollector) OnDNSCacheHit()                                                 {}
+func (nc *NoopCollector) OnDNSLookupRequestDropped()                                     {}
+func (nc *NoopCollector) UnstakedOutboundConnections(_ uint)                             {}
+func (nc *NoopCollector) UnstakedInboundConnections(_ uint)                              {}
+func (nc *NoopCollector) RanGC(duration time.Duration)                                   {}
+func (nc *NoopCollector) BadgerLSMSize(sizeBytes int64)                                  {}
+func (nc *NoopCollector) BadgerVLogSize(sizeBytes int64)                                 {}
+func (nc *NoopCollector) BadgerNumReads(n int64)                                         {}
+func (nc *NoopCollector) BadgerNumWrites(n int64)                                        {}
+func (nc *NoopCollector) BadgerNumBytesRead(n int64)                                     {}
+func (nc *NoopCollector) BadgerNumBytesWritten(n int64)                                  {}
+func (nc *NoopCollector) BadgerNumGets(n int64)                                          {}
+func (nc *NoopCollector) BadgerNumPuts(n int64)                                          {}
+func (nc *NoopCollector) BadgerNumBlockedPuts(n int64)                                   {}
+func (nc *NoopCollector) BadgerNumMemtableGets(n int64)                                  {}
+func (nc *NoopCollector) FinalizedHeight(height uint64)                                  {}
+func (nc *NoopCollector) SealedHeight(height uint64)                                     {}
+func (nc *NoopCollector) BlockProposed(*flow.Block)                                      {}
+func (nc *NoopCollector) BlockFinalized(*flow.Block)                                     {}
+func (nc *NoopCollector) BlockSealed(*flow.Block)                                        {}
+func (nc *NoopCollector) BlockProposalDuration(duration time.Duration)                   {}
+func (nc *NoopCollector) CommittedEpochFinalView(view uint64)                            {}
+func (nc *NoopCollector) CurrentEpochCounter(counter uint64)                             {}
+func (nc *NoopCollector) CurrentEpochPhase(phase flow.EpochPhase)                        {}
+func (nc *NoopCollector) CurrentEpochFinalView(view uint64)                              {}
+func (nc *NoopCollector) CurrentDKGPhase1FinalView(view uint64)                          {}
+func (nc *NoopCollector) CurrentDKGPhase2FinalView(view uint64)                          {}
+func (nc *NoopCollector) CurrentDKGPhase3FinalView(view uint64)                          {}
+func (nc *NoopCollector) EpochEmergencyFallbackTriggered()                               {}
+func (nc *NoopCollector) CacheEntries(resource string, entries uint)                     {}
+func (nc *NoopCollector) CacheHit(resource string)                                       {}
+func (nc *NoopCollector) CacheNotFound(resource string)                                  {}
+func (nc *NoopCollector) CacheMiss(resource string)                                      {}
+func (nc *NoopCollector) MempoolEntries(resource string, entries uint)                   {}
+func (nc *NoopCollector) Register(resource string, entriesFunc module.EntriesFunc) error { return nil }
+func (nc *NoopCollector) HotStuffBusyDuration(duration time.Duration, event string)      {}
+func (nc *NoopCollector) HotStuffIdleDuration(duration time.Duration)                    {}
+func (nc *NoopCollector) HotStuffWaitDuration(duration time.Duration, event string)      {}
+func (nc *NoopCollector) SetCurView(view uint64)                                         {}
+func (nc *NoopCollector) SetQCView(view uint64)                                          {}
+func (nc *NoopCollector) CountSkipped()                                                  {}
+func (nc *NoopCollector) CountTimeout()                                                  {}
+func (nc *NoopCollector) SetTimeout(duration time.Duration)                              {}
+func (nc *NoopCollector) SetTimeoutConfig(_, _, _ time.Duration, _, _, _ float64)        {}
+func (nc *NoopCollector) CommitteeProcessingDuration(duration time.Duration)             {}
+func (nc *NoopCollector) SignerProcessingDuration(duration time.Duration)                {}
+func (nc *NoopCollector) ValidatorProcessingDuration(duration time.Duration)             {}
+func (nc *NoopCollector) PayloadProductionDuration(duration time.Duration)               {}
+func (nc *NoopCollector) TransactionIngested(txID flow.Identifier)                       {}
+func (nc *NoopCollector) ClusterBlockProposed(*cluster.Block)                            {}
+func (nc *NoopCollector) ClusterBlockFinalized(*cluster.Block)                           {}
+func (nc *NoopCollector) StartCollectionToFinalized(collectionID flow.Identifier)        {}
+func (nc *NoopCollector) FinishCollectionToFinalized(collectionID flow.Identifier)       {}
+func (nc *NoopCollector) StartBlockToSeal(blockID flow.Identifier)                       {}
+func (nc *NoopCollector) FinishBlockToSeal(blockID flow.Identifier)                      {}
+func (nc *NoopCollector) EmergencySeal()                                                 {}
+func (nc *NoopCollector) OnReceiptProcessingDuration(duration time.Duration)             {}
+func (nc *NoopCollector) OnApprovalProcessingDuration(duration time.Duration)            {}
+func (nc *NoopCollector) CheckSealingDuration(duration time.Duration)                    {}
+func (nc *NoopCollector) OnExecutionResultReceivedAtAssignerEngine()                     {}
+func (nc *NoopCollector) OnVerifiableChunkReceivedAtVerifierEngine()                     {}
+func (nc *NoopCollector) OnResultApprovalDispatchedInNetworkByVerifier()                 {}
 func (nc *NoopCollector) SetMaxChunkDataPackAttemptsForNextUnsealedHeightAtRequester(attempts uint64) {
 }
 func (nc *NoopCollector) OnFinalizedBlockArrivedAtAssigner(height uint64)                       {}
@@ -118,6 +133,7 @@ func (nc *NoopCollector) ExecutionLastExecutedBlockHeight(height uint64)
 func (nc *NoopCollector) ExecutionBlockExecuted(_ time.Duration, _ uint64, _ int, _ int)        {}
 func (nc *NoopCollector) ExecutionCollectionExecuted(_ time.Duration, _ uint64, _ int)          {}
 func (nc *NoopCollector) ExecutionTransactionExecuted(_ time.Duration, _ uint64, _ int, _ bool) {}
+func (nc *NoopCollector) ExecutionTransactionMeteredUsage(_ string, _ uint64)                   {}
 func (nc *NoopCollector) ExecutionScriptExecuted(dur time.Duration, compUsed uint64)            {}
 func (nc *NoopCollector) ForestApproxMemorySize(bytes uint64)                                   {}
 func (nc *NoopCollector) ForestNumberOfTrees(number uint64)                                     {}
@@ -151,6 +167,7 @@ func (nc *NoopCollector) ExecutionSync(syncing bool)
 func (nc *NoopCollector) DiskSize(uint64)                                                       {}
 func (nc *NoopCollector) ExecutionBlockDataUploadStarted()                                      {}
 func (nc *NoopCollector) ExecutionBlockDataUploadFinished(dur time.Duration)                    {}
+func (nc *NoopCollector) ExecutionChunkDataPacksPruned(time.Duration, uint64, int)              {}
 func (nc *NoopCollector) ExecutionDataAddStarted()                                              {}
 func (nc *NoopCollector) ExecutionDataAddFinished(time.Duration, bool, uint64)                  {}
 func (nc *NoopCollector) ExecutionDataGetStarted()                                              {}
@@ -39,6 +39,11 @@ func (_m *ExecutionMetrics) ExecutionBlockExecuted(dur time.Duration, compUsed u
 	_m.Called(dur, compUsed, txCounts, colCounts)
 }
 
+// ExecutionChunkDataPacksPruned provides a mock function with given fields: dur, prunedHeight, chunkDataPacks
+func (_m *ExecutionMetrics) ExecutionChunkDataPacksPruned(dur time.Duration, prunedHeight uint64, chunkDataPacks int) {
+	_m.Called(dur, prunedHeight, chunkDataPacks)
+}
+
 // ExecutionCollectionExecuted provides a mock function with given fields: dur, compUsed, txCounts
 func (_m *ExecutionMetrics) ExecutionCollectionExecuted(dur time.Duration, compUsed uint64, txCounts int) {
 	_m.Called(dur, compUsed, txCounts)
@@ -84,6 +89,11 @@ func (_m *ExecutionMetrics) ExecutionTransactionExecuted(dur time.Duration, comp
 	_m.Called(dur, compUsed, eventCounts, failed)
 }
 
+// ExecutionTransactionMeteredUsage provides a mock function with given fields: kind, used
+func (_m *ExecutionMetrics) ExecutionTransactionMeteredUsage(kind string, used uint64) {
+	_m.Called(kind, used)
+}
+
 // FinishBlockReceivedToExecuted provides a mock function with given fields: blockID
 func (_m *ExecutionMetrics) FinishBlockReceivedToExecuted(blockID flow.Identifier) {
 	_m.Called(blockID)
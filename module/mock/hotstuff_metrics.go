@@ -63,6 +63,11 @@ func (_m *HotstuffMetrics) SetTimeout(duration time.Duration) {
 	_m.Called(duration)
 }
 
+// SetTimeoutConfig provides a mock function with given fields: minTimeout, maxTimeout, blockRateDelay, voteAggregationFraction, increaseFactor, decreaseFactor
+func (_m *HotstuffMetrics) SetTimeoutConfig(minTimeout time.Duration, maxTimeout time.Duration, blockRateDelay time.Duration, voteAggregationFraction float64, increaseFactor float64, decreaseFactor float64) {
+	_m.Called(minTimeout, maxTimeout, blockRateDelay, voteAggregationFraction, increaseFactor, decreaseFactor)
+}
+
 // SignerProcessingDuration provides a mock function with given fields: duration
 func (_m *HotstuffMetrics) SignerProcessingDuration(duration time.Duration) {
 	_m.Called(duration)
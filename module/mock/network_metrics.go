@@ -93,7 +93,62 @@ func (_m *NetworkMetrics) OutboundConnections(connectionCount uint) {
 	_m.Called(connectionCount)
 }
 
+// OutboundMessageDelayed provides a mock function with given fields: channel, delay
+func (_m *NetworkMetrics) OutboundMessageDelayed(channel string, delay time.Duration) {
+	_m.Called(channel, delay)
+}
+
+// OutboundMessageDropped provides a mock function with given fields: channel
+func (_m *NetworkMetrics) OutboundMessageDropped(channel string) {
+	_m.Called(channel)
+}
+
+// OutboundMessageAdded provides a mock function with given fields: priority
+func (_m *NetworkMetrics) OutboundMessageAdded(priority int) {
+	_m.Called(priority)
+}
+
+// OutboundMessageRemoved provides a mock function with given fields: priority
+func (_m *NetworkMetrics) OutboundMessageRemoved(priority int) {
+	_m.Called(priority)
+}
+
+// OutboundQueueDuration provides a mock function with given fields: duration, priority
+func (_m *NetworkMetrics) OutboundQueueDuration(duration time.Duration, priority int) {
+	_m.Called(duration, priority)
+}
+
+// OutboundQueueMessageDropped provides a mock function with given fields: channel, priority
+func (_m *NetworkMetrics) OutboundQueueMessageDropped(channel string, priority int) {
+	_m.Called(channel, priority)
+}
+
+// PubSubBytesReceived provides a mock function with given fields: sizeBytes, channel, peerID, role
+func (_m *NetworkMetrics) PubSubBytesReceived(sizeBytes int, channel string, peerID string, role string) {
+	_m.Called(sizeBytes, channel, peerID, role)
+}
+
+// PubSubBytesSent provides a mock function with given fields: sizeBytes, channel
+func (_m *NetworkMetrics) PubSubBytesSent(sizeBytes int, channel string) {
+	_m.Called(sizeBytes, channel)
+}
+
 // QueueDuration provides a mock function with given fields: duration, priority
 func (_m *NetworkMetrics) QueueDuration(duration time.Duration, priority int) {
 	_m.Called(duration, priority)
 }
+
+// UnicastBytesReceived provides a mock function with given fields: sizeBytes, channel, peerID, role
+func (_m *NetworkMetrics) UnicastBytesReceived(sizeBytes int, channel string, peerID string, role string) {
+	_m.Called(sizeBytes, channel, peerID, role)
+}
+
+// UnicastBytesSent provides a mock function with given fields: sizeBytes, channel, peerID, role
+func (_m *NetworkMetrics) UnicastBytesSent(sizeBytes int, channel string, peerID string, role string) {
+	_m.Called(sizeBytes, channel, peerID, role)
+}
+
+// UnknownMessageVersion provides a mock function with given fields: channel, messageType
+func (_m *NetworkMetrics) UnknownMessageVersion(channel string, messageType string) {
+	_m.Called(channel, messageType)
+}
@@ -0,0 +1,184 @@
+package capture_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/mocknetwork"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// messageFixture returns a network message carrying the given event on the channel.
+func messageFixture(t *testing.T, channel network.Channel, event interface{}) *message.Message {
+	payload, err := cbor.NewCodec().Encode(event)
+	require.NoError(t, err)
+
+	eventID := unittest.IdentifierFixture()
+	originID := unittest.IdentifierFixture()
+	targetID := unittest.IdentifierFixture()
+	return &message.Message{
+		ChannelID: channel.String(),
+		EventID:   eventID[:],
+		OriginID:  originID[:],
+		TargetIDs: [][]byte{targetID[:]},
+		Payload:   payload,
+		Type:      "RangeRequest",
+	}
+}
+
+func readAll(t *testing.T, dir string) []*capture.Record {
+	var records []*capture.Record
+	require.NoError(t, capture.ReadDir(dir, func(record *capture.Record) error {
+		records = append(records, record)
+		return nil
+	}))
+	return records
+}
+
+// TestRecorder tests that messages of the captured channels are recorded in order, and read back unchanged.
+func TestRecorder(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		recorder, err := capture.NewRecorder(zerolog.Nop(), capture.Config{
+			Dir:      dir,
+			Channels: []network.Channel{engine.SyncCommittee},
+		})
+		require.NoError(t, err)
+
+		inbound := messageFixture(t, engine.SyncCommittee, &messages.RangeRequest{Nonce: 1, FromHeight: 10, ToHeight: 20})
+		outbound := messageFixture(t, engine.SyncCommittee, &messages.RangeRequest{Nonce: 2, FromHeight: 20, ToHeight: 30})
+		filtered := messageFixture(t, engine.PushBlocks, &messages.RangeRequest{Nonce: 3})
+
+		recorder.Capture(capture.Inbound, inbound)
+		recorder.Capture(capture.Outbound, filtered)
+		recorder.Capture(capture.Outbound, outbound)
+		require.NoError(t, recorder.Close())
+
+		records := readAll(t, dir)
+		require.Len(t, records, 2)
+
+		assert.Equal(t, capture.Inbound, records[0].Direction)
+		assert.Equal(t, engine.SyncCommittee, records[0].Channel)
+		assert.Equal(t, flow.HashToID(inbound.OriginID), records[0].OriginID)
+		assert.Equal(t, inbound, records[0].Message())
+
+		assert.Equal(t, capture.Outbound, records[1].Direction)
+		assert.Equal(t, outbound, records[1].Message())
+		assert.False(t, records[1].Timestamp.Before(records[0].Timestamp))
+	})
+}
+
+// TestRecorder_Rotation tests that capture files are rotated once full, and only the newest files are kept.
+func TestRecorder_Rotation(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		msg := messageFixture(t, engine.SyncCommittee, &messages.RangeRequest{Nonce: 1})
+		encoded, err := json.Marshal(capture.NewRecord(time.Now(), capture.Inbound, msg))
+		require.NoError(t, err)
+		line := len(encoded) + 1
+
+		// each file holds at most two records
+		recorder, err := capture.NewRecorder(zerolog.Nop(), capture.Config{
+			Dir:         dir,
+			MaxFileSize: int64(2 * line),
+			MaxFiles:    2,
+		})
+		require.NoError(t, err)
+
+		for i := 0; i < 7; i++ {
+			recorder.Capture(capture.Inbound, msg)
+			// capture files are named after their creation time
+			time.Sleep(time.Millisecond)
+		}
+		require.NoError(t, recorder.Close())
+
+		files, err := capture.Files(dir)
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+		for _, file := range files {
+			info, err := os.Stat(file)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, info.Size(), int64(2*line))
+		}
+
+		// the records of the removed files are lost, the newest file holds the last record
+		assert.Len(t, readAll(t, dir), 3)
+	})
+}
+
+// TestRecorder_Closed tests that messages captured after the recorder is closed are ignored.
+func TestRecorder_Closed(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		recorder, err := capture.NewRecorder(zerolog.Nop(), capture.Config{Dir: dir})
+		require.NoError(t, err)
+
+		msg := messageFixture(t, engine.SyncCommittee, &messages.RangeRequest{Nonce: 1})
+		recorder.Capture(capture.Inbound, msg)
+		require.NoError(t, recorder.Close())
+		require.NoError(t, recorder.Close())
+
+		recorder.Capture(capture.Inbound, msg)
+		assert.Len(t, readAll(t, dir), 1)
+	})
+}
+
+// TestReplay tests that only the inbound messages of the replayed channels are delivered to the engines and
+// the overlay, in the order they were captured.
+func TestReplay(t *testing.T) {
+	unittest.RunWithTempDir(t, func(dir string) {
+		recorder, err := capture.NewRecorder(zerolog.Nop(), capture.Config{Dir: dir})
+		require.NoError(t, err)
+
+		first := &messages.RangeRequest{Nonce: 1, FromHeight: 10, ToHeight: 20}
+		second := &messages.RangeRequest{Nonce: 2, FromHeight: 20, ToHeight: 30}
+		firstMsg := messageFixture(t, engine.SyncCommittee, first)
+		secondMsg := messageFixture(t, engine.SyncCommittee, second)
+
+		recorder.Capture(capture.Inbound, firstMsg)
+		recorder.Capture(capture.Outbound, messageFixture(t, engine.SyncCommittee, &messages.RangeRequest{Nonce: 3}))
+		recorder.Capture(capture.Inbound, messageFixture(t, engine.PushBlocks, &messages.RangeRequest{Nonce: 4}))
+		recorder.Capture(capture.Inbound, secondMsg)
+		require.NoError(t, recorder.Close())
+
+		replayer := capture.NewReplayer(zerolog.Nop(), capture.WithReplayedChannels(engine.SyncCommittee))
+
+		t.Run("engines", func(t *testing.T) {
+			engines := &mocknetwork.MessageProcessor{}
+			var nonces []uint64
+			engines.On("Process", engine.SyncCommittee, mock.Anything, mock.Anything).
+				Run(func(args mock.Arguments) {
+					nonces = append(nonces, args.Get(2).(*messages.RangeRequest).Nonce)
+				}).
+				Return(nil)
+
+			replayed, err := replayer.ReplayToEngines(context.Background(), dir, cbor.NewCodec(), engines)
+			require.NoError(t, err)
+			assert.Equal(t, 2, replayed)
+			assert.Equal(t, []uint64{first.Nonce, second.Nonce}, nonces)
+		})
+
+		t.Run("overlay", func(t *testing.T) {
+			overlay := &mocknetwork.Overlay{}
+			overlay.On("Receive", flow.HashToID(firstMsg.OriginID), firstMsg).Return(nil).Once()
+			overlay.On("Receive", flow.HashToID(secondMsg.OriginID), secondMsg).Return(nil).Once()
+
+			replayed, err := replayer.ReplayToOverlay(context.Background(), dir, overlay)
+			require.NoError(t, err)
+			assert.Equal(t, 2, replayed)
+			overlay.AssertExpectations(t)
+		})
+	})
+}
@@ -0,0 +1,65 @@
+package capture
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// maxRecordSize is the maximum size of an encoded record, which is bounded by the maximum size of unicast messages.
+const maxRecordSize = 2 * 1024 * 1024 * 1024 // 2 GB
+
+// Read decodes the records of a capture file from the given reader, and calls fn for each of them in order.
+// Reading stops at the first error returned by fn.
+func Read(r io.Reader, fn func(*Record) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+
+		var record Record
+		err := json.Unmarshal(scanner.Bytes(), &record)
+		if err != nil {
+			return fmt.Errorf("could not decode record on line %d: %w", line, err)
+		}
+
+		err = fn(&record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// ReadDir reads the records of all capture files in the given directory, from oldest to newest, and calls fn for
+// each of them in order. Reading stops at the first error returned by fn.
+func ReadDir(dir string, fn func(*Record) error) error {
+	files, err := Files(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range files {
+		err = readFile(name, fn)
+		if err != nil {
+			return fmt.Errorf("could not read capture file %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func readFile(name string, fn func(*Record) error) error {
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return Read(file, fn)
+}
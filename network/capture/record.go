@@ -0,0 +1,66 @@
+package capture
+
+import (
+	"time"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/message"
+)
+
+// Direction is the direction of a captured message, relative to the capturing node.
+type Direction string
+
+const (
+	Inbound  Direction = "inbound"
+	Outbound Direction = "outbound"
+)
+
+// Record is a captured network message. The payload is kept in its network encoding, so that the message can be
+// replayed exactly as it was received.
+type Record struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Direction Direction         `json:"direction"`
+	Channel   network.Channel   `json:"channel"`
+	OriginID  flow.Identifier   `json:"origin_id"`
+	TargetIDs []flow.Identifier `json:"target_ids"`
+	Type      string            `json:"type"`
+	EventID   []byte            `json:"event_id"`
+	Payload   []byte            `json:"payload"`
+}
+
+// NewRecord creates a record of the given message, captured at the given time.
+func NewRecord(timestamp time.Time, direction Direction, msg *message.Message) *Record {
+	targetIDs := make([]flow.Identifier, 0, len(msg.TargetIDs))
+	for _, targetID := range msg.TargetIDs {
+		targetIDs = append(targetIDs, flow.HashToID(targetID))
+	}
+
+	return &Record{
+		Timestamp: timestamp,
+		Direction: direction,
+		Channel:   network.Channel(msg.ChannelID),
+		OriginID:  flow.HashToID(msg.OriginID),
+		TargetIDs: targetIDs,
+		Type:      msg.Type,
+		EventID:   msg.EventID,
+		Payload:   msg.Payload,
+	}
+}
+
+// Message returns the network message of the record.
+func (r *Record) Message() *message.Message {
+	targetIDs := make([][]byte, 0, len(r.TargetIDs))
+	for _, targetID := range r.TargetIDs {
+		targetIDs = append(targetIDs, targetID[:])
+	}
+
+	return &message.Message{
+		ChannelID: r.Channel.String(),
+		EventID:   r.EventID,
+		OriginID:  r.OriginID[:],
+		TargetIDs: targetIDs,
+		Payload:   r.Payload,
+		Type:      r.Type,
+	}
+}
@@ -0,0 +1,246 @@
+package capture
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/message"
+)
+
+const (
+	// DefaultMaxFileSize is the default size in bytes at which the capture file is rotated.
+	DefaultMaxFileSize = 100 * 1024 * 1024 // 100 MB
+
+	// DefaultMaxFiles is the default number of capture files kept, including the one being written.
+	DefaultMaxFiles = 10
+
+	filePrefix = "capture-"
+	fileSuffix = ".jsonl"
+
+	// fileTimeFormat is the format of the creation time in the names of capture files, which sorts in chronological order.
+	fileTimeFormat = "20060102T150405.000000000"
+
+	// queueSize is the number of records buffered for the writer. Records captured while the buffer is full are dropped.
+	queueSize = 10000
+
+	// dropLogInterval is the number of dropped records after which the next drop is logged.
+	dropLogInterval = 1000
+)
+
+// Config configures the recording of network messages.
+type Config struct {
+	// Dir is the directory the capture files are written to.
+	Dir string
+	// MaxFileSize is the size in bytes at which the capture file is rotated.
+	MaxFileSize int64
+	// MaxFiles is the number of capture files kept, including the one being written. The oldest files are
+	// removed on rotation.
+	MaxFiles int
+	// Channels are the channels whose messages are captured. If empty, the messages of all channels are captured.
+	Channels []network.Channel
+}
+
+// Recorder writes the inbound and outbound messages of a node to rotating capture files in its directory. Each
+// capture file holds one JSON encoded record per line. Records are written asynchronously by a single writer, so
+// capturing never blocks on the file system; records are dropped if the writer falls behind.
+type Recorder struct {
+	mu       sync.Mutex // guards closed, dropped and sending on records
+	closed   bool
+	dropped  uint64
+	records  chan *Record
+	done     chan struct{} // closed once the writer wrote all records
+	log      zerolog.Logger
+	config   Config
+	channels map[network.Channel]struct{}
+	file     *os.File // only accessed by the writer, and by Close once the writer is done
+	size     int64
+	now      func() time.Time
+}
+
+// NewRecorder creates a recorder writing to the directory of the given config, which is created if it does not exist.
+func NewRecorder(log zerolog.Logger, config Config) (*Recorder, error) {
+	if config.Dir == "" {
+		return nil, fmt.Errorf("capture directory must be provided")
+	}
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = DefaultMaxFileSize
+	}
+	if config.MaxFiles <= 0 {
+		config.MaxFiles = DefaultMaxFiles
+	}
+
+	err := os.MkdirAll(config.Dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("could not create capture directory: %w", err)
+	}
+
+	channels := make(map[network.Channel]struct{}, len(config.Channels))
+	for _, channel := range config.Channels {
+		channels[channel] = struct{}{}
+	}
+
+	r := &Recorder{
+		records:  make(chan *Record, queueSize),
+		done:     make(chan struct{}),
+		log:      log.With().Str("component", "network_capture").Logger(),
+		config:   config,
+		channels: channels,
+		now:      time.Now,
+	}
+	go r.writeLoop()
+
+	return r, nil
+}
+
+// Capture queues the record of the given message for writing, if its channel is captured. It never blocks: records
+// are dropped if the writer falls behind, and ignored once the recorder is closed. Failures are logged, as capturing
+// must not interfere with the delivery of messages.
+func (r *Recorder) Capture(direction Direction, msg *message.Message) {
+	if len(r.channels) > 0 {
+		if _, ok := r.channels[network.Channel(msg.ChannelID)]; !ok {
+			return
+		}
+	}
+
+	record := NewRecord(r.now(), direction, msg)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return
+	}
+	select {
+	case r.records <- record:
+	default:
+		r.dropped++
+		if r.dropped%dropLogInterval == 1 {
+			r.log.Warn().
+				Uint64("dropped", r.dropped).
+				Str("channel", msg.ChannelID).
+				Str("type", msg.Type).
+				Msg("capture writer is falling behind, dropping records")
+		}
+	}
+}
+
+// writeLoop writes the queued records until the recorder is closed.
+func (r *Recorder) writeLoop() {
+	defer close(r.done)
+
+	for record := range r.records {
+		err := r.write(record)
+		if err != nil {
+			r.log.Error().Err(err).
+				Str("channel", record.Channel.String()).
+				Str("type", record.Type).
+				Msg("could not capture message")
+		}
+	}
+}
+
+// write appends the record to the current capture file, rotating the file beforehand if it is full.
+func (r *Recorder) write(record *Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("could not encode record: %w", err)
+	}
+	line = append(line, '\n')
+
+	if r.file == nil || r.size+int64(len(line)) > r.config.MaxFileSize {
+		err = r.rotate()
+		if err != nil {
+			return fmt.Errorf("could not rotate capture file: %w", err)
+		}
+	}
+
+	n, err := r.file.Write(line)
+	r.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("could not write record: %w", err)
+	}
+	return nil
+}
+
+// rotate closes the current capture file, opens a new one, and removes the oldest files beyond the maximum.
+func (r *Recorder) rotate() error {
+	if r.file != nil {
+		err := r.file.Close()
+		if err != nil {
+			return fmt.Errorf("could not close capture file: %w", err)
+		}
+		r.file = nil
+	}
+
+	name := filepath.Join(r.config.Dir, filePrefix+r.now().UTC().Format(fileTimeFormat)+fileSuffix)
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("could not create capture file: %w", err)
+	}
+	r.file = file
+	r.size = 0
+
+	files, err := Files(r.config.Dir)
+	if err != nil {
+		return err
+	}
+	for len(files) > r.config.MaxFiles {
+		err = os.Remove(files[0])
+		if err != nil {
+			return fmt.Errorf("could not remove capture file %s: %w", files[0], err)
+		}
+		files = files[1:]
+	}
+
+	return nil
+}
+
+// Close stops capturing, waits for the queued records to be written, and closes the current capture file.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return nil
+	}
+	r.closed = true
+	close(r.records)
+	r.mu.Unlock()
+
+	<-r.done
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// Files returns the paths of the capture files in the given directory, from oldest to newest.
+func Files(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read capture directory: %w", err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, filePrefix) || !strings.HasSuffix(name, fileSuffix) {
+			continue
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+	sort.Strings(files)
+
+	return files, nil
+}
@@ -0,0 +1,120 @@
+package capture
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/network"
+)
+
+// Replayer feeds the inbound messages of a capture into the engines of a node, in the order they were received,
+// so that the behavior of the engines can be reproduced offline. Outbound messages of the capture are skipped.
+type Replayer struct {
+	log      zerolog.Logger
+	channels map[network.Channel]struct{}
+	timing   bool
+}
+
+// ReplayerOption configures a replayer.
+type ReplayerOption func(*Replayer)
+
+// WithReplayedChannels restricts the replay to the messages of the given channels.
+func WithReplayedChannels(channels ...network.Channel) ReplayerOption {
+	return func(r *Replayer) {
+		for _, channel := range channels {
+			r.channels[channel] = struct{}{}
+		}
+	}
+}
+
+// WithTiming preserves the time between consecutive messages of the capture. By default, messages are replayed
+// back to back.
+func WithTiming() ReplayerOption {
+	return func(r *Replayer) {
+		r.timing = true
+	}
+}
+
+// NewReplayer creates a replayer with the given options.
+func NewReplayer(log zerolog.Logger, opts ...ReplayerOption) *Replayer {
+	r := &Replayer{
+		log:      log.With().Str("component", "network_replay").Logger(),
+		channels: make(map[network.Channel]struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// ReplayToOverlay delivers the captured messages in the given directory to the overlay, i.e. the network of a node,
+// which decodes and dispatches them to the engines registered on their channels. As the network drops messages it
+// has seen before, messages are only replayed once per network instance.
+// It returns the number of replayed messages.
+func (r *Replayer) ReplayToOverlay(ctx context.Context, dir string, overlay network.Overlay) (int, error) {
+	return r.replay(ctx, dir, func(record *Record) error {
+		return overlay.Receive(record.OriginID, record.Message())
+	})
+}
+
+// ReplayToEngines decodes the captured messages in the given directory with the codec, and delivers them directly
+// to the engines, e.g. the engines of a node attached to a stub network.
+// It returns the number of replayed messages.
+func (r *Replayer) ReplayToEngines(ctx context.Context, dir string, codec network.Codec, engines network.MessageProcessor) (int, error) {
+	return r.replay(ctx, dir, func(record *Record) error {
+		event, err := codec.Decode(record.Payload)
+		if err != nil {
+			return fmt.Errorf("could not decode payload: %w", err)
+		}
+		return engines.Process(record.Channel, record.OriginID, event)
+	})
+}
+
+// replay calls deliver for each inbound record of the capture to be replayed. Delivery failures are logged, and do
+// not stop the replay, as they are usually the behavior to be reproduced.
+func (r *Replayer) replay(ctx context.Context, dir string, deliver func(*Record) error) (int, error) {
+	replayed := 0
+	var last time.Time
+	err := ReadDir(dir, func(record *Record) error {
+		if record.Direction != Inbound {
+			return nil
+		}
+		if len(r.channels) > 0 {
+			if _, ok := r.channels[record.Channel]; !ok {
+				return nil
+			}
+		}
+
+		if r.timing && !last.IsZero() {
+			select {
+			case <-time.After(record.Timestamp.Sub(last)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		} else if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		last = record.Timestamp
+
+		err := deliver(record)
+		if err != nil {
+			r.log.Warn().Err(err).
+				Str("channel", record.Channel.String()).
+				Str("type", record.Type).
+				Hex("origin_id", record.OriginID[:]).
+				Time("captured_at", record.Timestamp).
+				Msg("replayed message was not processed")
+		}
+		replayed++
+
+		return nil
+	})
+	if err != nil {
+		return replayed, fmt.Errorf("could not replay capture: %w", err)
+	}
+
+	return replayed, nil
+}
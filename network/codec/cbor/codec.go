@@ -11,29 +11,50 @@ import (
 
 	cborcodec "github.com/onflow/flow-go/model/encoding/cbor"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/codec"
 	_ "github.com/onflow/flow-go/utils/binstat"
 )
 
 // Codec represents a CBOR codec for our network.
 type Codec struct {
+	versions codec.Versions
 }
 
-// NewCodec creates a new CBOR codec.
-func NewCodec() *Codec {
-	c := &Codec{}
+type Option func(*Codec)
+
+// WithVersions sets the schema versions of the message types, replacing the default versions.
+func WithVersions(versions codec.Versions) Option {
+	return func(c *Codec) {
+		c.versions = versions
+	}
+}
+
+// NewCodec creates a new CBOR codec. It panics if the schema versions of the codec are inconsistent,
+// which is a programming error.
+func NewCodec(opts ...Option) *Codec {
+	c := &Codec{
+		versions: DefaultVersions(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	err := c.versions.Validate()
+	if err != nil {
+		panic(fmt.Sprintf("invalid schema versions: %v", err))
+	}
 	return c
 }
 
 // NewEncoder creates a new CBOR encoder with the given underlying writer.
 func (c *Codec) NewEncoder(w io.Writer) network.Encoder {
 	enc := cborcodec.EncMode.NewEncoder(w)
-	return &Encoder{enc: enc}
+	return &Encoder{enc: enc, versions: c.versions}
 }
 
 // NewDecoder creates a new CBOR decoder with the given underlying reader.
 func (c *Codec) NewDecoder(r io.Reader) network.Decoder {
 	dec := cbor.NewDecoder(r)
-	return &Decoder{dec: dec}
+	return &Decoder{dec: dec, versions: c.versions}
 }
 
 // Given a Golang interface 'v', return a []byte 'envelope'.
@@ -43,6 +64,8 @@ func (c *Codec) NewDecoder(r io.Reader) network.Decoder {
 // NOTE: 'what' is the 'code' name for debugging / instrumentation.
 // NOTE: 'envelope' contains 'code' & serialized / encoded 'v'.
 // i.e.  1st byte is 'code' and remaining bytes are CBOR encoded 'v'.
+// NOTE: if the schema version of 'code' is not 0, the 1st byte has the
+// codec.VersionedCode flag set and is followed by the schema version.
 func (c *Codec) Encode(v interface{}) ([]byte, error) {
 
 	// encode the value
@@ -57,7 +80,7 @@ func (c *Codec) Encode(v interface{}) ([]byte, error) {
 	// encode / append the envelope code
 	//bs1 := binstat.EnterTime(binstat.BinNet + ":wire<1(cbor)envelope2payload")
 	var data bytes.Buffer
-	data.WriteByte(code)
+	writeEnvelopeHeader(&data, c.versions, code)
 	//binstat.LeaveVal(bs1, int64(data.Len()))
 
 	// encode the payload
@@ -81,25 +104,8 @@ func (c *Codec) Encode(v interface{}) ([]byte, error) {
 // NOTE: 'what' is the 'code' name for debugging / instrumentation.
 // NOTE: 'envelope' contains 'code' & serialized / encoded 'v'.
 // i.e.  1st byte is 'code' and remaining bytes are CBOR encoded 'v'.
+// NOTE: 'v' is upgraded to the current schema version of its message type.
 func (c *Codec) Decode(data []byte) (interface{}, error) {
 
-	// decode the envelope
-	//bs1 := binstat.EnterTime(binstat.BinNet + ":wire>3(cbor)payload2envelope")
-	code := data[0] // only first byte
-	//binstat.LeaveVal(bs1, int64(len(data)))
-
-	what, v, err := envelopeCode2v(code)
-	if err != nil {
-		return nil, fmt.Errorf("could not determine interface from code: %w", err)
-	}
-
-	// unmarshal the payload
-	//bs2 := binstat.EnterTimeVal(fmt.Sprintf("%s%s%s:%d", binstat.BinNet, ":wire>4(cbor)", what, code), int64(len(data))) // e.g. ~3net:wire>4(cbor)CodeEntityRequest:23
-	err = cbor.Unmarshal(data[1:], v) // all but first byte
-	//binstat.Leave(bs2)
-	if err != nil {
-		return nil, fmt.Errorf("could not decode CBOR payload with envelope code %d AKA %s: %w", code, what, err) // e.g. 2, "CodeBlockProposal", <CBOR error>
-	}
-
-	return v, nil
+	return decodeEnvelope(c.versions, data)
 }
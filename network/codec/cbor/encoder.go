@@ -9,12 +9,14 @@ import (
 	"github.com/fxamacker/cbor/v2"
 
 	cborcodec "github.com/onflow/flow-go/model/encoding/cbor"
+	"github.com/onflow/flow-go/network/codec"
 	_ "github.com/onflow/flow-go/utils/binstat"
 )
 
 // Encoder is an encoder to write serialized CBOR to a writer.
 type Encoder struct {
-	enc *cbor.Encoder
+	enc      *cbor.Encoder
+	versions codec.Versions
 }
 
 // Encode will convert the given message into CBOR and write it to the
@@ -30,7 +32,7 @@ func (e *Encoder) Encode(v interface{}) error {
 	// encode the payload
 	//bs1 := binstat.EnterTime(fmt.Sprintf("%s%s%s:%d", binstat.BinNet, ":strm<1(cbor)", what, code)) // e.g. ~3net::strm<1(cbor)CodeEntityRequest:23
 	var data bytes.Buffer
-	data.WriteByte(code)
+	writeEnvelopeHeader(&data, e.versions, code)
 	encoder := cborcodec.EncMode.NewEncoder(&data)
 	err = encoder.Encode(v)
 	//binstat.LeaveVal(bs1, int64(data.Len()))
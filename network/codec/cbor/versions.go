@@ -0,0 +1,74 @@
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+
+	"github.com/onflow/flow-go/network/codec"
+)
+
+// DefaultVersions returns the schema versions of the message types of the CBOR codec. When a change to a
+// message type breaks the decoding of payloads encoded in its previous schema, its schema version is
+// incremented here, together with a decoder for the previous schema and an upgrade to the new one, e.g.
+//
+//	CodeBlockVote: {
+//	    Current: 1,
+//	    Legacy: map[codec.Version]codec.Legacy{
+//	        0: {New: func() interface{} { return &legacyBlockVote{} }, Upgrade: upgradeBlockVote},
+//	    },
+//	},
+//
+// Message types which are not listed are in schema version 0.
+func DefaultVersions() codec.Versions {
+	return codec.Versions{}
+}
+
+// writeEnvelopeHeader writes the envelope code of a message, followed by its schema version if it is not 0.
+func writeEnvelopeHeader(data *bytes.Buffer, versions codec.Versions, code uint8) {
+	version := versions.Current(code)
+	if version == 0 {
+		data.WriteByte(code)
+		return
+	}
+	data.WriteByte(code | codec.VersionedCode)
+	data.WriteByte(byte(version))
+}
+
+// decodeEnvelope decodes the message in the given envelope and upgrades it to its current schema version.
+func decodeEnvelope(versions codec.Versions, data []byte) (interface{}, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty envelope")
+	}
+
+	code := data[0] // only first byte
+	payload := data[1:]
+	version := codec.Version(0)
+	if code&codec.VersionedCode != 0 {
+		if len(payload) == 0 {
+			return nil, fmt.Errorf("missing schema version for envelope code %d", code&^codec.VersionedCode)
+		}
+		code &^= codec.VersionedCode
+		version = codec.Version(payload[0])
+		payload = payload[1:]
+	}
+
+	what, v, err := envelopeCode2v(code)
+	if err != nil {
+		return nil, fmt.Errorf("could not determine interface from code: %w", err)
+	}
+
+	// unmarshal the payload
+	//bs := binstat.EnterTimeVal(fmt.Sprintf("%s%s%s:%d", binstat.BinNet, ":wire>4(cbor)", what, code), int64(len(data))) // e.g. ~3net:wire>4(cbor)CodeEntityRequest:23
+	v, err = versions.Decode(code, what, version, v, func(value interface{}) error {
+		return cbor.Unmarshal(payload, value)
+	})
+	//binstat.Leave(bs)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode CBOR payload with envelope code %d AKA %s: %w", code, what, err) // e.g. 2, "CodeBlockProposal", <CBOR error>
+	}
+
+	return v, nil
+}
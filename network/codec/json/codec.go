@@ -8,36 +8,57 @@ import (
 	"io"
 
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/codec"
 	_ "github.com/onflow/flow-go/utils/binstat"
 )
 
 // Codec represents a JSON codec for our network.
 type Codec struct {
+	versions codec.Versions
 }
 
-// NewCodec creates a new JSON codec.
-func NewCodec() *Codec {
-	c := &Codec{}
+type Option func(*Codec)
+
+// WithVersions sets the schema versions of the message types, replacing the default versions.
+func WithVersions(versions codec.Versions) Option {
+	return func(c *Codec) {
+		c.versions = versions
+	}
+}
+
+// NewCodec creates a new JSON codec. It panics if the schema versions of the codec are inconsistent,
+// which is a programming error.
+func NewCodec(opts ...Option) *Codec {
+	c := &Codec{
+		versions: DefaultVersions(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	err := c.versions.Validate()
+	if err != nil {
+		panic(fmt.Sprintf("invalid schema versions: %v", err))
+	}
 	return c
 }
 
 // NewEncoder creates a new JSON encoder with the given underlying writer.
 func (c *Codec) NewEncoder(w io.Writer) network.Encoder {
 	enc := json.NewEncoder(w)
-	return &Encoder{enc: enc}
+	return &Encoder{enc: enc, versions: c.versions}
 }
 
 // NewDecoder creates a new JSON decoder with the given underlying reader.
 func (c *Codec) NewDecoder(r io.Reader) network.Decoder {
 	dec := json.NewDecoder(r)
-	return &Decoder{dec: dec}
+	return &Decoder{dec: dec, versions: c.versions}
 }
 
 // Encode will encode the givene entity and return the bytes.
 func (c *Codec) Encode(v interface{}) ([]byte, error) {
 
 	// encode the value
-	env, err := v2envEncode(v, ":wire<1(json)")
+	env, err := v2envEncode(c.versions, v, ":wire<1(json)")
 	if err != nil {
 		return nil, fmt.Errorf("could not encode envelope: %w", err)
 	}
@@ -68,7 +89,7 @@ func (c *Codec) Decode(data []byte) (interface{}, error) {
 	}
 
 	// decode the value
-	v, err := env2vDecode(env, ":wire>4(json)")
+	v, err := env2vDecode(c.versions, env, ":wire>4(json)")
 	if err != nil {
 		return nil, fmt.Errorf("could not decode value: %w", err)
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/libp2p/message"
 	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network/codec"
 	_ "github.com/onflow/flow-go/utils/binstat"
 )
 
@@ -181,7 +182,7 @@ func switchenv2what(env Envelope) (string, error) {
 }
 
 // decode will decode the envelope into an entity.
-func env2vDecode(env Envelope, via string) (interface{}, error) {
+func env2vDecode(versions codec.Versions, env Envelope, via string) (interface{}, error) {
 
 	// create the desired message
 	v, err := switchenv2v(env)
@@ -196,7 +197,9 @@ func env2vDecode(env Envelope, via string) (interface{}, error) {
 
 	// unmarshal the payload
 	//bs := binstat.EnterTimeVal(fmt.Sprintf("%s%s%s:%d", binstat.BinNet, via, what, env.Code), int64(len(env.Data))) // e.g. ~3net:wire>4(json)CodeEntityRequest:23
-	err = json.Unmarshal(env.Data, v)
+	v, err = versions.Decode(env.Code, what, env.Version, v, func(value interface{}) error {
+		return json.Unmarshal(env.Data, value)
+	})
 	//binstat.Leave(bs)
 	if err != nil {
 		return nil, fmt.Errorf("could not decode json payload of type %s: %w", what, err)
@@ -6,12 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/onflow/flow-go/network/codec"
 	_ "github.com/onflow/flow-go/utils/binstat"
 )
 
 // Decoder implements a stream decoder for JSON.
 type Decoder struct {
-	dec *json.Decoder
+	dec      *json.Decoder
+	versions codec.Versions
 }
 
 // Decode will decode the next JSON value from the stream.
@@ -27,7 +29,7 @@ func (d *Decoder) Decode() (interface{}, error) {
 	}
 
 	// decode the embedded value
-	v, err := env2vDecode(env, ":strm>2(json)")
+	v, err := env2vDecode(d.versions, env, ":strm>2(json)")
 	if err != nil {
 		return nil, fmt.Errorf("could not decode value: %w", err)
 	}
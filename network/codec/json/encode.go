@@ -11,6 +11,7 @@ import (
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/model/libp2p/message"
 	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network/codec"
 	_ "github.com/onflow/flow-go/utils/binstat"
 )
 
@@ -182,7 +183,7 @@ func switchv2what(v interface{}) (string, error) {
 	return what, nil
 }
 
-func v2envEncode(v interface{}, via string) (*Envelope, error) {
+func v2envEncode(versions codec.Versions, v interface{}, via string) (*Envelope, error) {
 
 	// determine the message type
 	code, err := switchv2code(v)
@@ -204,8 +205,9 @@ func v2envEncode(v interface{}, via string) (*Envelope, error) {
 	}
 
 	env := Envelope{
-		Code: code,
-		Data: data,
+		Code:    code,
+		Version: versions.Current(code),
+		Data:    data,
 	}
 
 	return &env, nil
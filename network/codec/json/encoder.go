@@ -6,12 +6,14 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/onflow/flow-go/network/codec"
 	_ "github.com/onflow/flow-go/utils/binstat"
 )
 
 // Encoder is an encoder to write serialized JSON to a writer.
 type Encoder struct {
-	enc *json.Encoder
+	enc      *json.Encoder
+	versions codec.Versions
 }
 
 // Encode will convert the given message into binary JSON and write it to the
@@ -19,7 +21,7 @@ type Encoder struct {
 func (e *Encoder) Encode(v interface{}) error {
 
 	// encode the value
-	env, err := v2envEncode(v, ":strm<1(json)")
+	env, err := v2envEncode(e.versions, v, ":strm<1(json)")
 	if err != nil {
 		return fmt.Errorf("could not encode value: %w", err)
 	}
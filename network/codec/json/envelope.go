@@ -4,6 +4,8 @@ package json
 
 import (
 	"encoding/json"
+
+	"github.com/onflow/flow-go/network/codec"
 )
 
 const (
@@ -57,8 +59,10 @@ const (
 )
 
 // Envelope is a wrapper to convey type information with JSON encoding without
-// writing custom bytes to the wire.
+// writing custom bytes to the wire. The schema version is omitted if it is 0,
+// which keeps envelopes compatible with nodes without schema versions.
 type Envelope struct {
-	Code uint8
-	Data json.RawMessage
+	Code    uint8
+	Version codec.Version `json:",omitempty"`
+	Data    json.RawMessage
 }
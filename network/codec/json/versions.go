@@ -0,0 +1,13 @@
+package json
+
+import (
+	"github.com/onflow/flow-go/network/codec"
+)
+
+// DefaultVersions returns the schema versions of the message types of the JSON codec. When a change to a
+// message type breaks the decoding of payloads encoded in its previous schema, its schema version is
+// incremented here, together with a decoder for the previous schema and an upgrade to the new one.
+// Message types which are not listed are in schema version 0.
+func DefaultVersions() codec.Versions {
+	return codec.Versions{}
+}
@@ -0,0 +1,133 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Version is the schema version of the payload of a message type. It has to be incremented whenever the
+// message type changes in a way that payloads encoded in the previous schema can no longer be decoded
+// into it, e.g. when a field is removed or changes its type.
+type Version uint8
+
+// VersionedCode is set in the envelope code of messages encoded in a schema version other than 0, which
+// is then written to the envelope right after the code. Messages in schema version 0 are encoded without
+// schema version, which keeps them compatible with nodes running a software version without schema versions.
+const VersionedCode = uint8(0x80)
+
+// MaxVersionsAhead is the number of schema versions beyond the current one which a node running a newer software
+// version may plausibly send during a rolling upgrade. Messages in such versions result in an UnknownVersionError,
+// which is not penalized, whereas messages in even newer versions are treated as invalid.
+const MaxVersionsAhead = 2
+
+// Legacy describes how to decode payloads in an older schema version of a message type.
+type Legacy struct {
+	// New returns an empty value of the message type in the older schema version, into which the payload is decoded.
+	New func() interface{}
+
+	// Upgrade converts a value in the older schema version into a value in the next schema version.
+	Upgrade func(interface{}) (interface{}, error)
+}
+
+// Schema holds the schema versions of a message type.
+type Schema struct {
+	// Current is the schema version in which messages of the type are encoded.
+	Current Version
+
+	// Legacy holds the older schema versions which messages of the type can still be decoded from. As values
+	// are upgraded one version at a time, it has to contain all versions from the oldest supported one up to
+	// the current one.
+	Legacy map[Version]Legacy
+}
+
+// Versions holds the schemas of the message types of a codec, by envelope code. Message types without
+// schema are in schema version 0.
+type Versions map[uint8]Schema
+
+// Validate checks that the legacy schema versions of all message types can be upgraded to the current one.
+func (v Versions) Validate() error {
+	for code, schema := range v {
+		if code&VersionedCode != 0 {
+			return fmt.Errorf("envelope code %d conflicts with the versioned code flag", code)
+		}
+		oldest := schema.Current
+		for version, legacy := range schema.Legacy {
+			if version >= schema.Current {
+				return fmt.Errorf("legacy schema version %d of envelope code %d is not older than current version %d", version, code, schema.Current)
+			}
+			if legacy.New == nil || legacy.Upgrade == nil {
+				return fmt.Errorf("legacy schema version %d of envelope code %d has no decoder or upgrade", version, code)
+			}
+			if version < oldest {
+				oldest = version
+			}
+		}
+		if len(schema.Legacy) != int(schema.Current-oldest) {
+			return fmt.Errorf("legacy schema versions of envelope code %d are not contiguous up to current version %d", code, schema.Current)
+		}
+	}
+	return nil
+}
+
+// Current returns the schema version in which messages with the given envelope code are encoded.
+func (v Versions) Current(code uint8) Version {
+	return v[code].Current
+}
+
+// Decode decodes the payload of a message with the given envelope code, which is in the given schema version,
+// and returns it in the current schema version. The value is the empty message in the current schema version, and
+// unmarshal decodes the payload into the given value. If the schema version is newer than the current one by at
+// most MaxVersionsAhead, an UnknownVersionError is returned.
+func (v Versions) Decode(code uint8, what string, version Version, value interface{}, unmarshal func(interface{}) error) (interface{}, error) {
+	schema := v[code]
+	if int(version) > int(schema.Current)+MaxVersionsAhead {
+		return nil, fmt.Errorf("invalid schema version %d of %s (current version: %d)", version, what, schema.Current)
+	}
+	if version > schema.Current {
+		return nil, UnknownVersionError{Code: code, What: what, Version: version, Current: schema.Current}
+	}
+
+	if version == schema.Current {
+		err := unmarshal(value)
+		if err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	legacy, ok := schema.Legacy[version]
+	if !ok {
+		return nil, fmt.Errorf("schema version %d of %s is no longer supported (current version: %d)", version, what, schema.Current)
+	}
+	value = legacy.New()
+	err := unmarshal(value)
+	if err != nil {
+		return nil, err
+	}
+	for ; version < schema.Current; version++ {
+		value, err = schema.Legacy[version].Upgrade(value)
+		if err != nil {
+			return nil, fmt.Errorf("could not upgrade %s from schema version %d: %w", what, version, err)
+		}
+	}
+	return value, nil
+}
+
+// UnknownVersionError is returned when decoding a message in a schema version newer than the current one of the
+// codec, which is typically sent by a node running a newer software version.
+type UnknownVersionError struct {
+	Code    uint8
+	What    string
+	Version Version
+	Current Version
+}
+
+func (e UnknownVersionError) Error() string {
+	return fmt.Sprintf("unknown schema version %d of %s with envelope code %d (current version: %d)", e.Version, e.What, e.Code, e.Current)
+}
+
+// IsUnknownVersionError returns whether the given error is an UnknownVersionError.
+func IsUnknownVersionError(err error) bool {
+	var e UnknownVersionError
+	return errors.As(err, &e)
+}
@@ -0,0 +1,159 @@
+package codec_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/libp2p/message"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/codec"
+	cborcodec "github.com/onflow/flow-go/network/codec/cbor"
+	jsoncodec "github.com/onflow/flow-go/network/codec/json"
+)
+
+// legacyTestMessage is the test message in schema version 0, whose text is upgraded to upper case in version 1.
+type legacyTestMessage struct {
+	Text string
+}
+
+func echoVersions(code uint8) codec.Versions {
+	return codec.Versions{
+		code: {
+			Current: 1,
+			Legacy: map[codec.Version]codec.Legacy{
+				0: {
+					New: func() interface{} { return &legacyTestMessage{} },
+					Upgrade: func(v interface{}) (interface{}, error) {
+						legacy := v.(*legacyTestMessage)
+						if legacy.Text == "" {
+							return nil, errors.New("empty text")
+						}
+						return &message.TestMessage{Text: strings.ToUpper(legacy.Text)}, nil
+					},
+				},
+			},
+		},
+	}
+}
+
+// TestSchemaVersions tests that messages in older schema versions are upgraded, and that messages in newer schema
+// versions are rejected, both by the codecs and their stream decoders.
+func TestSchemaVersions(t *testing.T) {
+	codecs := map[string]struct {
+		old     network.Codec
+		current network.Codec
+	}{
+		"cbor": {
+			old:     cborcodec.NewCodec(),
+			current: cborcodec.NewCodec(cborcodec.WithVersions(echoVersions(cborcodec.CodeEcho))),
+		},
+		"json": {
+			old:     jsoncodec.NewCodec(),
+			current: jsoncodec.NewCodec(jsoncodec.WithVersions(echoVersions(jsoncodec.CodeEcho))),
+		},
+	}
+
+	for name, c := range codecs {
+		c := c
+		t.Run(name, func(t *testing.T) {
+			roundTrips := map[string]func(encoder network.Codec, decoder network.Codec, v interface{}) (interface{}, error){
+				"wire": func(encoder network.Codec, decoder network.Codec, v interface{}) (interface{}, error) {
+					data, err := encoder.Encode(v)
+					require.NoError(t, err)
+					return decoder.Decode(data)
+				},
+				"stream": func(encoder network.Codec, decoder network.Codec, v interface{}) (interface{}, error) {
+					buf := new(bytes.Buffer)
+					require.NoError(t, encoder.NewEncoder(buf).Encode(v))
+					return decoder.NewDecoder(buf).Decode()
+				},
+			}
+
+			for via, roundTrip := range roundTrips {
+				roundTrip := roundTrip
+				t.Run(via, func(t *testing.T) {
+					msg := &message.TestMessage{Text: "hello"}
+
+					// messages in the current version are decoded as they are
+					decoded, err := roundTrip(c.current, c.current, msg)
+					require.NoError(t, err)
+					assert.Equal(t, msg, decoded)
+
+					// messages in the old version are upgraded
+					decoded, err = roundTrip(c.old, c.current, msg)
+					require.NoError(t, err)
+					assert.Equal(t, &message.TestMessage{Text: "HELLO"}, decoded)
+
+					// failed upgrades are reported
+					_, err = roundTrip(c.old, c.current, &message.TestMessage{})
+					require.Error(t, err)
+					assert.False(t, codec.IsUnknownVersionError(err))
+
+					// messages in a newer version are rejected
+					_, err = roundTrip(c.current, c.old, msg)
+					require.Error(t, err)
+					assert.True(t, codec.IsUnknownVersionError(err))
+
+					// messages of types without schema version are not affected
+					other := &message.TestMessage{Text: "other"}
+					decoded, err = roundTrip(c.old, c.old, other)
+					require.NoError(t, err)
+					assert.Equal(t, other, decoded)
+				})
+			}
+		})
+	}
+}
+
+// TestInvalidSchemaVersions tests that schema versions which cannot be upgraded to the current version are rejected.
+func TestInvalidSchemaVersions(t *testing.T) {
+	legacy := codec.Legacy{
+		New:     func() interface{} { return &legacyTestMessage{} },
+		Upgrade: func(v interface{}) (interface{}, error) { return v, nil },
+	}
+
+	require.NoError(t, codec.Versions{1: {Current: 2, Legacy: map[codec.Version]codec.Legacy{1: legacy}}}.Validate())
+	// dropping the support of all legacy versions is valid
+	require.NoError(t, codec.Versions{1: {Current: 2}}.Validate())
+
+	for i, versions := range []codec.Versions{
+		// missing intermediate version
+		{1: {Current: 2, Legacy: map[codec.Version]codec.Legacy{0: legacy}}},
+		// legacy version not older than the current one
+		{1: {Current: 1, Legacy: map[codec.Version]codec.Legacy{0: legacy, 1: legacy}}},
+		// missing upgrade
+		{1: {Current: 1, Legacy: map[codec.Version]codec.Legacy{0: {New: legacy.New}}}},
+		// code conflicting with the versioned code flag
+		{codec.VersionedCode | 1: {Current: 0}},
+	} {
+		assert.Error(t, versions.Validate(), fmt.Sprintf("versions %d", i))
+	}
+
+	assert.Panics(t, func() {
+		cborcodec.NewCodec(cborcodec.WithVersions(codec.Versions{1: {Current: 2, Legacy: map[codec.Version]codec.Legacy{0: legacy}}}))
+	})
+}
+
+// TestUnknownSchemaVersions tests that only schema versions slightly newer than the current one are reported as
+// unknown, and that even newer versions are rejected as invalid.
+func TestUnknownSchemaVersions(t *testing.T) {
+	versions := codec.Versions{1: {Current: 1}}
+	unmarshal := func(interface{}) error { return nil }
+
+	for version := codec.Version(2); version <= 1+codec.MaxVersionsAhead; version++ {
+		_, err := versions.Decode(1, "test", version, &message.TestMessage{}, unmarshal)
+		assert.True(t, codec.IsUnknownVersionError(err), fmt.Sprintf("version %d", version))
+	}
+
+	for _, version := range []codec.Version{2 + codec.MaxVersionsAhead, 0x7f, 0xff} {
+		_, err := versions.Decode(1, "test", version, &message.TestMessage{}, unmarshal)
+		require.Error(t, err)
+		assert.False(t, codec.IsUnknownVersionError(err), fmt.Sprintf("version %d", version))
+	}
+}
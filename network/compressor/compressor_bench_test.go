@@ -0,0 +1,120 @@
+package compressor_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack"
+
+	"github.com/onflow/flow-go/ledger"
+	"github.com/onflow/flow-go/ledger/common/encoding"
+	"github.com/onflow/flow-go/ledger/common/utils"
+	"github.com/onflow/flow-go/ledger/complete/mtrie/trie"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// registerKeys are commonly used register keys of an account.
+var registerKeys = []string{"storage_used", "public_key_count", "public_key_0", "contract_names", "exists",
+	"storage\x1fflowTokenVault", "public\x1fflowTokenReceiver", "public\x1fflowTokenBalance"}
+
+// chunkProofFixture returns the encoded batch proof of reading and updating the given number of registers
+// in a trie holding registers of many accounts, as included in a chunk data pack.
+func chunkProofFixture(t testing.TB, registers int, touched int) []byte {
+	paths := utils.RandomPaths(registers)
+	payloads := make([]ledger.Payload, 0, registers)
+	owners := make([][]byte, registers/len(registerKeys)+1)
+	for i := range owners {
+		owners[i] = make([]byte, flow.AddressLength)
+		rand.Read(owners[i])
+	}
+	for i := 0; i < registers; i++ {
+		value := make([]byte, 8+rand.Intn(120))
+		rand.Read(value)
+		payloads = append(payloads, ledger.Payload{
+			Key: ledger.Key{KeyParts: []ledger.KeyPart{
+				ledger.NewKeyPart(0, owners[i/len(registerKeys)]),
+				ledger.NewKeyPart(1, []byte{}),
+				ledger.NewKeyPart(2, []byte(registerKeys[i%len(registerKeys)])),
+			}},
+			Value: value,
+		})
+	}
+
+	mtrie, err := trie.NewTrieWithUpdatedRegisters(trie.NewEmptyMTrie(), paths, payloads, true)
+	require.NoError(t, err)
+
+	proof := mtrie.UnsafeProofs(paths[:touched])
+	return encoding.EncodeTrieBatchProof(proof)
+}
+
+// payloadFixtures returns the network encoding of messages which are typically sent through unicast streams.
+func payloadFixtures(t testing.TB) map[string][]byte {
+	codec := cbor.NewCodec()
+	payloads := make(map[string][]byte)
+
+	collection := unittest.CollectionFixture(100)
+	chunkDataResponse := unittest.ChunkDataResponseMsgFixture(unittest.IdentifierFixture(), func(response *messages.ChunkDataResponse) {
+		response.ChunkDataPack.Collection = &collection
+		response.ChunkDataPack.Proof = chunkProofFixture(t, 20_000, 500)
+	})
+	data, err := codec.Encode(chunkDataResponse)
+	require.NoError(t, err)
+	payloads["chunk_data_pack"] = data
+
+	// collections are exchanged through the entity provider, which encodes each entity separately
+	response := &messages.EntityResponse{Nonce: rand.Uint64()}
+	for i := 0; i < 10; i++ {
+		collection := unittest.CollectionFixture(100)
+		blob, err := msgpack.Marshal(&collection)
+		require.NoError(t, err)
+		response.EntityIDs = append(response.EntityIDs, collection.ID())
+		response.Blobs = append(response.Blobs, blob)
+	}
+	data, err = codec.Encode(response)
+	require.NoError(t, err)
+	payloads["collection"] = data
+
+	return payloads
+}
+
+// BenchmarkCompressors compares the throughput and compression ratio of the stream compressors on
+// chunk data pack and collection payloads.
+func BenchmarkCompressors(b *testing.B) {
+	for payloadName, payload := range payloadFixtures(b) {
+		for name, comp := range compressors() {
+			b.Run(fmt.Sprintf("%s/%s", payloadName, name), func(b *testing.B) {
+				buf := new(bytes.Buffer)
+				b.SetBytes(int64(len(payload)))
+				b.ReportAllocs()
+				b.ResetTimer()
+
+				for i := 0; i < b.N; i++ {
+					buf.Reset()
+
+					w, err := comp.NewWriter(buf)
+					require.NoError(b, err)
+					_, err = w.Write(payload)
+					require.NoError(b, err)
+					require.NoError(b, w.Close())
+					compressedSize := buf.Len()
+
+					r, err := comp.NewReader(buf)
+					require.NoError(b, err)
+					_, err = io.Copy(ioutil.Discard, r)
+					require.NoError(b, err)
+					require.NoError(b, r.Close())
+
+					b.ReportMetric(float64(compressedSize)/float64(len(payload)), "ratio")
+				}
+			})
+		}
+	}
+}
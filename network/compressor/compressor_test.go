@@ -0,0 +1,82 @@
+package compressor_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/compressor"
+)
+
+// compressors returns all stream compressors by name, for the benchmarks.
+func compressors() map[string]network.Compressor {
+	return map[string]network.Compressor{
+		"gzip":   compressor.GzipStreamCompressor{},
+		"zstd":   compressor.ZstdStreamCompressor{},
+		"snappy": compressor.SnappyStreamCompressor{},
+		"lz4":    compressor.NewLz4Compressor(),
+	}
+}
+
+// TestZstdRoundTrip evaluates that (1) reading what has been written by the zstd compressor yields in
+// same result, and (2) data is compressed when written.
+func TestZstdRoundTrip(t *testing.T) {
+	testRoundTrip(t, compressor.ZstdStreamCompressor{})
+}
+
+// TestZstdOversizedWindow evaluates that the zstd compressor rejects a frame that declares a window larger than
+// a unicast message may hold, instead of allocating a history buffer of that size.
+func TestZstdOversizedWindow(t *testing.T) {
+	// frame magic number, a frame header descriptor without content size, and a window descriptor with
+	// exponent 19, which declares a window of 1<<29 bytes.
+	frame := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 19 << 3}
+
+	r, err := compressor.ZstdStreamCompressor{}.NewReader(bytes.NewReader(frame))
+	require.NoError(t, err)
+
+	_, err = io.ReadAll(r)
+	require.ErrorIs(t, err, zstd.ErrWindowSizeExceeded)
+	require.NoError(t, r.Close())
+}
+
+// TestSnappyRoundTrip evaluates that (1) reading what has been written by the snappy compressor yields in
+// same result, and (2) data is compressed when written.
+func TestSnappyRoundTrip(t *testing.T) {
+	testRoundTrip(t, compressor.SnappyStreamCompressor{})
+}
+
+func testRoundTrip(t *testing.T, comp network.Compressor) {
+	text := strings.Repeat("hello world, ", 100)
+	textBytes := []byte(text)
+	textBytesLen := len(textBytes)
+	buf := new(bytes.Buffer)
+
+	w, err := comp.NewWriter(buf)
+	require.NoError(t, err)
+
+	// testing write
+	//
+	n, err := w.Write(textBytes)
+	require.NoError(t, err)
+	// written bytes should match original data
+	require.Equal(t, n, textBytesLen)
+	require.NoError(t, w.Close())
+	// written data on buffer should be compressed in size.
+	require.Less(t, buf.Len(), textBytesLen)
+
+	// testing read
+	//
+	r, err := comp.NewReader(buf)
+	require.NoError(t, err)
+
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	// we should read what we have written
+	require.Equal(t, textBytes, b)
+	require.NoError(t, r.Close())
+}
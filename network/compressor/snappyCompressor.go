@@ -0,0 +1,26 @@
+package compressor
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+
+	"github.com/klauspost/compress/snappy"
+
+	"github.com/onflow/flow-go/network"
+)
+
+var _ network.Compressor = (*SnappyStreamCompressor)(nil)
+
+// SnappyStreamCompressor compresses streams with the snappy framing format. Snappy compresses less than
+// gzip and zstd, but is considerably faster. Reads from the underlying stream are buffered, as the decoder otherwise
+// issues many small reads.
+type SnappyStreamCompressor struct{}
+
+func (snappyStreamComp SnappyStreamCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	return ioutil.NopCloser(snappy.NewReader(bufio.NewReader(r))), nil
+}
+
+func (snappyStreamComp SnappyStreamCompressor) NewWriter(w io.Writer) (network.WriteCloseFlusher, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
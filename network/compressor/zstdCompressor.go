@@ -0,0 +1,45 @@
+package compressor
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/onflow/flow-go/network"
+)
+
+// zstdWindowSize is the maximum distance of back-references of the encoder, which is sufficient for the
+// messages exchanged through unicast streams.
+const zstdWindowSize = 1 << 20
+
+// zstdMaxDecoderMemory bounds the window a frame read from a stream may declare, as the decoder allocates its
+// history buffer from the window in the frame header. It mirrors p2p.DefaultMaxUnicastMsgSize, the largest size
+// of a unicast message apart from chunk data responses, which are also written with a window of zstdWindowSize,
+// so that a peer can not make the decoder allocate more memory than a message may hold.
+const zstdMaxDecoderMemory = 10 << 20
+
+var _ network.Compressor = (*ZstdStreamCompressor)(nil)
+
+// ZstdStreamCompressor compresses streams with zstd. As each unicast stream only carries a few messages,
+// the encoder and decoder of a stream are single-threaded, instead of using one goroutine per CPU, and the
+// encoder window is bounded to limit the memory held by each open stream. Reads from the underlying stream are
+// buffered, as the decoder otherwise issues many small reads.
+type ZstdStreamCompressor struct{}
+
+func (zstdStreamComp ZstdStreamCompressor) NewReader(r io.Reader) (io.ReadCloser, error) {
+	d, err := zstd.NewReader(bufio.NewReader(r), zstd.WithDecoderConcurrency(1), zstd.WithDecoderLowmem(true),
+		zstd.WithDecoderMaxMemory(zstdMaxDecoderMemory))
+	if err != nil {
+		return nil, err
+	}
+	return d.IOReadCloser(), nil
+}
+
+func (zstdStreamComp ZstdStreamCompressor) NewWriter(w io.Writer) (network.WriteCloseFlusher, error) {
+	e, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1), zstd.WithWindowSize(zstdWindowSize))
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -47,6 +47,19 @@ type Conduit interface {
 	// The recipients are selected randomly from the targetIDs.
 	Multicast(event interface{}, num uint, targetIDs ...flow.Identifier) error
 
+	// Request sends the request in a reliable way to the given recipient, and blocks until the recipient
+	// responds or the context is done. The request is served by the engine of the recipient registered on
+	// the channel of this conduit, which must implement RequestProcessor. Unlike messages sent with Unicast,
+	// the response is returned to the caller instead of being delivered to the engine of this conduit.
+	// It returns a RemoteRequestError if the recipient could not serve the request, and a RequestNotSupportedError
+	// if the recipient does not support the request protocol.
+	Request(ctx context.Context, request interface{}, targetID flow.Identifier) (interface{}, error)
+
+	// ReportMisbehavior reports a misbehavior of the node with the given ID, e.g. an invalid message
+	// received on the channel of this conduit. The penalties are accumulated by the peer scoring of
+	// the networking layer, which disconnects and temporarily blocks nodes that misbehave repeatedly.
+	ReportMisbehavior(originID flow.Identifier, misbehavior Misbehavior)
+
 	// Close unsubscribes from the channels of this conduit. After calling close,
 	// the conduit can no longer be used to send a message.
 	Close() error
@@ -0,0 +1,302 @@
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/btcsuite/btcd/btcec"
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/id"
+	"github.com/onflow/flow-go/network"
+)
+
+const (
+	// envelopeVersion is the version of the format of encrypted payloads
+	envelopeVersion = byte(1)
+
+	keySize   = 32 // AES-256
+	nonceSize = 12 // standard nonce size of AES-GCM
+
+	// wrappedKeySize is the size of a content key encrypted with a pairwise key, including the authentication tag
+	wrappedKeySize = keySize + 16
+
+	// recipientSize is the size of the entry of a recipient in an encrypted payload
+	recipientSize = flow.IdentifierLen + nonceSize + wrappedKeySize
+)
+
+// pairwiseKeyInfo separates the keys derived from the networking keys of nodes for channel encryption from any other
+// use of the networking keys.
+var pairwiseKeyInfo = []byte("flow-channel-encryption-v1")
+
+// ErrNotRecipient is returned when a payload is not encrypted for this node.
+var ErrNotRecipient = errors.New("payload is not encrypted for this node")
+
+// Encryptor encrypts the payloads of messages end-to-end between the sender and each of their recipients, so that the
+// payloads can not be read by any other node relaying or subscribing to the channel of the messages.
+//
+// Each payload is encrypted with a random content key, which is in turn encrypted for each recipient with the key
+// shared by the sender and the recipient. Shared keys are derived pairwise from the networking keys of the nodes in
+// the identity table, by elliptic-curve Diffie-Hellman. An encrypted payload authenticates its sender, its recipients
+// and its channel.
+type Encryptor struct {
+	me         flow.Identifier
+	curve      elliptic.Curve
+	privateKey []byte
+	identities id.IdentityProvider
+
+	mu   sync.Mutex
+	keys map[flow.Identifier]*pairwiseKey // cache of the keys shared with other nodes
+}
+
+// pairwiseKey is the key shared with another node, along with the networking key of the node it was derived from.
+type pairwiseKey struct {
+	networkPubKey crypto.PublicKey
+	aead          cipher.AEAD
+}
+
+// NewEncryptor creates an encryptor for the node with the given ID and networking key, which looks up the networking
+// keys of other nodes with the given identity provider.
+func NewEncryptor(me flow.Identifier, networkKey crypto.PrivateKey, identities id.IdentityProvider) (*Encryptor, error) {
+	curve, err := curveOf(networkKey.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Encryptor{
+		me:         me,
+		curve:      curve,
+		privateKey: networkKey.Encode(),
+		identities: identities,
+		keys:       make(map[flow.Identifier]*pairwiseKey),
+	}, nil
+}
+
+// Seal encrypts the payload of a message on the channel for the given recipients.
+func (e *Encryptor) Seal(channel network.Channel, payload []byte, recipients []flow.Identifier) ([]byte, error) {
+	if len(recipients) == 0 {
+		return nil, network.EmptyTargetList
+	}
+
+	contentKey := make([]byte, keySize)
+	_, err := io.ReadFull(rand.Reader, contentKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate content key: %w", err)
+	}
+	content, err := newAEAD(contentKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var sealed bytes.Buffer
+	sealed.WriteByte(envelopeVersion)
+	var count [binary.MaxVarintLen64]byte
+	sealed.Write(count[:binary.PutUvarint(count[:], uint64(len(recipients)))])
+
+	for _, recipientID := range recipients {
+		key, err := e.pairwiseKey(recipientID)
+		if err != nil {
+			return nil, fmt.Errorf("could not derive key shared with %x: %w", recipientID, err)
+		}
+
+		nonce, err := randomNonce()
+		if err != nil {
+			return nil, err
+		}
+
+		sealed.Write(recipientID[:])
+		sealed.Write(nonce)
+		sealed.Write(key.Seal(nil, nonce, contentKey, keyData(channel, e.me, recipientID)))
+	}
+
+	nonce, err := randomNonce()
+	if err != nil {
+		return nil, err
+	}
+	sealed.Write(nonce)
+
+	// the content is bound to the whole header, which includes the recipients
+	return content.Seal(sealed.Bytes(), nonce, payload, contentData(channel, e.me, sealed.Bytes())), nil
+}
+
+// Open decrypts the payload of a message on the channel from the given sender. It returns ErrNotRecipient if the
+// payload is not encrypted for this node, and an error if the payload is malformed or fails authentication.
+func (e *Encryptor) Open(channel network.Channel, senderID flow.Identifier, sealed []byte) ([]byte, error) {
+	if len(sealed) == 0 || sealed[0] != envelopeVersion {
+		return nil, fmt.Errorf("unknown envelope version")
+	}
+
+	count, n := binary.Uvarint(sealed[1:])
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of recipients")
+	}
+	offset := 1 + n
+	if count == 0 || count > uint64((len(sealed)-offset)/recipientSize) {
+		return nil, fmt.Errorf("invalid number of recipients: %d", count)
+	}
+
+	header := offset + int(count)*recipientSize + nonceSize
+	if len(sealed) < header {
+		return nil, fmt.Errorf("encrypted payload too short")
+	}
+
+	var wrapped []byte
+	for i := 0; i < int(count); i++ {
+		entry := sealed[offset+i*recipientSize : offset+(i+1)*recipientSize]
+		if flow.HashToID(entry[:flow.IdentifierLen]) == e.me {
+			wrapped = entry[flow.IdentifierLen:]
+			break
+		}
+	}
+	if wrapped == nil {
+		return nil, ErrNotRecipient
+	}
+
+	key, err := e.pairwiseKey(senderID)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive key shared with %x: %w", senderID, err)
+	}
+	contentKey, err := key.Open(nil, wrapped[:nonceSize], wrapped[nonceSize:], keyData(channel, senderID, e.me))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt content key: %w", err)
+	}
+
+	content, err := newAEAD(contentKey)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := content.Open(nil, sealed[header-nonceSize:header], sealed[header:], contentData(channel, senderID, sealed[:header]))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt payload: %w", err)
+	}
+
+	return payload, nil
+}
+
+// pairwiseKey returns the key shared with the given node, derived from the current networking key of the node.
+func (e *Encryptor) pairwiseKey(nodeID flow.Identifier) (cipher.AEAD, error) {
+	identity, ok := e.identities.ByNodeID(nodeID)
+	if !ok {
+		return nil, fmt.Errorf("unknown node")
+	}
+	if identity.NetworkPubKey == nil {
+		return nil, fmt.Errorf("node has no networking key")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	cached, ok := e.keys[nodeID]
+	if ok && cached.networkPubKey.Equals(identity.NetworkPubKey) {
+		return cached.aead, nil
+	}
+
+	secret, err := e.sharedSecret(identity.NetworkPubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// the key is bound to both nodes, in the same order on either side
+	first, second := e.me, nodeID
+	if bytes.Compare(first[:], second[:]) > 0 {
+		first, second = second, first
+	}
+	info := append(append(append([]byte{}, pairwiseKeyInfo...), first[:]...), second[:]...)
+
+	key := make([]byte, keySize)
+	_, err = io.ReadFull(hkdf.New(sha256.New, secret, nil, info), key)
+	if err != nil {
+		return nil, fmt.Errorf("could not derive key: %w", err)
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	e.keys[nodeID] = &pairwiseKey{
+		networkPubKey: identity.NetworkPubKey,
+		aead:          aead,
+	}
+
+	return aead, nil
+}
+
+// sharedSecret returns the elliptic-curve Diffie-Hellman secret of the networking key of this node and the given
+// networking public key, which must be on the same curve.
+func (e *Encryptor) sharedSecret(networkPubKey crypto.PublicKey) ([]byte, error) {
+	curve, err := curveOf(networkPubKey.Algorithm())
+	if err != nil {
+		return nil, err
+	}
+	if curve != e.curve {
+		return nil, fmt.Errorf("networking key of node uses algorithm %s, different from the one of this node", networkPubKey.Algorithm())
+	}
+
+	encoded := networkPubKey.Encode()
+	x := new(big.Int).SetBytes(encoded[:len(encoded)/2])
+	y := new(big.Int).SetBytes(encoded[len(encoded)/2:])
+	if !curve.IsOnCurve(x, y) {
+		return nil, fmt.Errorf("networking key of node is not on curve")
+	}
+
+	sx, _ := curve.ScalarMult(x, y, e.privateKey)
+	secret := make([]byte, (curve.Params().BitSize+7)/8)
+	return sx.FillBytes(secret), nil
+}
+
+// curveOf returns the elliptic curve of networking keys of the given algorithm.
+func curveOf(algorithm crypto.SigningAlgorithm) (elliptic.Curve, error) {
+	switch algorithm {
+	case crypto.ECDSAP256:
+		return elliptic.P256(), nil
+	case crypto.ECDSASecp256k1:
+		return btcec.S256(), nil
+	default:
+		return nil, fmt.Errorf("unsupported networking key algorithm: %s", algorithm)
+	}
+}
+
+// keyData returns the additional authenticated data of the content key encrypted by the sender for the recipient.
+func keyData(channel network.Channel, senderID flow.Identifier, recipientID flow.Identifier) []byte {
+	data := append([]byte(channel), senderID[:]...)
+	return append(data, recipientID[:]...)
+}
+
+// contentData returns the additional authenticated data of the payload encrypted by the sender with the given header.
+func contentData(channel network.Channel, senderID flow.Identifier, header []byte) []byte {
+	data := append([]byte(channel), senderID[:]...)
+	return append(data, header...)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("could not create cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("could not create cipher: %w", err)
+	}
+	return aead, nil
+}
+
+func randomNonce() ([]byte, error) {
+	nonce := make([]byte, nonceSize)
+	_, err := io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate nonce: %w", err)
+	}
+	return nonce, nil
+}
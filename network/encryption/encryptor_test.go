@@ -0,0 +1,129 @@
+package encryption_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/id"
+	"github.com/onflow/flow-go/network/encryption"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestSealOpen tests that a payload encrypted for some recipients can be decrypted by each of them, and by no other node.
+func TestSealOpen(t *testing.T) {
+	for _, algorithm := range []crypto.SigningAlgorithm{crypto.ECDSAP256, crypto.ECDSASecp256k1} {
+		t.Run(algorithm.String(), func(t *testing.T) {
+			ids, encryptors := encryptorsFixture(t, 3, algorithm)
+			payload := []byte("dkg private share")
+
+			sealed, err := encryptors[0].Seal(engine.DKGCommittee, payload, ids.NodeIDs()[1:])
+			require.NoError(t, err)
+			assert.NotContains(t, string(sealed), string(payload))
+
+			for _, encryptor := range encryptors[1:] {
+				opened, err := encryptor.Open(engine.DKGCommittee, ids[0].NodeID, sealed)
+				require.NoError(t, err)
+				assert.Equal(t, payload, opened)
+			}
+
+			sealed, err = encryptors[0].Seal(engine.DKGCommittee, payload, ids.NodeIDs()[1:2])
+			require.NoError(t, err)
+			_, err = encryptors[2].Open(engine.DKGCommittee, ids[0].NodeID, sealed)
+			assert.ErrorIs(t, err, encryption.ErrNotRecipient)
+
+			// a payload is encrypted with a fresh content key every time
+			resealed, err := encryptors[0].Seal(engine.DKGCommittee, payload, ids.NodeIDs()[1:2])
+			require.NoError(t, err)
+			assert.NotEqual(t, sealed, resealed)
+		})
+	}
+}
+
+// TestAuthentication tests that a payload is only decrypted with the channel and the sender it was encrypted with,
+// and if it was not tampered with.
+func TestAuthentication(t *testing.T) {
+	ids, encryptors := encryptorsFixture(t, 3, crypto.ECDSAP256)
+	payload := []byte("dkg private share")
+
+	sealed, err := encryptors[0].Seal(engine.DKGCommittee, payload, ids.NodeIDs()[1:])
+	require.NoError(t, err)
+
+	_, err = encryptors[1].Open(engine.TestNetwork, ids[0].NodeID, sealed)
+	assert.Error(t, err, "payload opened on another channel")
+
+	_, err = encryptors[1].Open(engine.DKGCommittee, ids[2].NodeID, sealed)
+	assert.Error(t, err, "payload opened from another sender")
+
+	for _, index := range []int{0, 1, 10, len(sealed) - 1} {
+		tampered := append([]byte{}, sealed...)
+		tampered[index] ^= 0x01
+		_, err = encryptors[1].Open(engine.DKGCommittee, ids[0].NodeID, tampered)
+		assert.Error(t, err, "tampered payload opened, byte %d", index)
+	}
+
+	_, err = encryptors[1].Open(engine.DKGCommittee, ids[0].NodeID, sealed[:len(sealed)/2])
+	assert.Error(t, err)
+	_, err = encryptors[1].Open(engine.DKGCommittee, ids[0].NodeID, nil)
+	assert.Error(t, err)
+
+	_, err = encryptors[0].Seal(engine.DKGCommittee, payload, []flow.Identifier{unittest.IdentifierFixture()})
+	assert.Error(t, err, "payload sealed for unknown node")
+}
+
+// TestKeyRotation tests that the keys shared with a node are derived again once its networking key changes.
+func TestKeyRotation(t *testing.T) {
+	ids, encryptors := encryptorsFixture(t, 2, crypto.ECDSAP256)
+	payload := []byte("dkg private share")
+
+	sealed, err := encryptors[0].Seal(engine.DKGCommittee, payload, ids.NodeIDs()[1:])
+	require.NoError(t, err)
+	_, err = encryptors[1].Open(engine.DKGCommittee, ids[0].NodeID, sealed)
+	require.NoError(t, err)
+
+	// the second node rotates its networking key
+	key := unittest.NetworkingPrivKeyFixture()
+	ids[1].NetworkPubKey = key.PublicKey()
+	rotated, err := encryption.NewEncryptor(ids[1].NodeID, key, id.NewFixedIdentityProvider(ids))
+	require.NoError(t, err)
+
+	sealed, err = encryptors[0].Seal(engine.DKGCommittee, payload, ids.NodeIDs()[1:])
+	require.NoError(t, err)
+	opened, err := rotated.Open(engine.DKGCommittee, ids[0].NodeID, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, payload, opened)
+
+	_, err = encryptors[1].Open(engine.DKGCommittee, ids[0].NodeID, sealed)
+	assert.Error(t, err, "payload opened with previous networking key")
+}
+
+// encryptorsFixture returns n identities with networking keys of the given algorithm, and their encryptors.
+func encryptorsFixture(t *testing.T, n int, algorithm crypto.SigningAlgorithm) (flow.IdentityList, []*encryption.Encryptor) {
+	ids := make(flow.IdentityList, 0, n)
+	keys := make([]crypto.PrivateKey, 0, n)
+	for i := 0; i < n; i++ {
+		var key crypto.PrivateKey
+		switch algorithm {
+		case crypto.ECDSASecp256k1:
+			key = unittest.PrivateKeyFixture(crypto.ECDSASecp256k1, crypto.KeyGenSeedMinLenECDSASecp256k1)
+		default:
+			key = unittest.NetworkingPrivKeyFixture()
+		}
+		keys = append(keys, key)
+		ids = append(ids, unittest.IdentityFixture(unittest.WithNetworkingKey(key.PublicKey())))
+	}
+
+	provider := id.NewFixedIdentityProvider(ids)
+	encryptors := make([]*encryption.Encryptor, 0, n)
+	for i, key := range keys {
+		encryptor, err := encryption.NewEncryptor(ids[i].NodeID, key, provider)
+		require.NoError(t, err)
+		encryptors = append(encryptors, encryptor)
+	}
+
+	return ids, encryptors
+}
@@ -33,3 +33,10 @@ type Engine interface {
 type MessageProcessor interface {
 	Process(channel Channel, originID flow.Identifier, message interface{}) error
 }
+
+// RequestProcessor is implemented by the message processors which serve the requests sent with Conduit.Request
+// on their channel. The returned response is sent back to the requester, and a returned error is reported to
+// the requester as a RemoteRequestError.
+type RequestProcessor interface {
+	ProcessRequest(channel Channel, originID flow.Identifier, request interface{}) (interface{}, error)
+}
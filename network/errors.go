@@ -1,7 +1,84 @@
 package network
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	EmptyTargetList = errors.New("target list empty")
 )
+
+// RemoteRequestError is the error returned to the sender of a request if the recipient could not serve it,
+// e.g. because no engine serves requests on the channel of the request.
+type RemoteRequestError struct {
+	Reason string
+}
+
+// NewRemoteRequestError creates a RemoteRequestError with the reason reported by the recipient of a request.
+func NewRemoteRequestError(reason string) error {
+	return RemoteRequestError{
+		Reason: reason,
+	}
+}
+
+func (e RemoteRequestError) Error() string {
+	return fmt.Sprintf("remote node could not serve request: %s", e.Reason)
+}
+
+// IsRemoteRequestError returns whether the given error is RemoteRequestError
+func IsRemoteRequestError(err error) bool {
+	var e RemoteRequestError
+	return errors.As(err, &e)
+}
+
+// RequestNotSupportedError is the error returned to the sender of a request if the recipient does not support the
+// request protocol, e.g. because it runs an older version of the software. The sender may fall back to sending the
+// request as a message on the channel.
+type RequestNotSupportedError struct {
+	Err error
+}
+
+// NewRequestNotSupportedError creates a RequestNotSupportedError with the error of negotiating the request protocol.
+func NewRequestNotSupportedError(err error) error {
+	return RequestNotSupportedError{
+		Err: err,
+	}
+}
+
+func (e RequestNotSupportedError) Unwrap() error {
+	return e.Err
+}
+
+func (e RequestNotSupportedError) Error() string {
+	return fmt.Sprintf("remote node does not support the request protocol: %v", e.Err)
+}
+
+// IsRequestNotSupportedError returns whether the given error is RequestNotSupportedError
+func IsRequestNotSupportedError(err error) bool {
+	var e RequestNotSupportedError
+	return errors.As(err, &e)
+}
+
+// QueueFullError is the error returned to the sender of a message dropped as the outbound queue of the message is full.
+// It signals backpressure from the networking layer: the sender should slow down, or skip sending messages of low priority.
+type QueueFullError struct {
+	Channel Channel
+}
+
+// NewQueueFullError creates a QueueFullError for a message on the given channel.
+func NewQueueFullError(channel Channel) error {
+	return QueueFullError{
+		Channel: channel,
+	}
+}
+
+func (e QueueFullError) Error() string {
+	return fmt.Sprintf("outbound queue is full, message on channel %s dropped", e.Channel)
+}
+
+// IsQueueFullError returns whether the given error is QueueFullError
+func IsQueueFullError(err error) bool {
+	var e QueueFullError
+	return errors.As(err, &e)
+}
@@ -3,6 +3,8 @@
 package network
 
 import (
+	"context"
+
 	"github.com/ipfs/go-datastore"
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/protocol"
@@ -37,6 +39,10 @@ type Middleware interface {
 	// a more efficient candidate.
 	SendDirect(msg *message.Message, targetID flow.Identifier) error
 
+	// SendRequest sends the request msg on a 1-1 direct connection to the target ID, and blocks until the target
+	// responds or the context is done. Requests to the same target share a stream of the request protocol.
+	SendRequest(ctx context.Context, msg *message.Message, targetID flow.Identifier) (*message.Message, error)
+
 	// Publish publishes a message on the channel. It models a distributed broadcast where the message is meant for all or
 	// a many nodes subscribing to the channel. It does not guarantee the delivery though, and operates on a best
 	// effort.
@@ -59,6 +65,9 @@ type Middleware interface {
 	NewPingService(pingProtocol protocol.ID, provider PingInfoProvider) PingService
 
 	IsConnected(nodeID flow.Identifier) (bool, error)
+
+	// ReportMisbehavior penalizes the node with the given ID for the given misbehavior.
+	ReportMisbehavior(originID flow.Identifier, misbehavior Misbehavior)
 }
 
 // Overlay represents the interface that middleware uses to interact with the
@@ -74,6 +83,9 @@ type Overlay interface {
 	Identity(peer.ID) (*flow.Identity, bool)
 
 	Receive(nodeID flow.Identifier, msg *message.Message) error
+
+	// ReceiveRequest serves the request received from the given node, and returns the response to send back.
+	ReceiveRequest(nodeID flow.Identifier, msg *message.Message) (*message.Message, error)
 }
 
 // Connection represents an interface to read from & write to a connection.
@@ -0,0 +1,26 @@
+package network
+
+// Misbehavior is the type of misbehavior of a remote node, which is penalized by the peer scoring
+// of the networking layer.
+type Misbehavior string
+
+func (m Misbehavior) String() string {
+	return string(m)
+}
+
+const (
+	// InvalidMessage is reported for messages which cannot be decoded, or which are rejected by the
+	// engines as malformed.
+	InvalidMessage Misbehavior = "invalid_message"
+
+	// UnauthorizedSender is reported for messages received from nodes which are not allowed to send
+	// them, e.g. unstaked nodes on channels reserved for staked nodes.
+	UnauthorizedSender Misbehavior = "unauthorized_sender"
+
+	// OversizedMessage is reported for messages exceeding the maximum permissible message size.
+	OversizedMessage Misbehavior = "oversized_message"
+
+	// ProtocolViolation is reported by engines for messages which are well-formed, but violate the
+	// protocol, e.g. a response to a request that was never sent.
+	ProtocolViolation Misbehavior = "protocol_violation"
+)
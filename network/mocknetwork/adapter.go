@@ -3,6 +3,8 @@
 package mocknetwork
 
 import (
+	context "context"
+
 	flow "github.com/onflow/flow-go/model/flow"
 	mock "github.com/stretchr/testify/mock"
 
@@ -56,6 +58,34 @@ func (_m *Adapter) PublishOnChannel(_a0 network.Channel, _a1 interface{}, _a2 ..
 	return r0
 }
 
+// ReportMisbehaviorOnChannel provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Adapter) ReportMisbehaviorOnChannel(_a0 network.Channel, _a1 flow.Identifier, _a2 network.Misbehavior) {
+	_m.Called(_a0, _a1, _a2)
+}
+
+// RequestOnChannel provides a mock function with given fields: _a0, _a1, _a2, _a3
+func (_m *Adapter) RequestOnChannel(_a0 context.Context, _a1 network.Channel, _a2 interface{}, _a3 flow.Identifier) (interface{}, error) {
+	ret := _m.Called(_a0, _a1, _a2, _a3)
+
+	var r0 interface{}
+	if rf, ok := ret.Get(0).(func(context.Context, network.Channel, interface{}, flow.Identifier) interface{}); ok {
+		r0 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(interface{})
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, network.Channel, interface{}, flow.Identifier) error); ok {
+		r1 = rf(_a0, _a1, _a2, _a3)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UnRegisterChannel provides a mock function with given fields: channel
 func (_m *Adapter) UnRegisterChannel(channel network.Channel) error {
 	ret := _m.Called(channel)
@@ -3,6 +3,8 @@
 package mocknetwork
 
 import (
+	context "context"
+
 	datastore "github.com/ipfs/go-datastore"
 	flow "github.com/onflow/flow-go/model/flow"
 
@@ -128,6 +130,11 @@ func (_m *Middleware) Ready() <-chan struct{} {
 	return r0
 }
 
+// ReportMisbehavior provides a mock function with given fields: originID, misbehavior
+func (_m *Middleware) ReportMisbehavior(originID flow.Identifier, misbehavior network.Misbehavior) {
+	_m.Called(originID, misbehavior)
+}
+
 // SendDirect provides a mock function with given fields: msg, targetID
 func (_m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier) error {
 	ret := _m.Called(msg, targetID)
@@ -142,6 +149,29 @@ func (_m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier)
 	return r0
 }
 
+// SendRequest provides a mock function with given fields: ctx, msg, targetID
+func (_m *Middleware) SendRequest(ctx context.Context, msg *message.Message, targetID flow.Identifier) (*message.Message, error) {
+	ret := _m.Called(ctx, msg, targetID)
+
+	var r0 *message.Message
+	if rf, ok := ret.Get(0).(func(context.Context, *message.Message, flow.Identifier) *message.Message); ok {
+		r0 = rf(ctx, msg, targetID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*message.Message)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *message.Message, flow.Identifier) error); ok {
+		r1 = rf(ctx, msg, targetID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SetOverlay provides a mock function with given fields: _a0
 func (_m *Middleware) SetOverlay(_a0 network.Overlay) {
 	_m.Called(_a0)
@@ -69,6 +69,29 @@ func (_m *Overlay) Receive(nodeID flow.Identifier, msg *message.Message) error {
 	return r0
 }
 
+// ReceiveRequest provides a mock function with given fields: nodeID, msg
+func (_m *Overlay) ReceiveRequest(nodeID flow.Identifier, msg *message.Message) (*message.Message, error) {
+	ret := _m.Called(nodeID, msg)
+
+	var r0 *message.Message
+	if rf, ok := ret.Get(0).(func(flow.Identifier, *message.Message) *message.Message); ok {
+		r0 = rf(nodeID, msg)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*message.Message)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(flow.Identifier, *message.Message) error); ok {
+		r1 = rf(nodeID, msg)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Topology provides a mock function with given fields:
 func (_m *Overlay) Topology() (flow.IdentityList, error) {
 	ret := _m.Called()
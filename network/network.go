@@ -1,6 +1,8 @@
 package network
 
 import (
+	"context"
+
 	"github.com/ipfs/go-datastore"
 	"github.com/libp2p/go-libp2p-core/protocol"
 
@@ -43,6 +45,12 @@ type Adapter interface {
 	// selected from the specified targetIDs.
 	MulticastOnChannel(Channel, interface{}, uint, ...flow.Identifier) error
 
+	// RequestOnChannel sends the request in a reliable way to the given recipient and returns its response.
+	RequestOnChannel(context.Context, Channel, interface{}, flow.Identifier) (interface{}, error)
+
+	// ReportMisbehaviorOnChannel reports a misbehavior of the given node observed on the given channel.
+	ReportMisbehaviorOnChannel(Channel, flow.Identifier, Misbehavior)
+
 	// UnRegisterChannel unregisters the engine for the specified channel. The engine will no longer be able to send or
 	// receive messages from that channel.
 	UnRegisterChannel(channel Channel) error
@@ -8,18 +8,34 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	flownet "github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/compressor"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
+// compressors are the stream compressors which are supported by the compressed unicast protocols.
+var compressors = map[string]flownet.Compressor{
+	"gzip":   compressor.GzipStreamCompressor{},
+	"zstd":   compressor.ZstdStreamCompressor{},
+	"snappy": compressor.SnappyStreamCompressor{},
+}
+
 // TestHappyPath evaluates reading from a compressed stream retrieves what originally has been written on it.
 func TestHappyPath(t *testing.T) {
+	for name, comp := range compressors {
+		t.Run(name, func(t *testing.T) {
+			testHappyPath(t, comp)
+		})
+	}
+}
+
+func testHappyPath(t *testing.T, comp flownet.Compressor) {
 	text := "hello world, hello world!"
 	textByte := []byte(text)
 	textByteLen := len(textByte)
 
 	// creates a pair of compressed streams
-	mca, _, mcb, _ := newCompressedStreamPair(t)
+	mca, _, mcb, _ := newCompressedStreamPair(t, comp)
 
 	// writes on stream mca
 	writeWG := sync.WaitGroup{}
@@ -54,13 +70,21 @@ func TestHappyPath(t *testing.T) {
 // TestUnhappyPath evaluates that sending uncompressed data to the compressed end of a stream results
 // in an error at the reader side.
 func TestUnhappyPath(t *testing.T) {
+	for name, comp := range compressors {
+		t.Run(name, func(t *testing.T) {
+			testUnhappyPath(t, comp)
+		})
+	}
+}
+
+func testUnhappyPath(t *testing.T, comp flownet.Compressor) {
 	text := "hello world, hello world!"
 	textByte := []byte(text)
 	textByteLen := len(textByte)
 
 	// sa is the underlying stream of sender (non-compressed)
 	// mcb is the compressed stream of receiver
-	_, sa, mcb, _ := newCompressedStreamPair(t)
+	_, sa, mcb, _ := newCompressedStreamPair(t, comp)
 
 	// writes on sa (uncompressed)
 	writeWG := sync.WaitGroup{}
@@ -110,13 +134,13 @@ func newStreamPair() (*mockStream, *mockStream) {
 
 // newCompressedStreamPair is a test helper that creates a pair of compressed streams a and b such that
 // a reads what b writes and b reads what a writes.
-func newCompressedStreamPair(t *testing.T) (*compressedStream, *mockStream, *compressedStream, *mockStream) {
+func newCompressedStreamPair(t *testing.T, comp flownet.Compressor) (*compressedStream, *mockStream, *compressedStream, *mockStream) {
 	sa, sb := newStreamPair()
 
-	mca, err := NewCompressedStream(sa, compressor.GzipStreamCompressor{})
+	mca, err := NewCompressedStream(sa, comp)
 	require.NoError(t, err)
 
-	mcb, err := NewCompressedStream(sb, compressor.GzipStreamCompressor{})
+	mcb, err := NewCompressedStream(sb, comp)
 	require.NoError(t, err)
 
 	return mca, sa, mcb, sb
@@ -89,6 +89,24 @@ func (c *Conduit) Multicast(event interface{}, num uint, targetIDs ...flow.Ident
 	return c.adapter.MulticastOnChannel(c.channel, event, num, targetIDs...)
 }
 
+// Request sends the request in a reliable way to the given recipient, and blocks until the recipient responds
+// or the context is done.
+func (c *Conduit) Request(ctx context.Context, request interface{}, targetID flow.Identifier) (interface{}, error) {
+	if c.ctx.Err() != nil {
+		return nil, fmt.Errorf("conduit for channel %s closed", c.channel)
+	}
+	return c.adapter.RequestOnChannel(ctx, c.channel, request, targetID)
+}
+
+// ReportMisbehavior reports a misbehavior of the given node observed on the channel of this conduit.
+// The report is dropped if the conduit is closed.
+func (c *Conduit) ReportMisbehavior(originID flow.Identifier, misbehavior network.Misbehavior) {
+	if c.ctx.Err() != nil {
+		return
+	}
+	c.adapter.ReportMisbehaviorOnChannel(c.channel, originID, misbehavior)
+}
+
 func (c *Conduit) Close() error {
 	if c.ctx.Err() != nil {
 		return fmt.Errorf("conduit for channel %s already closed", c.channel)
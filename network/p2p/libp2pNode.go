@@ -272,6 +272,11 @@ func (n *Node) WithDefaultUnicastProtocol(defaultHandler libp2pnet.StreamHandler
 	return nil
 }
 
+// ConnectedPeers returns the peers this node currently has at least one connection to.
+func (n *Node) ConnectedPeers() peer.IDSlice {
+	return n.host.Network().Peers()
+}
+
 // IsConnected returns true is address is a direct peer of this node else false
 func (n *Node) IsConnected(peerID peer.ID) (bool, error) {
 	isConnected := n.host.Network().Connectedness(peerID) == libp2pnet.Connected
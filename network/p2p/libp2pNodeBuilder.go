@@ -28,6 +28,7 @@ import (
 	"github.com/onflow/flow-go/module/id"
 	flownet "github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/p2p/keyutils"
+	"github.com/onflow/flow-go/network/p2p/scoring"
 	"github.com/onflow/flow-go/network/p2p/unicast"
 )
 
@@ -36,6 +37,8 @@ type LibP2PFactoryFunc func(context.Context) (*Node, error)
 
 // DefaultLibP2PNodeFactory returns a LibP2PFactoryFunc which generates the libp2p host initialized with the
 // default options for the host, the pubsub and the ping service.
+// If peerScores is not nil, the connections to and from peers blocked by the peer scoring are rejected, and
+// the scores are fed into the peer scoring of GossipSub if the peer scoring is enforced.
 func DefaultLibP2PNodeFactory(
 	log zerolog.Logger,
 	address string,
@@ -45,14 +48,18 @@ func DefaultLibP2PNodeFactory(
 	metrics module.NetworkMetrics,
 	resolver madns.BasicResolver,
 	role string,
+	peerScores *scoring.Registry,
 ) LibP2PFactoryFunc {
 
 	return func(ctx context.Context) (*Node, error) {
 		connManager := NewConnManager(log, metrics)
 		connGater := NewConnGater(log, func(pid peer.ID) bool {
 			_, found := idProvider.ByPeerID(pid)
+			if !found {
+				return false
+			}
 
-			return found
+			return peerScores == nil || !peerScores.IsBlocked(pid)
 		})
 
 		builder := NewNodeBuilder(log, address, flowKey, sporkId).
@@ -64,6 +71,10 @@ func DefaultLibP2PNodeFactory(
 			}).
 			SetPubSub(pubsub.NewGossipSub)
 
+		if peerScores != nil && peerScores.Config().Enforce {
+			builder.SetPeerScores(peerScores)
+		}
+
 		if role != "ghost" {
 			r, _ := flow.ParseRole(role)
 			builder.SetSubscriptionFilter(NewRoleBasedFilter(r, idProvider))
@@ -87,6 +98,7 @@ type NodeBuilder interface {
 	SetConnectionGater(connmgr.ConnectionGater) NodeBuilder
 	SetRoutingSystem(func(context.Context, host.Host) (routing.Routing, error)) NodeBuilder
 	SetPubSub(func(context.Context, host.Host, ...pubsub.Option) (*pubsub.PubSub, error)) NodeBuilder
+	SetPeerScores(*scoring.Registry) NodeBuilder
 	Build(context.Context) (*Node, error)
 }
 
@@ -101,6 +113,7 @@ type LibP2PNodeBuilder struct {
 	connGater          connmgr.ConnectionGater
 	routingFactory     func(context.Context, host.Host) (routing.Routing, error)
 	pubsubFactory      func(context.Context, host.Host, ...pubsub.Option) (*pubsub.PubSub, error)
+	peerScores         *scoring.Registry
 }
 
 func NewNodeBuilder(
@@ -147,6 +160,12 @@ func (builder *LibP2PNodeBuilder) SetPubSub(f func(context.Context, host.Host, .
 	return builder
 }
 
+// SetPeerScores feeds the scores of the given registry into the peer scoring of the pubsub.
+func (builder *LibP2PNodeBuilder) SetPeerScores(peerScores *scoring.Registry) NodeBuilder {
+	builder.peerScores = peerScores
+	return builder
+}
+
 func (builder *LibP2PNodeBuilder) Build(ctx context.Context) (*Node, error) {
 	if builder.routingFactory == nil {
 		return nil, errors.New("routing factory is not set")
@@ -198,6 +217,10 @@ func (builder *LibP2PNodeBuilder) Build(ctx context.Context) (*Node, error) {
 		psOpts = append(psOpts, pubsub.WithSubscriptionFilter(builder.subscriptionFilter))
 	}
 
+	if builder.peerScores != nil {
+		psOpts = append(psOpts, builder.peerScores.PubSubOption())
+	}
+
 	pubSub, err := builder.pubsubFactory(ctx, host, psOpts...)
 
 	if err != nil {
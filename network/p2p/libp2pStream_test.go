@@ -131,6 +131,24 @@ func TestCreateStream_WithPreferredGzipUnicast(t *testing.T) {
 		unicast.FlowGzipProtocolId(sporkId))
 }
 
+// TestCreateStream_WithPreferredZstdUnicast evaluates correctness of creating zstd-compressed tcp unicast streams between two libp2p nodes.
+func TestCreateStream_WithPreferredZstdUnicast(t *testing.T) {
+	sporkId := unittest.IdentifierFixture()
+	testCreateStream(t,
+		sporkId,
+		[]unicast.ProtocolName{unicast.ZstdCompressionUnicast},
+		unicast.FlowZstdProtocolId(sporkId))
+}
+
+// TestCreateStream_WithPreferredSnappyUnicast evaluates correctness of creating snappy-compressed tcp unicast streams between two libp2p nodes.
+func TestCreateStream_WithPreferredSnappyUnicast(t *testing.T) {
+	sporkId := unittest.IdentifierFixture()
+	testCreateStream(t,
+		sporkId,
+		[]unicast.ProtocolName{unicast.SnappyCompressionUnicast},
+		unicast.FlowSnappyProtocolId(sporkId))
+}
+
 // testCreateStreams checks if a new streams of "preferred" type is created each time when CreateStream is called and an existing stream is not
 // reused. The "preferred" stream type is the one with the largest index in `unicasts` list.
 // To check that the streams are of "preferred" type, it evaluates the protocol id of established stream against the input `protocolID`.
@@ -254,6 +272,52 @@ func TestCreateStream_FallBack(t *testing.T) {
 	}
 }
 
+// TestCreateStream_FallBackToLessPreferred checks two libp2p nodes negotiate the most preferred unicast protocol among the
+// ones supported by both nodes. To do this, a node preferring zstd over gzip-compressed unicast tries creating streams to
+// another node that only supports gzip-compressed unicast, and the test evaluates that the established streams are gzip-compressed.
+func TestCreateStream_FallBackToLessPreferred(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sporkId := unittest.IdentifierFixture()
+	thisNode, _ := nodeFixture(t,
+		ctx,
+		sporkId,
+		"test_create_stream_fallback_to_less_preferred",
+		withPreferredUnicasts([]unicast.ProtocolName{unicast.GzipCompressionUnicast, unicast.ZstdCompressionUnicast}))
+	otherNode, otherId := nodeFixture(t,
+		ctx,
+		sporkId,
+		"test_create_stream_fallback_to_less_preferred",
+		withPreferredUnicasts([]unicast.ProtocolName{unicast.GzipCompressionUnicast}))
+
+	defer stopNodes(t, []*Node{thisNode, otherNode})
+
+	gzipProtocolId := unicast.FlowGzipProtocolId(sporkId)
+	zstdProtocolId := unicast.FlowZstdProtocolId(sporkId)
+
+	streamCount := 10
+	var streams []network.Stream
+	for i := 0; i < streamCount; i++ {
+		pInfo, err := PeerAddressInfo(otherId)
+		require.NoError(t, err)
+		thisNode.host.Peerstore().AddAddrs(pInfo.ID, pInfo.Addrs, peerstore.AddressTTL)
+
+		anotherStream, err := thisNode.CreateStream(ctx, pInfo.ID)
+		require.NoError(t, err)
+		require.NotNil(t, anotherStream)
+
+		// streams must be created on gzip, since the other node does not support zstd
+		require.Equal(t, i+1, CountStream(thisNode.host, otherNode.host.ID(), gzipProtocolId, network.DirOutbound))
+		require.Equal(t, 0, CountStream(thisNode.host, otherNode.host.ID(), zstdProtocolId, network.DirOutbound))
+		streams = append(streams, anotherStream)
+	}
+
+	for _, s := range streams {
+		require.NoError(t, s.Close())
+	}
+}
+
 // TestCreateStreamIsConcurrencySafe tests that the CreateStream is concurrency safe
 func TestCreateStreamIsConcurrencySafe(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -361,6 +425,18 @@ func TestUnicastOverStream_WithGzipStreamCompression(t *testing.T) {
 	testUnicastOverStream(t, withPreferredUnicasts([]unicast.ProtocolName{unicast.GzipCompressionUnicast}))
 }
 
+// TestUnicastOverStream_WithZstdStreamCompression checks two nodes can send and receive unicast messages on zstd compressed streams
+// when both nodes have zstd stream compression enabled.
+func TestUnicastOverStream_WithZstdStreamCompression(t *testing.T) {
+	testUnicastOverStream(t, withPreferredUnicasts([]unicast.ProtocolName{unicast.ZstdCompressionUnicast}))
+}
+
+// TestUnicastOverStream_WithSnappyStreamCompression checks two nodes can send and receive unicast messages on snappy compressed streams
+// when both nodes have snappy stream compression enabled.
+func TestUnicastOverStream_WithSnappyStreamCompression(t *testing.T) {
+	testUnicastOverStream(t, withPreferredUnicasts([]unicast.ProtocolName{unicast.SnappyCompressionUnicast}))
+}
+
 // testUnicastOverStream sends a message from node 1 to node 2 and then from node 2 to node 1 over a unicast stream.
 func testUnicastOverStream(t *testing.T, opts ...nodeFixtureParameterOption) {
 	ctx, cancel := context.WithCancel(context.Background())
@@ -17,6 +17,7 @@ import (
 	"github.com/libp2p/go-libp2p-core/peer"
 	"github.com/libp2p/go-libp2p-core/peerstore"
 	"github.com/libp2p/go-libp2p-core/protocol"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
 	"github.com/rs/zerolog"
 
 	"github.com/onflow/flow-go/engine"
@@ -26,8 +27,12 @@ import (
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/module/metrics"
 	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
 	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/p2p/ratelimit"
+	"github.com/onflow/flow-go/network/p2p/scoring"
 	"github.com/onflow/flow-go/network/p2p/unicast"
+	"github.com/onflow/flow-go/network/queue"
 	"github.com/onflow/flow-go/network/validator"
 	psValidator "github.com/onflow/flow-go/network/validator/pubsub"
 	_ "github.com/onflow/flow-go/utils/binstat"
@@ -57,8 +62,14 @@ const (
 
 	// maximum time to wait for a unicast request to complete for large message size
 	LargeMsgUnicastTimeout = 1000 * time.Second
+
+	// maximum time a published message waits for the egress rate limit of its channel before it is dropped
+	DefaultPublishEgressTimeout = 5 * time.Second
 )
 
+// outboundQueuePruneInterval is the interval at which idle outbound queues of direct messages are removed
+const outboundQueuePruneInterval = time.Minute
+
 var _ network.Middleware = (*Middleware)(nil)
 
 // Middleware handles the input & output on the direct connections we have to
@@ -85,6 +96,16 @@ type Middleware struct {
 	unicastMessageTimeout      time.Duration
 	idTranslator               IDTranslator
 	previousProtocolStatePeers []peer.AddrInfo
+	peerScores                 *scoring.Registry
+	egressLimiter              *ratelimit.EgressLimiter
+	recorder                   *capture.Recorder
+	requestStreamsLock         sync.Mutex
+	requestStreams             map[peer.ID]*requestStream // outbound streams of the request protocol
+	inboundRequestStreams      map[peer.ID]int            // number of incoming streams of the request protocol
+	outboundQueueConfig        *queue.OutboundQueueConfig
+	outboundQueuesLock         sync.Mutex
+	outboundQueues             map[peer.ID]*queue.OutboundQueue // outbound queues of direct messages to each peer
+	publishQueue               *queue.OutboundQueue             // outbound queue of published messages
 	component.Component
 }
 
@@ -108,6 +129,42 @@ func WithPeerManager(peerManagerFunc PeerManagerFactoryFunc) MiddlewareOption {
 	}
 }
 
+// WithPeerScores enables the scoring of remote peers. Misbehaviors detected by the middleware, or reported
+// by the engines, are penalized in the given registry, and peers are disconnected once they are blocked.
+func WithPeerScores(peerScores *scoring.Registry) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.peerScores = peerScores
+	}
+}
+
+// WithEgressLimiter enables the egress rate limits of channels. Outbound messages wait for the limit of their
+// channel, and are dropped if the wait exceeds the timeout of the message.
+func WithEgressLimiter(egressLimiter *ratelimit.EgressLimiter) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.egressLimiter = egressLimiter
+	}
+}
+
+// WithMessageCapture enables the capture of messages. Messages sent by this node, and messages received by this node
+// which pass the message validators, are written to the given recorder.
+func WithMessageCapture(recorder *capture.Recorder) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.recorder = recorder
+	}
+}
+
+// WithOutboundQueues enables the outbound queues of messages. Instead of being sent on the goroutine of the caller,
+// direct messages are inserted into a bounded priority queue of their recipient, and published messages into a
+// bounded priority queue shared by all channels, from which they are sent by workers in the order of their priority.
+// Messages dropped as their queue is full are reported to the sender as QueueFullError, while failures to send
+// queued messages are only logged.
+// Idle queues of direct messages are removed periodically, and as soon as their recipient disconnects.
+func WithOutboundQueues(config queue.OutboundQueueConfig) MiddlewareOption {
+	return func(mw *Middleware) {
+		mw.outboundQueueConfig = &config
+	}
+}
+
 // NewMiddleware creates a new middleware instance
 // libP2PNodeFactory is the factory used to create a LibP2PNode
 // flowID is this node's Flow ID
@@ -144,6 +201,9 @@ func NewMiddleware(
 		unicastMessageTimeout: unicastMessageTimeout,
 		peerManagerFactory:    nil,
 		idTranslator:          idTranslator,
+		requestStreams:        make(map[peer.ID]*requestStream),
+		inboundRequestStreams: make(map[peer.ID]int),
+		outboundQueues:        make(map[peer.ID]*queue.OutboundQueue),
 	}
 
 	for _, opt := range opts {
@@ -163,6 +223,24 @@ func NewMiddleware(
 
 			<-ctx.Done()
 			mw.stop()
+		}).
+		AddWorker(func(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+			ready()
+			if mw.outboundQueueConfig == nil {
+				return
+			}
+
+			// idle outbound queues are pruned periodically, in addition to the ones pruned as their peer disconnects
+			ticker := time.NewTicker(outboundQueuePruneInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					mw.pruneOutboundQueues()
+				}
+			}
 		}).Build()
 
 	mw.Component = cm
@@ -257,6 +335,14 @@ func (m *Middleware) start(ctx context.Context) error {
 		return errors.New("overlay must be configured by calling SetOverlay before middleware can be started")
 	}
 
+	if m.outboundQueueConfig != nil {
+		err := m.outboundQueueConfig.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid outbound queue config: %w", err)
+		}
+		m.publishQueue = queue.NewOutboundQueue(*m.outboundQueueConfig, m.sendQueued, m.metrics)
+	}
+
 	libP2PNode, err := m.libP2PNodeFactory(ctx)
 	if err != nil {
 		return fmt.Errorf("could not create libp2p node: %w", err)
@@ -267,6 +353,10 @@ func (m *Middleware) start(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("could not register preferred unicast protocols on libp2p node: %w", err)
 	}
+	m.libP2PNode.host.SetStreamHandler(unicast.RequestProtocolId(m.rootBlockID), m.handleIncomingRequestStream)
+	m.libP2PNode.host.Network().Notify(&libp2pnetwork.NotifyBundle{
+		DisconnectedF: m.disconnected,
+	})
 
 	m.UpdateNodeAddresses()
 
@@ -297,6 +387,9 @@ func (m *Middleware) stop() {
 		m.log.Debug().Msg("peer manager successfully stopped")
 	}
 
+	// drops the queued outbound messages, and waits for the messages being sent
+	m.closeOutboundQueues()
+
 	// stops libp2p
 	done, err := m.libP2PNode.Stop()
 	if err != nil {
@@ -310,13 +403,29 @@ func (m *Middleware) stop() {
 	m.wg.Wait()
 }
 
+// disconnected is called by libp2p when a connection closes. Once the last connection to a peer is closed, the state
+// kept for the peer is dropped.
+func (m *Middleware) disconnected(n libp2pnetwork.Network, c libp2pnetwork.Conn) {
+	peerID := c.RemotePeer()
+	if len(n.ConnsToPeer(peerID)) > 0 {
+		return
+	}
+	m.dropRequestStream(peerID)
+	if m.outboundQueueConfig != nil {
+		m.pruneOutboundQueues(peerID)
+	}
+}
+
 // SendDirect sends msg on a 1-1 direct connection to the target ID. It models a guaranteed delivery asynchronous
 // direct one-to-one connection on the underlying network. No intermediate node on the overlay is utilized
 // as the router.
 //
 // Dispatch should be used whenever guaranteed delivery to a specific target is required. Otherwise, Publish is
 // a more efficient candidate.
-func (m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier) (err error) {
+//
+// If the outbound queues are enabled, the message is inserted into the outbound queue of the target, and sent
+// asynchronously.
+func (m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier) error {
 	// translates identifier to peer id
 	peerID, err := m.idTranslator.GetPeerID(targetID)
 	if err != nil {
@@ -331,8 +440,33 @@ func (m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier)
 		return fmt.Errorf("message size %d exceeds configured max message size %d", msg.Size(), maxMsgSize)
 	}
 
+	if m.outboundQueueConfig == nil {
+		return m.sendDirect(msg, targetID, peerID)
+	}
+
+	err = m.enqueueDirect(peerID, &outboundMessage{
+		msg:  msg,
+		size: msg.Size(),
+		send: func() error {
+			return m.sendDirect(msg, targetID, peerID)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not send message to %s: %w", targetID, err)
+	}
+
+	return nil
+}
+
+// sendDirect sends msg on a new stream to the given peer, on the goroutine of the caller.
+func (m *Middleware) sendDirect(msg *message.Message, targetID flow.Identifier, peerID peer.ID) (err error) {
 	maxTimeout := m.unicastMaxMsgDuration(msg)
 
+	err = m.waitEgress(network.Channel(msg.ChannelID), msg.Size(), maxTimeout)
+	if err != nil {
+		return fmt.Errorf("could not send message to %s: %w", targetID, err)
+	}
+
 	m.metrics.DirectMessageStarted(msg.ChannelID)
 	defer m.metrics.DirectMessageFinished(msg.ChannelID)
 
@@ -367,6 +501,8 @@ func (m *Middleware) SendDirect(msg *message.Message, targetID flow.Identifier)
 
 			// OneToOne communication metrics are reported with topic OneToOne
 			m.metrics.NetworkMessageSent(msg.Size(), metrics.ChannelOneToOne, msg.Type)
+			m.metrics.UnicastBytesSent(msg.Size(), msg.ChannelID, peerID.String(), m.peerRole(peerID))
+			m.capture(capture.Outbound, msg)
 		} else {
 			resetErr := stream.Reset()
 			if resetErr != nil {
@@ -475,6 +611,7 @@ func (m *Middleware) handleIncomingStream(s libp2pnetwork.Stream) {
 				Str("channel", msg.ChannelID).
 				Int("maxSize", maxSize).
 				Msg("received message exceeded permissible message maxSize")
+			m.reportPeer(s.Conn().RemotePeer(), network.OversizedMessage)
 			return
 		}
 
@@ -484,6 +621,7 @@ func (m *Middleware) handleIncomingStream(s libp2pnetwork.Stream) {
 
 			// log metrics with the channel name as OneToOne
 			m.metrics.NetworkMessageReceived(msg.Size(), metrics.ChannelOneToOne, msg.Type)
+			m.metrics.UnicastBytesReceived(msg.Size(), msg.ChannelID, s.Conn().RemotePeer().String(), m.peerRole(s.Conn().RemotePeer()))
 			m.processAuthenticatedMessage(msg, s.Conn().RemotePeer())
 		}(&msg)
 	}
@@ -499,7 +637,7 @@ func (m *Middleware) Subscribe(channel network.Channel) error {
 	var validators []psValidator.MessageValidator
 	if !engine.PublicChannels().Contains(channel) {
 		// for channels used by the staked nodes, add the topic validator to filter out messages from non-staked nodes
-		validators = append(validators, psValidator.StakedValidator(m.ov.Identity))
+		validators = append(validators, m.reportRejected(psValidator.StakedValidator(m.ov.Identity), network.UnauthorizedSender))
 	}
 
 	s, err := m.libP2PNode.Subscribe(topic, validators...)
@@ -508,7 +646,7 @@ func (m *Middleware) Subscribe(channel network.Channel) error {
 	}
 
 	// create a new readSubscription with the context of the middleware
-	rs := newReadSubscription(m.ctx, s, m.processAuthenticatedMessage, m.log, m.metrics)
+	rs := newReadSubscription(m.ctx, s, m.processAuthenticatedMessage, m.peerRole, m.log, m.metrics)
 	m.wg.Add(1)
 
 	// kick off the receive loop to continuously receive messages
@@ -542,6 +680,7 @@ func (m *Middleware) processAuthenticatedMessage(msg *message.Message, peerID pe
 	flowID, err := m.idTranslator.GetFlowID(peerID)
 	if err != nil {
 		m.log.Warn().Err(err).Msgf("received message from unknown peer %v, and was dropped", peerID.String())
+		m.reportPeer(peerID, network.UnauthorizedSender)
 		return
 	}
 
@@ -568,6 +707,8 @@ func (m *Middleware) processMessage(msg *message.Message) {
 		}
 	}
 
+	m.capture(capture.Inbound, msg)
+
 	// if validation passed, send the message to the overlay
 	err := m.ov.Receive(originID, msg)
 	if err != nil {
@@ -578,6 +719,9 @@ func (m *Middleware) processMessage(msg *message.Message) {
 // Publish publishes a message on the channel. It models a distributed broadcast where the message is meant for all or
 // a many nodes subscribing to the channel. It does not guarantee the delivery though, and operates on a best
 // effort.
+//
+// If the outbound queues are enabled, the message is inserted into the outbound queue of published messages, and
+// published asynchronously.
 func (m *Middleware) Publish(msg *message.Message, channel network.Channel) error {
 	m.log.Debug().Str("channel", channel.String()).Interface("msg", msg).Msg("publishing new message")
 
@@ -596,6 +740,31 @@ func (m *Middleware) Publish(msg *message.Message, channel network.Channel) erro
 		return fmt.Errorf("message size %d exceeds configured max message size %d", msgSize, DefaultMaxPubSubMsgSize)
 	}
 
+	if m.publishQueue == nil {
+		return m.publish(msg, channel, data)
+	}
+
+	err = m.enqueue(m.publishQueue, &outboundMessage{
+		msg:  msg,
+		size: msgSize,
+		send: func() error {
+			return m.publish(msg, channel, data)
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("could not publish the message: %w", err)
+	}
+
+	return nil
+}
+
+// publish publishes the encoded message on the channel, on the goroutine of the caller.
+func (m *Middleware) publish(msg *message.Message, channel network.Channel, data []byte) error {
+	err := m.waitEgress(channel, len(data), DefaultPublishEgressTimeout)
+	if err != nil {
+		return fmt.Errorf("could not publish the message: %w", err)
+	}
+
 	topic := engine.TopicFromChannel(channel, m.rootBlockID)
 
 	// publish the bytes on the topic
@@ -605,10 +774,149 @@ func (m *Middleware) Publish(msg *message.Message, channel network.Channel) erro
 	}
 
 	m.metrics.NetworkMessageSent(len(data), string(channel), msg.Type)
+	m.metrics.PubSubBytesSent(len(data), string(channel))
+	m.capture(capture.Outbound, msg)
 
 	return nil
 }
 
+// capture writes the message to the recorder, if message capture is enabled.
+func (m *Middleware) capture(direction capture.Direction, msg *message.Message) {
+	if m.recorder != nil {
+		m.recorder.Capture(direction, msg)
+	}
+}
+
+// waitEgress blocks until the given number of bytes may be sent on the channel according to its egress rate limit.
+// It returns an error if the bytes cannot be sent within the timeout, in which case the message must be dropped.
+func (m *Middleware) waitEgress(channel network.Channel, size int, timeout time.Duration) error {
+	if m.egressLimiter == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, timeout)
+	defer cancel()
+
+	delay, err := m.egressLimiter.Wait(ctx, channel, size)
+	if err != nil {
+		m.metrics.OutboundMessageDropped(channel.String())
+		return fmt.Errorf("egress rate limit exceeded: %w", err)
+	}
+	if delay > 0 {
+		m.metrics.OutboundMessageDelayed(channel.String(), delay)
+	}
+
+	return nil
+}
+
+// outboundMessage is a message in an outbound queue, together with the function sending it.
+type outboundMessage struct {
+	msg      *message.Message
+	size     int
+	priority queue.Priority
+	send     func() error
+}
+
+// enqueueDirect inserts the direct message into the outbound queue of the given peer, which is created if needed. The
+// message is inserted while holding the lock of the outbound queues, so that the queue cannot be pruned meanwhile.
+func (m *Middleware) enqueueDirect(peerID peer.ID, om *outboundMessage) error {
+	m.outboundQueuesLock.Lock()
+	defer m.outboundQueuesLock.Unlock()
+
+	// the outbound queues are closed once the middleware stops
+	if m.outboundQueues == nil {
+		return queue.ErrQueueClosed
+	}
+
+	q, ok := m.outboundQueues[peerID]
+	if !ok {
+		q = queue.NewOutboundQueue(*m.outboundQueueConfig, m.sendQueued, m.metrics)
+		m.outboundQueues[peerID] = q
+	}
+	return m.enqueue(q, om)
+}
+
+// pruneOutboundQueues removes the idle outbound queues of the given peers, or of all peers if none are given. Queues
+// are created again on the next message to their peer.
+func (m *Middleware) pruneOutboundQueues(peerIDs ...peer.ID) {
+	m.outboundQueuesLock.Lock()
+	defer m.outboundQueuesLock.Unlock()
+
+	if len(peerIDs) == 0 {
+		for peerID := range m.outboundQueues {
+			peerIDs = append(peerIDs, peerID)
+		}
+	}
+
+	for _, peerID := range peerIDs {
+		q, ok := m.outboundQueues[peerID]
+		if ok && q.CloseIfIdle() {
+			delete(m.outboundQueues, peerID)
+		}
+	}
+}
+
+// enqueue inserts the message into the outbound queue, with the priority derived from its type and size. It returns a
+// QueueFullError if the message is dropped as the queue is full, and drops the message evicted from the queue, if any.
+func (m *Middleware) enqueue(q *queue.OutboundQueue, om *outboundMessage) error {
+	om.priority = queue.GetMessagePriority(om.msg.Type, om.size)
+
+	evicted, err := q.Insert(om, om.priority)
+	if errors.Is(err, queue.ErrQueueFull) {
+		m.metrics.OutboundQueueMessageDropped(om.msg.ChannelID, int(om.priority))
+		return network.NewQueueFullError(network.Channel(om.msg.ChannelID))
+	}
+	if err != nil {
+		return fmt.Errorf("could not queue message: %w", err)
+	}
+
+	if evicted != nil {
+		dropped := evicted.(*outboundMessage)
+		m.metrics.OutboundQueueMessageDropped(dropped.msg.ChannelID, int(dropped.priority))
+		m.log.Debug().
+			Str("channel", dropped.msg.ChannelID).
+			Str("type", dropped.msg.Type).
+			Int("priority", int(dropped.priority)).
+			Msg("dropped queued message of lower priority from full outbound queue")
+	}
+
+	return nil
+}
+
+// sendQueued sends a message removed from an outbound queue. It is called by the workers of the outbound queues.
+func (m *Middleware) sendQueued(item interface{}) {
+	om := item.(*outboundMessage)
+	err := om.send()
+	if err != nil {
+		m.log.Warn().Err(err).
+			Str("channel", om.msg.ChannelID).
+			Str("type", om.msg.Type).
+			Msg("could not send queued message")
+	}
+}
+
+// closeOutboundQueues closes all the outbound queues, which drops the queued messages, and waits for the messages
+// being sent.
+func (m *Middleware) closeOutboundQueues() {
+	m.outboundQueuesLock.Lock()
+	queues := m.outboundQueues
+	m.outboundQueues = nil
+	m.outboundQueuesLock.Unlock()
+
+	for _, q := range queues {
+		q.Close()
+	}
+	if m.publishQueue != nil {
+		m.publishQueue.Close()
+	}
+}
+
+// ConnectedPeers returns the peers this node is currently connected to, including peers which are not
+// part of its topology.
+func (m *Middleware) ConnectedPeers() peer.IDSlice {
+	return m.libP2PNode.ConnectedPeers()
+}
+
 // IsConnected returns true if this node is connected to the node with id nodeID.
 func (m *Middleware) IsConnected(nodeID flow.Identifier) (bool, error) {
 	peerID, err := m.idTranslator.GetPeerID(nodeID)
@@ -618,6 +926,64 @@ func (m *Middleware) IsConnected(nodeID flow.Identifier) (bool, error) {
 	return m.libP2PNode.IsConnected(peerID)
 }
 
+// ReportMisbehavior penalizes the node with the given ID for the given misbehavior. It is a no-op if peer
+// scoring is not enabled.
+func (m *Middleware) ReportMisbehavior(originID flow.Identifier, misbehavior network.Misbehavior) {
+	if m.peerScores == nil {
+		return
+	}
+
+	peerID, err := m.idTranslator.GetPeerID(originID)
+	if err != nil {
+		m.log.Warn().Err(err).
+			Hex("origin_id", originID[:]).
+			Str("misbehavior", misbehavior.String()).
+			Msg("could not find peer id for misbehaving node")
+		return
+	}
+
+	m.reportPeer(peerID, misbehavior)
+}
+
+// reportPeer penalizes the given peer for the given misbehavior, and disconnects it if it got blocked.
+// Once blocked, the connection gater rejects all connections to and from the peer.
+func (m *Middleware) reportPeer(peerID peer.ID, misbehavior network.Misbehavior) {
+	if m.peerScores == nil {
+		return
+	}
+
+	if !m.peerScores.Report(peerID, misbehavior) {
+		return
+	}
+
+	err := m.libP2PNode.RemovePeer(peerID)
+	if err != nil {
+		m.log.Err(err).Str("peer_id", peerID.Pretty()).Msg("failed to disconnect blocked peer")
+	}
+}
+
+// reportRejected wraps the given pubsub message validator to report the given misbehavior for every
+// message it rejects.
+func (m *Middleware) reportRejected(v psValidator.MessageValidator, misbehavior network.Misbehavior) psValidator.MessageValidator {
+	return func(ctx context.Context, from peer.ID, msg *message.Message) pubsub.ValidationResult {
+		result := v(ctx, from, msg)
+		if result == pubsub.ValidationReject {
+			m.reportPeer(from, misbehavior)
+		}
+		return result
+	}
+}
+
+// peerRole returns the role of the given peer, for aggregating the bandwidth metrics by role, or "unknown" if the peer
+// is not part of the identity table.
+func (m *Middleware) peerRole(peerID peer.ID) string {
+	id, ok := m.ov.Identity(peerID)
+	if !ok {
+		return "unknown"
+	}
+	return id.Role.String()
+}
+
 // unicastMaxMsgSize returns the max permissible size for a unicast message
 func unicastMaxMsgSize(msg *message.Message) int {
 	switch msg.Type {
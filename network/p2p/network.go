@@ -1,6 +1,7 @@
 package p2p
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -23,6 +24,8 @@ import (
 	"github.com/onflow/flow-go/module/irrecoverable"
 	"github.com/onflow/flow-go/network"
 	netcache "github.com/onflow/flow-go/network/cache"
+	"github.com/onflow/flow-go/network/codec"
+	"github.com/onflow/flow-go/network/encryption"
 	"github.com/onflow/flow-go/network/message"
 	"github.com/onflow/flow-go/network/p2p/conduit"
 	"github.com/onflow/flow-go/network/queue"
@@ -51,6 +54,15 @@ func WithConduitFactory(f network.ConduitFactory) NetworkOptFunction {
 	}
 }
 
+// WithEncryptor enables the end-to-end encryption of messages on the encrypted channels with the given encryptor.
+// Without an encryptor, messages on encrypted channels are sent and received in plaintext, so that nodes can exchange
+// them with nodes which do not support encryption until it is enabled on all the nodes.
+func WithEncryptor(encryptor *encryption.Encryptor) NetworkOptFunction {
+	return func(n *Network) {
+		n.encryptor = encryptor
+	}
+}
+
 // Network represents the overlay network of our peer-to-peer network, including
 // the protocols for handshakes, authentication, gossiping and heartbeats.
 type Network struct {
@@ -67,6 +79,7 @@ type Network struct {
 	queue                       network.MessageQueue
 	subMngr                     network.SubscriptionManager // used to keep track of subscribed channels
 	conduitFactory              network.ConduitFactory
+	encryptor                   *encryption.Encryptor // used to encrypt the messages on encrypted channels
 	registerEngineRequests      chan *registerEngineRequest
 	registerBlobServiceRequests chan *registerBlobServiceRequest
 }
@@ -323,6 +336,11 @@ func (n *Network) Identity(pid peer.ID) (*flow.Identity, bool) {
 	return n.identityProvider.ByPeerID(pid)
 }
 
+// SubscribedChannels returns the channels for which an engine is registered on this network.
+func (n *Network) SubscribedChannels() network.ChannelList {
+	return n.subMngr.Channels()
+}
+
 // Topology returns the identities of a uniform subset of nodes in protocol state using the topology provided earlier.
 // Independent invocations of Topology on different nodes collectively constructs a connected network graph.
 func (n *Network) Topology() (flow.IdentityList, error) {
@@ -364,9 +382,21 @@ func (n *Network) processNetworkMessage(senderID flow.Identifier, message *messa
 		return nil
 	}
 
+	payload, err := n.open(network.Channel(message.ChannelID), senderID, message.Payload)
+	if err != nil {
+		return fmt.Errorf("could not decrypt event: %w", err)
+	}
+
 	// Convert message payload to a known message type
-	decodedMessage, err := n.codec.Decode(message.Payload)
+	decodedMessage, err := n.codec.Decode(payload)
 	if err != nil {
+		var unknownVersion codec.UnknownVersionError
+		if errors.As(err, &unknownVersion) {
+			// the sender runs a newer software version, which is not a misbehavior
+			n.metrics.UnknownMessageVersion(message.ChannelID, unknownVersion.What)
+			return fmt.Errorf("could not decode event: %w", err)
+		}
+		n.mw.ReportMisbehavior(senderID, network.InvalidMessage)
 		return fmt.Errorf("could not decode event: %w", err)
 	}
 
@@ -387,6 +417,56 @@ func (n *Network) processNetworkMessage(senderID flow.Identifier, message *messa
 	return nil
 }
 
+// ReceiveRequest serves the request received from the given node with the engine registered on the channel of the
+// request, and returns the response to send back to the node. Requests are not deduplicated, as each one of them
+// awaits a response.
+func (n *Network) ReceiveRequest(originID flow.Identifier, msg *message.Message) (*message.Message, error) {
+	channel := network.Channel(msg.ChannelID)
+
+	payload, err := n.open(channel, originID, msg.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt request: %w", err)
+	}
+
+	request, err := n.codec.Decode(payload)
+	if err != nil {
+		var unknownVersion codec.UnknownVersionError
+		if errors.As(err, &unknownVersion) {
+			n.metrics.UnknownMessageVersion(msg.ChannelID, unknownVersion.What)
+			return nil, fmt.Errorf("could not decode request: %w", err)
+		}
+		n.mw.ReportMisbehavior(originID, network.InvalidMessage)
+		return nil, fmt.Errorf("could not decode request: %w", err)
+	}
+
+	eng, err := n.subMngr.GetEngine(channel)
+	if err != nil {
+		return nil, fmt.Errorf("no engine registered on channel %s", channel)
+	}
+	processor, ok := eng.(network.RequestProcessor)
+	if !ok {
+		return nil, fmt.Errorf("no engine serves requests on channel %s", channel)
+	}
+
+	n.metrics.MessageProcessingStarted(channel.String())
+	startTimestamp := time.Now()
+
+	response, err := processor.ProcessRequest(channel, originID, request)
+
+	n.metrics.MessageProcessingFinished(channel.String(), time.Since(startTimestamp))
+
+	if err != nil {
+		return nil, fmt.Errorf("could not process request: %w", err)
+	}
+
+	res, err := n.genNetworkMessage(channel, response, originID)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate network message for response: %w", err)
+	}
+
+	return res, nil
+}
+
 // genNetworkMessage uses the codec to encode an event into a NetworkMessage
 func (n *Network) genNetworkMessage(channel network.Channel, event interface{}, targetIDs ...flow.Identifier) (*message.Message, error) {
 	// encode the payload using the configured codec
@@ -395,6 +475,11 @@ func (n *Network) genNetworkMessage(channel network.Channel, event interface{},
 		return nil, fmt.Errorf("could not encode event: %w", err)
 	}
 
+	payload, err = n.seal(channel, payload, targetIDs)
+	if err != nil {
+		return nil, fmt.Errorf("could not encrypt event: %w", err)
+	}
+
 	//bs := binstat.EnterTimeVal(binstat.BinNet+":wire<3payload2message", int64(len(payload)))
 	//defer binstat.Leave(bs)
 
@@ -452,6 +537,50 @@ func (n *Network) UnicastOnChannel(channel network.Channel, message interface{},
 	return nil
 }
 
+// RequestOnChannel sends the request in a reliable way to the given recipient, and returns its response.
+// It uses the request protocol of the underlying network, which reuses a 1-1 direct stream for all the requests
+// to the recipient. It returns an error if the recipient does not respond before the context is done, a
+// RemoteRequestError if the recipient could not serve the request, and a RequestNotSupportedError if the
+// recipient does not support the request protocol.
+func (n *Network) RequestOnChannel(ctx context.Context, channel network.Channel, request interface{}, targetID flow.Identifier) (interface{}, error) {
+	if targetID == n.me.NodeID() {
+		return nil, fmt.Errorf("network can not send request to itself")
+	}
+
+	msg, err := n.genNetworkMessage(channel, request, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("request could not generate network message: %w", err)
+	}
+
+	res, err := n.mw.SendRequest(ctx, msg, targetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %x: %w", targetID, err)
+	}
+
+	if res.ChannelID != msg.ChannelID {
+		n.mw.ReportMisbehavior(targetID, network.InvalidMessage)
+		return nil, fmt.Errorf("received response on channel %s to request on channel %s", res.ChannelID, msg.ChannelID)
+	}
+
+	payload, err := n.open(channel, targetID, res.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt response: %w", err)
+	}
+
+	response, err := n.codec.Decode(payload)
+	if err != nil {
+		var unknownVersion codec.UnknownVersionError
+		if errors.As(err, &unknownVersion) {
+			n.metrics.UnknownMessageVersion(res.ChannelID, unknownVersion.What)
+			return nil, fmt.Errorf("could not decode response: %w", err)
+		}
+		n.mw.ReportMisbehavior(targetID, network.InvalidMessage)
+		return nil, fmt.Errorf("could not decode response: %w", err)
+	}
+
+	return response, nil
+}
+
 // PublishOnChannel sends the message in an unreliable way to the given recipients.
 // In this context, unreliable means that the message is published over a libp2p pub-sub
 // channel and can be read by any node subscribed to that channel.
@@ -491,6 +620,18 @@ func (n *Network) MulticastOnChannel(channel network.Channel, message interface{
 	return nil
 }
 
+// ReportMisbehaviorOnChannel reports a misbehavior of the given node observed by an engine on the given channel
+// to the middleware, which accumulates the penalties in the score of the node.
+func (n *Network) ReportMisbehaviorOnChannel(channel network.Channel, originID flow.Identifier, misbehavior network.Misbehavior) {
+	n.logger.Debug().
+		Str("channel", channel.String()).
+		Hex("origin_id", originID[:]).
+		Str("misbehavior", misbehavior.String()).
+		Msg("misbehavior reported by engine")
+
+	n.mw.ReportMisbehavior(originID, misbehavior)
+}
+
 // removeSelfFilter removes the flow.Identifier of this node if present, from the list of nodes
 func (n *Network) removeSelfFilter() flow.IdentifierFilter {
 	return func(id flow.Identifier) bool {
@@ -522,6 +663,37 @@ func (n *Network) sendOnChannel(channel network.Channel, message interface{}, ta
 	return nil
 }
 
+// seal encrypts the encoded event for the given recipients if the channel is encrypted and encryption is enabled,
+// and returns it unchanged otherwise.
+func (n *Network) seal(channel network.Channel, payload []byte, targetIDs []flow.Identifier) ([]byte, error) {
+	if !channels.IsEncryptedChannel(channel) || n.encryptor == nil {
+		return payload, nil
+	}
+
+	return n.encryptor.Seal(channel, payload, targetIDs)
+}
+
+// open decrypts the encoded event received from the given node if the channel is encrypted and encryption is enabled,
+// and returns it unchanged otherwise. Messages on encrypted channels which can not be decrypted are reported as
+// misbehavior of the sender, except for messages which are not encrypted for this node, as the sender may legitimately
+// not consider this node a recipient, e.g. if its view of the identity table differs.
+func (n *Network) open(channel network.Channel, senderID flow.Identifier, payload []byte) ([]byte, error) {
+	if !channels.IsEncryptedChannel(channel) || n.encryptor == nil {
+		return payload, nil
+	}
+
+	decrypted, err := n.encryptor.Open(channel, senderID, payload)
+	if errors.Is(err, encryption.ErrNotRecipient) {
+		return nil, err
+	}
+	if err != nil {
+		n.mw.ReportMisbehavior(senderID, network.InvalidMessage)
+		return nil, err
+	}
+
+	return decrypted, nil
+}
+
 // queueSubmitFunc submits the message to the engine synchronously. It is the callback for the queue worker
 // when it gets a message from the queue
 func (n *Network) queueSubmitFunc(message interface{}) {
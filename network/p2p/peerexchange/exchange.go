@@ -0,0 +1,408 @@
+package peerexchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"time"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/component"
+	"github.com/onflow/flow-go/module/irrecoverable"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/unicast"
+)
+
+const (
+	// DefaultInterval is the default time between two rounds of peer exchange.
+	DefaultInterval = time.Minute
+
+	// DefaultFanout is the default number of connected peers queried for their peers in each round.
+	DefaultFanout = 3
+
+	// DefaultMinPeers is the default number of connected peers below which known peers are connected to.
+	DefaultMinPeers = 4
+
+	// DefaultMaxPeers is the default maximum number of peers kept in the peer store.
+	DefaultMaxPeers = 1000
+
+	// DefaultMaxResponsePeers is the default maximum number of peers shared in a response.
+	DefaultMaxResponsePeers = 20
+
+	// DefaultTimeout is the default timeout of a peer exchange request, and of a connection attempt.
+	DefaultTimeout = 10 * time.Second
+
+	// maxMessageSize is the maximum size of a peer exchange request or response.
+	maxMessageSize = 64 * 1024
+)
+
+// Config configures the peer exchange.
+type Config struct {
+	// Interval is the time between two rounds of peer exchange.
+	Interval time.Duration
+	// Fanout is the number of connected peers queried for their peers in each round.
+	Fanout int
+	// MinPeers is the number of connected peers below which known peers are connected to in each round.
+	MinPeers int
+	// MaxPeers is the maximum number of peers kept in the peer store. The peers of lowest quality are forgotten first.
+	MaxPeers int
+	// MaxResponsePeers is the maximum number of peers shared with, and accepted from, another peer in a response.
+	MaxResponsePeers int
+	// Timeout is the timeout of a peer exchange request, and of a connection attempt.
+	Timeout time.Duration
+}
+
+// DefaultConfig returns the default configuration of the peer exchange.
+func DefaultConfig() Config {
+	return Config{
+		Interval:         DefaultInterval,
+		Fanout:           DefaultFanout,
+		MinPeers:         DefaultMinPeers,
+		MaxPeers:         DefaultMaxPeers,
+		MaxResponsePeers: DefaultMaxResponsePeers,
+		Timeout:          DefaultTimeout,
+	}
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c Config) Validate() error {
+	if c.Interval <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", c.Interval)
+	}
+	if c.Fanout <= 0 {
+		return fmt.Errorf("fanout must be positive, got %d", c.Fanout)
+	}
+	if c.MinPeers < 0 {
+		return fmt.Errorf("minimum number of peers must not be negative, got %d", c.MinPeers)
+	}
+	if c.MaxPeers <= 0 {
+		return fmt.Errorf("maximum number of peers must be positive, got %d", c.MaxPeers)
+	}
+	if c.MaxResponsePeers <= 0 {
+		return fmt.Errorf("maximum number of peers in a response must be positive, got %d", c.MaxResponsePeers)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive, got %s", c.Timeout)
+	}
+	return nil
+}
+
+// request is sent by a peer to ask for the healthy peers known to another peer.
+type request struct {
+	MaxPeers int `json:"max_peers"`
+}
+
+// response holds the healthy peers known to the responding peer.
+type response struct {
+	Peers []exchangedPeer `json:"peers"`
+}
+
+type exchangedPeer struct {
+	PeerID string   `json:"peer_id"`
+	Addrs  []string `json:"addrs"`
+}
+
+// PeerExchange discovers the peers of the unstaked network by gossip. In each round, it records the peers it is
+// connected to in the peer store, asks some of the connected peers which support the peer exchange protocol for the
+// healthy peers they know, and connects to the known peers of highest quality if it is connected to too few peers.
+// It answers the requests of other peers with the healthy peers of its peer store.
+//
+// As the peer store is persisted, the known peers complement the bootstrap peers after a restart.
+type PeerExchange struct {
+	component.Component
+	log        zerolog.Logger
+	node       *p2p.Node
+	store      *PeerStore
+	protocolID protocol.ID
+	config     Config
+}
+
+// NewPeerExchange creates a peer exchange for the given libp2p node, which keeps the peers it discovers in the given
+// peer store, and registers the handler of the peer exchange protocol on the node.
+func NewPeerExchange(log zerolog.Logger, node *p2p.Node, sporkID flow.Identifier, store *PeerStore, config Config) (*PeerExchange, error) {
+	err := config.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer exchange config: %w", err)
+	}
+
+	e := &PeerExchange{
+		log:        log.With().Str("component", "peer_exchange").Logger(),
+		node:       node,
+		store:      store,
+		protocolID: unicast.PeerExchangeProtocolId(sporkID),
+		config:     config,
+	}
+
+	e.Component = component.NewComponentManagerBuilder().
+		AddWorker(e.loop).
+		Build()
+
+	node.Host().SetStreamHandler(e.protocolID, e.handleStream)
+
+	return e, nil
+}
+
+// loop connects to the known peers once started, and runs a round of peer exchange at every interval.
+func (e *PeerExchange) loop(ctx irrecoverable.SignalerContext, ready component.ReadyFunc) {
+	ready()
+
+	e.connect(ctx)
+
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Round(ctx)
+		}
+	}
+}
+
+// Round runs a round of peer exchange.
+func (e *PeerExchange) Round(ctx context.Context) {
+	me := e.node.Host().ID()
+	connected := e.node.ConnectedPeers()
+
+	// the peers we are connected to are reachable, at the addresses of the local peerstore
+	for _, pid := range connected {
+		info := e.node.Host().Peerstore().PeerInfo(pid)
+		_, err := e.store.LearnConnected(info)
+		if err != nil {
+			e.log.Debug().Err(err).Str("peer_id", pid.Pretty()).Msg("could not record connected peer")
+			continue
+		}
+		e.reportSuccess(pid)
+	}
+
+	exchangers := e.node.GetPeersForProtocol(e.protocolID)
+	rand.Shuffle(len(exchangers), func(i, j int) {
+		exchangers[i], exchangers[j] = exchangers[j], exchangers[i]
+	})
+	if len(exchangers) > e.config.Fanout {
+		exchangers = exchangers[:e.config.Fanout]
+	}
+
+	for _, pid := range exchangers {
+		if pid == me {
+			continue
+		}
+
+		peers, err := e.Request(ctx, pid)
+		if err != nil {
+			e.log.Debug().Err(err).Str("peer_id", pid.Pretty()).Msg("peer exchange request failed")
+			e.reportFailure(pid)
+			continue
+		}
+		e.reportSuccess(pid)
+
+		learned := 0
+		for _, info := range peers {
+			if info.ID == me {
+				continue
+			}
+			added, err := e.store.Learn(info)
+			if err != nil {
+				e.log.Debug().Err(err).Str("peer_id", info.ID.Pretty()).Msg("could not record exchanged peer")
+				continue
+			}
+			if added {
+				learned++
+			}
+		}
+		e.log.Debug().
+			Str("peer_id", pid.Pretty()).
+			Int("peers", len(peers)).
+			Int("learned", learned).
+			Msg("exchanged peers")
+	}
+
+	e.connect(ctx)
+}
+
+// connect connects to the known peers of highest quality, if the node is connected to fewer than the minimum number
+// of peers.
+func (e *PeerExchange) connect(ctx context.Context) {
+	connected := e.node.ConnectedPeers()
+	missing := e.config.MinPeers - len(connected)
+	if missing <= 0 {
+		return
+	}
+
+	exclude := append(connected, e.node.Host().ID())
+	for _, info := range e.store.Candidates(missing, exclude...) {
+		connectCtx, cancel := context.WithTimeout(ctx, e.config.Timeout)
+		err := e.node.AddPeer(connectCtx, info)
+		cancel()
+		if err != nil {
+			e.log.Debug().Err(err).Str("peer_id", info.ID.Pretty()).Msg("could not connect to known peer")
+			e.reportFailure(info.ID)
+			continue
+		}
+		e.reportDialed(info)
+	}
+}
+
+// reportDialed reports the successful connection to the given peer, at the address of the peer which was dialed.
+func (e *PeerExchange) reportDialed(info peer.AddrInfo) {
+	for _, conn := range e.node.Host().Network().ConnsToPeer(info.ID) {
+		if conn.Stat().Direction != libp2pnetwork.DirOutbound {
+			continue
+		}
+		for _, addr := range info.Addrs {
+			if !addr.Equal(conn.RemoteMultiaddr()) {
+				continue
+			}
+			err := e.store.ReportDialed(info.ID, addr)
+			if err != nil {
+				e.log.Error().Err(err).Str("peer_id", info.ID.Pretty()).Msg("could not update peer record")
+			}
+			return
+		}
+	}
+
+	// the connection was not established by dialing one of the known addresses, e.g. as the peer connected first
+	e.reportSuccess(info.ID)
+}
+
+// Request asks the given peer for the healthy peers it knows.
+func (e *PeerExchange) Request(ctx context.Context, pid peer.ID) ([]peer.AddrInfo, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.config.Timeout)
+	defer cancel()
+
+	s, err := e.node.Host().NewStream(ctx, pid, e.protocolID)
+	if err != nil {
+		return nil, fmt.Errorf("could not open stream: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	err = s.SetDeadline(deadline)
+	if err != nil {
+		_ = s.Reset()
+		return nil, fmt.Errorf("could not set stream deadline: %w", err)
+	}
+
+	err = json.NewEncoder(s).Encode(request{MaxPeers: e.config.MaxResponsePeers})
+	if err != nil {
+		_ = s.Reset()
+		return nil, fmt.Errorf("could not write request: %w", err)
+	}
+	err = s.CloseWrite()
+	if err != nil {
+		_ = s.Reset()
+		return nil, fmt.Errorf("could not close stream for writing: %w", err)
+	}
+
+	var res response
+	err = json.NewDecoder(io.LimitReader(s, maxMessageSize)).Decode(&res)
+	if err != nil {
+		_ = s.Reset()
+		return nil, fmt.Errorf("could not read response: %w", err)
+	}
+	_ = s.Close()
+
+	if len(res.Peers) > e.config.MaxResponsePeers {
+		return nil, fmt.Errorf("response holds %d peers, more than the maximum %d", len(res.Peers), e.config.MaxResponsePeers)
+	}
+
+	infos := make([]peer.AddrInfo, 0, len(res.Peers))
+	for _, exchanged := range res.Peers {
+		info, err := decodePeer(exchanged)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer in response: %w", err)
+		}
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// handleStream answers a peer exchange request with the healthy peers of the peer store.
+func (e *PeerExchange) handleStream(s libp2pnetwork.Stream) {
+	remote := s.Conn().RemotePeer()
+	log := e.log.With().Str("peer_id", remote.Pretty()).Logger()
+
+	err := s.SetDeadline(time.Now().Add(e.config.Timeout))
+	if err != nil {
+		log.Debug().Err(err).Msg("could not set stream deadline")
+		_ = s.Reset()
+		return
+	}
+
+	var req request
+	err = json.NewDecoder(io.LimitReader(s, maxMessageSize)).Decode(&req)
+	if err != nil {
+		log.Debug().Err(err).Msg("could not read peer exchange request")
+		_ = s.Reset()
+		return
+	}
+
+	max := req.MaxPeers
+	if max <= 0 || max > e.config.MaxResponsePeers {
+		max = e.config.MaxResponsePeers
+	}
+
+	var res response
+	for _, info := range e.store.Healthy(max, remote, e.node.Host().ID()) {
+		res.Peers = append(res.Peers, encodePeer(info))
+	}
+
+	err = json.NewEncoder(s).Encode(res)
+	if err != nil {
+		log.Debug().Err(err).Msg("could not write peer exchange response")
+		_ = s.Reset()
+		return
+	}
+	_ = s.Close()
+}
+
+func (e *PeerExchange) reportSuccess(pid peer.ID) {
+	err := e.store.ReportSuccess(pid)
+	if err != nil {
+		e.log.Error().Err(err).Str("peer_id", pid.Pretty()).Msg("could not update peer record")
+	}
+}
+
+func (e *PeerExchange) reportFailure(pid peer.ID) {
+	err := e.store.ReportFailure(pid)
+	if err != nil {
+		e.log.Error().Err(err).Str("peer_id", pid.Pretty()).Msg("could not update peer record")
+	}
+}
+
+func encodePeer(info peer.AddrInfo) exchangedPeer {
+	exchanged := exchangedPeer{PeerID: info.ID.String()}
+	for _, addr := range info.Addrs {
+		exchanged.Addrs = append(exchanged.Addrs, addr.String())
+	}
+	return exchanged
+}
+
+func decodePeer(exchanged exchangedPeer) (peer.AddrInfo, error) {
+	pid, err := peer.Decode(exchanged.PeerID)
+	if err != nil {
+		return peer.AddrInfo{}, fmt.Errorf("invalid peer ID: %w", err)
+	}
+	if len(exchanged.Addrs) > maxAddrs {
+		return peer.AddrInfo{}, fmt.Errorf("peer %s has %d addresses, more than the maximum %d", pid, len(exchanged.Addrs), maxAddrs)
+	}
+
+	info := peer.AddrInfo{ID: pid}
+	for _, addr := range exchanged.Addrs {
+		maddr, err := multiaddr.NewMultiaddr(addr)
+		if err != nil {
+			return peer.AddrInfo{}, fmt.Errorf("invalid address %s of peer %s: %w", addr, pid, err)
+		}
+		info.Addrs = append(info.Addrs, maddr)
+	}
+	return info, nil
+}
@@ -0,0 +1,120 @@
+package peerexchange_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/libp2p/go-libp2p-core/routing"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/peerexchange"
+	"github.com/onflow/flow-go/network/p2p/unicast"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestPeerExchange tests that a node learns the healthy peers known to the peers it is connected to, and connects to
+// them when it has too few connections.
+func TestPeerExchange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sporkID := unittest.IdentifierFixture()
+	config := peerexchange.DefaultConfig()
+	config.MinPeers = 2
+
+	// the first node is connected to the second one, which is also connected to the third one
+	nodes := make([]*p2p.Node, 0, 3)
+	exchanges := make([]*peerexchange.PeerExchange, 0, 3)
+	stores := make([]*peerexchange.PeerStore, 0, 3)
+	for i := 0; i < 3; i++ {
+		node := nodeFixture(t, ctx, sporkID)
+		db, dir := unittest.TempBadgerDB(t)
+		defer os.RemoveAll(dir)
+		defer db.Close()
+
+		store, err := peerexchange.NewPeerStore(zerolog.Nop(), badgerstorage.NewPeerRecords(db), p2p.NewUnstakedNetworkIDTranslator(), config.MaxPeers)
+		require.NoError(t, err)
+		exchange, err := peerexchange.NewPeerExchange(zerolog.Nop(), node, sporkID, store, config)
+		require.NoError(t, err)
+
+		nodes = append(nodes, node)
+		stores = append(stores, store)
+		exchanges = append(exchanges, exchange)
+	}
+	defer func() {
+		for _, node := range nodes {
+			done, err := node.Stop()
+			assert.NoError(t, err)
+			unittest.RequireCloseBefore(t, done, time.Second, "could not stop node")
+		}
+	}()
+
+	connect(t, ctx, sporkID, nodes[1], nodes[2])
+	connect(t, ctx, sporkID, nodes[0], nodes[1])
+
+	// the second node records the nodes it is connected to as healthy peers
+	exchanges[1].Round(ctx)
+	assert.Len(t, stores[1].Healthy(10), 2)
+
+	peers, err := exchanges[0].Request(ctx, nodes[1].Host().ID())
+	require.NoError(t, err)
+	require.Len(t, peers, 1, "the requesting node is shared with itself")
+	assert.Equal(t, nodes[2].Host().ID(), peers[0].ID)
+
+	// the first node learns the third node from the second one, and connects to it
+	exchanges[0].Round(ctx)
+	connected, err := nodes[0].IsConnected(nodes[2].Host().ID())
+	require.NoError(t, err)
+	assert.True(t, connected)
+
+	// the DHT may have connected the first node to the third one before the peer exchange did, in which case the
+	// third node is recorded as a connected peer in the next round
+	exchanges[0].Round(ctx)
+	healthy := stores[0].Healthy(10)
+	require.Len(t, healthy, 2)
+	assert.ElementsMatch(t, []peer.ID{nodes[1].Host().ID(), nodes[2].Host().ID()}, []peer.ID{healthy[0].ID, healthy[1].ID})
+}
+
+func nodeFixture(t *testing.T, ctx context.Context, sporkID flow.Identifier) *p2p.Node {
+	node, err := p2p.NewNodeBuilder(zerolog.Nop(), "0.0.0.0:0", unstakedNetworkingKeyFixture(t), sporkID).
+		SetRoutingSystem(func(ctx context.Context, h host.Host) (routing.Routing, error) {
+			return p2p.NewDHT(ctx, h, unicast.FlowPublicDHTProtocolID(sporkID), p2p.AsServer())
+		}).
+		SetPubSub(pubsub.NewGossipSub).
+		Build(ctx)
+	require.NoError(t, err)
+	return node
+}
+
+// connect connects the first node to the second one, and waits until they identified each other as peers supporting
+// the peer exchange protocol of the given spork.
+func connect(t *testing.T, ctx context.Context, sporkID flow.Identifier, node *p2p.Node, other *p2p.Node) {
+	err := node.AddPeer(ctx, peer.AddrInfo{ID: other.Host().ID(), Addrs: other.Host().Addrs()})
+	require.NoError(t, err)
+
+	protocolID := unicast.PeerExchangeProtocolId(sporkID)
+	require.Eventually(t, func() bool {
+		return supports(node, other, protocolID) && supports(other, node, protocolID)
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
+// supports returns true if the node knows that the other node supports the given protocol.
+func supports(node *p2p.Node, other *p2p.Node, protocolID protocol.ID) bool {
+	for _, pid := range node.GetPeersForProtocol(protocolID) {
+		if pid == other.Host().ID() {
+			return true
+		}
+	}
+	return false
+}
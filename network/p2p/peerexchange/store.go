@@ -0,0 +1,422 @@
+package peerexchange
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/libp2p"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/storage"
+)
+
+const (
+	// initialScore is the quality score of a peer which was learned, but never interacted with.
+	initialScore = 0.5
+
+	// scoreWeight is the weight of the outcome of the latest interaction with a peer in its quality score, which is
+	// an exponential moving average of the outcomes of all interactions.
+	scoreWeight = 0.3
+
+	// healthyScore is the quality score from which a peer which has been successfully interacted with is healthy,
+	// and shared with other peers.
+	healthyScore = 0.5
+
+	// pruneScore is the quality score below which a peer is forgotten. A learned peer is forgotten after about five
+	// consecutive failures.
+	pruneScore = 0.1
+
+	// maxAddrs is the maximum number of addresses kept for a peer.
+	maxAddrs = 8
+)
+
+// PeerStore keeps track of the peers known to a node of the unstaked network, along with their quality score, and
+// persists them so that the node can discover the network again after a restart.
+//
+// The Flow ID of a peer is derived from its peer ID with the ID translator of the node, and is kept consistent with
+// it: records whose peer ID translates to another Flow ID on startup are updated, and a peer which rotated its
+// networking key, and so its peer ID and Flow ID, replaces the record of its previous key at the same address once it
+// has been successfully dialed at that address, and the previous key then fails.
+//
+// The addresses of known peers are only updated from the connections of the node, while peers gossiped by other
+// peers are only added if they are unknown, so that other peers can not redirect the node away from known peers.
+//
+// PeerStore is safe for concurrent use.
+type PeerStore struct {
+	mu         sync.Mutex
+	log        zerolog.Logger
+	storage    storage.PeerRecords
+	translator p2p.IDTranslator
+	maxPeers   int
+	records    map[peer.ID]*libp2p.PeerRecord
+	superseded map[peer.ID]peer.ID // peers which may have rotated their key, to the peer dialed at their address
+	now        func() time.Time
+}
+
+// NewPeerStore creates a peer store holding up to the given number of peers, which is loaded with the records
+// persisted in the given storage.
+func NewPeerStore(log zerolog.Logger, records storage.PeerRecords, translator p2p.IDTranslator, maxPeers int) (*PeerStore, error) {
+	s := &PeerStore{
+		log:        log.With().Str("component", "peer_store").Logger(),
+		storage:    records,
+		translator: translator,
+		maxPeers:   maxPeers,
+		records:    make(map[peer.ID]*libp2p.PeerRecord),
+		superseded: make(map[peer.ID]peer.ID),
+		now:        time.Now,
+	}
+
+	err := s.load()
+	if err != nil {
+		return nil, fmt.Errorf("could not load peer records: %w", err)
+	}
+
+	return s, nil
+}
+
+// load reads the persisted records, and drops or updates the records which are not consistent with the ID translator.
+func (s *PeerStore) load() error {
+	stored, err := s.storage.All()
+	if err != nil {
+		return err
+	}
+
+	for _, record := range stored {
+		pid, err := peer.Decode(record.PeerID)
+		if err != nil {
+			s.log.Warn().Err(err).Hex("node_id", record.NodeID[:]).Msg("dropping peer record with invalid peer ID")
+			err = s.storage.Remove(record.NodeID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		nodeID, err := s.translator.GetFlowID(pid)
+		if err != nil {
+			s.log.Warn().Err(err).Str("peer_id", pid.Pretty()).Msg("dropping peer record with untranslatable peer ID")
+			err = s.storage.Remove(record.NodeID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		// the peer ID may translate to another Flow ID than when the record was stored, for instance once a staked
+		// node has been added to the protocol state
+		if nodeID != record.NodeID {
+			err = s.storage.Remove(record.NodeID)
+			if err != nil {
+				return err
+			}
+			record.NodeID = nodeID
+			err = s.storage.Store(record)
+			if err != nil {
+				return err
+			}
+		}
+
+		s.records[pid] = record
+	}
+
+	return nil
+}
+
+// Learn adds the given peer, as gossiped by another peer, to the store if it is unknown. The addresses of known peers
+// are not updated, as gossiped addresses are not authenticated. It returns true if the peer was added.
+func (s *PeerStore) Learn(info peer.AddrInfo) (bool, error) {
+	addrs := encodeAddrs(info.Addrs)
+	if len(addrs) == 0 {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[info.ID]; ok {
+		return false, nil
+	}
+	return s.add(info.ID, addrs)
+}
+
+// LearnConnected adds the given peer, which the node is connected to, to the store if it is unknown, and updates its
+// addresses otherwise. The address information must be taken from the local peerstore of a live connection. It
+// returns true if the peer was added.
+func (s *PeerStore) LearnConnected(info peer.AddrInfo) (bool, error) {
+	addrs := encodeAddrs(info.Addrs)
+	if len(addrs) == 0 {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[info.ID]
+	if ok {
+		if equalAddrs(record.Addrs, addrs) {
+			return false, nil
+		}
+		record.Addrs = addrs
+		return false, s.storage.Store(record)
+	}
+	return s.add(info.ID, addrs)
+}
+
+// add adds the unknown peer with the given addresses to the store, and forgets the peer of lowest quality if the store
+// is full. It returns true if the peer was kept. Must be called while holding the lock.
+func (s *PeerStore) add(pid peer.ID, addrs []string) (bool, error) {
+	nodeID, err := s.translator.GetFlowID(pid)
+	if err != nil {
+		return false, fmt.Errorf("could not translate peer ID %s: %w", pid, err)
+	}
+
+	record := &libp2p.PeerRecord{
+		NodeID: nodeID,
+		PeerID: pid.String(),
+		Addrs:  addrs,
+		Score:  initialScore,
+	}
+	err = s.storage.Store(record)
+	if err != nil {
+		return false, err
+	}
+	s.records[pid] = record
+
+	if len(s.records) > s.maxPeers {
+		err = s.remove(s.lowest())
+		if err != nil {
+			return false, err
+		}
+	}
+
+	_, ok := s.records[pid]
+	return ok, nil
+}
+
+// ReportSuccess raises the quality score of the given peer after a successful interaction with it. It is a no-op for
+// unknown peers.
+func (s *PeerStore) ReportSuccess(pid peer.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.succeed(pid)
+}
+
+// ReportDialed raises the quality score of the given peer after it was successfully dialed at the given address. As
+// the peer is authenticated by its peer ID, the other peers known at that address may be keys it used before. These
+// peers are forgotten once an interaction with them fails, unless they are successfully interacted with before. It
+// is a no-op for unknown peers.
+func (s *PeerStore) ReportDialed(pid peer.ID, addr multiaddr.Multiaddr) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.records[pid]; !ok {
+		return nil
+	}
+
+	dialed := []string{addr.String()}
+	for other, known := range s.records {
+		if other != pid && sharesAddr(known.Addrs, dialed) {
+			s.superseded[other] = pid
+		}
+	}
+
+	return s.succeed(pid)
+}
+
+// succeed records a successful interaction with the given peer. Must be called while holding the lock.
+func (s *PeerStore) succeed(pid peer.ID) error {
+	record, ok := s.records[pid]
+	if !ok {
+		return nil
+	}
+
+	// the peer is still reachable with its own key, hence it did not rotate it
+	delete(s.superseded, pid)
+
+	record.Score += scoreWeight * (1 - record.Score)
+	record.Successes++
+	record.LastSuccess = s.now()
+	return s.storage.Store(record)
+}
+
+// ReportFailure lowers the quality score of the given peer after a failed interaction with it, and forgets the peer
+// once its score drops below the prune score, or if another peer was successfully dialed at its address since its
+// last successful interaction, as it rotated its networking key. It is a no-op for unknown peers.
+func (s *PeerStore) ReportFailure(pid peer.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, ok := s.records[pid]
+	if !ok {
+		return nil
+	}
+
+	if rotated, ok := s.superseded[pid]; ok {
+		s.log.Info().
+			Str("peer_id", rotated.Pretty()).
+			Str("previous_peer_id", pid.Pretty()).
+			Msg("peer rotated its networking key, replacing peer record")
+		return s.remove(pid)
+	}
+
+	record.Score -= scoreWeight * record.Score
+	record.Failures++
+	if record.Score < pruneScore {
+		s.log.Debug().Str("peer_id", pid.Pretty()).Msg("forgetting peer with low quality score")
+		return s.remove(pid)
+	}
+	return s.storage.Store(record)
+}
+
+// Healthy returns the address information of up to max healthy peers, other than the excluded ones, ordered from
+// highest to lowest quality score. Healthy peers have been successfully interacted with, and have a quality score
+// of at least the healthy score.
+func (s *PeerStore) Healthy(max int, exclude ...peer.ID) []peer.AddrInfo {
+	return s.peers(max, func(record *libp2p.PeerRecord) bool {
+		return record.Successes > 0 && record.Score >= healthyScore
+	}, exclude)
+}
+
+// Candidates returns the address information of up to max peers, other than the excluded ones, ordered from highest
+// to lowest quality score.
+func (s *PeerStore) Candidates(max int, exclude ...peer.ID) []peer.AddrInfo {
+	return s.peers(max, func(*libp2p.PeerRecord) bool {
+		return true
+	}, exclude)
+}
+
+// Records returns a copy of the records of all known peers, ordered from highest to lowest quality score.
+func (s *PeerStore) Records() []libp2p.PeerRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records := make([]libp2p.PeerRecord, 0, len(s.records))
+	for _, record := range s.sorted() {
+		copied := *record
+		copied.Addrs = append([]string(nil), record.Addrs...)
+		records = append(records, copied)
+	}
+	return records
+}
+
+func (s *PeerStore) peers(max int, include func(*libp2p.PeerRecord) bool, exclude []peer.ID) []peer.AddrInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	excluded := make(map[string]struct{}, len(exclude))
+	for _, pid := range exclude {
+		excluded[pid.String()] = struct{}{}
+	}
+
+	infos := make([]peer.AddrInfo, 0, max)
+	for _, record := range s.sorted() {
+		if len(infos) >= max {
+			break
+		}
+		if _, ok := excluded[record.PeerID]; ok || !include(record) {
+			continue
+		}
+		info, err := addrInfo(record)
+		if err != nil {
+			s.log.Warn().Err(err).Str("peer_id", record.PeerID).Msg("skipping invalid peer record")
+			continue
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// sorted returns the records ordered from highest to lowest quality score. Must be called with the lock held.
+func (s *PeerStore) sorted() []*libp2p.PeerRecord {
+	records := make([]*libp2p.PeerRecord, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Score != records[j].Score {
+			return records[i].Score > records[j].Score
+		}
+		return records[i].PeerID < records[j].PeerID
+	})
+	return records
+}
+
+// lowest returns the peer with the lowest quality score, and the least recently successful one among peers of the
+// same score. Must be called with a non-empty store, while holding the lock.
+func (s *PeerStore) lowest() peer.ID {
+	var lowest peer.ID
+	var record *libp2p.PeerRecord
+	for pid, r := range s.records {
+		if record == nil || r.Score < record.Score ||
+			(r.Score == record.Score && r.LastSuccess.Before(record.LastSuccess)) {
+			lowest, record = pid, r
+		}
+	}
+	return lowest
+}
+
+// remove forgets the given peer. Must be called while holding the lock.
+func (s *PeerStore) remove(pid peer.ID) error {
+	record := s.records[pid]
+	delete(s.records, pid)
+	delete(s.superseded, pid)
+	return s.storage.Remove(record.NodeID)
+}
+
+func addrInfo(record *libp2p.PeerRecord) (peer.AddrInfo, error) {
+	pid, err := peer.Decode(record.PeerID)
+	if err != nil {
+		return peer.AddrInfo{}, fmt.Errorf("invalid peer ID: %w", err)
+	}
+
+	info := peer.AddrInfo{ID: pid}
+	for _, addr := range record.Addrs {
+		maddr, err := multiaddr.NewMultiaddr(addr)
+		if err != nil {
+			return peer.AddrInfo{}, fmt.Errorf("invalid address %s: %w", addr, err)
+		}
+		info.Addrs = append(info.Addrs, maddr)
+	}
+	return info, nil
+}
+
+func encodeAddrs(maddrs []multiaddr.Multiaddr) []string {
+	addrs := make([]string, 0, len(maddrs))
+	for _, maddr := range maddrs {
+		if len(addrs) >= maxAddrs {
+			break
+		}
+		addrs = append(addrs, maddr.String())
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+func equalAddrs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sharesAddr(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,240 @@
+package peerexchange_test
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/libp2p"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/keyutils"
+	"github.com/onflow/flow-go/network/p2p/peerexchange"
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestPeerQuality tests that learned peers are healthy once successfully interacted with, and are forgotten after
+// repeated failures.
+func TestPeerQuality(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := peerStoreFixture(t, db, 10)
+
+		first := peerInfoFixture(t, "/ip4/10.0.0.1/tcp/3569")
+		second := peerInfoFixture(t, "/ip4/10.0.0.2/tcp/3569")
+		for _, info := range []peer.AddrInfo{first, second} {
+			added, err := store.Learn(info)
+			require.NoError(t, err)
+			assert.True(t, added)
+		}
+
+		// gossip does not update the addresses of a known peer, while a connection to it does
+		updated := peerInfoFixture(t, "/ip4/10.0.0.3/tcp/3569")
+		updated.ID = second.ID
+		added, err := store.Learn(updated)
+		require.NoError(t, err)
+		assert.False(t, added)
+		assert.Contains(t, store.Candidates(10), second)
+		added, err = store.LearnConnected(updated)
+		require.NoError(t, err)
+		assert.False(t, added)
+
+		assert.Len(t, store.Candidates(10), 2)
+		assert.Empty(t, store.Healthy(10), "peers are healthy before any interaction")
+
+		require.NoError(t, store.ReportSuccess(first.ID))
+		require.NoError(t, store.ReportSuccess(second.ID))
+		require.NoError(t, store.ReportSuccess(second.ID))
+		healthy := store.Healthy(10)
+		require.Len(t, healthy, 2)
+		assert.Equal(t, second.ID, healthy[0].ID, "peers are ordered by quality")
+		assert.Equal(t, updated.Addrs, healthy[0].Addrs)
+		assert.Equal(t, []peer.AddrInfo{healthy[1]}, store.Healthy(10, second.ID))
+
+		require.NoError(t, store.ReportFailure(first.ID))
+		assert.Equal(t, []peer.AddrInfo{healthy[0]}, store.Healthy(10), "peer is healthy after a failure")
+		assert.Len(t, store.Candidates(10), 2)
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, store.ReportFailure(first.ID))
+		}
+		records := store.Records()
+		require.Len(t, records, 1, "peer is not forgotten after repeated failures")
+		assert.Equal(t, second.ID.String(), records[0].PeerID)
+		assert.EqualValues(t, 2, records[0].Successes)
+	})
+}
+
+// TestPeerStorePersistence tests that the known peers are loaded again from the database, along with their quality.
+func TestPeerStorePersistence(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := peerStoreFixture(t, db, 10)
+
+		known := peerInfoFixture(t, "/ip4/10.0.0.1/tcp/3569")
+		_, err := store.Learn(known)
+		require.NoError(t, err)
+		require.NoError(t, store.ReportSuccess(known.ID))
+		forgotten := peerInfoFixture(t, "/ip4/10.0.0.2/tcp/3569")
+		_, err = store.Learn(forgotten)
+		require.NoError(t, err)
+		for i := 0; i < 6; i++ {
+			require.NoError(t, store.ReportFailure(forgotten.ID))
+		}
+
+		restarted := peerStoreFixture(t, db, 10)
+		expected := store.Records()
+		actual := restarted.Records()
+		require.Len(t, actual, 1)
+		assert.Equal(t, expected[0].NodeID, actual[0].NodeID)
+		assert.Equal(t, expected[0].PeerID, actual[0].PeerID)
+		assert.Equal(t, expected[0].Addrs, actual[0].Addrs)
+		assert.Equal(t, expected[0].Score, actual[0].Score)
+		assert.Equal(t, expected[0].Successes, actual[0].Successes)
+		assert.True(t, expected[0].LastSuccess.Equal(actual[0].LastSuccess))
+		assert.Equal(t, []peer.AddrInfo{known}, restarted.Healthy(10))
+	})
+}
+
+// TestPeerStoreTranslation tests that the Flow IDs of the known peers are kept consistent with the ID translator.
+func TestPeerStoreTranslation(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		translator := p2p.NewUnstakedNetworkIDTranslator()
+		records := badgerstorage.NewPeerRecords(db)
+
+		// a record stored with a Flow ID which is not the one its peer ID translates to
+		info := peerInfoFixture(t, "/ip4/10.0.0.1/tcp/3569")
+		require.NoError(t, records.Store(&libp2p.PeerRecord{
+			NodeID: unittest.IdentifierFixture(),
+			PeerID: info.ID.String(),
+			Addrs:  []string{"/ip4/10.0.0.1/tcp/3569"},
+			Score:  0.5,
+		}))
+
+		// a record whose peer ID does not translate to a Flow ID on the unstaked network
+		p256, err := keyutils.LibP2PPublicKeyFromFlow(unittest.NetworkingPrivKeyFixture().PublicKey())
+		require.NoError(t, err)
+		untranslatable, err := peer.IDFromPublicKey(p256)
+		require.NoError(t, err)
+		require.NoError(t, records.Store(&libp2p.PeerRecord{
+			NodeID: unittest.IdentifierFixture(),
+			PeerID: untranslatable.String(),
+			Addrs:  []string{"/ip4/10.0.0.2/tcp/3569"},
+			Score:  0.5,
+		}))
+
+		store, err := peerexchange.NewPeerStore(zerolog.Nop(), records, translator, 10)
+		require.NoError(t, err)
+
+		expected, err := translator.GetFlowID(info.ID)
+		require.NoError(t, err)
+		stored, err := records.All()
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, expected, stored[0].NodeID)
+		assert.Equal(t, expected, store.Records()[0].NodeID)
+
+		_, err = store.Learn(peer.AddrInfo{ID: untranslatable, Addrs: info.Addrs})
+		assert.Error(t, err)
+	})
+}
+
+// TestPeerKeyRotation tests that a peer which rotated its networking key replaces its previous record once it has been
+// successfully dialed at the address of the previous record, and the previous key then fails.
+func TestPeerKeyRotation(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := peerStoreFixture(t, db, 10)
+
+		previous := peerInfoFixture(t, "/ip4/10.0.0.1/tcp/3569")
+		_, err := store.Learn(previous)
+		require.NoError(t, err)
+		require.NoError(t, store.ReportSuccess(previous.ID))
+
+		rotated := peerInfoFixture(t, "/ip4/10.0.0.1/tcp/3569")
+		_, err = store.Learn(rotated)
+		require.NoError(t, err)
+		assert.Len(t, store.Records(), 2, "a peer claiming the address of another one replaced it")
+
+		// a successful interaction which did not dial the shared address does not replace the previous record
+		require.NoError(t, store.ReportSuccess(rotated.ID))
+		require.NoError(t, store.ReportFailure(previous.ID))
+		assert.Len(t, store.Records(), 2)
+
+		// a peer which is still reachable with its own key is not replaced
+		require.NoError(t, store.ReportDialed(rotated.ID, rotated.Addrs[0]))
+		require.NoError(t, store.ReportSuccess(previous.ID))
+		require.NoError(t, store.ReportFailure(previous.ID))
+		assert.Len(t, store.Records(), 2)
+
+		require.NoError(t, store.ReportDialed(rotated.ID, rotated.Addrs[0]))
+		assert.Len(t, store.Records(), 2, "the previous record is replaced before its key fails")
+		require.NoError(t, store.ReportFailure(previous.ID))
+		records := store.Records()
+		require.Len(t, records, 1)
+		assert.Equal(t, rotated.ID.String(), records[0].PeerID)
+
+		stored, err := badgerstorage.NewPeerRecords(db).All()
+		require.NoError(t, err)
+		require.Len(t, stored, 1)
+		assert.Equal(t, rotated.ID.String(), stored[0].PeerID)
+	})
+}
+
+// TestPeerStoreCapacity tests that the peer of lowest quality is forgotten once the store is full.
+func TestPeerStoreCapacity(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := peerStoreFixture(t, db, 2)
+
+		good := peerInfoFixture(t, "/ip4/10.0.0.1/tcp/3569")
+		bad := peerInfoFixture(t, "/ip4/10.0.0.2/tcp/3569")
+		for _, info := range []peer.AddrInfo{good, bad} {
+			_, err := store.Learn(info)
+			require.NoError(t, err)
+		}
+		require.NoError(t, store.ReportSuccess(good.ID))
+		require.NoError(t, store.ReportFailure(bad.ID))
+
+		added, err := store.Learn(peerInfoFixture(t, "/ip4/10.0.0.3/tcp/3569"))
+		require.NoError(t, err)
+		assert.True(t, added)
+
+		candidates := store.Candidates(10)
+		require.Len(t, candidates, 2)
+		assert.Equal(t, good.ID, candidates[0].ID)
+		assert.NotEqual(t, bad.ID, candidates[1].ID)
+	})
+}
+
+func peerStoreFixture(t *testing.T, db *badger.DB, maxPeers int) *peerexchange.PeerStore {
+	store, err := peerexchange.NewPeerStore(zerolog.Nop(), badgerstorage.NewPeerRecords(db), p2p.NewUnstakedNetworkIDTranslator(), maxPeers)
+	require.NoError(t, err)
+	return store
+}
+
+// peerInfoFixture returns the address information of a peer of the unstaked network at the given address.
+func peerInfoFixture(t *testing.T, addr string) peer.AddrInfo {
+	key, err := keyutils.LibP2PPublicKeyFromFlow(unstakedNetworkingKeyFixture(t).PublicKey())
+	require.NoError(t, err)
+	pid, err := peer.IDFromPublicKey(key)
+	require.NoError(t, err)
+
+	return peer.AddrInfo{
+		ID:    pid,
+		Addrs: []multiaddr.Multiaddr{multiaddr.StringCast(addr)},
+	}
+}
+
+// unstakedNetworkingKeyFixture returns a networking key which is valid on the unstaked network, that is a
+// ECDSASecp256k1 key with a positive public key.
+func unstakedNetworkingKeyFixture(t *testing.T) crypto.PrivateKey {
+	for {
+		key := unittest.PrivateKeyFixture(crypto.ECDSASecp256k1, crypto.KeyGenSeedMinLenECDSASecp256k1)
+		if key.PublicKey().EncodeCompressed()[0] == 0x02 {
+			return key
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/network"
+)
+
+// Limit is the egress limit of a channel, enforced through a token bucket of bytes.
+type Limit struct {
+	// BytesPerSecond is the rate at which the bucket is refilled, i.e. the sustained egress rate of the channel.
+	// Zero means the channel is not limited.
+	BytesPerSecond int
+	// Burst is the capacity of the bucket, i.e. the number of bytes which can be sent at once after the channel
+	// was idle. Zero means one second worth of bytes.
+	Burst int
+}
+
+func (l Limit) validate() error {
+	if l.BytesPerSecond < 0 {
+		return fmt.Errorf("rate must not be negative, got: %d", l.BytesPerSecond)
+	}
+	if l.Burst < 0 {
+		return fmt.Errorf("burst must not be negative, got: %d", l.Burst)
+	}
+	return nil
+}
+
+// Config configures the egress limits of the channels of a node.
+type Config struct {
+	// Default is the limit of channels without a specific limit.
+	Default Limit
+	// Channels are the limits of specific channels.
+	Channels map[network.Channel]Limit
+}
+
+func (c Config) validate() error {
+	if err := c.Default.validate(); err != nil {
+		return fmt.Errorf("invalid default limit: %w", err)
+	}
+	for channel, limit := range c.Channels {
+		if engine.IsConsensusChannel(channel) {
+			return fmt.Errorf("consensus channel %s must not be limited", channel)
+		}
+		if err := limit.validate(); err != nil {
+			return fmt.Errorf("invalid limit for channel %s: %w", channel, err)
+		}
+	}
+	return nil
+}
+
+// EgressLimiter limits the number of bytes sent per second on each channel. Consensus channels are exempt from
+// egress limits, so that bursts of bulk data, e.g. chunk data packs, do not delay consensus messages.
+type EgressLimiter struct {
+	mu      sync.Mutex
+	config  Config
+	buckets map[network.Channel]*rate.Limiter
+}
+
+// NewEgressLimiter creates an egress limiter enforcing the limits of the given config.
+func NewEgressLimiter(config Config) (*EgressLimiter, error) {
+	if err := config.validate(); err != nil {
+		return nil, fmt.Errorf("invalid egress limit config: %w", err)
+	}
+	return &EgressLimiter{
+		config:  config,
+		buckets: make(map[network.Channel]*rate.Limiter),
+	}, nil
+}
+
+// Wait blocks until the given number of bytes may be sent on the channel, and returns the time waited. Messages
+// larger than the burst of the channel consume several bursts worth of bytes. If the bytes cannot be sent before
+// the deadline of the context, an error is returned right away, and no bytes are consumed.
+func (l *EgressLimiter) Wait(ctx context.Context, channel network.Channel, size int) (time.Duration, error) {
+	bucket := l.bucket(channel)
+	if bucket == nil {
+		return 0, nil
+	}
+
+	now := time.Now()
+	burst := bucket.Burst()
+	reservations := make([]*rate.Reservation, 0, size/burst+1)
+	// cancelling a reservation only restores its tokens if no later reservation was made, hence reservations
+	// are cancelled in reverse order
+	cancel := func(at time.Time) {
+		for i := len(reservations) - 1; i >= 0; i-- {
+			reservations[i].CancelAt(at)
+		}
+	}
+
+	// reservations are served in order, hence the delay of the last one is the delay of the message
+	var delay time.Duration
+	for remaining := size; remaining > 0; remaining -= burst {
+		n := remaining
+		if n > burst {
+			n = burst
+		}
+		r := bucket.ReserveN(now, n)
+		reservations = append(reservations, r)
+		delay = r.DelayFrom(now)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && now.Add(delay).After(deadline) {
+		cancel(now)
+		return 0, fmt.Errorf("sending %d bytes on channel %s requires waiting %v, which exceeds the deadline", size, channel, delay)
+	}
+	if delay == 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return delay, nil
+	case <-ctx.Done():
+		cancel(time.Now())
+		return 0, ctx.Err()
+	}
+}
+
+// bucket returns the token bucket of the channel, or nil if the channel is not limited.
+func (l *EgressLimiter) bucket(channel network.Channel) *rate.Limiter {
+	if engine.IsConsensusChannel(channel) {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if bucket, ok := l.buckets[channel]; ok {
+		return bucket
+	}
+
+	limit, ok := l.config.Channels[channel]
+	if !ok {
+		limit = l.config.Default
+	}
+
+	var bucket *rate.Limiter
+	if limit.BytesPerSecond > 0 {
+		burst := limit.Burst
+		if burst == 0 {
+			burst = limit.BytesPerSecond
+		}
+		bucket = rate.NewLimiter(rate.Limit(limit.BytesPerSecond), burst)
+	}
+	l.buckets[channel] = bucket
+
+	return bucket
+}
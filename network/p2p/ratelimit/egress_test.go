@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/network"
+)
+
+func testLimiter(t *testing.T) *EgressLimiter {
+	limiter, err := NewEgressLimiter(Config{
+		Default: Limit{BytesPerSecond: 10_000, Burst: 1_000},
+		Channels: map[network.Channel]Limit{
+			engine.PushBlocks: {},
+		},
+	})
+	require.NoError(t, err)
+	return limiter
+}
+
+// TestWait tests that bytes exceeding the burst of a channel are delayed according to the rate of the channel,
+// and that channels are limited independently of each other.
+func TestWait(t *testing.T) {
+	limiter := testLimiter(t)
+	ctx := context.Background()
+
+	delay, err := limiter.Wait(ctx, engine.RequestChunks, 1_000)
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+
+	// the bucket is empty, hence 500 bytes take 50ms at 10kB/s
+	start := time.Now()
+	delay, err = limiter.Wait(ctx, engine.RequestChunks, 500)
+	require.NoError(t, err)
+	assert.InDelta(t, 50*time.Millisecond, delay, float64(10*time.Millisecond))
+	assert.GreaterOrEqual(t, time.Since(start), delay)
+
+	// other channels have their own bucket
+	delay, err = limiter.Wait(ctx, engine.RequestCollections, 1_000)
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+}
+
+// TestWait_LargeMessage tests that messages larger than the burst of a channel consume several bursts.
+func TestWait_LargeMessage(t *testing.T) {
+	limiter := testLimiter(t)
+
+	// the first burst is available right away, the remaining two take 100ms each
+	delay, err := limiter.Wait(context.Background(), engine.RequestChunks, 3_000)
+	require.NoError(t, err)
+	assert.InDelta(t, 200*time.Millisecond, delay, float64(10*time.Millisecond))
+}
+
+// TestWait_Deadline tests that messages which cannot be sent before the deadline are rejected right away,
+// without consuming bytes of the channel.
+func TestWait_Deadline(t *testing.T) {
+	limiter := testLimiter(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := limiter.Wait(ctx, engine.RequestChunks, 2_000)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 10*time.Millisecond)
+
+	// the rejected message did not consume the burst
+	delay, err := limiter.Wait(ctx, engine.RequestChunks, 1_000)
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+}
+
+// TestWait_Exempt tests that consensus channels and channels configured without rate are not limited.
+func TestWait_Exempt(t *testing.T) {
+	limiter := testLimiter(t)
+
+	for _, channel := range []network.Channel{
+		engine.ConsensusCommittee,
+		engine.ChannelConsensusCluster("cluster"),
+		engine.PushBlocks,
+	} {
+		for i := 0; i < 10; i++ {
+			delay, err := limiter.Wait(context.Background(), channel, 10_000)
+			require.NoError(t, err)
+			assert.Zero(t, delay, channel)
+		}
+	}
+}
+
+// TestInvalidConfig tests that negative limits and limits of consensus channels are rejected.
+func TestInvalidConfig(t *testing.T) {
+	_, err := NewEgressLimiter(Config{Default: Limit{BytesPerSecond: -1}})
+	require.Error(t, err)
+
+	_, err = NewEgressLimiter(Config{Channels: map[network.Channel]Limit{engine.RequestChunks: {Burst: -1}}})
+	require.Error(t, err)
+
+	_, err = NewEgressLimiter(Config{Channels: map[network.Channel]Limit{engine.ConsensusCommittee: {BytesPerSecond: 1}}})
+	require.Error(t, err)
+}
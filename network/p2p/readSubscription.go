@@ -23,12 +23,14 @@ type readSubscription struct {
 	sub      *pubsub.Subscription
 	metrics  module.NetworkMetrics
 	callback func(msg *message.Message, peerID peer.ID)
+	peerRole func(peerID peer.ID) string
 }
 
 // newReadSubscription reads the messages coming in on the subscription
 func newReadSubscription(ctx context.Context,
 	sub *pubsub.Subscription,
 	callback func(msg *message.Message, peerID peer.ID),
+	peerRole func(peerID peer.ID) string,
 	log zerolog.Logger,
 	metrics module.NetworkMetrics) *readSubscription {
 
@@ -41,6 +43,7 @@ func newReadSubscription(ctx context.Context,
 		log:      log,
 		sub:      sub,
 		callback: callback,
+		peerRole: peerRole,
 		metrics:  metrics,
 	}
 
@@ -89,6 +92,7 @@ func (r *readSubscription) receiveLoop(wg *sync.WaitGroup) {
 
 		// log metrics
 		r.metrics.NetworkMessageReceived(msg.Size(), msg.ChannelID, msg.Type)
+		r.metrics.PubSubBytesReceived(len(rawMsg.Data), msg.ChannelID, rawMsg.ReceivedFrom.String(), r.peerRole(rawMsg.ReceivedFrom))
 
 		// call the callback
 		r.callback(msg, validatorData.From)
@@ -0,0 +1,596 @@
+package p2p
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	libp2pnetwork "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/multiformats/go-multistream"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/message"
+	"github.com/onflow/flow-go/network/p2p/unicast"
+)
+
+// requestStreamIdleTimeout is the time after which an outbound stream of the request protocol without pending
+// requests is closed by the requester. The responder drops streams without requests after twice this time.
+const requestStreamIdleTimeout = time.Minute
+
+// maxRequestErrorSize is the maximum size of the reason sent back for a request which could not be served.
+const maxRequestErrorSize = kb
+
+// maxConcurrentRequestsPerStream is the maximum number of requests read from an incoming stream of the request
+// protocol which are served at the same time.
+const maxConcurrentRequestsPerStream = 8
+
+// maxInboundRequestStreamsPerPeer is the maximum number of incoming streams of the request protocol a remote peer
+// may have open at the same time. Requesters share a single stream for all their requests, but may open a new one
+// before the responder notices that the previous one was closed.
+const maxInboundRequestStreamsPerPeer = 2
+
+const (
+	requestFrameMessage byte = iota // frame carrying a request, or the response to a request
+	requestFrameError               // frame carrying the reason a request could not be served
+)
+
+var errOversizedRequestFrame = errors.New("request frame exceeds max size")
+
+// requestFrame is the unit written on the streams of the request protocol. Each frame carries the ID of its
+// request, which is chosen by the requester and copied by the responder to the frame of the response, so that
+// responses can be matched to requests sharing the same stream.
+type requestFrame struct {
+	id   uint64
+	kind byte
+	body []byte
+}
+
+// writeRequestFrame writes the frame to the writer and flushes it.
+func writeRequestFrame(w *bufio.Writer, frame requestFrame) error {
+	header := make([]byte, 2*binary.MaxVarintLen64+1)
+	n := binary.PutUvarint(header, frame.id)
+	header[n] = frame.kind
+	n++
+	n += binary.PutUvarint(header[n:], uint64(len(frame.body)))
+
+	_, err := w.Write(header[:n])
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(frame.body)
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// readRequestFrame reads the next frame from the reader. It returns io.EOF if the stream was closed before the
+// frame, and errOversizedRequestFrame if the body of the frame exceeds the given max size.
+func readRequestFrame(r *bufio.Reader, maxSize int) (requestFrame, error) {
+	id, err := binary.ReadUvarint(r)
+	if err != nil {
+		return requestFrame{}, err
+	}
+
+	kind, err := r.ReadByte()
+	if err != nil {
+		return requestFrame{}, fmt.Errorf("could not read frame kind: %w", err)
+	}
+	switch kind {
+	case requestFrameMessage:
+	case requestFrameError:
+		maxSize = maxRequestErrorSize
+	default:
+		return requestFrame{}, fmt.Errorf("unknown frame kind: %d", kind)
+	}
+
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return requestFrame{}, fmt.Errorf("could not read frame size: %w", err)
+	}
+	if size > uint64(maxSize) {
+		return requestFrame{}, fmt.Errorf("frame size %d exceeds max size %d: %w", size, maxSize, errOversizedRequestFrame)
+	}
+
+	body := make([]byte, size)
+	_, err = io.ReadFull(r, body)
+	if err != nil {
+		return requestFrame{}, fmt.Errorf("could not read frame body: %w", err)
+	}
+
+	return requestFrame{id: id, kind: kind, body: body}, nil
+}
+
+// requestErrorFrame returns the frame reporting that a request could not be served for the given reason.
+func requestErrorFrame(reason string) requestFrame {
+	if len(reason) > maxRequestErrorSize {
+		reason = reason[:maxRequestErrorSize]
+	}
+	return requestFrame{kind: requestFrameError, body: []byte(reason)}
+}
+
+// requestResult is the outcome of a request sent on a request stream.
+type requestResult struct {
+	msg *message.Message
+	err error
+}
+
+// requestStream is the outbound stream of the request protocol to a remote peer, which is shared by all the
+// requests to the peer. The stream is opened on the first request, and closed once it has been idle, i.e. without
+// pending requests, for requestStreamIdleTimeout. A reader routine matches the responses read from the stream to
+// the pending requests by their ID.
+type requestStream struct {
+	sync.Mutex
+	stream  libp2pnetwork.Stream // nil if no stream is open
+	writer  *bufio.Writer
+	idle    *time.Timer
+	nextID  uint64
+	pending map[uint64]chan requestResult
+}
+
+func newRequestStream() *requestStream {
+	return &requestStream{
+		pending: make(map[uint64]chan requestResult),
+	}
+}
+
+// open sets the given stream as the stream of the requests to the peer. The caller must hold the lock.
+func (rs *requestStream) open(s libp2pnetwork.Stream) {
+	rs.stream = s
+	rs.writer = bufio.NewWriter(s)
+	rs.idle = time.AfterFunc(requestStreamIdleTimeout, func() {
+		rs.closeIfIdle(s)
+	})
+}
+
+// resolve delivers the result of the request with the given ID, unless the request is no longer pending, e.g.
+// because its context is done.
+func (rs *requestStream) resolve(id uint64, result requestResult) {
+	rs.Lock()
+	defer rs.Unlock()
+
+	pending, ok := rs.pending[id]
+	if !ok {
+		return
+	}
+	delete(rs.pending, id)
+	pending <- result
+	rs.resetIdle()
+}
+
+// cancel drops the request with the given ID, whose response is ignored if it arrives later on.
+func (rs *requestStream) cancel(id uint64) {
+	rs.Lock()
+	defer rs.Unlock()
+
+	delete(rs.pending, id)
+	rs.resetIdle()
+}
+
+// resetIdle restarts the idle timer of the stream if it has no pending requests. The caller must hold the lock.
+func (rs *requestStream) resetIdle() {
+	if rs.stream != nil && len(rs.pending) == 0 {
+		rs.idle.Reset(requestStreamIdleTimeout)
+	}
+}
+
+// closeIfIdle closes the given stream if it is still the stream of the requests to the peer and has no pending
+// requests.
+func (rs *requestStream) closeIfIdle(s libp2pnetwork.Stream) {
+	rs.Lock()
+	defer rs.Unlock()
+
+	if rs.stream != s || len(rs.pending) > 0 {
+		return
+	}
+	rs.stream = nil
+	rs.writer = nil
+	_ = s.Close()
+}
+
+// fail drops the given stream after it failed, and fails all the requests pending on it with the given error.
+func (rs *requestStream) fail(s libp2pnetwork.Stream, err error) {
+	rs.Lock()
+	defer rs.Unlock()
+
+	rs.failLocked(s, err)
+}
+
+// failLocked is fail for callers holding the lock.
+func (rs *requestStream) failLocked(s libp2pnetwork.Stream, err error) {
+	if rs.stream != s {
+		return
+	}
+	rs.stream = nil
+	rs.writer = nil
+	rs.idle.Stop()
+	_ = s.Reset()
+
+	for id, pending := range rs.pending {
+		delete(rs.pending, id)
+		pending <- requestResult{err: err}
+	}
+}
+
+// SendRequest sends the request msg on a 1-1 direct connection to the target ID, and blocks until the target
+// responds or the context is done. If the context has no deadline, the request is bounded by the unicast
+// timeout of the middleware.
+//
+// Requests to the same target share a stream of the request protocol, which is kept open until it is idle.
+// It returns a RemoteRequestError if the target could not serve the request, and a RequestNotSupportedError if the
+// target does not support the request protocol.
+func (m *Middleware) SendRequest(ctx context.Context, msg *message.Message, targetID flow.Identifier) (*message.Message, error) {
+	peerID, err := m.idTranslator.GetPeerID(targetID)
+	if err != nil {
+		return nil, fmt.Errorf("could not find peer id for target id: %w", err)
+	}
+
+	maxMsgSize := unicastMaxMsgSize(msg)
+	if msg.Size() > maxMsgSize {
+		return nil, fmt.Errorf("message size %d exceeds configured max message size %d", msg.Size(), maxMsgSize)
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, m.unicastMaxMsgDuration(msg))
+		defer cancel()
+	}
+	deadline, _ := ctx.Deadline()
+
+	err = m.waitEgress(network.Channel(msg.ChannelID), msg.Size(), time.Until(deadline))
+	if err != nil {
+		return nil, fmt.Errorf("could not send request to %s: %w", targetID, err)
+	}
+
+	m.metrics.DirectMessageStarted(msg.ChannelID)
+	defer m.metrics.DirectMessageFinished(msg.ChannelID)
+
+	// protect the underlying connection from being inadvertently pruned by the peer manager while the request
+	// is pending
+	tag := fmt.Sprintf("%v:%v", msg.ChannelID, msg.Type)
+	m.libP2PNode.host.ConnManager().Protect(peerID, tag)
+	defer m.libP2PNode.host.ConnManager().Unprotect(peerID, tag)
+
+	data, err := msg.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	rs, id, result, err := m.writeRequest(ctx, peerID, data, deadline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request to %s: %w", targetID, err)
+	}
+
+	m.metrics.NetworkMessageSent(msg.Size(), metrics.ChannelOneToOne, msg.Type)
+	m.metrics.UnicastBytesSent(msg.Size(), msg.ChannelID, peerID.String(), m.peerRole(peerID))
+	m.capture(capture.Outbound, msg)
+
+	var res requestResult
+	select {
+	case <-ctx.Done():
+		rs.cancel(id)
+		return nil, fmt.Errorf("no response to request from %s: %w", targetID, ctx.Err())
+	case res = <-result:
+	}
+	if res.err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", targetID, res.err)
+	}
+
+	// the response is authenticated by the stream it was read from
+	res.msg.OriginID = targetID[:]
+	for _, v := range m.validators {
+		if !v.Validate(*res.msg) {
+			return nil, fmt.Errorf("response from %s rejected by message validators", targetID)
+		}
+	}
+
+	m.metrics.NetworkMessageReceived(res.msg.Size(), metrics.ChannelOneToOne, res.msg.Type)
+	m.metrics.UnicastBytesReceived(res.msg.Size(), res.msg.ChannelID, peerID.String(), m.peerRole(peerID))
+	m.capture(capture.Inbound, res.msg)
+
+	return res.msg, nil
+}
+
+// requestStreamTo returns the request stream to the given peer.
+func (m *Middleware) requestStreamTo(peerID peer.ID) *requestStream {
+	m.requestStreamsLock.Lock()
+	defer m.requestStreamsLock.Unlock()
+
+	rs, ok := m.requestStreams[peerID]
+	if !ok {
+		rs = newRequestStream()
+		m.requestStreams[peerID] = rs
+	}
+	return rs
+}
+
+// writeRequest writes the marshalled request to the request stream of the given peer, which is opened if needed.
+// It returns the stream and the ID of the request, and the channel on which the result of the request is delivered.
+func (m *Middleware) writeRequest(ctx context.Context, peerID peer.ID, data []byte, deadline time.Time) (*requestStream, uint64, chan requestResult, error) {
+	rs := m.requestStreamTo(peerID)
+
+	rs.Lock()
+	defer rs.Unlock()
+
+	if rs.stream == nil {
+		s, err := m.libP2PNode.host.NewStream(ctx, peerID, unicast.RequestProtocolId(m.rootBlockID))
+		if errors.Is(err, multistream.ErrNotSupported) {
+			return nil, 0, nil, network.NewRequestNotSupportedError(err)
+		}
+		if err != nil {
+			return nil, 0, nil, fmt.Errorf("failed to create stream: %w", err)
+		}
+		rs.open(s)
+
+		m.wg.Add(1)
+		go m.readResponses(peerID, rs, s)
+	}
+
+	rs.nextID++
+	id := rs.nextID
+	result := make(chan requestResult, 1)
+	rs.pending[id] = result
+	rs.idle.Stop()
+
+	s := rs.stream
+	err := s.SetWriteDeadline(deadline)
+	if err == nil {
+		err = writeRequestFrame(rs.writer, requestFrame{id: id, kind: requestFrameMessage, body: data})
+	}
+	if err != nil {
+		delete(rs.pending, id)
+		rs.failLocked(s, fmt.Errorf("failed to write to request stream: %w", err))
+		return nil, 0, nil, err
+	}
+
+	return rs, id, result, nil
+}
+
+// readResponses reads the responses from the given request stream to the peer, and delivers them to their pending
+// requests until the stream is closed or fails.
+func (m *Middleware) readResponses(peerID peer.ID, rs *requestStream, s libp2pnetwork.Stream) {
+	defer m.wg.Done()
+
+	r := bufio.NewReader(s)
+	for {
+		frame, err := readRequestFrame(r, LargeMsgMaxUnicastMsgSize)
+		if err != nil {
+			if errors.Is(err, errOversizedRequestFrame) {
+				m.reportPeer(peerID, network.OversizedMessage)
+			}
+			rs.fail(s, fmt.Errorf("failed to read from request stream: %w", err))
+			return
+		}
+
+		if frame.kind == requestFrameError {
+			rs.resolve(frame.id, requestResult{err: network.NewRemoteRequestError(string(frame.body))})
+			continue
+		}
+
+		var msg message.Message
+		err = msg.Unmarshal(frame.body)
+		if err != nil {
+			m.reportPeer(peerID, network.InvalidMessage)
+			rs.resolve(frame.id, requestResult{err: fmt.Errorf("could not unmarshal response: %w", err)})
+			continue
+		}
+		rs.resolve(frame.id, requestResult{msg: &msg})
+	}
+}
+
+// handleIncomingRequestStream handles an incoming stream of the request protocol from a remote peer. The requests
+// read from the stream are served concurrently by the overlay, with at most maxConcurrentRequestsPerStream requests
+// being served at a time, and their responses are written back to the stream as they complete, tagged with the ID
+// of their request. A peer may have at most maxInboundRequestStreamsPerPeer streams open at a time.
+func (m *Middleware) handleIncomingRequestStream(s libp2pnetwork.Stream) {
+	// qualify the logger with local and remote address
+	log := streamLogger(m.log, s)
+	peerID := s.Conn().RemotePeer()
+
+	success := false
+	var workers sync.WaitGroup
+	var resetOnce sync.Once
+	reset := func() {
+		resetOnce.Do(func() {
+			err := s.Reset()
+			if err != nil {
+				log.Err(err).Msg("failed to reset request stream")
+			}
+		})
+	}
+
+	defer func() {
+		if success {
+			// the responses of the requests being served are written before the stream is closed
+			workers.Wait()
+			err := s.Close()
+			if err != nil {
+				log.Err(err).Msg("failed to close request stream")
+			}
+		} else {
+			reset()
+			workers.Wait()
+		}
+	}()
+
+	originID, err := m.idTranslator.GetFlowID(peerID)
+	if err != nil {
+		log.Warn().Err(err).Msg("received request stream from unknown peer")
+		m.reportPeer(peerID, network.UnauthorizedSender)
+		return
+	}
+
+	if !m.acquireInboundRequestStream(peerID) {
+		log.Warn().Msg("dropped request stream, as the peer exceeds the max number of request streams")
+		return
+	}
+	defer m.releaseInboundRequestStream(peerID)
+
+	var writeLock sync.Mutex
+	w := bufio.NewWriter(s)
+	writeResponse := func(response requestFrame, res *message.Message) {
+		writeLock.Lock()
+		defer writeLock.Unlock()
+
+		err := s.SetWriteDeadline(time.Now().Add(m.unicastMessageTimeout))
+		if err != nil {
+			log.Err(err).Msg("failed to set write deadline for request stream")
+			reset()
+			return
+		}
+		err = writeRequestFrame(w, response)
+		if err != nil {
+			log.Err(err).Msg("failed to write response")
+			reset()
+			return
+		}
+
+		if res != nil {
+			m.metrics.NetworkMessageSent(res.Size(), metrics.ChannelOneToOne, res.Type)
+			m.metrics.UnicastBytesSent(res.Size(), res.ChannelID, peerID.String(), m.peerRole(peerID))
+			m.capture(capture.Outbound, res)
+		}
+	}
+
+	slots := make(chan struct{}, maxConcurrentRequestsPerStream)
+	r := bufio.NewReader(s)
+	for {
+		if m.ctx.Err() != nil {
+			return
+		}
+
+		// the requester closes idle streams, hence a stream without requests for longer is dropped
+		err = s.SetReadDeadline(time.Now().Add(2 * requestStreamIdleTimeout))
+		if err != nil {
+			log.Err(err).Msg("failed to set read deadline for request stream")
+			return
+		}
+
+		frame, err := readRequestFrame(r, DefaultMaxUnicastMsgSize)
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			if errors.Is(err, errOversizedRequestFrame) {
+				m.reportPeer(peerID, network.OversizedMessage)
+			}
+			log.Err(err).Msg("failed to read request")
+			return
+		}
+
+		msg := &message.Message{}
+		if frame.kind != requestFrameMessage || msg.Unmarshal(frame.body) != nil {
+			log.Warn().Msg("received invalid request")
+			m.reportPeer(peerID, network.InvalidMessage)
+			return
+		}
+
+		m.metrics.NetworkMessageReceived(msg.Size(), metrics.ChannelOneToOne, msg.Type)
+		m.metrics.UnicastBytesReceived(msg.Size(), msg.ChannelID, peerID.String(), m.peerRole(peerID))
+
+		// wait for a free slot, which stops reading further requests from the stream while all slots are taken
+		select {
+		case <-m.ctx.Done():
+			return
+		case slots <- struct{}{}:
+		}
+
+		workers.Add(1)
+		go func(id uint64) {
+			defer func() {
+				<-slots
+				workers.Done()
+			}()
+
+			response, res := m.serveRequest(originID, msg)
+			response.id = id
+			writeResponse(response, res)
+		}(frame.id)
+	}
+
+	success = true
+}
+
+// acquireInboundRequestStream registers an incoming request stream from the given peer. It returns false if the peer
+// already has maxInboundRequestStreamsPerPeer streams open, in which case the stream must be dropped.
+func (m *Middleware) acquireInboundRequestStream(peerID peer.ID) bool {
+	m.requestStreamsLock.Lock()
+	defer m.requestStreamsLock.Unlock()
+
+	if m.inboundRequestStreams[peerID] >= maxInboundRequestStreamsPerPeer {
+		return false
+	}
+	m.inboundRequestStreams[peerID]++
+	return true
+}
+
+// releaseInboundRequestStream unregisters an incoming request stream from the given peer.
+func (m *Middleware) releaseInboundRequestStream(peerID peer.ID) {
+	m.requestStreamsLock.Lock()
+	defer m.requestStreamsLock.Unlock()
+
+	m.inboundRequestStreams[peerID]--
+	if m.inboundRequestStreams[peerID] <= 0 {
+		delete(m.inboundRequestStreams, peerID)
+	}
+}
+
+// dropRequestStream removes the outbound request stream to the given peer, which has disconnected. The stream itself
+// fails with the connection, which fails the requests pending on it, and a new stream is opened on the next request.
+func (m *Middleware) dropRequestStream(peerID peer.ID) {
+	m.requestStreamsLock.Lock()
+	defer m.requestStreamsLock.Unlock()
+
+	delete(m.requestStreams, peerID)
+}
+
+// serveRequest serves the request received from the given node, and returns the frame to send back, together with
+// the response it carries, which is nil if the request could not be served.
+func (m *Middleware) serveRequest(originID flow.Identifier, msg *message.Message) (requestFrame, *message.Message) {
+	msg.OriginID = originID[:]
+
+	for _, v := range m.validators {
+		if !v.Validate(*msg) {
+			return requestErrorFrame("request rejected by message validators"), nil
+		}
+	}
+
+	m.capture(capture.Inbound, msg)
+
+	res, err := m.ov.ReceiveRequest(originID, msg)
+	if err != nil {
+		m.log.Debug().
+			Err(err).
+			Str("channel", msg.ChannelID).
+			Str("type", msg.Type).
+			Hex("origin_id", originID[:]).
+			Msg("could not serve request")
+		return requestErrorFrame(err.Error()), nil
+	}
+
+	maxMsgSize := unicastMaxMsgSize(res)
+	if res.Size() > maxMsgSize {
+		return requestErrorFrame(fmt.Sprintf("response size %d exceeds configured max message size %d", res.Size(), maxMsgSize)), nil
+	}
+
+	err = m.waitEgress(network.Channel(res.ChannelID), res.Size(), m.unicastMaxMsgDuration(res))
+	if err != nil {
+		return requestErrorFrame(err.Error()), nil
+	}
+
+	data, err := res.Marshal()
+	if err != nil {
+		return requestErrorFrame("could not marshal response"), nil
+	}
+
+	return requestFrame{kind: requestFrameMessage, body: data}, res
+}
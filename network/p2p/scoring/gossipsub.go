@@ -0,0 +1,59 @@
+package scoring
+
+import (
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+const (
+	// gossipThresholdFraction is the fraction of the block threshold at which GossipSub stops gossiping
+	// with a peer and prunes it from the mesh. Scores above it are reported to GossipSub as 0, so that
+	// occasional misbehaviors do not affect the mesh.
+	gossipThresholdFraction = 0.5
+
+	// publishThresholdFraction is the fraction of the block threshold at which GossipSub stops
+	// publishing messages to a peer.
+	publishThresholdFraction = 0.75
+
+	// decayInterval is the interval at which GossipSub decays its internal counters. The scores of
+	// the registry decay continuously, independent of this interval.
+	decayInterval = time.Minute
+)
+
+// PubSubOption returns a GossipSub option feeding the scores of the registry into the peer scoring of
+// GossipSub, as application specific score. Scores at or below the block threshold graylist the peer,
+// i.e. all of its messages are ignored, until it is disconnected.
+func (r *Registry) PubSubOption() pubsub.Option {
+	return pubsub.WithPeerScore(r.peerScoreParams(), r.peerScoreThresholds())
+}
+
+func (r *Registry) peerScoreParams() *pubsub.PeerScoreParams {
+	return &pubsub.PeerScoreParams{
+		AppSpecificScore:  r.appSpecificScore,
+		AppSpecificWeight: 1,
+		DecayInterval:     decayInterval,
+		DecayToZero:       pubsub.DefaultDecayToZero,
+		RetainScore:       r.config.BlockDuration,
+	}
+}
+
+func (r *Registry) peerScoreThresholds() *pubsub.PeerScoreThresholds {
+	return &pubsub.PeerScoreThresholds{
+		GossipThreshold:   gossipThresholdFraction * r.config.BlockThreshold,
+		PublishThreshold:  publishThresholdFraction * r.config.BlockThreshold,
+		GraylistThreshold: r.config.BlockThreshold,
+		// the application specific score is never positive, hence peer exchange is never accepted
+		AcceptPXThreshold: 1,
+	}
+}
+
+// appSpecificScore returns the score of the peer if it is at or below the gossip threshold, and 0 otherwise.
+func (r *Registry) appSpecificScore(pid peer.ID) float64 {
+	score := r.Score(pid)
+	if score > gossipThresholdFraction*r.config.BlockThreshold {
+		return 0
+	}
+	return score
+}
@@ -0,0 +1,279 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/network"
+)
+
+// decayToZero is the magnitude below which a decayed score is considered zero, and the record of a peer
+// which is not blocked is pruned.
+const decayToZero = 0.1
+
+// Config contains the parameters of the peer scoring.
+type Config struct {
+	// Enforce enables disconnecting and blocking peers, and feeding the scores into GossipSub. Otherwise
+	// the scores are only tracked and peers whose score drops to the BlockThreshold are logged.
+	Enforce bool
+
+	// Penalties are the (negative) values added to the score of a peer for each reported misbehavior.
+	// Misbehaviors without a configured penalty are counted, but not penalized.
+	Penalties map[network.Misbehavior]float64
+
+	// DecayHalfLife is the time after which the penalties of a peer have decayed to half of their value.
+	DecayHalfLife time.Duration
+
+	// BlockThreshold is the (negative) score at or below which a peer is disconnected and blocked.
+	BlockThreshold float64
+
+	// BlockDuration is the time for which a peer is blocked once its score dropped to the BlockThreshold.
+	BlockDuration time.Duration
+}
+
+// DefaultConfig returns the default peer scoring parameters. Scoring is not enforced by default; if it
+// is, a peer is blocked after sending about ten invalid messages, or four oversized messages, within a
+// few minutes.
+func DefaultConfig() Config {
+	return Config{
+		Enforce: false,
+		Penalties: map[network.Misbehavior]float64{
+			network.InvalidMessage:     -10,
+			network.UnauthorizedSender: -25,
+			network.OversizedMessage:   -25,
+			network.ProtocolViolation:  -10,
+		},
+		DecayHalfLife:  10 * time.Minute,
+		BlockThreshold: -100,
+		BlockDuration:  30 * time.Minute,
+	}
+}
+
+func (c Config) validate() error {
+	for misbehavior, penalty := range c.Penalties {
+		if penalty > 0 || math.IsNaN(penalty) || math.IsInf(penalty, 0) {
+			return fmt.Errorf("invalid penalty %f for %s: must be negative or zero", penalty, misbehavior)
+		}
+	}
+	if c.DecayHalfLife <= 0 {
+		return fmt.Errorf("invalid decay half life %s: must be positive", c.DecayHalfLife)
+	}
+	if c.BlockThreshold >= 0 || math.IsNaN(c.BlockThreshold) || math.IsInf(c.BlockThreshold, 0) {
+		return fmt.Errorf("invalid block threshold %f: must be negative", c.BlockThreshold)
+	}
+	if c.BlockDuration <= 0 {
+		return fmt.Errorf("invalid block duration %s: must be positive", c.BlockDuration)
+	}
+	return nil
+}
+
+// PeerScore is a snapshot of the score of a single peer.
+type PeerScore struct {
+	PeerID       peer.ID
+	Score        float64
+	BlockedUntil time.Time // zero if the peer has never been blocked
+	Misbehaviors map[network.Misbehavior]uint64
+}
+
+// Blocked returns true if the peer was blocked at the given time.
+func (s PeerScore) Blocked(now time.Time) bool {
+	return s.BlockedUntil.After(now)
+}
+
+type record struct {
+	score        float64
+	updated      time.Time
+	blockedUntil time.Time
+	misbehaviors map[network.Misbehavior]uint64
+	// belowThreshold is set once an unenforced score drop to the threshold is logged, so it is only
+	// logged again after the score recovered
+	belowThreshold bool
+}
+
+// Registry keeps track of the misbehaviors reported for remote peers. Every misbehavior adds a penalty to
+// the score of the peer, which decays exponentially over time. Peers whose score drops to the block
+// threshold are blocked for the configured duration. All peers start with, and decay towards, a score of 0.
+//
+// Registry is safe for concurrent use.
+type Registry struct {
+	sync.Mutex
+	log        zerolog.Logger
+	config     Config
+	records    map[peer.ID]*record
+	lastPruned time.Time
+	now        func() time.Time
+}
+
+// NewRegistry creates a new peer score registry with the given config.
+func NewRegistry(log zerolog.Logger, config Config) (*Registry, error) {
+	err := config.validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid peer scoring config: %w", err)
+	}
+
+	return &Registry{
+		log:     log.With().Str("component", "peer_scoring").Logger(),
+		config:  config,
+		records: make(map[peer.ID]*record),
+		now:     time.Now,
+	}, nil
+}
+
+// Config returns the peer scoring parameters of the registry.
+func (r *Registry) Config() Config {
+	return r.config
+}
+
+// Report penalizes the given peer for the given misbehavior. It returns true if the peer got blocked
+// as a consequence of the report, and false if it is not blocked or had been blocked before.
+func (r *Registry) Report(pid peer.ID, misbehavior network.Misbehavior) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	now := r.now()
+	if now.Sub(r.lastPruned) >= r.config.DecayHalfLife {
+		r.prune(now)
+	}
+
+	rec, ok := r.records[pid]
+	if !ok {
+		rec = &record{
+			updated:      now,
+			misbehaviors: make(map[network.Misbehavior]uint64),
+		}
+		r.records[pid] = rec
+	}
+	r.decay(rec, now)
+
+	rec.score += r.config.Penalties[misbehavior]
+	rec.misbehaviors[misbehavior]++
+
+	if rec.score > r.config.BlockThreshold {
+		rec.belowThreshold = false
+		return false
+	}
+	if rec.blockedUntil.After(now) {
+		return false
+	}
+
+	if !r.config.Enforce {
+		// only log when the score crosses the threshold, as misbehaving peers could flood the logs otherwise
+		if rec.belowThreshold {
+			return false
+		}
+		rec.belowThreshold = true
+		r.log.Warn().
+			Str("peer_id", pid.Pretty()).
+			Str("misbehavior", misbehavior.String()).
+			Float64("score", rec.score).
+			Msg("peer score dropped below threshold, not blocking peer as peer scoring is not enforced")
+		return false
+	}
+
+	rec.blockedUntil = now.Add(r.config.BlockDuration)
+	r.log.Warn().
+		Str("peer_id", pid.Pretty()).
+		Str("misbehavior", misbehavior.String()).
+		Float64("score", rec.score).
+		Time("blocked_until", rec.blockedUntil).
+		Msg("peer score dropped below threshold, blocking peer")
+
+	return true
+}
+
+// Score returns the current score of the given peer, which is 0 for peers without reported misbehaviors.
+func (r *Registry) Score(pid peer.ID) float64 {
+	r.Lock()
+	defer r.Unlock()
+
+	rec, ok := r.records[pid]
+	if !ok {
+		return 0
+	}
+	r.decay(rec, r.now())
+	return rec.score
+}
+
+// IsBlocked returns true if the given peer is currently blocked.
+func (r *Registry) IsBlocked(pid peer.ID) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	rec, ok := r.records[pid]
+	return ok && rec.blockedUntil.After(r.now())
+}
+
+// Reset forgets all misbehaviors of the given peer and unblocks it. It returns false if no misbehaviors
+// were known for the peer.
+func (r *Registry) Reset(pid peer.ID) bool {
+	r.Lock()
+	defer r.Unlock()
+
+	_, ok := r.records[pid]
+	delete(r.records, pid)
+	return ok
+}
+
+// Peers returns the scores of all peers with reported misbehaviors, ordered from lowest to highest score.
+// Records of peers which are not blocked and whose penalties have decayed are pruned.
+func (r *Registry) Peers() []PeerScore {
+	r.Lock()
+	defer r.Unlock()
+
+	r.prune(r.now())
+
+	scores := make([]PeerScore, 0, len(r.records))
+	for pid, rec := range r.records {
+		misbehaviors := make(map[network.Misbehavior]uint64, len(rec.misbehaviors))
+		for misbehavior, count := range rec.misbehaviors {
+			misbehaviors[misbehavior] = count
+		}
+		scores = append(scores, PeerScore{
+			PeerID:       pid,
+			Score:        rec.score,
+			BlockedUntil: rec.blockedUntil,
+			Misbehaviors: misbehaviors,
+		})
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score < scores[j].Score
+		}
+		return scores[i].PeerID < scores[j].PeerID
+	})
+	return scores
+}
+
+// prune decays the scores of all records up to the given time, and removes the records of peers which
+// are not blocked and whose penalties have decayed. Reports prune the records once per decay half life,
+// so records of peers which stopped misbehaving do not accumulate. Must be called with the lock held.
+func (r *Registry) prune(now time.Time) {
+	for pid, rec := range r.records {
+		r.decay(rec, now)
+		if rec.score > -decayToZero && !rec.blockedUntil.After(now) {
+			delete(r.records, pid)
+		}
+	}
+	r.lastPruned = now
+}
+
+// decay applies the exponential decay to the score of the given record up to the given time.
+// Must be called with the lock held.
+func (r *Registry) decay(rec *record, now time.Time) {
+	elapsed := now.Sub(rec.updated)
+	if elapsed <= 0 {
+		return
+	}
+	rec.score *= math.Pow(0.5, float64(elapsed)/float64(r.config.DecayHalfLife))
+	if rec.score > -decayToZero {
+		rec.score = 0
+	}
+	rec.updated = now
+}
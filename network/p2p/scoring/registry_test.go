@@ -0,0 +1,205 @@
+package scoring
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/network"
+)
+
+// testRegistry returns a registry enforcing the default config and a clock controlled by the returned function.
+func testRegistry(t *testing.T) (*Registry, func(time.Duration)) {
+	config := DefaultConfig()
+	config.Enforce = true
+	registry, err := NewRegistry(zerolog.Nop(), config)
+	require.NoError(t, err)
+
+	now := time.Unix(1_000_000, 0)
+	registry.now = func() time.Time { return now }
+	return registry, func(d time.Duration) { now = now.Add(d) }
+}
+
+// TestReport tests that penalties accumulate and decay over time.
+func TestReport(t *testing.T) {
+	registry, advance := testRegistry(t)
+	pid := peer.ID("peer-1")
+
+	assert.Equal(t, float64(0), registry.Score(pid))
+
+	assert.False(t, registry.Report(pid, network.InvalidMessage))
+	assert.False(t, registry.Report(pid, network.InvalidMessage))
+	assert.Equal(t, float64(-20), registry.Score(pid))
+
+	advance(registry.config.DecayHalfLife)
+	assert.InDelta(t, -10, registry.Score(pid), 1e-9)
+
+	assert.False(t, registry.Report(pid, network.OversizedMessage))
+	assert.InDelta(t, -35, registry.Score(pid), 1e-9)
+
+	// misbehaviors without penalty are counted only
+	assert.False(t, registry.Report(pid, network.Misbehavior("unknown")))
+	assert.InDelta(t, -35, registry.Score(pid), 1e-9)
+
+	peers := registry.Peers()
+	require.Len(t, peers, 1)
+	assert.Equal(t, pid, peers[0].PeerID)
+	assert.Equal(t, map[network.Misbehavior]uint64{
+		network.InvalidMessage:         2,
+		network.OversizedMessage:       1,
+		network.Misbehavior("unknown"): 1,
+	}, peers[0].Misbehaviors)
+
+	// once the penalties decayed, the record is pruned
+	advance(20 * registry.config.DecayHalfLife)
+	assert.Equal(t, float64(0), registry.Score(pid))
+	assert.Empty(t, registry.Peers())
+}
+
+// TestBlock tests that peers are blocked once their score drops to the threshold, and are
+// unblocked after the block duration, or when reset.
+func TestBlock(t *testing.T) {
+	registry, advance := testRegistry(t)
+	pid := peer.ID("peer-1")
+	other := peer.ID("peer-2")
+
+	assert.False(t, registry.Report(other, network.InvalidMessage))
+	for i := 0; i < 3; i++ {
+		assert.False(t, registry.Report(pid, network.UnauthorizedSender))
+		assert.False(t, registry.IsBlocked(pid))
+	}
+	assert.True(t, registry.Report(pid, network.UnauthorizedSender))
+	assert.True(t, registry.IsBlocked(pid))
+	assert.False(t, registry.IsBlocked(other))
+
+	// reports for a blocked peer do not block it again
+	assert.False(t, registry.Report(pid, network.UnauthorizedSender))
+
+	peers := registry.Peers()
+	require.Len(t, peers, 2)
+	assert.Equal(t, pid, peers[0].PeerID)
+	assert.True(t, peers[0].Blocked(registry.now()))
+	assert.Equal(t, other, peers[1].PeerID)
+	assert.False(t, peers[1].Blocked(registry.now()))
+
+	advance(registry.config.BlockDuration)
+	assert.False(t, registry.IsBlocked(pid))
+
+	// blocked peers can be reset by the operator
+	for i := 0; i < 4; i++ {
+		registry.Report(pid, network.OversizedMessage)
+	}
+	require.True(t, registry.IsBlocked(pid))
+	assert.True(t, registry.Reset(pid))
+	assert.False(t, registry.IsBlocked(pid))
+	assert.Equal(t, float64(0), registry.Score(pid))
+	assert.False(t, registry.Reset(pid))
+}
+
+// TestNotEnforced tests that peers are never blocked if the peer scoring is not enforced.
+func TestNotEnforced(t *testing.T) {
+	registry, _ := testRegistry(t)
+	registry.config.Enforce = false
+	pid := peer.ID("peer-1")
+
+	for i := 0; i < 10; i++ {
+		assert.False(t, registry.Report(pid, network.OversizedMessage))
+		assert.False(t, registry.IsBlocked(pid))
+	}
+	assert.Equal(t, float64(-250), registry.Score(pid))
+
+	peers := registry.Peers()
+	require.Len(t, peers, 1)
+	assert.False(t, peers[0].Blocked(registry.now()))
+}
+
+// TestNotEnforcedLogging tests that unenforced score drops to the threshold are only logged when the
+// score crosses the threshold, rather than for every report of a peer below the threshold.
+func TestNotEnforcedLogging(t *testing.T) {
+	registry, advance := testRegistry(t)
+	registry.config.Enforce = false
+	logs := &bytes.Buffer{}
+	registry.log = zerolog.New(logs)
+	pid := peer.ID("peer-1")
+
+	for i := 0; i < 10; i++ {
+		registry.Report(pid, network.OversizedMessage)
+	}
+	assert.Equal(t, 1, strings.Count(logs.String(), "not blocking peer"))
+
+	// -250 decays to -62.5 after two half lives, so the next drop to the threshold is logged again
+	advance(2 * registry.config.DecayHalfLife)
+	registry.Report(pid, network.OversizedMessage)
+	assert.Equal(t, 1, strings.Count(logs.String(), "not blocking peer"))
+	registry.Report(pid, network.OversizedMessage)
+	assert.Equal(t, 2, strings.Count(logs.String(), "not blocking peer"))
+}
+
+// TestPrune tests that reports prune the records of peers whose penalties have decayed.
+func TestPrune(t *testing.T) {
+	registry, advance := testRegistry(t)
+	pid := peer.ID("peer-1")
+	other := peer.ID("peer-2")
+
+	registry.Report(pid, network.InvalidMessage)
+	for i := 0; i < 4; i++ {
+		registry.Report(other, network.OversizedMessage)
+	}
+	require.True(t, registry.IsBlocked(other))
+	require.Len(t, registry.records, 2)
+
+	// records are only pruned once per decay half life
+	advance(registry.config.DecayHalfLife / 2)
+	registry.Report(peer.ID("peer-3"), network.Misbehavior("unknown"))
+	assert.Len(t, registry.records, 3)
+
+	// once the penalties have decayed and the block expired, the records are pruned
+	advance(10 * registry.config.DecayHalfLife)
+	registry.Report(peer.ID("peer-4"), network.Misbehavior("unknown"))
+	assert.Len(t, registry.records, 1)
+	assert.Contains(t, registry.records, peer.ID("peer-4"))
+}
+
+// TestInvalidConfig tests that configs with positive penalties or thresholds are rejected.
+func TestInvalidConfig(t *testing.T) {
+	config := DefaultConfig()
+	config.BlockThreshold = 0
+	_, err := NewRegistry(zerolog.Nop(), config)
+	require.Error(t, err)
+
+	config = DefaultConfig()
+	config.Penalties[network.InvalidMessage] = 1
+	_, err = NewRegistry(zerolog.Nop(), config)
+	require.Error(t, err)
+
+	config = DefaultConfig()
+	config.DecayHalfLife = 0
+	_, err = NewRegistry(zerolog.Nop(), config)
+	require.Error(t, err)
+}
+
+// TestPubSubScore tests that only scores at or below the gossip threshold are fed into GossipSub, and
+// that the thresholds satisfy the constraints of GossipSub.
+func TestPubSubScore(t *testing.T) {
+	registry, _ := testRegistry(t)
+	pid := peer.ID("peer-1")
+
+	params := registry.peerScoreParams()
+	registry.Report(pid, network.OversizedMessage)
+	assert.Equal(t, float64(0), params.AppSpecificScore(pid))
+	registry.Report(pid, network.OversizedMessage)
+	assert.Equal(t, float64(-50), params.AppSpecificScore(pid))
+	assert.GreaterOrEqual(t, params.DecayInterval, time.Second)
+
+	thresholds := registry.peerScoreThresholds()
+	assert.LessOrEqual(t, thresholds.GossipThreshold, float64(0))
+	assert.LessOrEqual(t, thresholds.PublishThreshold, thresholds.GossipThreshold)
+	assert.LessOrEqual(t, thresholds.GraylistThreshold, thresholds.PublishThreshold)
+	assert.Equal(t, registry.config.BlockThreshold, thresholds.GraylistThreshold)
+}
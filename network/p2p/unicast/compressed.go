@@ -0,0 +1,50 @@
+package unicast
+
+import (
+	libp2pnet "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/p2p/compressed"
+)
+
+// CompressedStream is a stream compression protocol that creates and returns a compressed stream out of input stream,
+// using the compressor of the protocol.
+type CompressedStream struct {
+	protocolId     protocol.ID
+	compressor     network.Compressor
+	defaultHandler libp2pnet.StreamHandler
+	logger         zerolog.Logger
+}
+
+func newCompressedUnicast(logger zerolog.Logger,
+	protocolId protocol.ID,
+	compressor network.Compressor,
+	defaultHandler libp2pnet.StreamHandler) *CompressedStream {
+	return &CompressedStream{
+		protocolId:     protocolId,
+		compressor:     compressor,
+		defaultHandler: defaultHandler,
+		logger:         logger,
+	}
+}
+
+// UpgradeRawStream wraps compression and decompression around the plain libp2p stream.
+func (c CompressedStream) UpgradeRawStream(s libp2pnet.Stream) (libp2pnet.Stream, error) {
+	return compressed.NewCompressedStream(s, c.compressor)
+}
+
+func (c CompressedStream) Handler(s libp2pnet.Stream) {
+	// converts native libp2p stream to compressed stream
+	s, err := c.UpgradeRawStream(s)
+	if err != nil {
+		c.logger.Error().Err(err).Msg("could not create compressed stream")
+		return
+	}
+	c.defaultHandler(s)
+}
+
+func (c CompressedStream) ProtocolId() protocol.ID {
+	return c.protocolId
+}
@@ -26,8 +26,22 @@ const (
 	// FlowLibP2PPingProtocolPrefix is the Flow Ping protocol prefix
 	FlowLibP2PPingProtocolPrefix = FlowLibP2PProtocolCommonPrefix + "/ping/"
 
+	// FlowLibP2PRequestProtocolPrefix is the Flow request protocol prefix. Requests and their responses are exchanged on
+	// streams of this protocol, which are reused for all the requests to the same peer.
+	FlowLibP2PRequestProtocolPrefix = FlowLibP2PProtocolCommonPrefix + "/request/"
+
+	// FlowLibP2PPeerExchangeProtocolPrefix is the Flow peer exchange protocol prefix, on which nodes of the unstaked
+	// network share the peers they know.
+	FlowLibP2PPeerExchangeProtocolPrefix = FlowLibP2PProtocolCommonPrefix + "/peer-exchange/"
+
 	// FlowLibP2PProtocolGzipCompressedOneToOne represents the protocol id for compressed streams under gzip compressor.
 	FlowLibP2PProtocolGzipCompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/gzip/"
+
+	// FlowLibP2PProtocolZstdCompressedOneToOne represents the protocol id for compressed streams under zstd compressor.
+	FlowLibP2PProtocolZstdCompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/zstd/"
+
+	// FlowLibP2PProtocolSnappyCompressedOneToOne represents the protocol id for compressed streams under snappy compressor.
+	FlowLibP2PProtocolSnappyCompressedOneToOne = FlowLibP2POneToOneProtocolIDPrefix + "/snappy/"
 )
 
 // IsFlowProtocolStream returns true if the libp2p stream is for a Flow protocol
@@ -52,6 +66,14 @@ func PingProtocolId(sporkId flow.Identifier) protocol.ID {
 	return protocol.ID(FlowLibP2PPingProtocolPrefix + sporkId.String())
 }
 
+func RequestProtocolId(sporkId flow.Identifier) protocol.ID {
+	return protocol.ID(FlowLibP2PRequestProtocolPrefix + sporkId.String())
+}
+
+func PeerExchangeProtocolId(sporkId flow.Identifier) protocol.ID {
+	return protocol.ID(FlowLibP2PPeerExchangeProtocolPrefix + sporkId.String())
+}
+
 type ProtocolName string
 type ProtocolFactory func(zerolog.Logger, flow.Identifier, libp2pnet.StreamHandler) Protocol
 
@@ -69,6 +91,14 @@ func ToProtocolFactory(name ProtocolName) (ProtocolFactory, error) {
 		return func(logger zerolog.Logger, sporkId flow.Identifier, handler libp2pnet.StreamHandler) Protocol {
 			return NewGzipCompressedUnicast(logger, sporkId, handler)
 		}, nil
+	case ZstdCompressionUnicast:
+		return func(logger zerolog.Logger, sporkId flow.Identifier, handler libp2pnet.StreamHandler) Protocol {
+			return NewZstdCompressedUnicast(logger, sporkId, handler)
+		}, nil
+	case SnappyCompressionUnicast:
+		return func(logger zerolog.Logger, sporkId flow.Identifier, handler libp2pnet.StreamHandler) Protocol {
+			return NewSnappyCompressedUnicast(logger, sporkId, handler)
+		}, nil
 	default:
 		return nil, fmt.Errorf("unknown unicast protocol name: %s", name)
 	}
@@ -0,0 +1,25 @@
+package unicast
+
+import (
+	libp2pnet "github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/rs/zerolog"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/network/compressor"
+)
+
+const ZstdCompressionUnicast = ProtocolName("zstd-compression")
+
+func FlowZstdProtocolId(sporkId flow.Identifier) protocol.ID {
+	return protocol.ID(FlowLibP2PProtocolZstdCompressedOneToOne + sporkId.String())
+}
+
+// NewZstdCompressedUnicast creates a unicast protocol that wraps zstd compression and decompression around the plain
+// libp2p stream.
+func NewZstdCompressedUnicast(logger zerolog.Logger, sporkId flow.Identifier, defaultHandler libp2pnet.StreamHandler) *CompressedStream {
+	return newCompressedUnicast(logger.With().Str("subsystem", "zstd-unicast").Logger(),
+		FlowZstdProtocolId(sporkId),
+		compressor.ZstdStreamCompressor{},
+		defaultHandler)
+}
@@ -1,6 +1,8 @@
 package proxy
 
 import (
+	"context"
+
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/network"
 )
@@ -23,3 +25,7 @@ func (c *ProxyConduit) Unicast(event interface{}, targetID flow.Identifier) erro
 func (c *ProxyConduit) Multicast(event interface{}, num uint, targetIDs ...flow.Identifier) error {
 	return c.Conduit.Multicast(event, 1, c.targetNodeID)
 }
+
+func (c *ProxyConduit) Request(ctx context.Context, request interface{}, targetID flow.Identifier) (interface{}, error) {
+	return c.Conduit.Request(ctx, request, c.targetNodeID)
+}
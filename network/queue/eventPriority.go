@@ -3,6 +3,7 @@ package queue
 import (
 	"fmt"
 	"math"
+	"strings"
 
 	"github.com/onflow/flow-go/model/flow"
 	libp2pmessage "github.com/onflow/flow-go/model/libp2p/message"
@@ -31,86 +32,89 @@ func GetEventPriority(message interface{}) (Priority, error) {
 	if !ok {
 		return 0, fmt.Errorf("invalid message format: %T", message)
 	}
-	priorityByType := getPriorityByType(qm.Payload)
-	priorityBySize := getPriorityBySize(qm.Size)
-	return Priority(math.Ceil(float64(priorityByType+priorityBySize) / 2)), nil
+	return averagePriority(getPriorityByType(qm.Payload), getPriorityBySize(qm.Size)), nil
 }
 
-// getPriorityByType maps a message type to its priority
-func getPriorityByType(message interface{}) Priority {
-	switch message.(type) {
+// GetMessagePriority returns the priority of an outbound network message with the given type, as in the Type field of
+// the network message, and size. It is derived in the same way as the priority of inbound messages, i.e. it is the
+// average of the priority by message type and priority by message size, rounded up. Hence messages of a low priority
+// type get a priority below MediumPriority only if they are larger than a KiB, e.g. a small SyncRequest gets a priority
+// above MediumPriority.
+func GetMessagePriority(messageType string, size int) Priority {
+	return averagePriority(getPriorityByTypeName(messageType), getPriorityBySize(size))
+}
+
+// averagePriority returns the average of the priority by message type and the priority by message size.
+func averagePriority(priorityByType Priority, priorityBySize Priority) Priority {
+	return Priority(math.Ceil(float64(priorityByType+priorityBySize) / 2))
+}
+
+// priorityByType maps the name of a message type, as in the Type field of network messages, to its priority.
+// Messages of any other type have medium priority.
+var priorityByType = map[string]Priority{
 	// consensus
-	case *messages.BlockProposal:
-		return HighPriority
-	case *messages.BlockVote:
-		return HighPriority
+	typeName(&messages.BlockProposal{}): HighPriority,
+	typeName(&messages.BlockVote{}):     HighPriority,
 
 	// protocol state sync
-	case *messages.SyncRequest:
-		return LowPriority
-	case *messages.SyncResponse:
-		return LowPriority
-	case *messages.RangeRequest:
-		return MediumPriority
-	case *messages.BatchRequest:
-		return MediumPriority
-	case *messages.BlockResponse:
-		return HighPriority
+	typeName(&messages.SyncRequest{}):   LowPriority,
+	typeName(&messages.SyncResponse{}):  LowPriority,
+	typeName(&messages.RangeRequest{}):  MediumPriority,
+	typeName(&messages.BatchRequest{}):  MediumPriority,
+	typeName(&messages.BlockResponse{}): HighPriority,
 
 	// cluster consensus
-	case *messages.ClusterBlockProposal:
-		return HighPriority
-	case *messages.ClusterBlockVote:
-		return HighPriority
-	case *messages.ClusterBlockResponse:
-		return HighPriority
+	typeName(&messages.ClusterBlockProposal{}): HighPriority,
+	typeName(&messages.ClusterBlockVote{}):     HighPriority,
+	typeName(&messages.ClusterBlockResponse{}): HighPriority,
 
 	// collections, guarantees & transactions
-	case *flow.CollectionGuarantee:
-		return HighPriority
-	case *flow.TransactionBody:
-		return HighPriority
-	case *flow.Transaction:
-		return HighPriority
+	typeName(&flow.CollectionGuarantee{}): HighPriority,
+	typeName(&flow.TransactionBody{}):     HighPriority,
+	typeName(&flow.Transaction{}):         HighPriority,
 
 	// core messages for execution & verification
-	case *flow.ExecutionReceipt:
-		return HighPriority
-	case *flow.ResultApproval:
-		return HighPriority
+	typeName(&flow.ExecutionReceipt{}): HighPriority,
+	typeName(&flow.ResultApproval{}):   HighPriority,
 
 	// execution state synchronization
-	case *messages.ExecutionStateSyncRequest:
-		return MediumPriority
-	case *messages.ExecutionStateDelta:
-		return HighPriority
+	typeName(&messages.ExecutionStateSyncRequest{}): MediumPriority,
+	typeName(&messages.ExecutionStateDelta{}):       HighPriority,
 
 	// data exchange for execution of blocks
-	case *messages.ChunkDataRequest:
-		return HighPriority
-	case *messages.ChunkDataResponse:
-		return HighPriority
+	typeName(&messages.ChunkDataRequest{}):  HighPriority,
+	typeName(&messages.ChunkDataResponse{}): HighPriority,
 
 	// request/response for result approvals
-	case *messages.ApprovalRequest:
-		return MediumPriority
-	case *messages.ApprovalResponse:
-		return MediumPriority
+	typeName(&messages.ApprovalRequest{}):  MediumPriority,
+	typeName(&messages.ApprovalResponse{}): MediumPriority,
 
 	// generic entity exchange engines
-	case *messages.EntityRequest:
-		return LowPriority
-	case *messages.EntityResponse:
-		return LowPriority
+	typeName(&messages.EntityRequest{}):  LowPriority,
+	typeName(&messages.EntityResponse{}): LowPriority,
 
 	// test message
-	case *libp2pmessage.TestMessage:
-		return LowPriority
+	typeName(&libp2pmessage.TestMessage{}): LowPriority,
+}
 
-	// anything else
-	default:
+// typeName returns the name of the type of the message, in the same way as the Type field of network messages.
+func typeName(message interface{}) string {
+	return strings.TrimLeft(fmt.Sprintf("%T", message), "*")
+}
+
+// getPriorityByType maps a message type to its priority
+func getPriorityByType(message interface{}) Priority {
+	return getPriorityByTypeName(typeName(message))
+}
+
+// getPriorityByTypeName maps the name of a message type to its priority
+func getPriorityByTypeName(messageType string) Priority {
+	priority, ok := priorityByType[messageType]
+	if !ok {
+		// anything else
 		return MediumPriority
 	}
+	return priority
 }
 
 // getPriorityBySize returns a priority of a message by size. Smaller messages have higher priority than larger ones.
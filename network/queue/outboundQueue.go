@@ -0,0 +1,203 @@
+package queue
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow-go/module"
+)
+
+const (
+	// DefaultOutboundQueueCapacity is the default maximum number of messages in an outbound queue
+	DefaultOutboundQueueCapacity = 500
+
+	// DefaultOutboundQueueLowPriorityCapacity is the default number of messages in an outbound queue from which messages
+	// of low priority are dropped
+	DefaultOutboundQueueLowPriorityCapacity = 250
+
+	// DefaultOutboundQueueWorkers is the default maximum number of messages sent concurrently from an outbound queue
+	DefaultOutboundQueueWorkers = 4
+)
+
+// ErrQueueFull is returned when a message is not inserted into an outbound queue, as the queue is full of messages of
+// the same or higher priority.
+var ErrQueueFull = errors.New("queue is full")
+
+// ErrQueueClosed is returned when a message is inserted into an outbound queue which has been closed.
+var ErrQueueClosed = errors.New("queue is closed")
+
+// OutboundQueueConfig configures the capacity and the workers of outbound queues.
+type OutboundQueueConfig struct {
+	// Capacity is the maximum number of messages in the queue.
+	Capacity int
+	// LowPriorityCapacity is the number of messages in the queue from which messages with a priority below
+	// MediumPriority are dropped, which leaves the remaining capacity to messages of higher priority. As the priority
+	// of a message is the average of its priorities by type and by size (see GetMessagePriority), small messages of
+	// a low priority type are not dropped by this policy.
+	LowPriorityCapacity int
+	// Workers is the maximum number of messages sent concurrently from the queue.
+	Workers int
+}
+
+// DefaultOutboundQueueConfig returns the default configuration of outbound queues.
+func DefaultOutboundQueueConfig() OutboundQueueConfig {
+	return OutboundQueueConfig{
+		Capacity:            DefaultOutboundQueueCapacity,
+		LowPriorityCapacity: DefaultOutboundQueueLowPriorityCapacity,
+		Workers:             DefaultOutboundQueueWorkers,
+	}
+}
+
+// Validate returns an error if the configuration is invalid.
+func (c OutboundQueueConfig) Validate() error {
+	if c.Capacity <= 0 {
+		return fmt.Errorf("capacity must be positive, got %d", c.Capacity)
+	}
+	if c.LowPriorityCapacity < 0 || c.LowPriorityCapacity > c.Capacity {
+		return fmt.Errorf("low priority capacity must be between 0 and the capacity %d, got %d", c.Capacity, c.LowPriorityCapacity)
+	}
+	if c.Workers <= 0 {
+		return fmt.Errorf("number of workers must be positive, got %d", c.Workers)
+	}
+	return nil
+}
+
+// OutboundQueue is a bounded priority queue of outbound messages, which are sent in the order of their priority by up
+// to the configured number of workers. Workers are started as messages are inserted, and exit once the queue is empty.
+type OutboundQueue struct {
+	mu      sync.Mutex
+	pq      priorityQueue
+	config  OutboundQueueConfig
+	send    func(interface{})
+	metrics module.NetworkMetrics
+	workers int // number of running workers
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+// NewOutboundQueue creates an outbound queue with the given configuration, whose workers call send for each message.
+func NewOutboundQueue(config OutboundQueueConfig, send func(interface{}), nm module.NetworkMetrics) *OutboundQueue {
+	return &OutboundQueue{
+		pq:      make(priorityQueue, 0),
+		config:  config,
+		send:    send,
+		metrics: nm,
+	}
+}
+
+// Insert inserts the message with the given priority into the queue, and starts a worker to send it if fewer than the
+// configured number of workers are running.
+// If the queue is full, the queued message of lowest priority is evicted to make room for the message, and returned to
+// the caller, if its priority is lower than the given one. Otherwise, and if the message has a priority below
+// MediumPriority while the queue holds at least LowPriorityCapacity messages, the message is dropped and ErrQueueFull
+// is returned.
+func (q *OutboundQueue) Insert(message interface{}, priority Priority) (interface{}, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		return nil, ErrQueueClosed
+	}
+
+	if priority < MediumPriority && q.pq.Len() >= q.config.LowPriorityCapacity {
+		return nil, ErrQueueFull
+	}
+
+	var evicted interface{}
+	if q.pq.Len() >= q.config.Capacity {
+		lowest := q.lowest()
+		if lowest.priority >= int(priority) {
+			return nil, ErrQueueFull
+		}
+		heap.Remove(&q.pq, lowest.index)
+		q.metrics.OutboundMessageRemoved(lowest.priority)
+		evicted = lowest.message
+	}
+
+	heap.Push(&q.pq, &item{
+		message:   message,
+		priority:  int(priority),
+		timestamp: time.Now(),
+	})
+	q.metrics.OutboundMessageAdded(int(priority))
+
+	if q.workers < q.config.Workers {
+		q.workers++
+		q.wg.Add(1)
+		go q.work()
+	}
+
+	return evicted, nil
+}
+
+// Len returns the number of messages in the queue.
+func (q *OutboundQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pq.Len()
+}
+
+// Close stops the queue from accepting messages and drops the queued messages. It blocks until the workers finished
+// sending the messages they already removed from the queue.
+func (q *OutboundQueue) Close() {
+	q.mu.Lock()
+	q.closed = true
+	for q.pq.Len() > 0 {
+		item := heap.Pop(&q.pq).(*item)
+		q.metrics.OutboundMessageRemoved(item.priority)
+	}
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
+
+// CloseIfIdle closes the queue if it is idle, i.e. holds no messages and has no running workers, and returns whether
+// the queue was closed.
+func (q *OutboundQueue) CloseIfIdle() bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.pq.Len() > 0 || q.workers > 0 {
+		return false
+	}
+	q.closed = true
+	return true
+}
+
+// lowest returns the queued message of lowest priority, and the most recent one among messages of the same priority.
+// It must be called with a non-empty queue, while holding the lock.
+func (q *OutboundQueue) lowest() *item {
+	// the message of lowest priority is a leaf of the heap
+	lowest := q.pq[len(q.pq)/2]
+	for _, item := range q.pq[len(q.pq)/2+1:] {
+		if item.priority < lowest.priority ||
+			(item.priority == lowest.priority && item.timestamp.After(lowest.timestamp)) {
+			lowest = item
+		}
+	}
+	return lowest
+}
+
+// work sends the queued messages in the order of their priority, until the queue is empty or closed.
+func (q *OutboundQueue) work() {
+	defer q.wg.Done()
+
+	for {
+		q.mu.Lock()
+		if q.closed || q.pq.Len() == 0 {
+			q.workers--
+			q.mu.Unlock()
+			return
+		}
+		item := heap.Pop(&q.pq).(*item)
+		q.mu.Unlock()
+
+		q.metrics.OutboundQueueDuration(time.Since(item.timestamp), item.priority)
+		q.metrics.OutboundMessageRemoved(item.priority)
+
+		q.send(item.message)
+	}
+}
@@ -0,0 +1,243 @@
+package queue_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	libp2pmessage "github.com/onflow/flow-go/model/libp2p/message"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/module/metrics"
+	"github.com/onflow/flow-go/network/queue"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// TestOutboundQueueOrder tests that queued messages are sent in the order of their priority, and in insertion order
+// among messages of the same priority.
+func TestOutboundQueueOrder(t *testing.T) {
+	sender := newBlockingSender()
+	q := queue.NewOutboundQueue(queue.OutboundQueueConfig{Capacity: 10, LowPriorityCapacity: 10, Workers: 1}, sender.send, metrics.NewNoopCollector())
+
+	// the worker blocks on the first message, while the others are queued
+	sender.insertAndWait(t, q, "first")
+	insert(t, q, "low-1", queue.LowPriority)
+	insert(t, q, "high-1", queue.HighPriority)
+	insert(t, q, "medium-1", queue.MediumPriority)
+	insert(t, q, "high-2", queue.HighPriority)
+	insert(t, q, "low-2", queue.LowPriority)
+
+	sender.release()
+	assert.Eventually(t, func() bool { return len(sender.sent()) == 6 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"first", "high-1", "high-2", "medium-1", "low-1", "low-2"}, sender.sent())
+	assert.Equal(t, 0, q.Len())
+}
+
+// TestOutboundQueueDropPolicy tests that a full queue evicts its most recent message of lowest priority in favor of
+// messages of higher priority, and that low priority messages are dropped once the low priority capacity is reached.
+func TestOutboundQueueDropPolicy(t *testing.T) {
+	sender := newBlockingSender()
+	q := queue.NewOutboundQueue(queue.OutboundQueueConfig{Capacity: 4, LowPriorityCapacity: 2, Workers: 1}, sender.send, metrics.NewNoopCollector())
+	sender.insertAndWait(t, q, "first")
+
+	insert(t, q, "low-1", queue.LowPriority)
+	insert(t, q, "low-2", queue.LowPriority)
+	_, err := q.Insert("low-3", queue.LowPriority)
+	assert.ErrorIs(t, err, queue.ErrQueueFull)
+
+	insert(t, q, "medium-1", queue.MediumPriority)
+	insert(t, q, "medium-2", queue.MediumPriority)
+	assert.Equal(t, 4, q.Len())
+
+	evicted, err := q.Insert("high-1", queue.HighPriority)
+	require.NoError(t, err)
+	assert.Equal(t, "low-2", evicted)
+
+	evicted, err = q.Insert("medium-3", queue.MediumPriority)
+	require.NoError(t, err)
+	assert.Equal(t, "low-1", evicted)
+
+	// the queue is full of messages of the same or higher priority
+	_, err = q.Insert("medium-4", queue.MediumPriority)
+	assert.ErrorIs(t, err, queue.ErrQueueFull)
+	assert.Equal(t, 4, q.Len())
+
+	sender.release()
+	assert.Eventually(t, func() bool { return len(sender.sent()) == 5 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []string{"first", "high-1", "medium-1", "medium-2", "medium-3"}, sender.sent())
+}
+
+// TestOutboundQueueWorkers tests that no more than the configured number of messages are sent concurrently, and
+// that the workers exit once the queue is empty.
+func TestOutboundQueueWorkers(t *testing.T) {
+	const workers = 3
+
+	lock := sync.Mutex{}
+	sending, maxSending, sent := 0, 0, 0
+	send := func(interface{}) {
+		lock.Lock()
+		sending++
+		if sending > maxSending {
+			maxSending = sending
+		}
+		lock.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		lock.Lock()
+		sending--
+		sent++
+		lock.Unlock()
+	}
+	q := queue.NewOutboundQueue(queue.OutboundQueueConfig{Capacity: 100, LowPriorityCapacity: 100, Workers: workers}, send, metrics.NewNoopCollector())
+
+	for i := 0; i < 20; i++ {
+		insert(t, q, fmt.Sprintf("message-%d", i), queue.MediumPriority)
+	}
+
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return sent == 20
+	}, 2*time.Second, 5*time.Millisecond)
+	assert.Equal(t, workers, maxSending)
+
+	// the queue starts new workers once it was drained
+	insert(t, q, "late", queue.MediumPriority)
+	assert.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return sent == 21
+	}, time.Second, 5*time.Millisecond)
+
+	unittest.RequireReturnsBefore(t, q.Close, time.Second, "could not close queue")
+}
+
+// TestOutboundQueueClose tests that closing the queue drops the queued messages, waits for the messages being sent,
+// and rejects subsequent messages.
+func TestOutboundQueueClose(t *testing.T) {
+	sender := newBlockingSender()
+	q := queue.NewOutboundQueue(queue.OutboundQueueConfig{Capacity: 10, LowPriorityCapacity: 10, Workers: 1}, sender.send, metrics.NewNoopCollector())
+	sender.insertAndWait(t, q, "first")
+	insert(t, q, "queued", queue.HighPriority)
+
+	closed := make(chan struct{})
+	go func() {
+		q.Close()
+		close(closed)
+	}()
+
+	assert.Eventually(t, func() bool { return q.Len() == 0 }, time.Second, 5*time.Millisecond)
+	_, err := q.Insert("late", queue.HighPriority)
+	assert.ErrorIs(t, err, queue.ErrQueueClosed)
+
+	// closing waits for the message being sent
+	select {
+	case <-closed:
+		t.Fatal("queue closed while a message is being sent")
+	case <-time.After(50 * time.Millisecond):
+	}
+	sender.release()
+	unittest.AssertClosesBefore(t, closed, time.Second)
+
+	assert.Equal(t, []string{"first"}, sender.sent())
+}
+
+// TestOutboundQueueCloseIfIdle tests that only queues without queued messages and running workers are closed as idle.
+func TestOutboundQueueCloseIfIdle(t *testing.T) {
+	sender := newBlockingSender()
+	q := queue.NewOutboundQueue(queue.OutboundQueueConfig{Capacity: 10, LowPriorityCapacity: 10, Workers: 1}, sender.send, metrics.NewNoopCollector())
+
+	// the worker sending a message is running
+	sender.insertAndWait(t, q, "first")
+	assert.False(t, q.CloseIfIdle())
+	insert(t, q, "queued", queue.HighPriority)
+	assert.False(t, q.CloseIfIdle())
+
+	// the worker exits once the queue is empty
+	sender.release()
+	assert.Eventually(t, q.CloseIfIdle, time.Second, 5*time.Millisecond)
+
+	_, err := q.Insert("late", queue.HighPriority)
+	assert.ErrorIs(t, err, queue.ErrQueueClosed)
+	assert.Equal(t, []string{"first", "queued"}, sender.sent())
+}
+
+// TestMessagePriority tests that outbound messages have the same priority as inbound messages of the same type and size.
+func TestMessagePriority(t *testing.T) {
+	events := []interface{}{
+		&messages.BlockProposal{},
+		&messages.SyncRequest{},
+		&messages.RangeRequest{},
+		&flow.TransactionBody{},
+		&libp2pmessage.TestMessage{},
+		&flow.Header{},
+	}
+	for _, event := range events {
+		for _, size := range []int{100, 10 * queue.KiB, 10 * queue.MiB} {
+			expected, err := queue.GetEventPriority(queue.QMessage{Payload: event, Size: size})
+			require.NoError(t, err)
+			messageType := fmt.Sprintf("%T", event)[1:]
+			assert.Equal(t, expected, queue.GetMessagePriority(messageType, size), messageType)
+		}
+	}
+
+	assert.Equal(t, queue.HighPriority, queue.GetMessagePriority("messages.BlockProposal", 100))
+	assert.Equal(t, queue.LowPriority, queue.GetMessagePriority("messages.SyncRequest", 10*queue.MiB))
+	assert.Equal(t, queue.MediumPriority, queue.GetMessagePriority("unknown.Message", 10*queue.KiB))
+
+	// the priority by type is averaged with the priority by size, hence small messages of low priority types are
+	// not subject to the low priority capacity of outbound queues
+	assert.Greater(t, queue.GetMessagePriority("messages.SyncRequest", 100), queue.MediumPriority)
+	assert.Less(t, queue.GetMessagePriority("messages.SyncRequest", 10*queue.KiB), queue.MediumPriority)
+}
+
+func insert(t *testing.T, q *queue.OutboundQueue, message string, priority queue.Priority) {
+	evicted, err := q.Insert(message, priority)
+	require.NoError(t, err)
+	require.Nil(t, evicted)
+}
+
+// blockingSender records the messages sent by the workers of a queue, and blocks them until it is released.
+type blockingSender struct {
+	sync.Mutex
+	messages []string
+	started  chan struct{}
+	released chan struct{}
+}
+
+func newBlockingSender() *blockingSender {
+	return &blockingSender{
+		started:  make(chan struct{}, 100),
+		released: make(chan struct{}),
+	}
+}
+
+func (s *blockingSender) send(message interface{}) {
+	s.Lock()
+	s.messages = append(s.messages, message.(string))
+	s.Unlock()
+
+	s.started <- struct{}{}
+	<-s.released
+}
+
+// insertAndWait inserts the message into the queue, and waits until a worker blocks on sending it.
+func (s *blockingSender) insertAndWait(t *testing.T, q *queue.OutboundQueue, message string) {
+	insert(t, q, message, queue.HighPriority)
+	unittest.RequireReturnsBefore(t, func() { <-s.started }, time.Second, "worker did not send message")
+}
+
+func (s *blockingSender) release() {
+	close(s.released)
+}
+
+func (s *blockingSender) sent() []string {
+	s.Lock()
+	defer s.Unlock()
+	return append([]string(nil), s.messages...)
+}
@@ -138,6 +138,52 @@ func (n *Network) MulticastOnChannel(channel network.Channel, event interface{},
 	return n.submit(channel, event, targetIDs...)
 }
 
+// RequestOnChannel is called when an engine attached to the channel is sending a request to a single target Engine
+// attached to the same channel on another node. Unlike other messages, requests are not buffered, but served
+// synchronously by the engine of the target.
+func (n *Network) RequestOnChannel(ctx context.Context, channel network.Channel, request interface{}, targetID flow.Identifier) (interface{}, error) {
+	receiverNetwork, exist := n.hub.GetNetwork(targetID)
+	if !exist {
+		return nil, fmt.Errorf("could not find target network on hub: %v", targetID)
+	}
+
+	receiverNetwork.Lock()
+	receiverEngine, ok := receiverNetwork.engines[channel]
+	receiverNetwork.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("could find engine ID: %v for node: %v", channel, targetID)
+	}
+
+	processor, ok := receiverEngine.(network.RequestProcessor)
+	if !ok {
+		return nil, network.NewRemoteRequestError(fmt.Sprintf("no engine serves requests on channel %s", channel))
+	}
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	return processor.ProcessRequest(channel, n.GetID(), request)
+}
+
+// ReportMisbehaviorOnChannel is a no-op, as the stub network does not score the attached nodes.
+func (n *Network) ReportMisbehaviorOnChannel(channel network.Channel, originID flow.Identifier, misbehavior network.Misbehavior) {
+}
+
+// Process delivers the event to the engine of the attached node registered on the channel, as if it was received
+// from the origin. It allows feeding the engines with events which are not sent through the hub, e.g. captured
+// network messages.
+func (n *Network) Process(channel network.Channel, originID flow.Identifier, event interface{}) error {
+	n.Lock()
+	engine, ok := n.engines[channel]
+	n.Unlock()
+	if !ok {
+		return fmt.Errorf("no engine registered on channel %s", channel)
+	}
+
+	return engine.Process(channel, originID, event)
+}
+
 // haveSeen returns true if the node attached to this Network instance has seen the event ID.
 // Otherwise, it returns false.
 //
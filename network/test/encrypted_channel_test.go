@@ -0,0 +1,154 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-log"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/libp2p/message"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/capture"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// EncryptedChannelTestSuite tests the delivery of messages on channels whose payloads are encrypted end-to-end.
+// With plaintext set, the nodes do not enable encryption, and exchange the messages on these channels in plaintext.
+type EncryptedChannelTestSuite struct {
+	suite.Suite
+	plaintext bool
+	ids       flow.IdentityList
+	nets      []network.Network
+	engines   []*MeshEngine
+	recorder  *capture.Recorder
+	dir       string
+	cancel    context.CancelFunc
+}
+
+func TestEncryptedChannelTestSuite(t *testing.T) {
+	suite.Run(t, new(EncryptedChannelTestSuite))
+}
+
+func TestEncryptedChannelTestSuite_NotEnabled(t *testing.T) {
+	suite.Run(t, &EncryptedChannelTestSuite{plaintext: true})
+}
+
+// SetupTest creates three nodes with an engine on the DKG committee channel, whose messages are captured.
+func (suite *EncryptedChannelTestSuite) SetupTest() {
+	logger := zerolog.New(os.Stderr).Level(zerolog.ErrorLevel)
+	log.SetAllLoggers(log.LevelError)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.cancel = cancel
+
+	suite.dir = unittest.TempDir(suite.T())
+	recorder, err := capture.NewRecorder(logger, capture.Config{
+		Dir:         suite.dir,
+		MaxFileSize: 1 << 20,
+		MaxFiles:    1,
+		Channels:    []network.Channel{engine.DKGCommittee},
+	})
+	require.NoError(suite.T(), err)
+	suite.recorder = recorder
+
+	var nodes []*p2p.Node
+	suite.ids, nodes, _ = GenerateIDs(suite.T(), logger, 3)
+	mws, _ := GenerateMiddlewares(suite.T(), logger, suite.ids, nodes, WithMiddlewareOpts(p2p.WithMessageCapture(recorder)))
+	sms := GenerateSubscriptionManagers(suite.T(), mws)
+	suite.nets = generateNetworks(ctx, suite.T(), logger, suite.ids, mws, 100, nil, sms, !suite.plaintext)
+
+	suite.engines = make([]*MeshEngine, 0, len(suite.nets))
+	for _, net := range suite.nets {
+		suite.engines = append(suite.engines, NewMeshEngine(suite.T(), net, 10, engine.DKGCommittee))
+	}
+
+	// allows nodes to heartbeat and discover each other on the pubsub topic
+	time.Sleep(2 * time.Second)
+}
+
+// TearDownTest closes the networks within a specified timeout
+func (suite *EncryptedChannelTestSuite) TearDownTest() {
+	suite.cancel()
+	stopNetworks(suite.T(), suite.nets, 3*time.Second)
+	require.NoError(suite.T(), suite.recorder.Close())
+	require.NoError(suite.T(), os.RemoveAll(suite.dir))
+}
+
+// TestUnicast evaluates that a unicast message on an encrypted channel is delivered decrypted to its recipient, or
+// in plaintext if encryption is not enabled.
+func (suite *EncryptedChannelTestSuite) TestUnicast() {
+	event := &message.TestMessage{Text: "unicast dkg private share"}
+	err := suite.engines[0].con.Unicast(event, suite.ids[1].NodeID)
+	require.NoError(suite.T(), err)
+
+	suite.assertReceived(1, event)
+	suite.assertNotReceived(2)
+	suite.assertOnWire(event.Text)
+}
+
+// TestPublish evaluates that a published message on an encrypted channel is delivered decrypted to each of its
+// recipients, and to no other node.
+func (suite *EncryptedChannelTestSuite) TestPublish() {
+	event := &message.TestMessage{Text: "published dkg private share"}
+	err := suite.engines[0].con.Publish(event, suite.ids[1].NodeID, suite.ids[2].NodeID)
+	require.NoError(suite.T(), err)
+
+	suite.assertReceived(1, event)
+	suite.assertReceived(2, event)
+
+	event = &message.TestMessage{Text: "published dkg private share for a single node"}
+	err = suite.engines[0].con.Publish(event, suite.ids[1].NodeID)
+	require.NoError(suite.T(), err)
+
+	suite.assertReceived(1, event)
+	suite.assertNotReceived(2)
+	suite.assertOnWire("published dkg private share")
+}
+
+// assertReceived asserts that the engine of the node with the given index receives the event from the first node.
+func (suite *EncryptedChannelTestSuite) assertReceived(index int, expected *message.TestMessage) {
+	e := suite.engines[index]
+	unittest.RequireReturnsBefore(suite.T(), func() { <-e.received }, 5*time.Second, "message not received")
+
+	assert.Equal(suite.T(), network.Channel(engine.DKGCommittee), <-e.channel)
+	assert.Equal(suite.T(), expected, <-e.event)
+	e.Lock()
+	assert.Equal(suite.T(), suite.ids[0].NodeID, e.originID)
+	e.Unlock()
+}
+
+// assertNotReceived asserts that the engine of the node with the given index does not receive any event.
+func (suite *EncryptedChannelTestSuite) assertNotReceived(index int) {
+	select {
+	case <-suite.engines[index].received:
+		suite.T().Fatal("message received by node which is not a recipient")
+	case <-time.After(500 * time.Millisecond):
+	}
+}
+
+// assertOnWire asserts that messages were captured on the encrypted channel, and that none of their payloads contains
+// the given plaintext, unless encryption is not enabled, in which case all of them do.
+func (suite *EncryptedChannelTestSuite) assertOnWire(plaintext string) {
+	records := 0
+	err := capture.ReadDir(suite.dir, func(record *capture.Record) error {
+		records++
+		assert.Equal(suite.T(), network.Channel(engine.DKGCommittee), record.Channel)
+		if suite.plaintext {
+			assert.Contains(suite.T(), string(record.Payload), plaintext)
+		} else {
+			assert.NotContains(suite.T(), string(record.Payload), plaintext)
+		}
+		return nil
+	})
+	require.NoError(suite.T(), err)
+	assert.Greater(suite.T(), records, 0)
+}
@@ -0,0 +1,129 @@
+package test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-log"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/libp2p/message"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/queue"
+)
+
+// OutboundQueueTestSuite tests the delivery of messages sent through the outbound queues of the middleware.
+type OutboundQueueTestSuite struct {
+	suite.Suite
+	ids    flow.IdentityList
+	nets   []network.Network
+	cancel context.CancelFunc
+}
+
+func TestOutboundQueueTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboundQueueTestSuite))
+}
+
+// TearDownTest closes the networks within a specified timeout
+func (suite *OutboundQueueTestSuite) TearDownTest() {
+	suite.cancel()
+	stopNetworks(suite.T(), suite.nets, 3*time.Second)
+}
+
+// setup creates two nodes whose middlewares use outbound queues with the given configuration.
+func (suite *OutboundQueueTestSuite) setup(config queue.OutboundQueueConfig) {
+	logger := zerolog.New(os.Stderr).Level(zerolog.ErrorLevel)
+	log.SetAllLoggers(log.LevelError)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.cancel = cancel
+
+	var nodes []*p2p.Node
+	suite.ids, nodes, _ = GenerateIDs(suite.T(), logger, 2)
+	mws, _ := GenerateMiddlewares(suite.T(), logger, suite.ids, nodes, WithMiddlewareOpts(p2p.WithOutboundQueues(config)))
+	sms := GenerateSubscriptionManagers(suite.T(), mws)
+	suite.nets = GenerateNetworks(ctx, suite.T(), logger, suite.ids, mws, 100, nil, sms)
+}
+
+// TestQueuedMessages evaluates that unicast and published messages are all delivered through the outbound queues.
+func (suite *OutboundQueueTestSuite) TestQueuedMessages() {
+	suite.setup(queue.DefaultOutboundQueueConfig())
+
+	con, err := suite.nets[0].Register(engine.TestNetwork, &countingEngine{})
+	require.NoError(suite.T(), err)
+	receiver := &countingEngine{}
+	_, err = suite.nets[1].Register(engine.TestNetwork, receiver)
+	require.NoError(suite.T(), err)
+
+	// allows nodes to heartbeat and discover each other on the pubsub topic
+	time.Sleep(2 * time.Second)
+
+	const count = 20
+	for i := 0; i < count; i++ {
+		err := con.Unicast(&message.TestMessage{Text: fmt.Sprintf("unicast %d", i)}, suite.ids[1].NodeID)
+		require.NoError(suite.T(), err)
+		err = con.Publish(&message.TestMessage{Text: fmt.Sprintf("publish %d", i)}, suite.ids[1].NodeID)
+		require.NoError(suite.T(), err)
+	}
+
+	assert.Eventually(suite.T(), func() bool {
+		return receiver.count() == 2*count
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// TestBackpressure evaluates that messages sent faster than the outbound queue of their recipient is drained are
+// dropped with a QueueFullError, while all the queued messages are delivered.
+func (suite *OutboundQueueTestSuite) TestBackpressure() {
+	suite.setup(queue.OutboundQueueConfig{Capacity: 1, LowPriorityCapacity: 1, Workers: 1})
+
+	con, err := suite.nets[0].Register(engine.TestNetwork, &countingEngine{})
+	require.NoError(suite.T(), err)
+	receiver := &countingEngine{}
+	_, err = suite.nets[1].Register(engine.TestNetwork, receiver)
+	require.NoError(suite.T(), err)
+
+	queued, dropped := 0, 0
+	for i := 0; i < 200; i++ {
+		err := con.Unicast(&message.TestMessage{Text: fmt.Sprintf("unicast %d", i)}, suite.ids[1].NodeID)
+		if err != nil {
+			require.True(suite.T(), network.IsQueueFullError(err), err)
+			dropped++
+			continue
+		}
+		queued++
+	}
+	assert.Greater(suite.T(), dropped, 0)
+
+	assert.Eventually(suite.T(), func() bool {
+		return receiver.count() == queued
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// countingEngine is an engine which counts the messages it receives.
+type countingEngine struct {
+	sync.Mutex
+	received int
+}
+
+func (e *countingEngine) Process(network.Channel, flow.Identifier, interface{}) error {
+	e.Lock()
+	defer e.Unlock()
+	e.received++
+	return nil
+}
+
+func (e *countingEngine) count() int {
+	e.Lock()
+	defer e.Unlock()
+	return e.received
+}
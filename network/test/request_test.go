@@ -0,0 +1,229 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-log"
+	libp2pprotocol "github.com/libp2p/go-libp2p-core/protocol"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/onflow/flow-go/engine"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/libp2p/message"
+	"github.com/onflow/flow-go/model/messages"
+	"github.com/onflow/flow-go/network"
+	"github.com/onflow/flow-go/network/p2p"
+	"github.com/onflow/flow-go/network/p2p/unicast"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+// RequestTestSuite tests the request protocol of the network, which exchanges requests and their responses on
+// a stream reused for all the requests to the same node.
+type RequestTestSuite struct {
+	suite.Suite
+	ids    flow.IdentityList
+	nodes  []*p2p.Node
+	nets   []network.Network
+	cancel context.CancelFunc
+}
+
+func TestRequestTestSuite(t *testing.T) {
+	suite.Run(t, new(RequestTestSuite))
+}
+
+func (suite *RequestTestSuite) SetupTest() {
+	logger := zerolog.New(os.Stderr).Level(zerolog.ErrorLevel)
+	log.SetAllLoggers(log.LevelError)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	suite.cancel = cancel
+
+	suite.ids, suite.nodes, _ = GenerateIDs(suite.T(), logger, 2)
+	mws, _ := GenerateMiddlewares(suite.T(), logger, suite.ids, suite.nodes)
+	sms := GenerateSubscriptionManagers(suite.T(), mws)
+	suite.nets = GenerateNetworks(ctx, suite.T(), logger, suite.ids, mws, 100, nil, sms)
+}
+
+// TearDownTest closes the networks within a specified timeout
+func (suite *RequestTestSuite) TearDownTest() {
+	suite.cancel()
+	stopNetworks(suite.T(), suite.nets, 3*time.Second)
+}
+
+// TestConcurrentRequests evaluates that concurrent requests to the same node are all answered with their own
+// response, over a single stream of the request protocol.
+func (suite *RequestTestSuite) TestConcurrentRequests() {
+	con := suite.register(0, engine.TestNetwork, &noopEngine{})
+	suite.register(1, engine.TestNetwork, &requestEngine{})
+
+	const count = 50
+	wg := sync.WaitGroup{}
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			res, err := con.Request(ctx, &message.TestMessage{Text: fmt.Sprintf("request %d", i)}, suite.ids[1].NodeID)
+			require.NoError(suite.T(), err)
+			expected := &message.TestMessage{Text: fmt.Sprintf("response to request %d from %s", i, suite.ids[0].NodeID)}
+			assert.Equal(suite.T(), expected, res)
+		}(i)
+	}
+	unittest.RequireReturnsBefore(suite.T(), wg.Wait, 10*time.Second, "could not receive all responses")
+
+	assert.Equal(suite.T(), 1, suite.requestStreams(1))
+}
+
+// TestRequestsServedConcurrently evaluates that the requests sharing a stream are served concurrently by the
+// recipient, so that slow requests do not hold back the responses of the others.
+func (suite *RequestTestSuite) TestRequestsServedConcurrently() {
+	con := suite.register(0, engine.TestNetwork, &noopEngine{})
+	suite.register(1, engine.TestNetwork, &requestEngine{delay: time.Second})
+
+	const count = 8
+	wg := sync.WaitGroup{}
+	wg.Add(count)
+	for i := 0; i < count; i++ {
+		go func(i int) {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			_, err := con.Request(ctx, &message.TestMessage{Text: fmt.Sprintf("request %d", i)}, suite.ids[1].NodeID)
+			require.NoError(suite.T(), err)
+		}(i)
+	}
+	// served one at a time, the requests would take count seconds
+	unittest.RequireReturnsBefore(suite.T(), wg.Wait, 3*time.Second, "requests were not served concurrently")
+
+	assert.Equal(suite.T(), 1, suite.requestStreams(1))
+}
+
+// TestRequestErrors evaluates that requests which cannot be served by the recipient return a RemoteRequestError,
+// while the stream stays usable for subsequent requests.
+func (suite *RequestTestSuite) TestRequestErrors() {
+	con := suite.register(0, engine.TestNetwork, &noopEngine{})
+	suite.register(1, engine.TestNetwork, &requestEngine{})
+	metricsCon := suite.register(0, engine.TestMetrics, &noopEngine{})
+	// the engine on the metrics channel does not serve requests
+	suite.register(1, engine.TestMetrics, &noopEngine{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := metricsCon.Request(ctx, &message.TestMessage{Text: "request"}, suite.ids[1].NodeID)
+	require.Error(suite.T(), err)
+	assert.True(suite.T(), network.IsRemoteRequestError(err))
+
+	// the request engine rejects requests of unexpected type
+	_, err = con.Request(ctx, &message.TestMessage{Text: "request"}, suite.ids[1].NodeID)
+	require.NoError(suite.T(), err)
+	_, err = con.Request(ctx, &messages.SyncRequest{}, suite.ids[1].NodeID)
+	require.Error(suite.T(), err)
+	assert.True(suite.T(), network.IsRemoteRequestError(err))
+
+	_, err = con.Request(ctx, &message.TestMessage{Text: "request"}, suite.ids[0].NodeID)
+	require.Error(suite.T(), err, "requests to the node itself should fail")
+
+	res, err := con.Request(ctx, &message.TestMessage{Text: "request"}, suite.ids[1].NodeID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &message.TestMessage{Text: fmt.Sprintf("response to request from %s", suite.ids[0].NodeID)}, res)
+}
+
+// TestRequestTimeout evaluates that a request fails once its context is done, and that its late response does not
+// interfere with subsequent requests on the same stream.
+func (suite *RequestTestSuite) TestRequestTimeout() {
+	con := suite.register(0, engine.TestNetwork, &noopEngine{})
+	suite.register(1, engine.TestNetwork, &requestEngine{delay: 500 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := con.Request(ctx, &message.TestMessage{Text: "slow request"}, suite.ids[1].NodeID)
+	require.Error(suite.T(), err)
+	assert.True(suite.T(), errors.Is(err, context.DeadlineExceeded))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := con.Request(ctx, &message.TestMessage{Text: "request"}, suite.ids[1].NodeID)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &message.TestMessage{Text: fmt.Sprintf("response to request from %s", suite.ids[0].NodeID)}, res)
+
+	assert.Equal(suite.T(), 1, suite.requestStreams(1))
+}
+
+// TestRequestNotSupported evaluates that requests to a node which does not support the request protocol, e.g. as it
+// runs an older version, return a RequestNotSupportedError.
+func (suite *RequestTestSuite) TestRequestNotSupported() {
+	con := suite.register(0, engine.TestNetwork, &noopEngine{})
+	suite.register(1, engine.TestNetwork, &requestEngine{})
+
+	host := suite.nodes[1].Host()
+	for _, protocol := range host.Mux().Protocols() {
+		if strings.HasPrefix(protocol, unicast.FlowLibP2PRequestProtocolPrefix) {
+			host.RemoveStreamHandler(libp2pprotocol.ID(protocol))
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := con.Request(ctx, &message.TestMessage{Text: "request"}, suite.ids[1].NodeID)
+	require.Error(suite.T(), err)
+	assert.True(suite.T(), network.IsRequestNotSupportedError(err))
+}
+
+// register registers the engine on the channel of the network with the given index.
+func (suite *RequestTestSuite) register(index int, channel network.Channel, e network.MessageProcessor) network.Conduit {
+	con, err := suite.nets[index].Register(channel, e)
+	require.NoError(suite.T(), err)
+	return con
+}
+
+// requestStreams returns the number of open streams of the request protocol of the node with the given index.
+func (suite *RequestTestSuite) requestStreams(index int) int {
+	count := 0
+	for _, conn := range suite.nodes[index].Host().Network().Conns() {
+		for _, s := range conn.GetStreams() {
+			if strings.HasPrefix(string(s.Protocol()), unicast.FlowLibP2PRequestProtocolPrefix) {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// noopEngine is an engine which drops all the messages it receives.
+type noopEngine struct{}
+
+func (e *noopEngine) Process(network.Channel, flow.Identifier, interface{}) error {
+	return nil
+}
+
+// requestEngine is an engine serving requests of test messages, after the given delay.
+type requestEngine struct {
+	noopEngine
+	delay time.Duration
+}
+
+func (e *requestEngine) ProcessRequest(_ network.Channel, originID flow.Identifier, request interface{}) (interface{}, error) {
+	time.Sleep(e.delay)
+
+	req, ok := request.(*message.TestMessage)
+	if !ok {
+		return nil, fmt.Errorf("unexpected request type: %T", request)
+	}
+	return &message.TestMessage{Text: fmt.Sprintf("response to %s from %s", req.Text, originID)}, nil
+}
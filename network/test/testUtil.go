@@ -34,6 +34,7 @@ import (
 	"github.com/onflow/flow-go/module/util"
 	"github.com/onflow/flow-go/network"
 	"github.com/onflow/flow-go/network/codec/cbor"
+	"github.com/onflow/flow-go/network/encryption"
 	"github.com/onflow/flow-go/network/p2p"
 	"github.com/onflow/flow-go/network/p2p/unicast"
 	"github.com/onflow/flow-go/network/topology"
@@ -170,7 +171,7 @@ func GenerateMiddlewares(t *testing.T, logger zerolog.Logger, identities flow.Id
 			sporkID,
 			p2p.DefaultUnicastTimeout,
 			p2p.NewIdentityProviderIDTranslator(idProviders[i]),
-			p2p.WithPeerManager(peerManagerFactory),
+			append([]p2p.MiddlewareOption{p2p.WithPeerManager(peerManagerFactory)}, o.mwOpts...)...,
 		)
 	}
 	return mws, idProviders
@@ -186,6 +187,22 @@ func GenerateNetworks(
 	csize int,
 	tops []network.Topology,
 	sms []network.SubscriptionManager,
+) []network.Network {
+	return generateNetworks(ctx, t, log, ids, mws, csize, tops, sms, true)
+}
+
+// generateNetworks generates the network for the given middlewares, which encrypt the messages on encrypted channels
+// only if encrypt is set.
+func generateNetworks(
+	ctx context.Context,
+	t *testing.T,
+	log zerolog.Logger,
+	ids flow.IdentityList,
+	mws []network.Middleware,
+	csize int,
+	tops []network.Topology,
+	sms []network.SubscriptionManager,
+	encrypt bool,
 ) []network.Network {
 	count := len(ids)
 	nets := make([]network.Network, 0)
@@ -214,6 +231,16 @@ func GenerateNetworks(
 		me.On("NotMeFilter").Return(filter.Not(filter.HasNodeID(me.NodeID())))
 		me.On("Address").Return(ids[i].Address)
 
+		var netOpts []p2p.NetworkOptFunction
+		if encrypt {
+			// creates the encryptor of the encrypted channels from the networking key of the node
+			key, err := generateNetworkingKey(ids[i].NodeID)
+			require.NoError(t, err)
+			encryptor, err := encryption.NewEncryptor(ids[i].NodeID, key, id.NewFixedIdentityProvider(ids))
+			require.NoError(t, err)
+			netOpts = append(netOpts, p2p.WithEncryptor(encryptor))
+		}
+
 		// create the network
 		net, err := p2p.NewNetwork(
 			log,
@@ -225,6 +252,7 @@ func GenerateNetworks(
 			sms[i],
 			metrics,
 			id.NewFixedIdentityProvider(ids),
+			netOpts...,
 		)
 		require.NoError(t, err)
 
@@ -268,6 +296,7 @@ type optsConfig struct {
 	dhtOpts          []dht.Option
 	peerManagerOpts  []p2p.Option
 	connectionGating bool
+	mwOpts           []p2p.MiddlewareOption
 }
 
 func WithIdentityOpts(idOpts ...func(*flow.Identity)) func(*optsConfig) {
@@ -289,6 +318,12 @@ func WithPeerManagerOpts(peerManagerOpts ...p2p.Option) func(*optsConfig) {
 	}
 }
 
+func WithMiddlewareOpts(mwOpts ...p2p.MiddlewareOption) func(*optsConfig) {
+	return func(o *optsConfig) {
+		o.mwOpts = mwOpts
+	}
+}
+
 func GenerateIDsMiddlewaresNetworks(
 	ctx context.Context,
 	t *testing.T,
@@ -698,12 +698,16 @@ func (m *FollowerState) epochStatus(block *flow.Header) (*flow.EpochStatus, erro
 		if parentStatus.NextEpoch.CommitID == flow.ZeroID {
 			return nil, fmt.Errorf("missing commit event for starting next epoch: %w", errIncompleteEpochConfiguration)
 		}
-		status, err := flow.NewEpochStatus(
-			parentStatus.CurrentEpoch.SetupID, parentStatus.CurrentEpoch.CommitID,
-			parentStatus.NextEpoch.SetupID, parentStatus.NextEpoch.CommitID,
-			flow.ZeroID, flow.ZeroID,
-		)
-		return status, err
+		// the epochs move on together with the QC participation recorded for them
+		status := &flow.EpochStatus{
+			PreviousEpoch: parentStatus.CurrentEpoch,
+			CurrentEpoch:  parentStatus.NextEpoch,
+		}
+		err = status.Check()
+		if err != nil {
+			return nil, err
+		}
+		return status, nil
 	}
 
 	// Block is in the same epoch as its parent, re-use the same epoch status
@@ -807,7 +811,7 @@ SealLoop:
 			case *flow.EpochSetup:
 
 				// validate the service event
-				err := isValidExtendingEpochSetup(ev, activeSetup, epochStatus)
+				err := protocol.IsValidExtendingEpochSetup(ev, activeSetup, epochStatus)
 				if protocol.IsInvalidServiceEventError(err) {
 					// EECC - we have observed an invalid service event, which is
 					// an unrecoverable failure. Flag this in the DB and exit
@@ -848,12 +852,72 @@ SealLoop:
 		}
 	}
 
+	participationOps, err := m.participationOps(block.Header, epochStatus)
+	if err != nil {
+		return nil, fmt.Errorf("could not record qc participation: %w", err)
+	}
+	ops = append(ops, participationOps...)
+
 	// we always index the epoch status, even when there are no service events
 	ops = append(ops, m.epoch.statuses.StoreTx(block.ID(), epochStatus))
 
 	return ops, nil
 }
 
+// participationOps records the QC participation of the blocks of the setup phase
+// for the leader selection of the next epoch, if liveness-weighted leader
+// selection is enabled for the spork. The participation is only stored once per
+// epoch and fork, by the block which transitions the epoch from the setup to the
+// committed phase. It updates the participation ID of the next epoch in the
+// block's epoch status, so the following blocks reference the record through
+// their epoch status, and returns the operations to insert it.
+//
+// The participation recorded for an epoch only depends on the blocks of its
+// fork. It is part of the protocol state snapshots, so all nodes agree on it,
+// including nodes bootstrapped from a snapshot within the setup phase. As the
+// next epoch's leaders are only computed once it is committed, its recorded
+// participation does not change anymore at that point.
+//
+// No errors are expected during normal operation.
+func (m *FollowerState) participationOps(header *flow.Header, status *flow.EpochStatus) ([]func(*transaction.Tx) error, error) {
+	weighting, err := m.Params().LivenessWeighting()
+	if err != nil {
+		return nil, fmt.Errorf("could not get liveness weighting: %w", err)
+	}
+	if weighting == nil {
+		return nil, nil
+	}
+	phase, err := status.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not get epoch phase: %w", err)
+	}
+	if phase != flow.EpochPhaseCommitted {
+		return nil, nil
+	}
+	parentStatus, err := m.epoch.statuses.ByBlockID(header.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve parent's epoch status: %w", err)
+	}
+	parentPhase, err := parentStatus.Phase()
+	if err != nil {
+		return nil, fmt.Errorf("could not get parent's epoch phase: %w", err)
+	}
+	if parentPhase != flow.EpochPhaseSetup {
+		return nil, nil
+	}
+
+	participation, err := m.setupPhaseParticipation(header.ParentID, parentStatus)
+	if err != nil {
+		return nil, fmt.Errorf("could not get qc participation of setup phase: %w", err)
+	}
+
+	participationID := participation.ID()
+	status.NextEpoch.ParticipationID = participationID
+	return []func(*transaction.Tx) error{
+		transaction.WithTx(operation.InsertQCParticipation(participationID, participation)),
+	}, nil
+}
+
 // MarkValid marks the block as valid in protocol state, and triggers
 // `BlockProcessable` event to notify that its parent block is processable.
 //
@@ -1,9 +1,11 @@
 package badger
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/storage/badger/operation"
 )
 
@@ -44,6 +46,21 @@ func (p *Params) ProtocolVersion() (uint, error) {
 	return version, nil
 }
 
+func (p *Params) LivenessWeighting() (*flow.LivenessWeighting, error) {
+
+	var weighting flow.LivenessWeighting
+	err := p.state.db.View(operation.RetrieveLivenessWeighting(&weighting))
+	if errors.Is(err, storage.ErrNotFound) {
+		// liveness-weighted leader selection is disabled for this spork
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get liveness weighting: %w", err)
+	}
+
+	return &weighting, nil
+}
+
 func (p *Params) Root() (*flow.Header, error) {
 
 	// retrieve the root height
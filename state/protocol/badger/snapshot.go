@@ -477,8 +477,12 @@ func (q *EpochQuery) Current() protocol.Epoch {
 	if err != nil {
 		return invalid.NewEpoch(err)
 	}
+	participation, err := q.participation(status.CurrentEpoch.ParticipationID)
+	if err != nil {
+		return invalid.NewEpoch(err)
+	}
 
-	epoch, err := inmem.NewCommittedEpoch(setup, commit)
+	epoch, err := inmem.NewCommittedEpoch(setup, commit, participation)
 	if err != nil {
 		return invalid.NewEpoch(err)
 	}
@@ -507,11 +511,16 @@ func (q *EpochQuery) Next() protocol.Epoch {
 		return invalid.NewEpoch(fmt.Errorf("failed to retrieve setup event for next epoch: %w", err))
 	}
 	if phase == flow.EpochPhaseSetup {
-		epoch, err := inmem.NewSetupEpoch(nextSetup)
+		epoch, err := inmem.NewSetupEpoch(nextSetup, nil)
 		if err != nil {
 			return invalid.NewEpoch(err)
 		}
-		return epoch
+		return &setupPhaseEpoch{Epoch: epoch, snap: q.snap, status: status}
+	}
+
+	participation, err := q.participation(status.NextEpoch.ParticipationID)
+	if err != nil {
+		return invalid.NewEpoch(fmt.Errorf("failed to retrieve qc participation for next epoch: %w", err))
 	}
 
 	// if we are in committed phase, return a CommittedEpoch
@@ -519,7 +528,7 @@ func (q *EpochQuery) Next() protocol.Epoch {
 	if err != nil {
 		return invalid.NewEpoch(fmt.Errorf("failed to retrieve commit event for next epoch: %w", err))
 	}
-	epoch, err := inmem.NewCommittedEpoch(nextSetup, nextCommit)
+	epoch, err := inmem.NewCommittedEpoch(nextSetup, nextCommit, participation)
 	if err != nil {
 		return invalid.NewEpoch(err)
 	}
@@ -552,10 +561,52 @@ func (q *EpochQuery) Previous() protocol.Epoch {
 	if err != nil {
 		return invalid.NewEpoch(err)
 	}
+	participation, err := q.participation(status.PreviousEpoch.ParticipationID)
+	if err != nil {
+		return invalid.NewEpoch(err)
+	}
 
-	epoch, err := inmem.NewCommittedEpoch(setup, commit)
+	epoch, err := inmem.NewCommittedEpoch(setup, commit, participation)
 	if err != nil {
 		return invalid.NewEpoch(err)
 	}
 	return epoch
 }
+
+// setupPhaseEpoch is the next epoch of a snapshot in the epoch setup phase. Its QC
+// participation is still being recorded, and is only computed when requested, as
+// this requires walking the blocks of the setup phase.
+type setupPhaseEpoch struct {
+	*inmem.Epoch
+	snap   *Snapshot
+	status *flow.EpochStatus
+}
+
+func (e *setupPhaseEpoch) QCParticipation() (*flow.QCParticipation, error) {
+	weighting, err := e.snap.state.Params().LivenessWeighting()
+	if err != nil {
+		return nil, fmt.Errorf("could not get liveness weighting: %w", err)
+	}
+	if weighting == nil {
+		return nil, nil
+	}
+	participation, err := e.snap.state.setupPhaseParticipation(e.snap.blockID, e.status)
+	if err != nil {
+		return nil, fmt.Errorf("could not get qc participation of setup phase: %w", err)
+	}
+	return participation, nil
+}
+
+// participation retrieves the QC participation record with the given ID, or
+// returns nil if the ID is ZeroID, as no participation was recorded.
+func (q *EpochQuery) participation(participationID flow.Identifier) (*flow.QCParticipation, error) {
+	if participationID == flow.ZeroID {
+		return nil, nil
+	}
+	var participation flow.QCParticipation
+	err := q.snap.state.db.View(operation.RetrieveQCParticipation(participationID, &participation))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve qc participation: %w", err)
+	}
+	return &participation, nil
+}
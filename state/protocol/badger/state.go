@@ -293,7 +293,7 @@ func (state *State) bootstrapEpoch(root protocol.Snapshot, verifyNetworkAddress
 				return fmt.Errorf("could not get previous epoch commit event: %w", err)
 			}
 
-			if err := verifyEpochSetup(setup, verifyNetworkAddress); err != nil {
+			if err := protocol.IsValidEpochSetup(setup, verifyNetworkAddress); err != nil {
 				return fmt.Errorf("invalid setup: %w", err)
 			}
 
@@ -301,10 +301,16 @@ func (state *State) bootstrapEpoch(root protocol.Snapshot, verifyNetworkAddress
 				return fmt.Errorf("invalid commit")
 			}
 
+			participationID, err := state.bootstrapParticipation(previous)(tx)
+			if err != nil {
+				return fmt.Errorf("could not bootstrap previous epoch qc participation: %w", err)
+			}
+
 			setups = append(setups, setup)
 			commits = append(commits, commit)
 			status.PreviousEpoch.SetupID = setup.ID()
 			status.PreviousEpoch.CommitID = commit.ID()
+			status.PreviousEpoch.ParticipationID = participationID
 		} else if !errors.Is(err, protocol.ErrNoPreviousEpoch) {
 			return fmt.Errorf("could not retrieve previous epoch: %w", err)
 		}
@@ -319,7 +325,7 @@ func (state *State) bootstrapEpoch(root protocol.Snapshot, verifyNetworkAddress
 			return fmt.Errorf("could not get current epoch commit event: %w", err)
 		}
 
-		if err := verifyEpochSetup(setup, verifyNetworkAddress); err != nil {
+		if err := protocol.IsValidEpochSetup(setup, verifyNetworkAddress); err != nil {
 			return fmt.Errorf("invalid setup: %w", err)
 		}
 
@@ -327,10 +333,16 @@ func (state *State) bootstrapEpoch(root protocol.Snapshot, verifyNetworkAddress
 			return fmt.Errorf("invalid commit")
 		}
 
+		participationID, err := state.bootstrapParticipation(current)(tx)
+		if err != nil {
+			return fmt.Errorf("could not bootstrap current epoch qc participation: %w", err)
+		}
+
 		setups = append(setups, setup)
 		commits = append(commits, commit)
 		status.CurrentEpoch.SetupID = setup.ID()
 		status.CurrentEpoch.CommitID = commit.ID()
+		status.CurrentEpoch.ParticipationID = participationID
 
 		// insert next epoch, if it exists
 		_, err = next.Counter()
@@ -341,12 +353,18 @@ func (state *State) bootstrapEpoch(root protocol.Snapshot, verifyNetworkAddress
 				return fmt.Errorf("could not get next epoch setup event: %w", err)
 			}
 
-			if err := verifyEpochSetup(setup, verifyNetworkAddress); err != nil {
+			if err := protocol.IsValidEpochSetup(setup, verifyNetworkAddress); err != nil {
 				return fmt.Errorf("invalid setup: %w", err)
 			}
 
+			participationID, err := state.bootstrapParticipation(next)(tx)
+			if err != nil {
+				return fmt.Errorf("could not bootstrap next epoch qc participation: %w", err)
+			}
+
 			setups = append(setups, setup)
 			status.NextEpoch.SetupID = setup.ID()
+			status.NextEpoch.ParticipationID = participationID
 			commit, err := protocol.ToEpochCommit(next)
 			if err != nil && !errors.Is(err, protocol.ErrEpochNotCommitted) {
 				return fmt.Errorf("could not get next epoch commit event: %w", err)
@@ -400,6 +418,76 @@ func (state *State) bootstrapEpoch(root protocol.Snapshot, verifyNetworkAddress
 	}
 }
 
+// bootstrapParticipation inserts the QC participation recorded for the given
+// epoch as of the root snapshot. It returns the ID of the participation record,
+// or ZeroID if no participation was recorded for the epoch.
+func (state *State) bootstrapParticipation(epoch protocol.Epoch) func(*transaction.Tx) (flow.Identifier, error) {
+	return func(tx *transaction.Tx) (flow.Identifier, error) {
+		participation, err := epoch.QCParticipation()
+		if err != nil {
+			return flow.ZeroID, fmt.Errorf("could not get qc participation: %w", err)
+		}
+		if participation == nil {
+			return flow.ZeroID, nil
+		}
+
+		participationID := participation.ID()
+		err = transaction.WithTx(operation.InsertQCParticipation(participationID, participation))(tx)
+		if err != nil {
+			return flow.ZeroID, fmt.Errorf("could not insert qc participation: %w", err)
+		}
+		return participationID, nil
+	}
+}
+
+// setupPhaseParticipation returns the QC participation of the next epoch as of the
+// given block of the epoch setup phase. During the setup phase, the epoch status
+// only references the participation recorded before the phase, which is empty
+// or bootstrapped from the root snapshot. The participation of the blocks of the
+// setup phase is added by walking the fork back to the start of the phase, or to
+// the root block, whose participation is part of the bootstrapped record.
+//
+// No errors are expected during normal operation.
+func (state *State) setupPhaseParticipation(blockID flow.Identifier, status *flow.EpochStatus) (*flow.QCParticipation, error) {
+	participation := flow.NewQCParticipation()
+	if status.NextEpoch.ParticipationID != flow.ZeroID {
+		err := state.db.View(operation.RetrieveQCParticipation(status.NextEpoch.ParticipationID, participation))
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve qc participation: %w", err)
+		}
+	}
+
+	var rootHeight uint64
+	err := state.db.View(operation.RetrieveRootHeight(&rootHeight))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve root height: %w", err)
+	}
+
+	for {
+		phase, err := status.Phase()
+		if err != nil {
+			return nil, fmt.Errorf("could not get epoch phase of block %x: %w", blockID, err)
+		}
+		if phase != flow.EpochPhaseSetup {
+			return participation, nil
+		}
+		header, err := state.headers.ByBlockID(blockID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve header of block %x: %w", blockID, err)
+		}
+		if header.Height <= rootHeight {
+			return participation, nil
+		}
+		participation.Add(header.ParentVoterIDs)
+
+		blockID = header.ParentID
+		status, err = state.epoch.statuses.ByBlockID(blockID)
+		if err != nil {
+			return nil, fmt.Errorf("could not retrieve epoch status of block %x: %w", blockID, err)
+		}
+	}
+}
+
 // bootstrapSporkInfo bootstraps the protocol state with information about the
 // spork which is used to disambiguate Flow networks.
 func (state *State) bootstrapSporkInfo(root protocol.Snapshot) func(*badger.Txn) error {
@@ -424,6 +512,21 @@ func (state *State) bootstrapSporkInfo(root protocol.Snapshot) func(*badger.Txn)
 			return fmt.Errorf("could not insert protocol version: %w", err)
 		}
 
+		weighting, err := params.LivenessWeighting()
+		if err != nil {
+			return fmt.Errorf("could not get liveness weighting: %w", err)
+		}
+		if weighting != nil {
+			err = weighting.Validate()
+			if err != nil {
+				return fmt.Errorf("invalid liveness weighting: %w", err)
+			}
+			err = operation.InsertLivenessWeighting(weighting)(tx)
+			if err != nil {
+				return fmt.Errorf("could not insert liveness weighting: %w", err)
+			}
+		}
+
 		return nil
 	}
 }
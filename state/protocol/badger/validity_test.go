@@ -2,7 +2,6 @@ package badger
 
 import (
 	"testing"
-	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -14,48 +13,6 @@ import (
 
 var participants = unittest.IdentityListFixture(20, unittest.WithAllRoles())
 
-func TestEpochSetupValidity(t *testing.T) {
-	t.Run("invalid first/final view", func(t *testing.T) {
-		_, result, _ := unittest.BootstrapFixture(participants)
-		setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
-		// set an invalid final view for the first epoch
-		setup.FinalView = setup.FirstView
-
-		err := isValidEpochSetup(setup)
-		require.Error(t, err)
-	})
-
-	t.Run("non-canonically ordered identities", func(t *testing.T) {
-		_, result, _ := unittest.BootstrapFixture(participants)
-		setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
-		// randomly shuffle the identities so they are not canonically ordered
-		setup.Participants = setup.Participants.DeterministicShuffle(time.Now().UnixNano())
-
-		err := isValidEpochSetup(setup)
-		require.Error(t, err)
-	})
-
-	t.Run("invalid cluster assignments", func(t *testing.T) {
-		_, result, _ := unittest.BootstrapFixture(participants)
-		setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
-		// create an invalid cluster assignment (node appears in multiple clusters)
-		collector := participants.Filter(filter.HasRole(flow.RoleCollection))[0]
-		setup.Assignments = append(setup.Assignments, []flow.Identifier{collector.NodeID})
-
-		err := isValidEpochSetup(setup)
-		require.Error(t, err)
-	})
-
-	t.Run("short seed", func(t *testing.T) {
-		_, result, _ := unittest.BootstrapFixture(participants)
-		setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
-		setup.RandomSource = unittest.SeedFixture(crypto.SeedMinLenDKG - 1)
-
-		err := isValidEpochSetup(setup)
-		require.Error(t, err)
-	})
-}
-
 func TestBootstrapInvalidEpochCommit(t *testing.T) {
 	t.Run("inconsistent counter", func(t *testing.T) {
 		_, result, _ := unittest.BootstrapFixture(participants)
@@ -79,4 +79,11 @@ type Epoch interface {
 
 	// DKG returns the result of the distributed key generation procedure.
 	DKG() (DKG, error)
+
+	// QCParticipation returns the participation of the consensus committee in the QCs
+	// of the blocks in the setup phase of the preceding epoch, as of the reference block.
+	// It weighs the leader selection of this epoch, if liveness-weighted leader selection
+	// is enabled. Returns nil if the participation was not recorded, which is the case if
+	// liveness-weighted leader selection is disabled or for the first epoch of the spork.
+	QCParticipation() (*flow.QCParticipation, error)
 }
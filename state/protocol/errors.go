@@ -25,6 +25,15 @@ var (
 	// ErrSealingSegmentBelowRootBlock is a sentinel error returned for queries
 	// for a sealing segment below the root block.
 	ErrSealingSegmentBelowRootBlock = fmt.Errorf("cannot query sealing segment below root block")
+
+	// ErrHeightNotSealed is a sentinel error returned when a snapshot for
+	// bootstrapping is queried at a height that has not been sealed yet.
+	ErrHeightNotSealed = fmt.Errorf("height has not been sealed")
+
+	// ErrSealingSegmentSpansTransition is a sentinel error returned when the
+	// sealing segment of a snapshot for bootstrapping spans an epoch or epoch
+	// phase transition.
+	ErrSealingSegmentSpansTransition = fmt.Errorf("sealing segment spans an epoch or epoch phase transition")
 )
 
 type IdentityNotFoundError struct {
@@ -105,6 +105,10 @@ func FromParams(from protocol.GlobalParams) (*Params, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not get protocol version: %w", err)
 	}
+	params.LivenessWeighting, err = from.LivenessWeighting()
+	if err != nil {
+		return nil, fmt.Errorf("could not get liveness weighting: %w", err)
+	}
 
 	return &Params{params}, nil
 }
@@ -147,6 +151,10 @@ func FromEpoch(from protocol.Epoch) (*Epoch, error) {
 		return nil, fmt.Errorf("could not get clustering: %w", err)
 	}
 	epoch.Clustering = clustering
+	epoch.QCParticipation, err = from.QCParticipation()
+	if err != nil {
+		return nil, fmt.Errorf("could not get qc participation: %w", err)
+	}
 
 	// convert dkg
 	dkg, err := from.DKG()
@@ -233,6 +241,20 @@ func SnapshotFromBootstrapStateWithProtocolVersion(
 	qc *flow.QuorumCertificate,
 	version uint,
 ) (*Snapshot, error) {
+	return SnapshotFromBootstrapStateWithParams(root, result, seal, qc, version, nil)
+}
+
+// SnapshotFromBootstrapStateWithParams is SnapshotFromBootstrapState with a
+// caller-specified protocol version and liveness weighting of the consensus
+// leader selection, which is disabled if nil.
+func SnapshotFromBootstrapStateWithParams(
+	root *flow.Block,
+	result *flow.ExecutionResult,
+	seal *flow.Seal,
+	qc *flow.QuorumCertificate,
+	version uint,
+	livenessWeighting *flow.LivenessWeighting,
+) (*Snapshot, error) {
 
 	setup, ok := result.ServiceEvents[0].Event.(*flow.EpochSetup)
 	if !ok {
@@ -243,7 +265,7 @@ func SnapshotFromBootstrapStateWithProtocolVersion(
 		return nil, fmt.Errorf("invalid commit event type (%T)", result.ServiceEvents[1].Event)
 	}
 
-	current, err := NewCommittedEpoch(setup, commit)
+	current, err := NewCommittedEpoch(setup, commit, nil)
 	if err != nil {
 		return nil, fmt.Errorf("could not convert epoch: %w", err)
 	}
@@ -252,9 +274,10 @@ func SnapshotFromBootstrapStateWithProtocolVersion(
 	}
 
 	params := EncodableParams{
-		ChainID:         root.Header.ChainID, // chain ID must match the root block
-		SporkID:         root.ID(),           // use root block ID as the unique spork identifier
-		ProtocolVersion: version,             // major software version for this spork
+		ChainID:           root.Header.ChainID, // chain ID must match the root block
+		SporkID:           root.ID(),           // use root block ID as the unique spork identifier
+		ProtocolVersion:   version,             // major software version for this spork
+		LivenessWeighting: livenessWeighting,   // consensus leader selection for this spork
 	}
 
 	snap := SnapshotFromEncodable(EncodableSnapshot{
@@ -39,6 +39,7 @@ type EncodableEpoch struct {
 	Clustering         flow.ClusterList
 	Clusters           []EncodableCluster
 	DKG                *EncodableDKG
+	QCParticipation    *flow.QCParticipation
 }
 
 // EncodableDKG is the encoding format for protocol.DKG
@@ -64,7 +65,8 @@ type EncodableCluster struct {
 
 // EncodableParams is the encoding format for protocol.GlobalParams
 type EncodableParams struct {
-	ChainID         flow.ChainID
-	SporkID         flow.Identifier
-	ProtocolVersion uint
+	ChainID           flow.ChainID
+	SporkID           flow.Identifier
+	ProtocolVersion   uint
+	LivenessWeighting *flow.LivenessWeighting
 }
@@ -44,6 +44,10 @@ func (e Epoch) DKG() (protocol.DKG, error) {
 	return nil, protocol.ErrEpochNotCommitted
 }
 
+func (e Epoch) QCParticipation() (*flow.QCParticipation, error) {
+	return e.enc.QCParticipation, nil
+}
+
 func (e Epoch) Cluster(i uint) (protocol.Cluster, error) {
 	if e.enc.Clusters != nil {
 		if i >= uint(len(e.enc.Clusters)) {
@@ -79,6 +83,8 @@ func (eq Epochs) Next() protocol.Epoch {
 type setupEpoch struct {
 	// EpochSetup service event
 	setupEvent *flow.EpochSetup
+	// QC participation recorded for the epoch, if any
+	participation *flow.QCParticipation
 }
 
 func (es *setupEpoch) Counter() (uint64, error) {
@@ -131,6 +137,10 @@ func (es *setupEpoch) RandomSource() ([]byte, error) {
 	return es.setupEvent.RandomSource, nil
 }
 
+func (es *setupEpoch) QCParticipation() (*flow.QCParticipation, error) {
+	return es.participation, nil
+}
+
 // committedEpoch is an implementation of protocol.Epoch backed by an EpochSetup
 // and EpochCommit service event. This is used for converting service events to
 // inmem.Epoch.
@@ -195,21 +205,25 @@ func (es *committedEpoch) DKG() (protocol.DKG, error) {
 }
 
 // NewSetupEpoch returns a memory-backed epoch implementation based on an
-// EpochSetup event. Epoch information available after the setup phase will
-// not be accessible in the resulting epoch instance.
-func NewSetupEpoch(setupEvent *flow.EpochSetup) (*Epoch, error) {
+// EpochSetup event and the QC participation recorded for the epoch, which may
+// be nil. Epoch information available after the setup phase will not be
+// accessible in the resulting epoch instance.
+func NewSetupEpoch(setupEvent *flow.EpochSetup, participation *flow.QCParticipation) (*Epoch, error) {
 	convertible := &setupEpoch{
-		setupEvent: setupEvent,
+		setupEvent:    setupEvent,
+		participation: participation,
 	}
 	return FromEpoch(convertible)
 }
 
 // NewCommittedEpoch returns a memory-backed epoch implementation based on an
-// EpochSetup and EpochCommit event.
-func NewCommittedEpoch(setupEvent *flow.EpochSetup, commitEvent *flow.EpochCommit) (*Epoch, error) {
+// EpochSetup and EpochCommit event and the QC participation recorded for the
+// epoch, which may be nil.
+func NewCommittedEpoch(setupEvent *flow.EpochSetup, commitEvent *flow.EpochCommit, participation *flow.QCParticipation) (*Epoch, error) {
 	convertible := &committedEpoch{
 		setupEpoch: setupEpoch{
-			setupEvent: setupEvent,
+			setupEvent:    setupEvent,
+			participation: participation,
 		},
 		commitEvent: commitEvent,
 	}
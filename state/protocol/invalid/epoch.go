@@ -54,6 +54,10 @@ func (u *Epoch) RandomSource() ([]byte, error) {
 	return nil, u.err
 }
 
+func (u *Epoch) QCParticipation() (*flow.QCParticipation, error) {
+	return nil, u.err
+}
+
 func NewEpoch(err error) *Epoch {
 	return &Epoch{err: err}
 }
@@ -20,3 +20,7 @@ func (p *Params) SporkID() (flow.Identifier, error) {
 func (p *Params) ProtocolVersion() (uint, error) {
 	return 0, p.err
 }
+
+func (p *Params) LivenessWeighting() (*flow.LivenessWeighting, error) {
+	return nil, p.err
+}
@@ -232,6 +232,29 @@ func (_m *Epoch) InitialIdentities() (flow.IdentityList, error) {
 	return r0, r1
 }
 
+// QCParticipation provides a mock function with given fields:
+func (_m *Epoch) QCParticipation() (*flow.QCParticipation, error) {
+	ret := _m.Called()
+
+	var r0 *flow.QCParticipation
+	if rf, ok := ret.Get(0).(func() *flow.QCParticipation); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.QCParticipation)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // RandomSource provides a mock function with given fields:
 func (_m *Epoch) RandomSource() ([]byte, error) {
 	ret := _m.Called()
@@ -33,6 +33,29 @@ func (_m *Params) ChainID() (flow.ChainID, error) {
 	return r0, r1
 }
 
+// LivenessWeighting provides a mock function with given fields:
+func (_m *Params) LivenessWeighting() (*flow.LivenessWeighting, error) {
+	ret := _m.Called()
+
+	var r0 *flow.LivenessWeighting
+	if rf, ok := ret.Get(0).(func() *flow.LivenessWeighting); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*flow.LivenessWeighting)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ProtocolVersion provides a mock function with given fields:
 func (_m *Params) ProtocolVersion() (uint, error) {
 	ret := _m.Called()
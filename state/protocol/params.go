@@ -45,4 +45,10 @@ type GlobalParams interface {
 	// ProtocolVersion returns the protocol version, the major software version
 	// of the protocol software.
 	ProtocolVersion() (uint, error)
+
+	// LivenessWeighting returns the parameters of liveness-weighted leader selection
+	// for the consensus committee, or nil if it is disabled for this spork. If enabled,
+	// the leader selection weight of consensus nodes which did not participate in the
+	// QCs recorded for an epoch is reduced.
+	LivenessWeighting() (*flow.LivenessWeighting, error)
 }
@@ -69,3 +69,68 @@ func IsSporkRootSnapshot(snapshot Snapshot) (bool, error) {
 	}
 	return true, nil
 }
+
+// SealingSegmentSpansTransition returns whether the sealing segment of the given snapshot spans an
+// epoch transition or an epoch phase transition, in which case the snapshot can't be used to
+// bootstrap a node. All blocks of the sealing segment must be known to the given state.
+func SealingSegmentSpansTransition(state State, snapshot Snapshot) (bool, error) {
+	segment, err := snapshot.SealingSegment()
+	if err != nil {
+		return false, fmt.Errorf("could not get sealing segment: %w", err)
+	}
+
+	highest := state.AtBlockID(segment.Highest().ID())
+	lowest := state.AtBlockID(segment.Lowest().ID())
+
+	highestCounter, err := highest.Epochs().Current().Counter()
+	if err != nil {
+		return false, fmt.Errorf("could not get epoch counter at highest block of sealing segment: %w", err)
+	}
+	lowestCounter, err := lowest.Epochs().Current().Counter()
+	if err != nil {
+		return false, fmt.Errorf("could not get epoch counter at lowest block of sealing segment: %w", err)
+	}
+	highestPhase, err := highest.Phase()
+	if err != nil {
+		return false, fmt.Errorf("could not get epoch phase at highest block of sealing segment: %w", err)
+	}
+	lowestPhase, err := lowest.Phase()
+	if err != nil {
+		return false, fmt.Errorf("could not get epoch phase at lowest block of sealing segment: %w", err)
+	}
+
+	return highestCounter != lowestCounter || highestPhase != lowestPhase, nil
+}
+
+// SnapshotAtSealedHeight returns the snapshot at the finalized block with the given height, which
+// can be used to bootstrap a node.
+// Expected errors during normal operations:
+//  * ErrHeightNotSealed if the block at the given height has not been sealed yet
+//  * ErrSealingSegmentSpansTransition if the sealing segment spans an epoch or epoch phase transition
+//  * storage.ErrNotFound if there is no finalized block at the given height
+// All other errors are unexpected and potential symptoms of internal state corruption.
+func SnapshotAtSealedHeight(state State, height uint64) (Snapshot, error) {
+	sealed, err := state.Sealed().Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get sealed block: %w", err)
+	}
+	if height > sealed.Height {
+		return nil, fmt.Errorf("block at height %d is not sealed (latest sealed height: %d): %w", height, sealed.Height, ErrHeightNotSealed)
+	}
+
+	snapshot := state.AtHeight(height)
+	_, err = snapshot.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not get block at height %d: %w", height, err)
+	}
+
+	spansTransition, err := SealingSegmentSpansTransition(state, snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("could not check sealing segment: %w", err)
+	}
+	if spansTransition {
+		return nil, fmt.Errorf("snapshot at height %d: %w", height, ErrSealingSegmentSpansTransition)
+	}
+
+	return snapshot, nil
+}
@@ -0,0 +1,58 @@
+package protocol_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/crypto"
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/flow/filter"
+	"github.com/onflow/flow-go/state/protocol"
+	"github.com/onflow/flow-go/utils/unittest"
+)
+
+func TestEpochSetupValidity(t *testing.T) {
+	participants := unittest.IdentityListFixture(20, unittest.WithAllRoles())
+
+	t.Run("invalid first/final view", func(t *testing.T) {
+		_, result, _ := unittest.BootstrapFixture(participants)
+		setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
+		// set an invalid final view for the first epoch
+		setup.FinalView = setup.FirstView
+
+		err := protocol.IsValidEpochSetup(setup, true)
+		require.Error(t, err)
+	})
+
+	t.Run("non-canonically ordered identities", func(t *testing.T) {
+		_, result, _ := unittest.BootstrapFixture(participants)
+		setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
+		// randomly shuffle the identities so they are not canonically ordered
+		setup.Participants = setup.Participants.DeterministicShuffle(time.Now().UnixNano())
+
+		err := protocol.IsValidEpochSetup(setup, true)
+		require.Error(t, err)
+	})
+
+	t.Run("invalid cluster assignments", func(t *testing.T) {
+		_, result, _ := unittest.BootstrapFixture(participants)
+		setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
+		// create an invalid cluster assignment (node appears in multiple clusters)
+		collector := participants.Filter(filter.HasRole(flow.RoleCollection))[0]
+		setup.Assignments = append(setup.Assignments, []flow.Identifier{collector.NodeID})
+
+		err := protocol.IsValidEpochSetup(setup, true)
+		require.Error(t, err)
+	})
+
+	t.Run("short seed", func(t *testing.T) {
+		_, result, _ := unittest.BootstrapFixture(participants)
+		setup := result.ServiceEvents[0].Event.(*flow.EpochSetup)
+		setup.RandomSource = unittest.SeedFixture(crypto.SeedMinLenDKG - 1)
+
+		err := protocol.IsValidEpochSetup(setup, true)
+		require.Error(t, err)
+	})
+}
@@ -176,6 +176,15 @@ func (h *Headers) IndexByChunkID(headerID, chunkID flow.Identifier) error {
 	return operation.RetryOnConflictTx(h.db, transaction.Update, h.chunkIDCache.PutTx(chunkID, headerID))
 }
 
+func (h *Headers) RemoveChunkBlockIndexByChunkID(chunkID flow.Identifier) error {
+	err := operation.RetryOnConflict(h.db.Update, operation.RemoveBlockIDByChunkID(chunkID))
+	if err != nil {
+		return fmt.Errorf("could not remove chunk block index: %w", err)
+	}
+	h.chunkIDCache.Remove(chunkID)
+	return nil
+}
+
 func (h *Headers) BatchIndexByChunkID(headerID, chunkID flow.Identifier, batch storage.BatchStorage) error {
 	writeBatch := batch.GetWriter()
 	return operation.BatchIndexBlockByChunkID(headerID, chunkID)(writeBatch)
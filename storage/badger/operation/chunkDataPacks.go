@@ -26,3 +26,18 @@ func RetrieveChunkDataPack(chunkID flow.Identifier, c *badgermodel.StoredChunkDa
 func RemoveChunkDataPack(chunkID flow.Identifier) func(*badger.Txn) error {
 	return remove(makePrefix(codeChunkDataPack, chunkID))
 }
+
+// InsertChunkDataPacksPrunedHeight inserts the height up to which chunk data packs have been pruned.
+func InsertChunkDataPacksPrunedHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeChunkDataPacksPrunedHeight), height)
+}
+
+// UpdateChunkDataPacksPrunedHeight updates the height up to which chunk data packs have been pruned.
+func UpdateChunkDataPacksPrunedHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeChunkDataPacksPrunedHeight), height)
+}
+
+// RetrieveChunkDataPacksPrunedHeight retrieves the height up to which chunk data packs have been pruned.
+func RetrieveChunkDataPacksPrunedHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeChunkDataPacksPrunedHeight), height)
+}
@@ -25,6 +25,16 @@ func RetrieveEpochCommit(eventID flow.Identifier, event *flow.EpochCommit) func(
 	return retrieve(makePrefix(codeEpochCommit, eventID), event)
 }
 
+// InsertQCParticipation inserts a QC participation record. Records are keyed by
+// their ID, so inserting the same record again is a no-op.
+func InsertQCParticipation(participationID flow.Identifier, participation *flow.QCParticipation) func(*badger.Txn) error {
+	return SkipDuplicates(insert(makePrefix(codeQCParticipation, participationID), participation))
+}
+
+func RetrieveQCParticipation(participationID flow.Identifier, participation *flow.QCParticipation) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeQCParticipation, participationID), participation)
+}
+
 func InsertEpochStatus(blockID flow.Identifier, status *flow.EpochStatus) func(*badger.Txn) error {
 	return insert(makePrefix(codeBlockEpochStatus, blockID), status)
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/dgraph-io/badger/v2"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/flow"
 	"github.com/onflow/flow-go/utils/unittest"
@@ -66,3 +67,24 @@ func TestEpochEmergencyFallback(t *testing.T) {
 		})
 	})
 }
+
+func TestQCParticipation_InsertRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		participation := flow.NewQCParticipation()
+		participation.Add(unittest.IdentifierListFixture(3))
+		participation.Add(unittest.IdentifierListFixture(2))
+		participationID := participation.ID()
+
+		err := db.Update(InsertQCParticipation(participationID, participation))
+		require.NoError(t, err)
+		// inserting the same record again is a no-op
+		err = db.Update(InsertQCParticipation(participationID, participation))
+		require.NoError(t, err)
+
+		var actual flow.QCParticipation
+		err = db.View(RetrieveQCParticipation(participationID, &actual))
+		require.NoError(t, err)
+		assert.Equal(t, participation, &actual)
+		assert.Equal(t, participationID, actual.ID())
+	})
+}
@@ -63,6 +63,11 @@ func BatchIndexBlockByChunkID(blockID, chunkID flow.Identifier) func(batch *badg
 	return batchInsert(makePrefix(codeIndexBlockByChunkID, chunkID), blockID)
 }
 
+// RemoveBlockIDByChunkID removes the index of the block ID by chunk ID.
+func RemoveBlockIDByChunkID(chunkID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codeIndexBlockByChunkID, chunkID))
+}
+
 // LookupCollectionBlock looks up a block by a collection within that block.
 func LookupCollectionBlock(collID flow.Identifier, blockID *flow.Identifier) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeCollectionBlock, collID), blockID)
@@ -0,0 +1,38 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/libp2p"
+)
+
+func InsertPeerRecord(nodeID flow.Identifier, record *libp2p.PeerRecord) func(*badger.Txn) error {
+	return insert(makePrefix(codePeerRecord, nodeID), record)
+}
+
+func UpdatePeerRecord(nodeID flow.Identifier, record *libp2p.PeerRecord) func(*badger.Txn) error {
+	return update(makePrefix(codePeerRecord, nodeID), record)
+}
+
+func RemovePeerRecord(nodeID flow.Identifier) func(*badger.Txn) error {
+	return remove(makePrefix(codePeerRecord, nodeID))
+}
+
+// RetrieveAllPeerRecords retrieves the records of all known peers, ordered by node ID.
+func RetrieveAllPeerRecords(records *[]*libp2p.PeerRecord) func(*badger.Txn) error {
+	return traverse(makePrefix(codePeerRecord), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var val libp2p.PeerRecord
+		create := func() interface{} {
+			return &val
+		}
+		handle := func() error {
+			*records = append(*records, &val)
+			return nil
+		}
+		return check, create, handle
+	})
+}
@@ -18,11 +18,11 @@ const (
 	// codes for views with special meaning
 	codeStartedView = 10 // latest view hotstuff started
 	codeVotedView   = 11 // latest view hotstuff voted on
-
 	// codes for fields associated with the root state
 	codeRootQuorumCertificate = 12
 	codeSporkID               = 13
 	codeProtocolVersion       = 14
+	codeLivenessWeighting     = 15
 
 	// code for heights with special meaning
 	codeFinalizedHeight         = 20 // latest finalized block height
@@ -69,12 +69,27 @@ const (
 	codeBeaconPrivateKey = 63 // BeaconPrivateKey, keyed by epoch counter
 	codeDKGStarted       = 64 // flag that the DKG for an epoch has been started
 	codeDKGEnded         = 65 // flag that the DKG for an epoch has ended (stores end state)
+	codeQCParticipation  = 66 // QC participation recorded for an epoch, keyed by ID
 
 	// job queue consumers and producers
 	codeJobConsumerProcessed = 70
 	codeJobQueue             = 71
 	codeJobQueuePointer      = 72
 
+	// execution node settings that should be preserved across restarts
+	codeExecutionStopHeight        = 80 // height after which the execution node stops executing blocks
+	codeExecutionPaused            = 81 // flag that block execution is paused
+	codeChunkDataPacksPrunedHeight = 82 // height up to which chunk data packs have been pruned
+
+	// consensus settings that should be preserved across restarts
+	codeTimeoutConfigUpdate = 83 // changes of the pacemaker timeout parameters made at runtime
+
+	// codes related to protocol violations
+	codeSlashingEvidence = 90 // slashing evidence for HotStuff violations, keyed by evidence ID
+
+	// networking state that should be preserved across restarts
+	codePeerRecord = 95 // records of the peers known on the unstaked network, keyed by node ID
+
 	// legacy codes (should be cleaned up)
 	codeChunkDataPack                = 100
 	codeCommit                       = 101
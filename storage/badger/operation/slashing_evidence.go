@@ -0,0 +1,33 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+func InsertSlashingEvidence(evidenceID flow.Identifier, evidence *flow.SlashingEvidence) func(*badger.Txn) error {
+	return insert(makePrefix(codeSlashingEvidence, evidenceID), evidence)
+}
+
+func RetrieveSlashingEvidence(evidenceID flow.Identifier, evidence *flow.SlashingEvidence) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeSlashingEvidence, evidenceID), evidence)
+}
+
+// RetrieveAllSlashingEvidence retrieves all stored slashing evidence, ordered by evidence ID.
+func RetrieveAllSlashingEvidence(evidence *[]*flow.SlashingEvidence) func(*badger.Txn) error {
+	return traverse(makePrefix(codeSlashingEvidence), func() (checkFunc, createFunc, handleFunc) {
+		check := func(key []byte) bool {
+			return true
+		}
+		var val flow.SlashingEvidence
+		create := func() interface{} {
+			return &val
+		}
+		handle := func() error {
+			*evidence = append(*evidence, &val)
+			return nil
+		}
+		return check, create, handle
+	})
+}
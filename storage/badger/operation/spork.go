@@ -30,3 +30,17 @@ func InsertProtocolVersion(version uint) func(*badger.Txn) error {
 func RetrieveProtocolVersion(version *uint) func(*badger.Txn) error {
 	return retrieve(makePrefix(codeProtocolVersion), version)
 }
+
+// InsertLivenessWeighting inserts the liveness weighting of the consensus leader
+// selection for the present spork. It is only inserted, exactly once when
+// bootstrapping the state, if liveness-weighted leader selection is enabled.
+func InsertLivenessWeighting(weighting *flow.LivenessWeighting) func(*badger.Txn) error {
+	return insert(makePrefix(codeLivenessWeighting), weighting)
+}
+
+// RetrieveLivenessWeighting retrieves the liveness weighting of the consensus leader
+// selection for the present spork. Returns storage.ErrNotFound if liveness-weighted
+// leader selection is disabled.
+func RetrieveLivenessWeighting(weighting *flow.LivenessWeighting) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeLivenessWeighting), weighting)
+}
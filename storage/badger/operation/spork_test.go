@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
 	"github.com/onflow/flow-go/utils/unittest"
 )
 
@@ -41,3 +42,19 @@ func TestProtocolVersion_InsertRetrieve(t *testing.T) {
 		assert.Equal(t, version, actual)
 	})
 }
+
+func TestLivenessWeighting_InsertRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		var actual flow.LivenessWeighting
+		err := db.View(RetrieveLivenessWeighting(&actual))
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		weighting := flow.DefaultLivenessWeighting
+		err = db.Update(InsertLivenessWeighting(&weighting))
+		require.NoError(t, err)
+
+		err = db.View(RetrieveLivenessWeighting(&actual))
+		require.NoError(t, err)
+		assert.Equal(t, weighting, actual)
+	})
+}
@@ -0,0 +1,29 @@
+package operation
+
+import (
+	"github.com/dgraph-io/badger/v2"
+)
+
+func InsertExecutionStopHeight(height uint64) func(*badger.Txn) error {
+	return insert(makePrefix(codeExecutionStopHeight), height)
+}
+
+func UpdateExecutionStopHeight(height uint64) func(*badger.Txn) error {
+	return update(makePrefix(codeExecutionStopHeight), height)
+}
+
+func RetrieveExecutionStopHeight(height *uint64) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeExecutionStopHeight), height)
+}
+
+func InsertExecutionPaused(paused bool) func(*badger.Txn) error {
+	return insert(makePrefix(codeExecutionPaused), paused)
+}
+
+func UpdateExecutionPaused(paused bool) func(*badger.Txn) error {
+	return update(makePrefix(codeExecutionPaused), paused)
+}
+
+func RetrieveExecutionPaused(paused *bool) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeExecutionPaused), paused)
+}
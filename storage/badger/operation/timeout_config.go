@@ -0,0 +1,35 @@
+package operation
+
+import (
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// TimeoutConfigUpdate is the stored form of the runtime changes of the pacemaker timeout parameters.
+// Only the non-nil fields have been changed.
+type TimeoutConfigUpdate struct {
+	MinReplicaTimeout              *time.Duration
+	MaxReplicaTimeout              *time.Duration
+	VoteAggregationTimeoutFraction *float64
+	TimeoutIncrease                *float64
+	TimeoutDecrease                *float64
+	BlockRateDelay                 *time.Duration
+}
+
+// InsertTimeoutConfigUpdate inserts the runtime changes of the pacemaker timeout parameters into the database.
+func InsertTimeoutConfigUpdate(chainID flow.ChainID, configUpdate *TimeoutConfigUpdate) func(*badger.Txn) error {
+	return insert(makePrefix(codeTimeoutConfigUpdate, chainID), configUpdate)
+}
+
+// UpdateTimeoutConfigUpdate updates the runtime changes of the pacemaker timeout parameters in the database.
+func UpdateTimeoutConfigUpdate(chainID flow.ChainID, configUpdate *TimeoutConfigUpdate) func(*badger.Txn) error {
+	return update(makePrefix(codeTimeoutConfigUpdate, chainID), configUpdate)
+}
+
+// RetrieveTimeoutConfigUpdate retrieves the runtime changes of the pacemaker timeout parameters from the database.
+func RetrieveTimeoutConfigUpdate(chainID flow.ChainID, configUpdate *TimeoutConfigUpdate) func(*badger.Txn) error {
+	return retrieve(makePrefix(codeTimeoutConfigUpdate, chainID), configUpdate)
+}
@@ -0,0 +1,56 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/libp2p"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// PeerRecords implements persistent storage for the records of the peers known on the unstaked network.
+// Records are kept in memory by their users and only read on startup, so they are not cached.
+type PeerRecords struct {
+	db *badger.DB
+}
+
+func NewPeerRecords(db *badger.DB) *PeerRecords {
+	return &PeerRecords{
+		db: db,
+	}
+}
+
+func (p *PeerRecords) Store(record *libp2p.PeerRecord) error {
+	err := operation.RetryOnConflict(p.db.Update, func(tx *badger.Txn) error {
+		err := operation.UpdatePeerRecord(record.NodeID, record)(tx)
+		if errors.Is(err, storage.ErrNotFound) {
+			return operation.InsertPeerRecord(record.NodeID, record)(tx)
+		}
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("could not store peer record: %w", err)
+	}
+	return nil
+}
+
+func (p *PeerRecords) Remove(nodeID flow.Identifier) error {
+	err := operation.RetryOnConflict(p.db.Update, operation.RemovePeerRecord(nodeID))
+	if err != nil {
+		return fmt.Errorf("could not remove peer record: %w", err)
+	}
+	return nil
+}
+
+func (p *PeerRecords) All() ([]*libp2p.PeerRecord, error) {
+	var records []*libp2p.PeerRecord
+	err := p.db.View(operation.RetrieveAllPeerRecords(&records))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve peer records: %w", err)
+	}
+	return records, nil
+}
@@ -0,0 +1,75 @@
+package badger_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/libp2p"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+// TestPeerRecordsStoreAndRemove tests that peer records can be stored, replaced and removed.
+func TestPeerRecordsStoreAndRemove(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewPeerRecords(db)
+
+		all, err := store.All()
+		require.NoError(t, err)
+		assert.Empty(t, all)
+
+		record := &libp2p.PeerRecord{
+			NodeID:      unittest.IdentifierFixture(),
+			PeerID:      "16Uiu2HAmQ3gL6rGZzcM5NQ4qXmM3GxAhbEznxJrBbZaXR1JB4p3F",
+			Addrs:       []string{"/ip4/10.0.0.1/tcp/3569"},
+			Score:       0.5,
+			Successes:   1,
+			LastSuccess: time.Now().UTC().Truncate(time.Second),
+		}
+		require.NoError(t, store.Store(record))
+
+		// storing the record of the same node replaces the previous one
+		updated := *record
+		updated.Score = 0.65
+		updated.Successes = 2
+		require.NoError(t, store.Store(&updated))
+
+		other := &libp2p.PeerRecord{
+			NodeID: unittest.IdentifierFixture(),
+			PeerID: "16Uiu2HAm9YUuyxkvzHFQ4DZTCeKvnaPEAoHoZ4XbmeHBe8FBDjqe",
+			Addrs:  []string{"/ip4/10.0.0.2/tcp/3569"},
+			Score:  0.5,
+		}
+		require.NoError(t, store.Store(other))
+
+		all, err = store.All()
+		require.NoError(t, err)
+		require.Len(t, all, 2)
+		for _, stored := range all {
+			if stored.NodeID == record.NodeID {
+				assert.Equal(t, updated.Score, stored.Score)
+				assert.Equal(t, updated.Successes, stored.Successes)
+				assert.Equal(t, updated.Addrs, stored.Addrs)
+				assert.True(t, updated.LastSuccess.Equal(stored.LastSuccess))
+			} else {
+				assert.Equal(t, other, stored)
+			}
+		}
+
+		require.NoError(t, store.Remove(record.NodeID))
+		err = store.Remove(record.NodeID)
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+		all, err = store.All()
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		assert.Equal(t, other.NodeID, all[0].NodeID)
+	})
+}
@@ -0,0 +1,59 @@
+package badger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dgraph-io/badger/v2"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/storage/badger/operation"
+)
+
+// SlashingEvidence implements persistent storage for evidence of protocol violations.
+// Evidence is rare and only read by operators, so it is not cached.
+type SlashingEvidence struct {
+	db *badger.DB
+}
+
+func NewSlashingEvidence(db *badger.DB) *SlashingEvidence {
+	return &SlashingEvidence{
+		db: db,
+	}
+}
+
+func (s *SlashingEvidence) Store(evidence *flow.SlashingEvidence) (bool, error) {
+	var stored bool
+	err := operation.RetryOnConflict(s.db.Update, func(tx *badger.Txn) error {
+		stored = true
+		err := operation.InsertSlashingEvidence(evidence.ID(), evidence)(tx)
+		if errors.Is(err, storage.ErrAlreadyExists) {
+			stored = false
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return false, fmt.Errorf("could not store slashing evidence: %w", err)
+	}
+	return stored, nil
+}
+
+func (s *SlashingEvidence) ByID(evidenceID flow.Identifier) (*flow.SlashingEvidence, error) {
+	var evidence flow.SlashingEvidence
+	err := s.db.View(operation.RetrieveSlashingEvidence(evidenceID, &evidence))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve slashing evidence: %w", err)
+	}
+	return &evidence, nil
+}
+
+func (s *SlashingEvidence) All() ([]*flow.SlashingEvidence, error) {
+	var evidence []*flow.SlashingEvidence
+	err := s.db.View(operation.RetrieveAllSlashingEvidence(&evidence))
+	if err != nil {
+		return nil, fmt.Errorf("could not retrieve slashing evidence: %w", err)
+	}
+	return evidence, nil
+}
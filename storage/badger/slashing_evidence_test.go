@@ -0,0 +1,61 @@
+package badger_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dgraph-io/badger/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/storage"
+	"github.com/onflow/flow-go/utils/unittest"
+
+	badgerstorage "github.com/onflow/flow-go/storage/badger"
+)
+
+// TestSlashingEvidenceStoreAndRetrieve tests that evidence can be stored and retrieved,
+// and that the same violation is only stored once.
+func TestSlashingEvidenceStoreAndRetrieve(t *testing.T) {
+	unittest.RunWithBadgerDB(t, func(db *badger.DB) {
+		store := badgerstorage.NewSlashingEvidence(db)
+
+		_, err := store.ByID(unittest.IdentifierFixture())
+		assert.True(t, errors.Is(err, storage.ErrNotFound))
+
+		all, err := store.All()
+		require.NoError(t, err)
+		assert.Empty(t, all)
+
+		expected := unittest.SlashingEvidenceFixture()
+		stored, err := store.Store(expected)
+		require.NoError(t, err)
+		assert.True(t, stored)
+
+		actual, err := store.ByID(expected.ID())
+		require.NoError(t, err)
+		assert.Equal(t, expected.ID(), actual.ID())
+		assert.Equal(t, expected.First, actual.First)
+		assert.Equal(t, expected.Second, actual.Second)
+		assert.True(t, expected.DetectedAt.Equal(actual.DetectedAt))
+
+		// the same violation with the conflicting votes observed in the opposite order is a duplicate
+		duplicate := *expected
+		duplicate.First, duplicate.Second = expected.Second, expected.First
+		stored, err = store.Store(&duplicate)
+		require.NoError(t, err)
+		assert.False(t, stored)
+
+		other := unittest.SlashingEvidenceFixture(func(evidence *flow.SlashingEvidence) {
+			evidence.Type = flow.SlashingEvidenceDoubleProposal
+		})
+		stored, err = store.Store(other)
+		require.NoError(t, err)
+		assert.True(t, stored)
+
+		all, err = store.All()
+		require.NoError(t, err)
+		assert.Len(t, all, 2)
+	})
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+	"github.com/onflow/flow-go/model/libp2p"
+)
+
+// PeerRecords is the storage interface for the records of the peers known to a node of the unstaked network.
+type PeerRecords interface {
+
+	// Store persists the given record, replacing any record stored for the same node.
+	Store(record *libp2p.PeerRecord) error
+
+	// Remove removes the record of the given node.
+	// It returns storage.ErrNotFound if no record is stored for the node.
+	Remove(nodeID flow.Identifier) error
+
+	// All returns all stored records, ordered by node ID.
+	All() ([]*libp2p.PeerRecord, error)
+}
@@ -0,0 +1,21 @@
+package storage
+
+import (
+	"github.com/onflow/flow-go/model/flow"
+)
+
+// SlashingEvidence is the storage interface for evidence of protocol violations
+// committed by consensus participants.
+type SlashingEvidence interface {
+
+	// Store persists the given evidence, unless evidence with the same ID is already stored.
+	// It returns true if the evidence was stored and false if it is a duplicate.
+	Store(evidence *flow.SlashingEvidence) (bool, error)
+
+	// ByID returns the evidence with the given ID.
+	// It returns storage.ErrNotFound if no such evidence is stored.
+	ByID(evidenceID flow.Identifier) (*flow.SlashingEvidence, error)
+
+	// All returns all stored evidence, ordered by evidence ID.
+	All() ([]*flow.SlashingEvidence, error)
+}
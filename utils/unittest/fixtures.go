@@ -1617,6 +1617,36 @@ func VoteForBlockFixture(block *hotstuff.Block, opts ...func(vote *hotstuff.Vote
 	return vote
 }
 
+// SlashingEvidenceFixture returns double vote evidence for a random offender and view.
+func SlashingEvidenceFixture(opts ...func(*flow.SlashingEvidence)) *flow.SlashingEvidence {
+	offenderID := IdentifierFixture()
+	view := uint64(rand.Uint32())
+	evidence := &flow.SlashingEvidence{
+		Type:       flow.SlashingEvidenceDoubleVote,
+		OffenderID: offenderID,
+		View:       view,
+		First: flow.SignedVote{
+			View:     view,
+			BlockID:  IdentifierFixture(),
+			SignerID: offenderID,
+			SigData:  RandomBytes(128),
+		},
+		Second: flow.SignedVote{
+			View:     view,
+			BlockID:  IdentifierFixture(),
+			SignerID: offenderID,
+			SigData:  RandomBytes(128),
+		},
+		DetectedAt: time.Now().UTC(),
+	}
+
+	for _, opt := range opts {
+		opt(evidence)
+	}
+
+	return evidence
+}
+
 func VoteWithStakingSig() func(*hotstuff.Vote) {
 	return func(vote *hotstuff.Vote) {
 		vote.SigData = append([]byte{byte(hotstuffroot.SigTypeStaking)}, vote.SigData...)
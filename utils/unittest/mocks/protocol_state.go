@@ -54,6 +54,10 @@ func (p *Params) ProtocolVersion() (uint, error) {
 	return 0, fmt.Errorf("not implemented")
 }
 
+func (p *Params) LivenessWeighting() (*flow.LivenessWeighting, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
 func (p *Params) Root() (*flow.Header, error) {
 	return p.state.root.Header, nil
 }